@@ -0,0 +1,185 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// AggregateFunc reduces the values of one column (nulls included) to a
+// single value.
+type AggregateFunc func(values []interface{}) (interface{}, error)
+
+var (
+	aggregationsMu sync.RWMutex
+	aggregations   = map[string]AggregateFunc{}
+)
+
+var builtinAggregations = []string{"count", "sum", "mean", "median", "std", "min", "max", "first", "last"}
+
+// RegisterAggregation makes fn available by name wherever an aggregation is
+// named: Grouped.Agg and ToDataFrame, Resample, and the REPL's agg command.
+// Built-in names cannot be replaced, and fn must not be nil.
+func RegisterAggregation(name string, fn AggregateFunc) error {
+	if name == "" {
+		return fmt.Errorf("aggregation name is empty")
+	}
+	if containsString(builtinAggregations, name) {
+		return fmt.Errorf("aggregation '%s' is built in", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("aggregation '%s' has no function", name)
+	}
+	aggregationsMu.Lock()
+	defer aggregationsMu.Unlock()
+	aggregations[name] = fn
+	return nil
+}
+
+// Aggregations lists the built-in and registered aggregation names.
+func Aggregations() []string {
+	aggregationsMu.RLock()
+	defer aggregationsMu.RUnlock()
+	names := append([]string{}, builtinAggregations...)
+	for name := range aggregations {
+		if !containsString(builtinAggregations, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func registeredAggregation(name string) (AggregateFunc, bool) {
+	aggregationsMu.RLock()
+	defer aggregationsMu.RUnlock()
+	fn, ok := aggregations[name]
+	return fn, ok
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func aggregateValues(name string, values []interface{}) (interface{}, error) {
+	if fn, ok := registeredAggregation(name); ok {
+		return fn(values)
+	}
+
+	switch name {
+	case "count":
+		count := 0
+		for _, val := range values {
+			if val != nil {
+				count++
+			}
+		}
+		return count, nil
+	case "sum", "mean", "median":
+		numbers := numericValues(values)
+		if name != "median" {
+			numbers = summableValues(values)
+		}
+		if len(numbers) == 0 {
+			return nil, nil
+		}
+		sum := sumFloat64(numbers)
+		switch name {
+		case "sum":
+			return sum, nil
+		case "mean":
+			return sum / float64(len(numbers)), nil
+		}
+		sort.Float64s(numbers)
+		mid := len(numbers) / 2
+		if len(numbers)%2 == 0 {
+			return (numbers[mid-1] + numbers[mid]) / 2, nil
+		}
+		return numbers[mid], nil
+	case "std":
+		numbers := numericValues(values)
+		if len(numbers) < 2 {
+			return nil, nil
+		}
+		var sum float64
+		for _, n := range numbers {
+			sum += n
+		}
+		mean := sum / float64(len(numbers))
+		var sq float64
+		for _, n := range numbers {
+			sq += (n - mean) * (n - mean)
+		}
+		return math.Sqrt(sq / float64(len(numbers)-1)), nil
+	case "min", "max":
+		var best interface{}
+		for _, val := range values {
+			if val == nil {
+				continue
+			}
+			if best == nil {
+				best = val
+				continue
+			}
+			comp := compareValues(val, best)
+			if (name == "min" && comp < 0) || (name == "max" && comp > 0) {
+				best = val
+			}
+		}
+		return best, nil
+	case "first":
+		for _, val := range values {
+			if val != nil {
+				return val, nil
+			}
+		}
+		return nil, nil
+	case "last":
+		for i := len(values) - 1; i >= 0; i-- {
+			if values[i] != nil {
+				return values[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown aggregation '%s'", name)
+}
+
+// summableFloat is toFloat64 with bools counted as 0 and 1, the way
+// Series.Sum and Mean treat them.
+func summableFloat(val interface{}) (float64, bool) {
+	if b, ok := val.(bool); ok {
+		if b {
+			return 1, true
+		}
+		return 0, true
+	}
+	return toFloat64(val)
+}
+
+func summableValues(values []interface{}) []float64 {
+	numbers := make([]float64, 0, len(values))
+	for _, val := range values {
+		if f, ok := summableFloat(val); ok {
+			numbers = append(numbers, f)
+		}
+	}
+	return numbers
+}
+
+func numericValues(values []interface{}) []float64 {
+	numbers := make([]float64, 0, len(values))
+	for _, val := range values {
+		if f, ok := toFloat64(val); ok {
+			numbers = append(numbers, f)
+		}
+	}
+	return numbers
+}
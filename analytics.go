@@ -0,0 +1,250 @@
+package gopandas
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SessionColumn is the column Sessionize adds.
+const SessionColumn = "session_id"
+
+// Sessionize assigns a session ID to every event: a user's events, taken in
+// timestamp order, belong to one session until the time since the previous
+// event exceeds gap. IDs are ints numbered across all users in order of
+// session start. Rows with a null user or a timestamp that is not a
+// time.Time get nil. The frame's row order is kept.
+func Sessionize(df *DataFrame, userCol, tsCol string, gap time.Duration) (*DataFrame, error) {
+	u := df.columnIndex(userCol)
+	if u == -1 {
+		return nil, fmt.Errorf("column '%s' not found", userCol)
+	}
+	ts := df.columnIndex(tsCol)
+	if ts == -1 {
+		return nil, fmt.Errorf("column '%s' not found", tsCol)
+	}
+	if gap <= 0 {
+		return nil, fmt.Errorf("session gap must be positive, got %v", gap)
+	}
+
+	users := make(map[string]int)
+	events := make([][]int, 0)
+	for i, row := range df.data {
+		if _, ok := row[ts].(time.Time); !ok || row[u] == nil {
+			continue
+		}
+		key := indexKey(row[u])
+		if _, ok := users[key]; !ok {
+			users[key] = len(events)
+			events = append(events, nil)
+		}
+		events[users[key]] = append(events[users[key]], i)
+	}
+
+	type session struct {
+		user  int
+		start time.Time
+		rows  []int
+	}
+	sessions := make([]session, 0)
+	for user, rows := range events {
+		sort.SliceStable(rows, func(a, b int) bool {
+			return df.data[rows[a]][ts].(time.Time).Before(df.data[rows[b]][ts].(time.Time))
+		})
+		var last time.Time
+		for k, i := range rows {
+			t := df.data[i][ts].(time.Time)
+			if k == 0 || t.Sub(last) > gap {
+				sessions = append(sessions, session{user: user, start: t})
+			}
+			current := &sessions[len(sessions)-1]
+			current.rows = append(current.rows, i)
+			last = t
+		}
+	}
+	sort.SliceStable(sessions, func(a, b int) bool {
+		if !sessions[a].start.Equal(sessions[b].start) {
+			return sessions[a].start.Before(sessions[b].start)
+		}
+		return sessions[a].user < sessions[b].user
+	})
+	ids := make([]interface{}, len(df.data))
+	for id, s := range sessions {
+		for _, i := range s.rows {
+			ids[i] = id
+		}
+	}
+
+	resultCols := append([]string{}, df.columns...)
+	target := df.columnIndex(SessionColumn)
+	if target == -1 {
+		target = len(resultCols)
+		resultCols = append(resultCols, SessionColumn)
+	}
+	result := df.derive(NewDataFrame(resultCols), "sessionize", map[string]interface{}{
+		"user": userCol, "timestamp": tsCol, "gap": gap.String(), "sessions": len(sessions),
+	})
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(resultCols))
+		copy(newRow, row)
+		newRow[target] = ids[i]
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+// Funnel counts how many users got through each of orderedSteps. A user
+// reaches a step with an event for it that comes after they reached the
+// previous step, going by row order, so sort the frame by time first. The
+// result has one row per step with columns step, users, conversion (share
+// of the users who reached the first step) and step_conversion (share of
+// those who reached the previous step).
+func Funnel(df *DataFrame, userCol, eventCol string, orderedSteps []string) (*DataFrame, error) {
+	u := df.columnIndex(userCol)
+	if u == -1 {
+		return nil, fmt.Errorf("column '%s' not found", userCol)
+	}
+	e := df.columnIndex(eventCol)
+	if e == -1 {
+		return nil, fmt.Errorf("column '%s' not found", eventCol)
+	}
+	if len(orderedSteps) == 0 {
+		return nil, fmt.Errorf("no funnel steps given")
+	}
+
+	// progress is the number of steps each user has completed so far
+	progress := make(map[string]int)
+	for _, row := range df.data {
+		if row[u] == nil {
+			continue
+		}
+		key := indexKey(row[u])
+		if done := progress[key]; done < len(orderedSteps) && row[e] != nil && fmt.Sprint(row[e]) == orderedSteps[done] {
+			progress[key] = done + 1
+		}
+	}
+	reached := make([]int, len(orderedSteps))
+	for _, done := range progress {
+		for k := 0; k < done; k++ {
+			reached[k]++
+		}
+	}
+
+	result := df.derive(NewDataFrame([]string{"step", "users", "conversion", "step_conversion"}), "funnel", map[string]interface{}{
+		"user": userCol, "event": eventCol, "steps": orderedSteps,
+	})
+	for k, step := range orderedSteps {
+		var conversion, stepConversion interface{}
+		if reached[0] > 0 {
+			conversion = float64(reached[k]) / float64(reached[0])
+		}
+		if k == 0 && reached[0] > 0 {
+			stepConversion = 1.0
+		} else if k > 0 && reached[k-1] > 0 {
+			stepConversion = float64(reached[k]) / float64(reached[k-1])
+		}
+		result.data = append(result.data, []interface{}{step, reached[k], conversion, stepConversion})
+		result.index = append(result.index, k)
+	}
+	return result, nil
+}
+
+// RetentionCohorts groups users into cohorts by the period (a frequency
+// such as "W" or "M", see Resample) of their first event and reports, for
+// each later period, the share of the cohort active in it. The result has
+// columns cohort, users and period_0, period_1, ..., one row per cohort in
+// time order; periods after the last event in the data are nil.
+func RetentionCohorts(df *DataFrame, userCol, tsCol, period string) (*DataFrame, error) {
+	u := df.columnIndex(userCol)
+	if u == -1 {
+		return nil, fmt.Errorf("column '%s' not found", userCol)
+	}
+	ts := df.columnIndex(tsCol)
+	if ts == -1 {
+		return nil, fmt.Errorf("column '%s' not found", tsCol)
+	}
+	f, err := ParseFrequency(period)
+	if err != nil {
+		return nil, err
+	}
+
+	first := make(map[string]time.Time)
+	var last time.Time
+	for _, row := range df.data {
+		t, ok := row[ts].(time.Time)
+		if !ok || row[u] == nil {
+			continue
+		}
+		key := indexKey(row[u])
+		if seen, ok := first[key]; !ok || t.Before(seen) {
+			first[key] = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	// offset counts whole periods from a cohort's start to t
+	offset := func(start, t time.Time) int {
+		k := 0
+		for p := f.Next(start); !p.After(t); p = f.Next(p) {
+			k++
+		}
+		return k
+	}
+
+	cohorts := make(map[time.Time]int)
+	starts := make([]time.Time, 0)
+	userCohort := make(map[string]time.Time, len(first))
+	for key, t := range first {
+		start := f.Floor(t)
+		userCohort[key] = start
+		if _, ok := cohorts[start]; !ok {
+			starts = append(starts, start)
+		}
+		cohorts[start]++
+	}
+	sort.Slice(starts, func(a, b int) bool { return starts[a].Before(starts[b]) })
+
+	active := make(map[time.Time][]map[string]bool)
+	width := 0
+	for _, start := range starts {
+		periods := offset(start, last) + 1
+		active[start] = make([]map[string]bool, periods)
+		if periods > width {
+			width = periods
+		}
+	}
+	for _, row := range df.data {
+		t, ok := row[ts].(time.Time)
+		if !ok || row[u] == nil {
+			continue
+		}
+		key := indexKey(row[u])
+		start := userCohort[key]
+		k := offset(start, t)
+		if active[start][k] == nil {
+			active[start][k] = make(map[string]bool)
+		}
+		active[start][k][key] = true
+	}
+
+	columns := []string{"cohort", "users"}
+	for k := 0; k < width; k++ {
+		columns = append(columns, fmt.Sprintf("period_%d", k))
+	}
+	result := df.derive(NewDataFrame(columns), "retention_cohorts", map[string]interface{}{
+		"user": userCol, "timestamp": tsCol, "period": period,
+	})
+	for i, start := range starts {
+		row := make([]interface{}, len(columns))
+		row[0], row[1] = start, cohorts[start]
+		for k, users := range active[start] {
+			row[2+k] = float64(len(users)) / float64(cohorts[start])
+		}
+		result.data = append(result.data, row)
+		result.index = append(result.index, i)
+	}
+	return result, nil
+}
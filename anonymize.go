@@ -0,0 +1,118 @@
+package gopandas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+)
+
+// AnonymizeStrategy replaces one value. nil values are passed through
+// without calling the strategy.
+type AnonymizeStrategy func(value interface{}) interface{}
+
+// AnonymizeHash replaces values with a salted SHA-256 digest (16 hex
+// characters), so equal inputs still match across rows and tables.
+func AnonymizeHash(salt string) AnonymizeStrategy {
+	return func(value interface{}) interface{} {
+		sum := sha256.Sum256([]byte(salt + fmt.Sprintf("%v", value)))
+		return hex.EncodeToString(sum[:8])
+	}
+}
+
+// AnonymizeMask replaces all but the last keep characters with '*'.
+func AnonymizeMask(keep int) AnonymizeStrategy {
+	return func(value interface{}) interface{} {
+		runes := []rune(fmt.Sprintf("%v", value))
+		for i := 0; i < len(runes)-keep; i++ {
+			runes[i] = '*'
+		}
+		return string(runes)
+	}
+}
+
+// AnonymizeGeneralize replaces numbers with the bin of the given width they
+// fall in, such as "30-39" for ages with width 10.
+func AnonymizeGeneralize(width float64) AnonymizeStrategy {
+	return func(value interface{}) interface{} {
+		f, ok := toFloat64(value)
+		if !ok || width <= 0 {
+			return nil
+		}
+		low := math.Floor(f/width) * width
+		if width == math.Trunc(width) {
+			return fmt.Sprintf("%v-%v", low, low+width-1)
+		}
+		return fmt.Sprintf("[%v, %v)", low, low+width)
+	}
+}
+
+var fakeValues = map[string][]string{
+	"first_name": {"Alex", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn", "Skyler", "Minjun", "Seoyeon", "Haruto", "Yui", "Mateo", "Sofia"},
+	"last_name":  {"Smith", "Kim", "Garcia", "Müller", "Rossi", "Tanaka", "Dubois", "Silva", "Novak", "Lee", "Park", "Brown", "Wilson", "Kowalski", "Jensen", "Costa"},
+	"city":       {"Springfield", "Riverton", "Lakeside", "Fairview", "Greenville", "Maple Grove", "Oakridge", "Hillcrest", "Brookfield", "Westport"},
+	"domain":     {"example.com", "example.org", "example.net"},
+}
+
+// AnonymizeFake replaces values with realistic fake ones of a kind: "name",
+// "first_name", "last_name", "email", "phone" or "city". The choice is
+// derived from the original value, so repeated values map to the same fake.
+func AnonymizeFake(kind string) AnonymizeStrategy {
+	return func(value interface{}) interface{} {
+		h := fnv.New64a()
+		h.Write([]byte(kind + "\x00" + fmt.Sprintf("%v", value)))
+		seed := h.Sum64()
+		pick := func(list string, salt uint64) string {
+			values := fakeValues[list]
+			return values[(seed/(salt+1))%uint64(len(values))]
+		}
+
+		switch kind {
+		case "first_name", "last_name", "city":
+			return pick(kind, 0)
+		case "name":
+			return pick("first_name", 0) + " " + pick("last_name", 7)
+		case "email":
+			return fmt.Sprintf("%s.%s%d@%s", strings.ToLower(pick("first_name", 0)), strings.ToLower(pick("last_name", 7)), seed%1000, pick("domain", 13))
+		case "phone":
+			return fmt.Sprintf("555-%04d-%04d", seed%10000, (seed/10000)%10000)
+		}
+		return nil
+	}
+}
+
+// Anonymize returns a copy of the frame with each listed column rewritten by
+// its strategy, for producing shareable extracts of production data.
+func (df *DataFrame) Anonymize(strategies map[string]AnonymizeStrategy) (*DataFrame, error) {
+	colStrategies := make(map[int]AnonymizeStrategy, len(strategies))
+	for column, strategy := range strategies {
+		colIndex := df.columnIndex(column)
+		if colIndex == -1 {
+			return nil, fmt.Errorf("column '%s' not found", column)
+		}
+		colStrategies[colIndex] = strategy
+	}
+
+	columns := make([]string, 0, len(strategies))
+	for column := range strategies {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	result := df.derive(NewDataFrame(df.columns), "anonymize", map[string]interface{}{"columns": columns})
+
+	for i, row := range df.data {
+		newRow := append([]interface{}{}, row...)
+		for j, strategy := range colStrategies {
+			if newRow[j] != nil {
+				newRow[j] = strategy(newRow[j])
+			}
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+
+	return result, nil
+}
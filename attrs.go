@@ -0,0 +1,49 @@
+package gopandas
+
+import "reflect"
+
+// SetName labels the frame, e.g. with the table or step it came from.
+func (df *DataFrame) SetName(name string) *DataFrame {
+	df.name = name
+	return df
+}
+
+func (df *DataFrame) Name() string {
+	return df.name
+}
+
+// Attrs returns the frame's attribute bag for tags such as run IDs, source
+// systems or load timestamps. The map is live: changes apply to the frame.
+// Derived frames start with a copy; Concat keeps attributes only when every
+// input has the same ones.
+func (df *DataFrame) Attrs() map[string]interface{} {
+	if df.attrs == nil {
+		df.attrs = make(map[string]interface{})
+	}
+	return df.attrs
+}
+
+func (df *DataFrame) inheritAttrs(src *DataFrame) {
+	df.name = src.name
+	df.attrs = nil
+	for key, value := range src.attrs {
+		df.Attrs()[key] = value
+	}
+}
+
+func (df *DataFrame) inheritCommonAttrs(inputs []*DataFrame) {
+	if len(inputs) == 0 {
+		return
+	}
+	for _, input := range inputs[1:] {
+		if input.name != inputs[0].name {
+			return
+		}
+		if len(input.attrs) != 0 || len(inputs[0].attrs) != 0 {
+			if !reflect.DeepEqual(input.attrs, inputs[0].attrs) {
+				return
+			}
+		}
+	}
+	df.inheritAttrs(inputs[0])
+}
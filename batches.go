@@ -0,0 +1,97 @@
+package gopandas
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchOption configures ApplyBatches.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	workers int
+}
+
+// WithWorkers runs up to n batches at once. The default is one, so batches
+// run in order one after another.
+func WithWorkers(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.workers = n
+	}
+}
+
+// ApplyBatches splits the frame into batches of batchSize rows, passes each
+// to fn (a copy, so fn may modify it) and stacks the frames fn returns in
+// batch order, aligning columns by name as Concat does. It suits work that
+// is cheaper per batch than per row, such as API enrichment or model
+// scoring. After the first error no new batches start, and the error of
+// the earliest failed batch is returned. A frame with no rows is passed to
+// fn once, so the result still has fn's columns.
+func (df *DataFrame) ApplyBatches(batchSize int, fn func(*DataFrame) (*DataFrame, error), options ...BatchOption) (*DataFrame, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	config := &batchConfig{workers: 1}
+	for _, option := range options {
+		option(config)
+	}
+	if config.workers < 1 {
+		return nil, fmt.Errorf("worker count must be positive, got %d", config.workers)
+	}
+
+	count := (len(df.data) + batchSize - 1) / batchSize
+	if count == 0 {
+		count = 1
+	}
+	results := make([]*DataFrame, count)
+	errs := make([]error, count)
+
+	jobs := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < config.workers && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				if failed.Load() {
+					continue
+				}
+				results[b], errs[b] = fn(df.batch(b*batchSize, (b+1)*batchSize))
+				if errs[b] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for b := 0; b < count; b++ {
+		jobs <- b
+	}
+	close(jobs)
+	wg.Wait()
+
+	frames := make([]*DataFrame, 0, count)
+	for b, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("batch %d: %w", b, err)
+		}
+		if results[b] != nil {
+			frames = append(frames, results[b])
+		}
+	}
+	return df.derive(Concat(frames...), "apply_batches", map[string]interface{}{"batch_size": batchSize, "batches": count}), nil
+}
+
+// batch copies rows [start, end) into a frame of their own.
+func (df *DataFrame) batch(start, end int) *DataFrame {
+	if end > len(df.data) {
+		end = len(df.data)
+	}
+	result := NewDataFrame(append([]string{}, df.columns...))
+	for i := start; i < end; i++ {
+		result.data = append(result.data, append([]interface{}{}, df.data[i]...))
+		result.index = append(result.index, df.index[i])
+	}
+	return df.derive(result, "batch", map[string]interface{}{"start": start, "end": end})
+}
@@ -0,0 +1,144 @@
+package gopandas
+
+import "fmt"
+
+// Mean returns per-column means of the numeric columns (axis 0), indexed by
+// column name, or per-row means across numeric cells (axis 1).
+func (df *DataFrame) Mean(axis int) (*Series, error) {
+	switch axis {
+	case 0:
+		data := make([]interface{}, 0, len(df.columns))
+		index := make([]interface{}, 0, len(df.columns))
+		for j, col := range df.columns {
+			values := make([]interface{}, len(df.data))
+			for i, row := range df.data {
+				values[i] = row[j]
+			}
+			if len(numericValues(values)) == 0 {
+				continue
+			}
+			mean, _ := aggregateValues("mean", values)
+			data = append(data, mean)
+			index = append(index, col)
+		}
+		series := NewSeries("mean", data)
+		series.index = index
+		return series, nil
+	case 1:
+		data := make([]interface{}, len(df.data))
+		for i, row := range df.data {
+			data[i], _ = aggregateValues("mean", row)
+		}
+		series := NewSeries("mean", data)
+		series.index = append([]interface{}{}, df.index...)
+		return series, nil
+	}
+	return nil, fmt.Errorf("invalid axis %d: use 0 or 1", axis)
+}
+
+// AddSeries adds s to every row or column. With axis 1, s holds one value
+// per column, matched by index label when the labels are column names and by
+// position otherwise; with axis 0, s holds one value per row. Cells that are
+// not numeric, or have no matching value, become nil.
+func (df *DataFrame) AddSeries(s *Series, axis int) (*DataFrame, error) {
+	return df.broadcast(s, axis, "+")
+}
+
+func (df *DataFrame) SubSeries(s *Series, axis int) (*DataFrame, error) {
+	return df.broadcast(s, axis, "-")
+}
+
+func (df *DataFrame) MulSeries(s *Series, axis int) (*DataFrame, error) {
+	return df.broadcast(s, axis, "*")
+}
+
+func (df *DataFrame) DivSeries(s *Series, axis int) (*DataFrame, error) {
+	return df.broadcast(s, axis, "/")
+}
+
+func (df *DataFrame) broadcast(s *Series, axis int, op string) (*DataFrame, error) {
+	var operand func(i, j int) interface{}
+
+	switch axis {
+	case 0:
+		if len(s.data) != len(df.data) {
+			return nil, fmt.Errorf("series length %d does not match rows length %d", len(s.data), len(df.data))
+		}
+		operand = func(i, _ int) interface{} { return s.data[i] }
+	case 1:
+		byName := make(map[string]int)
+		for k, label := range s.index {
+			if name, ok := label.(string); ok {
+				byName[name] = k
+			}
+		}
+		positions := make([]int, len(df.columns))
+		for j, col := range df.columns {
+			positions[j] = -1
+			if k, ok := byName[col]; ok {
+				positions[j] = k
+			} else if len(byName) == 0 && j < len(s.data) {
+				positions[j] = j
+			}
+		}
+		if len(byName) == 0 && len(s.data) != len(df.columns) {
+			return nil, fmt.Errorf("series length %d does not match columns length %d", len(s.data), len(df.columns))
+		}
+		operand = func(_, j int) interface{} {
+			if positions[j] == -1 {
+				return nil
+			}
+			return s.data[positions[j]]
+		}
+	default:
+		return nil, fmt.Errorf("invalid axis %d: use 0 or 1", axis)
+	}
+
+	result := df.derive(NewDataFrame(df.columns), "broadcast", map[string]interface{}{"op": op, "series": s.name, "axis": axis})
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(row))
+		for j, val := range row {
+			newRow[j] = arithmeticValue(op, val, operand(i, j))
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+// arithmeticValue applies op to two numbers, keeping ints for +, - and *.
+// It returns nil when either side is not numeric or on division by zero.
+func arithmeticValue(op string, a, b interface{}) interface{} {
+	ai, aInt := a.(int)
+	bi, bInt := b.(int)
+	if aInt && bInt && op != "/" {
+		switch op {
+		case "+":
+			return ai + bi
+		case "-":
+			return ai - bi
+		case "*":
+			return ai * bi
+		}
+	}
+
+	x, ok1 := toFloat64(a)
+	y, ok2 := toFloat64(b)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	switch op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		if y == 0 {
+			return nil
+		}
+		return x / y
+	}
+	return nil
+}
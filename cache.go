@@ -0,0 +1,84 @@
+package gopandas
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KVStore is the storage behind Cached; adapters for Redis, BoltDB and
+// similar stores only need to move bytes.
+type KVStore interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (m *MemoryStore) Get(key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+func Cached(loader func() (*DataFrame, error), key string, ttl time.Duration, store KVStore) (*DataFrame, error) {
+	data, ok, err := store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry '%s': %w", key, err)
+	}
+	if ok {
+		df := NewDataFrame(nil)
+		if err := df.UnmarshalBinary(data); err == nil {
+			return df, nil
+		}
+		// a corrupt or outdated entry is treated as a miss
+	}
+
+	df, err := loader()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := df.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode frame for cache: %w", err)
+	}
+	if err := store.Set(key, encoded, ttl); err != nil {
+		return nil, fmt.Errorf("failed to write cache entry '%s': %w", key, err)
+	}
+
+	return df, nil
+}
@@ -0,0 +1,354 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Calendar struct {
+	Weekend  map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+var DefaultCalendar = NewCalendar()
+
+func NewCalendar(holidays ...time.Time) *Calendar {
+	cal := &Calendar{
+		Weekend:  map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		holidays: make(map[string]bool),
+	}
+	cal.AddHolidays(holidays...)
+	return cal
+}
+
+func (c *Calendar) AddHolidays(holidays ...time.Time) {
+	for _, h := range holidays {
+		c.holidays[h.Format("2006-01-02")] = true
+	}
+}
+
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	return c.holidays[t.Format("2006-01-02")]
+}
+
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return !c.Weekend[t.Weekday()] && !c.IsHoliday(t)
+}
+
+// checkBusinessDays fails when every weekday is a weekend day, where
+// stepping to the next business day would never stop. Holidays are finite,
+// so they alone cannot cause that.
+func (c *Calendar) checkBusinessDays() error {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if !c.Weekend[day] {
+			return nil
+		}
+	}
+	return fmt.Errorf("calendar has no business days: every weekday is a weekend day")
+}
+
+// AddBusinessDays moves t by n business days, first rolling a date outside
+// the calendar onto a business day in the direction of travel.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	if err := c.checkBusinessDays(); err != nil {
+		return time.Time{}, err
+	}
+	return c.addBusinessDays(t, n), nil
+}
+
+func (c *Calendar) addBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step = -1
+		n = -n
+	}
+
+	// a start date outside the calendar first rolls onto a business day
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, step)
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+func (c *Calendar) BusinessDaysBetween(start, end time.Time) int {
+	if end.Before(start) {
+		return -c.BusinessDaysBetween(end, start)
+	}
+
+	count := 0
+	for d := truncateDay(start); d.Before(truncateDay(end)); d = d.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+type Frequency struct {
+	N        int
+	Unit     string
+	Calendar *Calendar
+}
+
+func ParseFrequency(freq string) (Frequency, error) {
+	freq = strings.TrimSpace(freq)
+	i := 0
+	for i < len(freq) && freq[i] >= '0' && freq[i] <= '9' {
+		i++
+	}
+
+	n := 1
+	if i > 0 {
+		parsed, err := strconv.Atoi(freq[:i])
+		if err != nil || parsed <= 0 {
+			return Frequency{}, fmt.Errorf("invalid frequency '%s'", freq)
+		}
+		n = parsed
+	}
+
+	unit := freq[i:]
+	switch unit {
+	case "s", "S":
+		unit = "s"
+	case "m", "min", "T":
+		unit = "min"
+	case "h", "H":
+		unit = "h"
+	case "d", "D":
+		unit = "D"
+	case "B":
+		if err := DefaultCalendar.checkBusinessDays(); err != nil {
+			return Frequency{}, fmt.Errorf("invalid frequency '%s': %w", freq, err)
+		}
+	case "W", "M", "MS", "Q", "QS", "Y", "YS", "A":
+		unit = strings.TrimSuffix(unit, "S")
+		if unit == "A" {
+			unit = "Y"
+		}
+	default:
+		return Frequency{}, fmt.Errorf("invalid frequency '%s'", freq)
+	}
+
+	return Frequency{N: n, Unit: unit, Calendar: DefaultCalendar}, nil
+}
+
+func (f Frequency) Duration() (time.Duration, bool) {
+	switch f.Unit {
+	case "s":
+		return time.Duration(f.N) * time.Second, true
+	case "min":
+		return time.Duration(f.N) * time.Minute, true
+	case "h":
+		return time.Duration(f.N) * time.Hour, true
+	}
+	return 0, false
+}
+
+func (f Frequency) Floor(t time.Time) time.Time {
+	if d, ok := f.Duration(); ok {
+		return t.Truncate(d)
+	}
+
+	day := truncateDay(t)
+	switch f.Unit {
+	case "B":
+		for !f.calendar().IsBusinessDay(day) {
+			day = day.AddDate(0, 0, -1)
+		}
+		return day
+	case "W":
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "M":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	case "Q":
+		month := time.Month((int(day.Month())-1)/3*3 + 1)
+		return time.Date(day.Year(), month, 1, 0, 0, 0, 0, day.Location())
+	case "Y":
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+func (f Frequency) Next(t time.Time) time.Time {
+	if d, ok := f.Duration(); ok {
+		return t.Add(d)
+	}
+
+	switch f.Unit {
+	case "B":
+		// a calendar without business days falls back to calendar days
+		// rather than never returning
+		if next, err := f.calendar().AddBusinessDays(t, f.N); err == nil {
+			return next
+		}
+	case "W":
+		return t.AddDate(0, 0, 7*f.N)
+	case "M":
+		return t.AddDate(0, f.N, 0)
+	case "Q":
+		return t.AddDate(0, 3*f.N, 0)
+	case "Y":
+		return t.AddDate(f.N, 0, 0)
+	}
+	return t.AddDate(0, 0, f.N)
+}
+
+// bucket floors t to a multiple of N units counted from the Unix epoch
+// (from a Monday for weeks), so "2D" buckets line up the same way whatever
+// the data's first date is. Business days have no such origin and floor to
+// the previous business day.
+func (f Frequency) bucket(t time.Time) time.Time {
+	if _, ok := f.Duration(); ok || f.N <= 1 || f.Unit == "B" {
+		return f.Floor(t)
+	}
+
+	day := f.Floor(t)
+	loc := day.Location()
+	switch f.Unit {
+	case "D", "W":
+		origin := time.Date(1970, time.January, 1, 0, 0, 0, 0, loc)
+		step := f.N
+		if f.Unit == "W" {
+			origin = time.Date(1970, time.January, 5, 0, 0, 0, 0, loc)
+			step *= 7
+		}
+		days := int(math.Round(day.Sub(origin).Hours() / 24))
+		return origin.AddDate(0, 0, floorDiv(days, step)*step)
+	case "M", "Q":
+		step := f.N
+		if f.Unit == "Q" {
+			step *= 3
+		}
+		months := floorDiv(day.Year()*12+int(day.Month())-1, step) * step
+		return time.Date(months/12, time.Month(months%12+1), 1, 0, 0, 0, 0, loc)
+	case "Y":
+		return time.Date(floorDiv(day.Year(), f.N)*f.N, time.January, 1, 0, 0, 0, 0, loc)
+	}
+	return day
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func (f Frequency) calendar() *Calendar {
+	if f.Calendar == nil {
+		return DefaultCalendar
+	}
+	return f.Calendar
+}
+
+func DateRange(start, end time.Time, freq string) ([]time.Time, error) {
+	f, err := ParseFrequency(freq)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]time.Time, 0)
+	t := start
+	if f.Unit == "B" && !f.calendar().IsBusinessDay(t) {
+		t = f.calendar().addBusinessDays(t, 0)
+	}
+	for !t.After(end) {
+		dates = append(dates, t)
+		t = f.Next(t)
+	}
+	return dates, nil
+}
+
+func (s *Series) AddBusinessDays(n int) (*Series, error) {
+	return s.AddBusinessDaysWith(DefaultCalendar, n)
+}
+
+func (s *Series) AddBusinessDaysWith(cal *Calendar, n int) (*Series, error) {
+	if err := cal.checkBusinessDays(); err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if t, ok := val.(time.Time); ok {
+			result[i] = cal.addBusinessDays(t, n)
+		}
+	}
+	return s.withData(s.name, result), nil
+}
+
+func (df *DataFrame) Resample(column, freq, agg string) (*DataFrame, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+
+	f, err := ParseFrequency(freq)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, 0, len(df.data))
+	for i, row := range df.data {
+		if _, ok := row[colIndex].(time.Time); ok {
+			order = append(order, i)
+		}
+	}
+
+	result := df.derive(NewDataFrame(df.columns), "resample", map[string]interface{}{"column": column, "freq": freq, "agg": agg})
+	if len(order) == 0 {
+		return result, nil
+	}
+
+	at := func(i int) time.Time { return df.data[order[i]][colIndex].(time.Time) }
+	sort.SliceStable(order, func(i, j int) bool { return at(i).Before(at(j)) })
+
+	// Buckets are [key, f.Next(key)), stepped exactly N units from the
+	// first row's aligned bucket, so every row lands in one.
+	next := 0
+	for key := f.bucket(at(0)); next < len(order); key = f.Next(key) {
+		end := f.Next(key)
+		rows := make([]int, 0)
+		for next < len(order) && at(next).Before(end) {
+			rows = append(rows, order[next])
+			next++
+		}
+		newRow := make([]interface{}, len(df.columns))
+		newRow[colIndex] = key
+
+		for j := range df.columns {
+			if j == colIndex {
+				continue
+			}
+			values := make([]interface{}, len(rows))
+			for k, r := range rows {
+				values[k] = df.data[r][j]
+			}
+			value, err := aggregateValues(agg, values)
+			if err != nil {
+				return nil, err
+			}
+			newRow[j] = value
+		}
+
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, key)
+	}
+
+	return result, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,47 @@
+package gopandas
+
+// CaseWhen builds a tiered value like SQL's CASE WHEN: the first condition
+// that holds for a row picks the value.
+type CaseWhen struct {
+	conditions []func(Row) bool
+	values     []interface{}
+}
+
+// CaseExpr is a finished CaseWhen. It can be passed straight to Assign, or
+// turned into column values for SetColumn with Values.
+type CaseExpr func(Row) interface{}
+
+// When starts a CaseWhen with its first branch.
+func When(condition func(Row) bool, value interface{}) *CaseWhen {
+	return (&CaseWhen{}).When(condition, value)
+}
+
+// When adds a branch, checked after the ones before it.
+func (c *CaseWhen) When(condition func(Row) bool, value interface{}) *CaseWhen {
+	c.conditions = append(c.conditions, condition)
+	c.values = append(c.values, value)
+	return c
+}
+
+// Else sets the value used when no branch matches and completes the builder.
+func (c *CaseWhen) Else(value interface{}) CaseExpr {
+	conditions := append([]func(Row) bool{}, c.conditions...)
+	values := append([]interface{}{}, c.values...)
+	return func(r Row) interface{} {
+		for k, condition := range conditions {
+			if condition(r) {
+				return values[k]
+			}
+		}
+		return value
+	}
+}
+
+// Values evaluates the expression for every row of df.
+func (e CaseExpr) Values(df *DataFrame) []interface{} {
+	values := make([]interface{}, len(df.data))
+	for i, row := range df.Rows() {
+		values[i] = e(row)
+	}
+	return values
+}
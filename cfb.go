@@ -0,0 +1,205 @@
+package gopandas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+const (
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFreeSector = 0xFFFFFFFF
+)
+
+type cfbEntry struct {
+	name  string
+	kind  byte
+	start uint32
+	size  uint64
+}
+
+// compoundFile is a read-only view of an OLE compound file, the container
+// used for legacy .xls workbooks and for encrypted OOXML packages.
+type compoundFile struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	cutoff         uint64
+	fat            []uint32
+	miniFAT        []uint32
+	miniStream     []byte
+	entries        []cfbEntry
+}
+
+func isCompoundFile(data []byte) bool {
+	return len(data) >= len(cfbSignature) && bytes.Equal(data[:len(cfbSignature)], cfbSignature)
+}
+
+func openCompoundFile(data []byte) (*compoundFile, error) {
+	if len(data) < 512 || !isCompoundFile(data) {
+		return nil, fmt.Errorf("invalid compound file: bad signature")
+	}
+
+	cf := &compoundFile{
+		data:           data,
+		sectorSize:     1 << binary.LittleEndian.Uint16(data[0x1E:]),
+		miniSectorSize: 1 << binary.LittleEndian.Uint16(data[0x20:]),
+		cutoff:         uint64(binary.LittleEndian.Uint32(data[0x38:])),
+	}
+	if cf.sectorSize != 512 && cf.sectorSize != 4096 {
+		return nil, fmt.Errorf("invalid compound file: sector size %d", cf.sectorSize)
+	}
+
+	// the FAT sectors are listed in the header DIFAT and any DIFAT sectors after it
+	fatSectors := make([]uint32, 0)
+	for i := 0; i < 109; i++ {
+		sector := binary.LittleEndian.Uint32(data[0x4C+4*i:])
+		if sector == cfbFreeSector || sector == cfbEndOfChain {
+			break
+		}
+		fatSectors = append(fatSectors, sector)
+	}
+	difat := binary.LittleEndian.Uint32(data[0x44:])
+	perSector := cf.sectorSize/4 - 1
+	for seen := 0; difat != cfbEndOfChain && difat != cfbFreeSector; seen++ {
+		sector, err := cf.sector(difat)
+		if err != nil || seen > len(data)/cf.sectorSize {
+			return nil, fmt.Errorf("invalid compound file: broken DIFAT chain")
+		}
+		for i := 0; i < perSector; i++ {
+			entry := binary.LittleEndian.Uint32(sector[4*i:])
+			if entry == cfbFreeSector || entry == cfbEndOfChain {
+				continue
+			}
+			fatSectors = append(fatSectors, entry)
+		}
+		difat = binary.LittleEndian.Uint32(sector[4*perSector:])
+	}
+
+	for _, s := range fatSectors {
+		sector, err := cf.sector(s)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < cf.sectorSize; i += 4 {
+			cf.fat = append(cf.fat, binary.LittleEndian.Uint32(sector[i:]))
+		}
+	}
+
+	dir, err := cf.chain(binary.LittleEndian.Uint32(data[0x30:]))
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+128 <= len(dir); i += 128 {
+		raw := dir[i : i+128]
+		nameLen := int(binary.LittleEndian.Uint16(raw[64:]))
+		if nameLen > 64 {
+			nameLen = 64
+		}
+		units := make([]uint16, 0, nameLen/2)
+		for j := 0; j+1 < nameLen; j += 2 {
+			if u := binary.LittleEndian.Uint16(raw[j:]); u != 0 {
+				units = append(units, u)
+			}
+		}
+		size := binary.LittleEndian.Uint64(raw[120:])
+		if cf.sectorSize == 512 {
+			// version 3 files only define the low 32 bits
+			size &= 0xFFFFFFFF
+		}
+		cf.entries = append(cf.entries, cfbEntry{
+			name:  string(utf16.Decode(units)),
+			kind:  raw[66],
+			start: binary.LittleEndian.Uint32(raw[116:]),
+			size:  size,
+		})
+	}
+	if len(cf.entries) == 0 || cf.entries[0].kind != 5 {
+		return nil, fmt.Errorf("invalid compound file: missing root entry")
+	}
+
+	if miniFATStart := binary.LittleEndian.Uint32(data[0x3C:]); miniFATStart != cfbEndOfChain {
+		raw, err := cf.chain(miniFATStart)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i+4 <= len(raw); i += 4 {
+			cf.miniFAT = append(cf.miniFAT, binary.LittleEndian.Uint32(raw[i:]))
+		}
+		root := cf.entries[0]
+		if cf.miniStream, err = cf.chain(root.start); err != nil {
+			return nil, err
+		}
+		if uint64(len(cf.miniStream)) > root.size {
+			cf.miniStream = cf.miniStream[:root.size]
+		}
+	}
+
+	return cf, nil
+}
+
+func (cf *compoundFile) sector(n uint32) ([]byte, error) {
+	offset := (int(n) + 1) * cf.sectorSize
+	if offset < 0 || offset+cf.sectorSize > len(cf.data) {
+		return nil, fmt.Errorf("invalid compound file: sector %d out of range", n)
+	}
+	return cf.data[offset : offset+cf.sectorSize], nil
+}
+
+func (cf *compoundFile) chain(start uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	for steps := 0; start != cfbEndOfChain; steps++ {
+		if int(start) >= len(cf.fat) || steps > len(cf.fat) {
+			return nil, fmt.Errorf("invalid compound file: broken sector chain")
+		}
+		sector, err := cf.sector(start)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sector)
+		start = cf.fat[start]
+	}
+	return buf.Bytes(), nil
+}
+
+func (cf *compoundFile) miniChain(start uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	for steps := 0; start != cfbEndOfChain; steps++ {
+		offset := int(start) * cf.miniSectorSize
+		if int(start) >= len(cf.miniFAT) || steps > len(cf.miniFAT) || offset+cf.miniSectorSize > len(cf.miniStream) {
+			return nil, fmt.Errorf("invalid compound file: broken mini sector chain")
+		}
+		buf.Write(cf.miniStream[offset : offset+cf.miniSectorSize])
+		start = cf.miniFAT[start]
+	}
+	return buf.Bytes(), nil
+}
+
+// stream returns the contents of the named stream, matched case-insensitively.
+func (cf *compoundFile) stream(name string) ([]byte, error) {
+	for _, entry := range cf.entries[1:] {
+		if entry.kind != 2 || !strings.EqualFold(entry.name, name) {
+			continue
+		}
+
+		var data []byte
+		var err error
+		if entry.size < cf.cutoff {
+			data, err = cf.miniChain(entry.start)
+		} else {
+			data, err = cf.chain(entry.start)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(data)) < entry.size {
+			return nil, fmt.Errorf("invalid compound file: stream %s is truncated", name)
+		}
+		return data[:entry.size], nil
+	}
+	return nil, fmt.Errorf("stream %s not found", name)
+}
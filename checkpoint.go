@@ -0,0 +1,96 @@
+package gopandas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Checkpoint persists the output of pipeline steps in a directory so a
+// restarted pipeline can skip steps whose inputs have not changed. Each
+// checkpoint is keyed by the step name and a hash of the input frames.
+type Checkpoint struct {
+	dir string
+}
+
+func NewCheckpoint(dir string) (*Checkpoint, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &Checkpoint{dir: dir}, nil
+}
+
+// Step returns the stored result for name and inputs when a valid checkpoint
+// exists, otherwise it runs fn and stores its result. Change the name when
+// the step's logic changes, since the key only covers name and inputs.
+func (c *Checkpoint) Step(name string, fn func() (*DataFrame, error), inputs ...*DataFrame) (*DataFrame, error) {
+	key, err := checkpointKey(name, inputs)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.dir, checkpointFileName(name)+"-"+key+".gpd")
+
+	if data, err := os.ReadFile(path); err == nil {
+		df := &DataFrame{}
+		if err := df.UnmarshalBinary(data); err == nil {
+			return df, nil
+		}
+		// a corrupt checkpoint is recomputed below
+	}
+
+	df, err := fn()
+	if err != nil {
+		return nil, fmt.Errorf("step '%s' failed: %w", name, err)
+	}
+
+	data, err := df.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode checkpoint '%s': %w", name, err)
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return nil, fmt.Errorf("failed to write checkpoint '%s': %w", name, err)
+	}
+
+	return df, nil
+}
+
+// Clear removes every stored checkpoint.
+func (c *Checkpoint) Clear() error {
+	matches, err := filepath.Glob(filepath.Join(c.dir, "*.gpd"))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkpointKey(name string, inputs []*DataFrame) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(name))
+	for _, input := range inputs {
+		data, err := input.MarshalBinary()
+		if err != nil {
+			return "", fmt.Errorf("failed to hash checkpoint input: %w", err)
+		}
+		fmt.Fprintf(h, "\x00%d\x00", len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+func checkpointFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
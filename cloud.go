@@ -0,0 +1,356 @@
+package gopandas
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type S3FileSystem struct {
+	Region       string
+	Endpoint     string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Client       *http.Client
+}
+
+type GCSFileSystem struct {
+	Token    string
+	Endpoint string
+	Client   *http.Client
+}
+
+type AzureBlobFileSystem struct {
+	Account  string
+	SASToken string
+	Endpoint string
+	Client   *http.Client
+}
+
+// uploadWriter buffers an object in memory and uploads it on Close.
+type uploadWriter struct {
+	bytes.Buffer
+	upload func(body []byte) error
+}
+
+func (w *uploadWriter) Close() error {
+	return w.upload(w.Bytes())
+}
+
+func NewS3FileSystemFromEnv() *S3FileSystem {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3FileSystem{
+		Region:       region,
+		Endpoint:     os.Getenv("AWS_ENDPOINT_URL_S3"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+func (fs *S3FileSystem) Open(path string) (io.ReadCloser, error) {
+	return fs.do(http.MethodGet, path, nil)
+}
+
+func (fs *S3FileSystem) Create(path string) (io.WriteCloser, error) {
+	if _, _, err := splitBucketPath(path); err != nil {
+		return nil, err
+	}
+	return &uploadWriter{upload: func(body []byte) error {
+		rc, err := fs.do(http.MethodPut, path, body)
+		if err != nil {
+			return err
+		}
+		return rc.Close()
+	}}, nil
+}
+
+func (fs *S3FileSystem) do(method, path string, body []byte) (io.ReadCloser, error) {
+	bucket, key, err := splitBucketPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint *url.URL
+	if fs.Endpoint != "" {
+		endpoint, err = url.Parse(strings.TrimRight(fs.Endpoint, "/") + "/" + bucket + "/" + awsURIEncode(key, false))
+	} else {
+		endpoint, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, fs.Region, awsURIEncode(key, false)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if fs.AccessKey != "" {
+		fs.sign(req, body, time.Now().UTC())
+	}
+
+	return doObjectRequest(fs.Client, req, path)
+}
+
+func (fs *S3FileSystem) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if fs.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", fs.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + fs.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+fs.SecretKey), date)
+	key = hmacSHA256(key, fs.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		fs.AccessKey, scope, signedHeaders, signature))
+}
+
+func NewGCSFileSystemFromEnv() *GCSFileSystem {
+	return &GCSFileSystem{
+		Token:    os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		Endpoint: os.Getenv("STORAGE_EMULATOR_HOST"),
+	}
+}
+
+func (fs *GCSFileSystem) endpoint() string {
+	if fs.Endpoint != "" {
+		return strings.TrimRight(fs.Endpoint, "/")
+	}
+	return "https://storage.googleapis.com"
+}
+
+func (fs *GCSFileSystem) Open(path string) (io.ReadCloser, error) {
+	bucket, key, err := splitBucketPath(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", fs.endpoint(), url.PathEscape(bucket), url.PathEscape(key)), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.authorize(req); err != nil {
+		return nil, err
+	}
+	return doObjectRequest(fs.Client, req, path)
+}
+
+func (fs *GCSFileSystem) Create(path string) (io.WriteCloser, error) {
+	bucket, key, err := splitBucketPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadWriter{upload: func(body []byte) error {
+		req, err := http.NewRequest(http.MethodPost,
+			fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s", fs.endpoint(), url.PathEscape(bucket), url.QueryEscape(key)),
+			bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if err := fs.authorize(req); err != nil {
+			return err
+		}
+		rc, err := doObjectRequest(fs.Client, req, path)
+		if err != nil {
+			return err
+		}
+		return rc.Close()
+	}}, nil
+}
+
+func (fs *GCSFileSystem) authorize(req *http.Request) error {
+	token := fs.Token
+	if token == "" && fs.Endpoint == "" {
+		// fall back to the metadata server when running on Google Cloud
+		metaReq, err := http.NewRequest(http.MethodGet,
+			"http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token", nil)
+		if err != nil {
+			return err
+		}
+		metaReq.Header.Set("Metadata-Flavor", "Google")
+		client := &http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Do(metaReq)
+		if err != nil {
+			return fmt.Errorf("no GCS credentials: set GOOGLE_OAUTH_ACCESS_TOKEN or run on Google Cloud: %w", err)
+		}
+		defer resp.Body.Close()
+
+		var payload struct {
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return fmt.Errorf("failed to decode metadata token: %w", err)
+		}
+		token = payload.AccessToken
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func NewAzureBlobFileSystemFromEnv() *AzureBlobFileSystem {
+	return &AzureBlobFileSystem{
+		Account:  os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		SASToken: strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"),
+		Endpoint: os.Getenv("AZURE_STORAGE_ENDPOINT"),
+	}
+}
+
+func (fs *AzureBlobFileSystem) blobURL(path string) (string, error) {
+	container, blob, err := splitBucketPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := strings.TrimRight(fs.Endpoint, "/")
+	if endpoint == "" {
+		if fs.Account == "" {
+			return "", fmt.Errorf("no Azure storage account: set AZURE_STORAGE_ACCOUNT")
+		}
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", fs.Account)
+	}
+
+	u := fmt.Sprintf("%s/%s/%s", endpoint, url.PathEscape(container), awsURIEncode(blob, false))
+	if fs.SASToken != "" {
+		u += "?" + fs.SASToken
+	}
+	return u, nil
+}
+
+func (fs *AzureBlobFileSystem) Open(path string) (io.ReadCloser, error) {
+	u, err := fs.blobURL(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", "2021-08-06")
+	return doObjectRequest(fs.Client, req, path)
+}
+
+func (fs *AzureBlobFileSystem) Create(path string) (io.WriteCloser, error) {
+	u, err := fs.blobURL(path)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadWriter{upload: func(body []byte) error {
+		req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("x-ms-version", "2021-08-06")
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+		rc, err := doObjectRequest(fs.Client, req, path)
+		if err != nil {
+			return err
+		}
+		return rc.Close()
+	}}, nil
+}
+
+func doObjectRequest(client *http.Client, req *http.Request, path string) (io.ReadCloser, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request for '%s' failed: %w", path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("object '%s' not found: %w", path, fs.ErrNotExist)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("request for '%s' failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp.Body, nil
+}
+
+func awsURIEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
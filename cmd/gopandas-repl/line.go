@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// lineReader reads commands, completing names on tab when stdin is a
+// terminal. The terminal is switched to character-at-a-time mode with stty,
+// which keeps the REPL free of dependencies; piped input is read line by
+// line.
+type lineReader struct {
+	in       *bufio.Reader
+	complete func(line string) []string
+	saved    string
+}
+
+func newLineReader(in *os.File, complete func(line string) []string) *lineReader {
+	r := &lineReader{in: bufio.NewReader(in), complete: complete}
+	if info, err := in.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return r
+	}
+	saved, err := stty(in, "-g")
+	if err != nil {
+		return r
+	}
+	if _, err := stty(in, "-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return r
+	}
+	r.saved = strings.TrimSpace(saved)
+	return r
+}
+
+// close restores the terminal settings newLineReader changed.
+func (r *lineReader) close() {
+	if r.saved != "" {
+		stty(os.Stdin, r.saved)
+	}
+}
+
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// readLine prints prompt and returns the next line; ok is false at the end
+// of input or on Ctrl-D at an empty prompt.
+func (r *lineReader) readLine(prompt string) (string, bool) {
+	fmt.Print(prompt)
+	if r.saved == "" {
+		line, err := r.in.ReadString('\n')
+		if err != nil && line == "" {
+			return "", false
+		}
+		return strings.TrimRight(line, "\r\n"), true
+	}
+
+	buf := make([]rune, 0)
+	for {
+		c, _, err := r.in.ReadRune()
+		if err != nil {
+			return string(buf), len(buf) > 0
+		}
+		switch c {
+		case '\r', '\n':
+			fmt.Println()
+			return string(buf), true
+		case 4: // Ctrl-D
+			if len(buf) == 0 {
+				return "", false
+			}
+		case 3: // Ctrl-C drops the line
+			fmt.Print("^C\n", prompt)
+			buf = buf[:0]
+		case 127, '\b':
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				fmt.Print("\b \b")
+			}
+		case '\t':
+			buf = r.tab(prompt, buf)
+		case 27: // skip arrow keys and other escape sequences
+			if next, _, err := r.in.ReadRune(); err == nil && next == '[' {
+				for {
+					b, _, err := r.in.ReadRune()
+					if err != nil || (b >= 0x40 && b <= 0x7e) {
+						break
+					}
+				}
+			}
+		default:
+			if c >= ' ' {
+				buf = append(buf, c)
+				fmt.Print(string(c))
+			}
+		}
+	}
+}
+
+// tab completes the word before the cursor: a single match is filled in,
+// several are extended to their common prefix or listed.
+func (r *lineReader) tab(prompt string, buf []rune) []rune {
+	line := string(buf)
+	word := line[strings.LastIndexAny(line, " \t")+1:]
+	matches := r.complete(line)
+	if len(matches) == 0 {
+		return buf
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(matches) == 1 {
+		common += " "
+	}
+	if len(common) > len(word) {
+		suffix := common[len(word):]
+		fmt.Print(suffix)
+		return append(buf, []rune(suffix)...)
+	}
+
+	fmt.Printf("\n%s\n%s%s", strings.Join(matches, "  "), prompt, line)
+	return buf
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/donghquinn/gopandas"
+)
+
+var commands = []string{
+	"agg", "aggs", "columns", "complete", "eval", "exit", "frames", "help",
+	"load", "mean", "query", "quit", "save", "select", "show", "sort",
+}
+
+type session struct {
+	frames map[string]*gopandas.DataFrame
+}
+
+func main() {
+	s := &session{frames: make(map[string]*gopandas.DataFrame)}
+
+	for _, arg := range os.Args[1:] {
+		name := strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg))
+		if err := s.load(name, arg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
+	fmt.Println("gopandas REPL - type 'help' for commands")
+	lines := newLineReader(os.Stdin, s.completeLine)
+	defer lines.close()
+	for {
+		line, ok := lines.readLine("> ")
+		if !ok {
+			fmt.Println()
+			return
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return
+		}
+		if err := s.execute(line); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+func (s *session) execute(line string) error {
+	fields := strings.Fields(line)
+	cmd, args := fields[0], fields[1:]
+
+	switch cmd {
+	case "help":
+		fmt.Println(`commands:
+  load <name> <path>                 load a CSV or Excel file
+  frames                             list loaded frames
+  show <name> [n]                    print the first n rows (default 10)
+  columns <name>                     list column names
+  select <dst> <src> <col>...        keep the given columns
+  sort <dst> <src> <col> [asc|desc]  sort by a column
+  query <dst> <src> <expr>           keep rows where expr holds, e.g. amount > 100
+  eval <dst> <src> <expr>            add columns, e.g. profit = revenue - cost
+  mean <name> <col>                  mean of a column
+  agg <dst> <src> <by> <col> <func>  aggregate col per group of by
+  aggs                               list aggregation functions
+  save <name> <path>                 write a frame to CSV
+  complete <prefix>                  list frame and column names starting with prefix
+                                     (or press tab)
+  quit                               leave the REPL`)
+	case "load":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: load <name> <path>")
+		}
+		return s.load(args[0], args[1])
+	case "frames":
+		for _, name := range s.names() {
+			rows, cols := s.frames[name].Shape()
+			fmt.Printf("%s (%d, %d)\n", name, rows, cols)
+		}
+	case "show":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: show <name> [n]")
+		}
+		df, err := s.frame(args[0])
+		if err != nil {
+			return err
+		}
+		n := 10
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 0 {
+				return fmt.Errorf("usage: show <name> [n] (n must be a non-negative integer, got '%s')", args[1])
+			}
+		}
+		fmt.Print(df.Head(n))
+	case "columns":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: columns <name>")
+		}
+		df, err := s.frame(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.Join(df.Columns(), ", "))
+	case "select":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: select <dst> <src> <col>...")
+		}
+		df, err := s.frame(args[1])
+		if err != nil {
+			return err
+		}
+		result, err := df.Select(args[2:]...)
+		if err != nil {
+			return err
+		}
+		s.frames[args[0]] = result
+	case "sort":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: sort <dst> <src> <col> [asc|desc]")
+		}
+		df, err := s.frame(args[1])
+		if err != nil {
+			return err
+		}
+		ascending := len(args) < 4 || args[3] != "desc"
+		result, err := df.Sort(args[2], ascending)
+		if err != nil {
+			return err
+		}
+		s.frames[args[0]] = result
+	case "query", "eval":
+		expr := afterFields(line, 3)
+		if len(args) < 3 || expr == "" {
+			return fmt.Errorf("usage: %s <dst> <src> <expr>", cmd)
+		}
+		df, err := s.frame(args[1])
+		if err != nil {
+			return err
+		}
+		var result *gopandas.DataFrame
+		if cmd == "query" {
+			result, err = df.Query(expr)
+		} else {
+			result, err = df.Eval(expr)
+		}
+		if err != nil {
+			return err
+		}
+		s.frames[args[0]] = result
+		rows, cols := result.Shape()
+		fmt.Printf("%s (%d, %d)\n", args[0], rows, cols)
+	case "mean":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: mean <name> <col>")
+		}
+		df, err := s.frame(args[0])
+		if err != nil {
+			return err
+		}
+		col, err := df.GetColumn(args[1])
+		if err != nil {
+			return err
+		}
+		mean, err := col.Mean()
+		if err != nil {
+			return err
+		}
+		fmt.Println(mean)
+	case "agg":
+		if len(args) != 5 {
+			return fmt.Errorf("usage: agg <dst> <src> <by> <col> <func>")
+		}
+		df, err := s.frame(args[1])
+		if err != nil {
+			return err
+		}
+		groups, err := df.GroupByColumns(args[2])
+		if err != nil {
+			return err
+		}
+		result, err := groups.Agg(args[3], args[4])
+		if err != nil {
+			return err
+		}
+		s.frames[args[0]] = result
+	case "aggs":
+		fmt.Println(strings.Join(gopandas.Aggregations(), " "))
+	case "save":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: save <name> <path>")
+		}
+		df, err := s.frame(args[0])
+		if err != nil {
+			return err
+		}
+		return df.ToCSV(args[1])
+	case "complete":
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		fmt.Println(strings.Join(s.complete(prefix), " "))
+	default:
+		return fmt.Errorf("unknown command '%s' (type 'help')", cmd)
+	}
+
+	return nil
+}
+
+func (s *session) load(name, path string) error {
+	var df *gopandas.DataFrame
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xls":
+		df, err = gopandas.ReadExcel(path)
+	default:
+		df, err = gopandas.ReadCSV(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.frames[name] = df
+	rows, cols := df.Shape()
+	fmt.Printf("loaded %s (%d, %d)\n", name, rows, cols)
+	return nil
+}
+
+func (s *session) frame(name string) (*gopandas.DataFrame, error) {
+	df, ok := s.frames[name]
+	if !ok {
+		return nil, fmt.Errorf("no frame named '%s'", name)
+	}
+	return df, nil
+}
+
+func (s *session) names() []string {
+	names := make([]string, 0, len(s.frames))
+	for name := range s.frames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeLine completes the last word of line: a command name when it is
+// the first word, otherwise a frame or column name.
+func (s *session) completeLine(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(line, " ")) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		matches := make([]string, 0)
+		for _, cmd := range commands {
+			if strings.HasPrefix(cmd, prefix) {
+				matches = append(matches, cmd)
+			}
+		}
+		return matches
+	}
+	if strings.HasSuffix(line, " ") {
+		return s.complete("")
+	}
+	return s.complete(fields[len(fields)-1])
+}
+
+func (s *session) complete(prefix string) []string {
+	seen := make(map[string]bool)
+	matches := make([]string, 0)
+	for _, name := range s.names() {
+		candidates := append([]string{name}, s.frames[name].Columns()...)
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+				seen[candidate] = true
+				matches = append(matches, candidate)
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// afterFields returns line with its first n whitespace-separated fields
+// removed, keeping the spacing of the rest (expressions may quote strings).
+func afterFields(line string, n int) string {
+	rest := strings.TrimSpace(line)
+	for i := 0; i < n; i++ {
+		end := strings.IndexAny(rest, " \t")
+		if end == -1 {
+			return ""
+		}
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	return rest
+}
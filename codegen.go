@@ -0,0 +1,191 @@
+package gopandas
+
+import (
+	"fmt"
+	gofmt "go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// goInitialisms are written in upper case in generated field names.
+var goInitialisms = map[string]bool{
+	"api": true, "id": true, "ip": true, "json": true, "html": true, "http": true,
+	"sql": true, "uid": true, "url": true, "uri": true, "uuid": true, "xml": true,
+}
+
+// goTypes maps the value types reported by valueType to Go type names.
+var goTypes = map[string]string{
+	"int":      "int",
+	"float":    "float64",
+	"string":   "string",
+	"bool":     "bool",
+	"time":     "time.Time",
+	"duration": "time.Duration",
+	"decimal":  "gopandas.Decimal",
+	"ip":       "netip.Addr",
+}
+
+type generatedField struct {
+	name   string
+	column string
+	kind   string // valueType name, or "" for interface{}
+	ptr    bool
+}
+
+// GenerateStruct returns gofmt'ed Go source for a struct named typeName
+// with one field per column, plus a Load<typeName>Rows function that
+// converts a frame with those columns into a slice of the struct. Field
+// types follow the values in each column: ints mixed with floats become
+// float64, columns with nulls become pointers, and anything else is
+// interface{}. The source is a list of declarations, starting with its
+// imports, ready to paste under a package clause.
+func GenerateStruct(df *DataFrame, typeName string) (string, error) {
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return "", fmt.Errorf("invalid type name '%s': must be an exported Go identifier", typeName)
+	}
+
+	fields := make([]generatedField, len(df.columns))
+	used := make(map[string]bool)
+	for j, col := range df.columns {
+		name := goFieldName(col)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", goFieldName(col), n)
+		}
+		used[name] = true
+		fields[j] = generatedField{name: name, column: col}
+
+		kinds := make(map[string]bool)
+		for _, row := range df.data {
+			if row[j] == nil {
+				fields[j].ptr = true
+			} else {
+				kinds[valueType(row[j])] = true
+			}
+		}
+		if kinds["int"] && kinds["float"] {
+			delete(kinds, "int")
+		}
+		if len(kinds) == 1 {
+			for kind := range kinds {
+				if _, ok := goTypes[kind]; ok {
+					fields[j].kind = kind
+				}
+			}
+		}
+		if fields[j].kind == "" {
+			fields[j].ptr = false
+		}
+	}
+
+	imports := map[string]bool{"fmt": true}
+	for _, field := range fields {
+		switch field.kind {
+		case "time", "duration":
+			imports["time"] = true
+		case "ip":
+			imports["net/netip"] = true
+		}
+	}
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString("\n\"github.com/donghquinn/gopandas\"\n)\n\n")
+
+	fmt.Fprintf(&b, "// %s holds one row of a frame.\n", typeName)
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+	for _, field := range fields {
+		fmt.Fprintf(&b, "%s %s `df:%q json:%q`\n", field.name, field.goType(), field.column, field.column)
+	}
+	b.WriteString("}\n\n")
+
+	loader := "Load" + typeName + "Rows"
+	fmt.Fprintf(&b, "// %s converts df into one %s per row.\n", loader, typeName)
+	fmt.Fprintf(&b, "func %s(df *gopandas.DataFrame) ([]%s, error) {\n", loader, typeName)
+	fmt.Fprintf(&b, "rows, _ := df.Shape()\nout := make([]%s, rows)\n", typeName)
+	for j, field := range fields {
+		fmt.Fprintf(&b, "\ncol%d, err := df.GetColumn(%q)\nif err != nil {\nreturn nil, err\n}\n", j, field.column)
+		fmt.Fprintf(&b, "for i, val := range col%d.Data() {\n", j)
+		field.writeConversion(&b)
+		b.WriteString("}\n")
+	}
+	b.WriteString("return out, nil\n}\n")
+
+	src, err := gofmt.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return string(src), nil
+}
+
+func (f generatedField) goType() string {
+	if f.kind == "" {
+		return "interface{}"
+	}
+	if f.ptr {
+		return "*" + goTypes[f.kind]
+	}
+	return goTypes[f.kind]
+}
+
+func (f generatedField) writeConversion(b *strings.Builder) {
+	if f.kind == "" {
+		fmt.Fprintf(b, "out[i].%s = val\n", f.name)
+		return
+	}
+
+	assign := fmt.Sprintf("out[i].%s = v", f.name)
+	if f.ptr {
+		assign = fmt.Sprintf("out[i].%s = &v", f.name)
+	}
+	b.WriteString("switch v := val.(type) {\n")
+	if f.ptr {
+		b.WriteString("case nil:\n")
+	}
+	fmt.Fprintf(b, "case %s:\n%s\n", goTypes[f.kind], assign)
+	if f.kind == "float" {
+		if f.ptr {
+			fmt.Fprintf(b, "case int:\nf := float64(v)\nout[i].%s = &f\n", f.name)
+		} else {
+			fmt.Fprintf(b, "case int:\nout[i].%s = float64(v)\n", f.name)
+		}
+	}
+	message := "row %d, column '" + strings.ReplaceAll(f.column, "%", "%%") + "': unexpected %T"
+	fmt.Fprintf(b, "default:\nreturn nil, fmt.Errorf(%q, i, val)\n}\n", message)
+}
+
+// goFieldName turns a column name such as "employee_id" or "First Name"
+// into an exported Go identifier (EmployeeID, FirstName).
+func goFieldName(column string) string {
+	words := strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if goInitialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		b.WriteString(strings.ToUpper(string(runes[0])) + string(runes[1:]))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if first := []rune(name)[0]; !unicode.IsLetter(first) || !unicode.IsUpper(first) {
+		name = "Col" + name
+	}
+	return name
+}
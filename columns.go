@@ -0,0 +1,127 @@
+package gopandas
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (df *DataFrame) columnIndex(name string) int {
+	for i, col := range df.columns {
+		if col == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (df *DataFrame) SetColumn(name string, values []interface{}) error {
+	if len(values) != len(df.data) {
+		return fmt.Errorf("values length %d does not match rows length %d", len(values), len(df.data))
+	}
+
+	if name == df.sortedBy {
+		df.sortedBy = ""
+	}
+	df.DropIndex(name)
+
+	// rows and the column list may be shared with the frame this one
+	// derives from and its siblings, so write to copies rather than
+	// changing those frames and their indexes
+	colIndex := df.columnIndex(name)
+	if colIndex == -1 {
+		df.columns = append(append(make([]string, 0, len(df.columns)+1), df.columns...), name)
+		for i, row := range df.data {
+			newRow := make([]interface{}, len(row)+1)
+			copy(newRow, row)
+			newRow[len(row)] = values[i]
+			df.data[i] = newRow
+		}
+	} else {
+		for i, row := range df.data {
+			newRow := append([]interface{}{}, row...)
+			newRow[colIndex] = values[i]
+			df.data[i] = newRow
+		}
+	}
+
+	df.record("set_column", map[string]interface{}{"column": name})
+	return nil
+}
+
+func (df *DataFrame) SplitColumn(column, sep string, into ...string) (*DataFrame, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	if len(into) == 0 {
+		return nil, fmt.Errorf("no target columns given for split")
+	}
+
+	columns := make([]string, 0, len(df.columns)-1+len(into))
+	columns = append(columns, df.columns[:colIndex]...)
+	columns = append(columns, into...)
+	columns = append(columns, df.columns[colIndex+1:]...)
+
+	result := NewDataFrame(columns)
+
+	for i, row := range df.data {
+		newRow := make([]interface{}, 0, len(columns))
+		newRow = append(newRow, row[:colIndex]...)
+
+		parts := make([]interface{}, len(into))
+		if row[colIndex] != nil {
+			for j, part := range strings.SplitN(fmt.Sprintf("%v", row[colIndex]), sep, len(into)) {
+				parts[j] = inferType(part)
+			}
+		}
+		newRow = append(newRow, parts...)
+		newRow = append(newRow, row[colIndex+1:]...)
+
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+
+	return df.derive(result, "split_column", map[string]interface{}{"column": column, "into": into}), nil
+}
+
+func (df *DataFrame) CombineColumns(newColumn, sep string, columns ...string) (*DataFrame, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given to combine")
+	}
+	if df.columnIndex(newColumn) != -1 {
+		return nil, fmt.Errorf("column '%s' already exists", newColumn)
+	}
+
+	colIndices := make([]int, len(columns))
+	for i, col := range columns {
+		colIndices[i] = df.columnIndex(col)
+		if colIndices[i] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+	}
+
+	result := NewDataFrame(append(append([]string{}, df.columns...), newColumn))
+
+	for i, row := range df.data {
+		parts := make([]string, 0, len(colIndices))
+		for _, colIdx := range colIndices {
+			if row[colIdx] != nil {
+				parts = append(parts, fmt.Sprintf("%v", row[colIdx]))
+			}
+		}
+
+		var combined interface{}
+		if len(parts) > 0 {
+			combined = strings.Join(parts, sep)
+		}
+
+		newRow := make([]interface{}, 0, len(row)+1)
+		newRow = append(newRow, row...)
+		newRow = append(newRow, combined)
+
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+
+	return df.derive(result, "combine_columns", map[string]interface{}{"column": newColumn, "from": columns}), nil
+}
@@ -1,45 +1,85 @@
 package gopandas
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
 )
 
 func ReadCSV(filename string, options ...CSVOption) (*DataFrame, error) {
+	return ReadCSVContext(context.Background(), filename, options...)
+}
+
+// ReadCSVContext is ReadCSV with ctx as the parent of its trace span.
+func ReadCSVContext(ctx context.Context, filename string, options ...CSVOption) (*DataFrame, error) {
+	span := startSpan(ctx, "read_csv")
+	span.set("path", filename)
+	df, err := readCSV(filename, options...)
+	span.end(df, err)
+	return df, err
+}
+
+func readCSV(filename string, options ...CSVOption) (*DataFrame, error) {
 	config := &CSVConfig{
 		HasHeader: true,
 		Delimiter: ',',
 	}
-	
+
 	for _, option := range options {
 		option(config)
 	}
-	
-	file, err := os.Open(filename)
+
+	var locale *Locale
+	if config.Locale != "" {
+		l, err := LookupLocale(config.Locale)
+		if err != nil {
+			return nil, err
+		}
+		locale = l
+	}
+
+	file, err := openPath(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
-	
-	reader := csv.NewReader(file)
-	reader.Comma = config.Delimiter
-	
-	records, err := reader.ReadAll()
+
+	input, err := config.detectDialect(file)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newDialectReader(input, config.Delimiter, config.quote())
+
+	headerRows := 0
+	if config.HeaderRows > 1 {
+		headerRows = config.HeaderRows
+	} else if config.HasHeader {
+		headerRows = 1
+	}
+	limit := newLimitTracker("read_csv")
+	records, err := readCheckedRecords(reader, config.quote(), config.Hardening, limit, headerRows)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
-	
+
 	if len(records) == 0 {
 		return nil, fmt.Errorf("CSV file is empty")
 	}
-	
+
 	var columns []string
 	var dataStart int
-	
-	if config.HasHeader {
+
+	if config.HeaderRows > 1 {
+		if len(records) < config.HeaderRows {
+			return nil, fmt.Errorf("CSV file has fewer than %d header rows", config.HeaderRows)
+		}
+		columns = combineHeaderRows(records[:config.HeaderRows], config.HeaderJoiner)
+		dataStart = config.HeaderRows
+	} else if config.HasHeader {
 		columns = records[0]
 		dataStart = 1
 	} else {
@@ -49,62 +89,259 @@ func ReadCSV(filename string, options ...CSVOption) (*DataFrame, error) {
 		}
 		dataStart = 0
 	}
-	
+
+	columns, err = resolveDuplicates(columns, config.Duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	parsers, err := config.columnParsers(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	records = append(records[:dataStart:dataStart], trimFooter(records[dataStart:], config.SkipFooter, config.AutoFooter)...)
+	if config.useColumns != nil {
+		columns, parsers = config.project(columns, parsers, records[dataStart:])
+	}
+
 	df := NewDataFrame(columns)
-	
+	builder := newRowBuilder(len(columns))
+
 	for i := dataStart; i < len(records); i++ {
-		row := make([]interface{}, len(records[i]))
-		for j, val := range records[i] {
-			row[j] = inferType(val)
+		var row []interface{}
+		if locale != nil {
+			row = make([]interface{}, len(records[i]))
+			for j, val := range records[i] {
+				row[j] = locale.ParseValue(val)
+			}
+		} else {
+			row = builder.build(records[i], len(records[i]))
+		}
+		if err := applyParsers(row, records[i], parsers, columns, i-dataStart); err != nil {
+			return nil, err
 		}
 		df.AddRow(row)
 	}
-	
+
+	df.record("read_csv", map[string]interface{}{"source": filename})
 	return df, nil
 }
 
 func (df *DataFrame) ToCSV(filename string, options ...CSVOption) error {
+	return df.ToCSVContext(context.Background(), filename, options...)
+}
+
+// ToCSVContext is ToCSV with ctx as the parent of its trace span.
+func (df *DataFrame) ToCSVContext(ctx context.Context, filename string, options ...CSVOption) error {
+	span := startSpan(ctx, "to_csv")
+	span.set("path", filename)
+	span.set("rows_in", len(df.data))
+	err := df.toCSV(filename, options...)
+	span.end(nil, err)
+	return err
+}
+
+func (df *DataFrame) toCSV(filename string, options ...CSVOption) error {
 	config := &CSVConfig{
 		HasHeader: true,
 		Delimiter: ',',
 	}
-	
+
 	for _, option := range options {
 		option(config)
 	}
-	
-	file, err := os.Create(filename)
+
+	var locale *Locale
+	if config.Locale != "" {
+		l, err := LookupLocale(config.Locale)
+		if err != nil {
+			return err
+		}
+		locale = l
+	}
+
+	file, err := createPath(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
-	
-	writer := csv.NewWriter(file)
+
+	if err := df.writeCSV(file, config, locale); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
+
+// ToTSV writes the frame tab-separated; options apply as for ToCSV.
+func (df *DataFrame) ToTSV(filename string, options ...CSVOption) error {
+	return df.ToCSV(filename, append([]CSVOption{WithDelimiter('\t')}, options...)...)
+}
+
+func (df *DataFrame) writeCSV(w io.Writer, config *CSVConfig, locale *Locale) error {
+	writer := csv.NewWriter(w)
 	writer.Comma = config.Delimiter
-	defer writer.Flush()
-	
+
 	if config.HasHeader {
 		if err := writer.Write(df.columns); err != nil {
 			return fmt.Errorf("failed to write header: %w", err)
 		}
 	}
-	
+
 	for _, row := range df.data {
 		stringRow := make([]string, len(row))
 		for i, val := range row {
-			stringRow[i] = fmt.Sprintf("%v", val)
+			if !config.FloatFormat.IsZero() {
+				sep := ""
+				if locale != nil {
+					sep = locale.DecimalSep
+				}
+				if text, ok := config.FloatFormat.formatFloatValue(val, sep); ok {
+					stringRow[i] = text
+					continue
+				}
+			}
+			if locale != nil {
+				stringRow[i] = locale.FormatValue(val)
+			} else {
+				stringRow[i] = fmt.Sprintf("%v", val)
+			}
+			if _, ok := val.(string); ok && config.EscapeFormulas {
+				stringRow[i] = escapeFormula(stringRow[i])
+			}
 		}
 		if err := writer.Write(stringRow); err != nil {
 			return fmt.Errorf("failed to write row: %w", err)
 		}
 	}
-	
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
 	return nil
 }
 
 type CSVConfig struct {
-	HasHeader bool
-	Delimiter rune
+	HasHeader      bool
+	Delimiter      rune
+	Quote          rune
+	Locale         string
+	AutoDetect     bool
+	SourceColumn   bool
+	Parsers        map[string]ColumnParser
+	Duplicates     DuplicatePolicy
+	HeaderRows     int
+	HeaderJoiner   string
+	SkipFooter     int
+	AutoFooter     bool
+	FloatFormat    FloatFormat
+	Hardening      *Hardening
+	EscapeFormulas bool
+	// useColumns, set by a LazyFrame scan, limits the frame to these
+	// columns so the others are never converted
+	useColumns []string
+}
+
+// project narrows columns, parsers and records to useColumns. Records that
+// do not match the header are emptied, so they are skipped as before.
+func (c *CSVConfig) project(columns []string, parsers []ColumnParser, records [][]string) ([]string, []ColumnParser) {
+	keep := make([]int, 0, len(c.useColumns))
+	for j, col := range columns {
+		if containsString(c.useColumns, col) {
+			keep = append(keep, j)
+		}
+	}
+
+	for i, record := range records {
+		if len(record) != len(columns) {
+			records[i] = nil
+			continue
+		}
+		narrowed := make([]string, len(keep))
+		for k, j := range keep {
+			narrowed[k] = record[j]
+		}
+		records[i] = narrowed
+	}
+
+	kept := make([]string, len(keep))
+	var keptParsers []ColumnParser
+	if parsers != nil {
+		keptParsers = make([]ColumnParser, len(keep))
+	}
+	for k, j := range keep {
+		kept[k] = columns[j]
+		if parsers != nil {
+			keptParsers[k] = parsers[j]
+		}
+	}
+	return kept, keptParsers
+}
+
+// ColumnParser converts the raw text of a cell into a value.
+type ColumnParser func(string) (interface{}, error)
+
+// WithColumnParser converts the cells of column with fn while the file is
+// read, instead of the usual type inference. Use it for formats inference
+// cannot handle, such as epoch milliseconds or "1,234원".
+func WithColumnParser(column string, fn ColumnParser) CSVOption {
+	return func(c *CSVConfig) {
+		if c.Parsers == nil {
+			c.Parsers = make(map[string]ColumnParser)
+		}
+		c.Parsers[column] = fn
+	}
+}
+
+// columnParsers lines the configured parsers up with the columns.
+func (c *CSVConfig) columnParsers(columns []string) ([]ColumnParser, error) {
+	if len(c.Parsers) == 0 {
+		return nil, nil
+	}
+	parsers := make([]ColumnParser, len(columns))
+	for name, fn := range c.Parsers {
+		found := false
+		for j, col := range columns {
+			if col == name {
+				parsers[j] = fn
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column '%s' not found for parser", name)
+		}
+	}
+	return parsers, nil
+}
+
+// applyParsers replaces the values of parsed columns in row with the
+// parsers' results.
+func applyParsers(row []interface{}, record []string, parsers []ColumnParser, columns []string, line int) error {
+	for j, fn := range parsers {
+		if fn == nil || j >= len(record) {
+			continue
+		}
+		val, err := fn(record[j])
+		if err != nil {
+			return fmt.Errorf("row %d, column '%s': %w", line, columns[j], err)
+		}
+		row[j] = val
+	}
+	return nil
+}
+
+func (c *CSVConfig) quote() rune {
+	if c.Quote == 0 {
+		return '"'
+	}
+	return c.Quote
 }
 
 type CSVOption func(*CSVConfig)
@@ -123,22 +360,24 @@ func WithDelimiter(delimiter rune) CSVOption {
 
 func inferType(value string) interface{} {
 	value = strings.TrimSpace(value)
-	
+
 	if value == "" {
 		return nil
 	}
-	
-	if intVal, err := strconv.Atoi(value); err == nil {
-		return intVal
-	}
-	
-	if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-		return floatVal
+
+	if mayBeNumber(value) {
+		if intVal, err := strconv.Atoi(value); err == nil {
+			return intVal
+		}
+
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
 	}
-	
-	if boolVal, err := strconv.ParseBool(value); err == nil {
+
+	if boolVal, ok := parseBool(value); ok {
 		return boolVal
 	}
-	
+
 	return value
-}
\ No newline at end of file
+}
@@ -0,0 +1,246 @@
+package gopandas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sniffSampleSize is how much of the input SniffCSV inspects.
+const sniffSampleSize = 64 * 1024
+
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// CSVDialect describes the layout of a CSV file as guessed by SniffCSV.
+type CSVDialect struct {
+	Delimiter rune
+	Quote     rune
+	HasHeader bool
+}
+
+// SniffCSV samples the first 64 KB of r and guesses the delimiter (comma,
+// semicolon, tab or pipe), the quote character and whether the first row is
+// a header.
+func SniffCSV(r io.Reader) (*CSVDialect, error) {
+	sample, err := io.ReadAll(io.LimitReader(r, sniffSampleSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sample: %w", err)
+	}
+	return sniffSample(sample, len(sample) == sniffSampleSize)
+}
+
+func sniffSample(sample []byte, truncated bool) (*CSVDialect, error) {
+	text := strings.ReplaceAll(string(sample), "\r\n", "\n")
+	if truncated {
+		// the last line is probably cut off
+		if i := strings.LastIndexByte(text, '\n'); i > 0 {
+			text = text[:i]
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("CSV sample is empty")
+	}
+
+	// the quote is judged on fields split by the delimiter, which is first
+	// guessed with double quotes and re-guessed if single quotes win
+	delimiter := sniffDelimiter(text, '"')
+	quote := sniffQuote(text, delimiter)
+	if quote != '"' {
+		delimiter = sniffDelimiter(text, quote)
+	}
+	dialect := &CSVDialect{
+		Delimiter: delimiter,
+		Quote:     quote,
+		HasHeader: true,
+	}
+
+	reader := newDialectReader(strings.NewReader(text), dialect.Delimiter, quote)
+	reader.FieldsPerRecord = -1
+	if truncated {
+		// the cut may fall inside a quoted field spanning lines
+		reader.LazyQuotes = true
+	}
+	records, err := readDialectRecords(reader, quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sample: %w", err)
+	}
+	dialect.HasHeader = sniffHeader(records)
+	return dialect, nil
+}
+
+// sniffQuote prefers the double quote unless single quotes wrap more of the
+// fields split on delimiter.
+func sniffQuote(text string, delimiter rune) rune {
+	double, single := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		for _, field := range strings.Split(line, string(delimiter)) {
+			field = strings.TrimSpace(field)
+			if len(field) < 2 {
+				continue
+			}
+			switch {
+			case field[0] == '"' && field[len(field)-1] == '"':
+				double++
+			case field[0] == '\'' && field[len(field)-1] == '\'':
+				single++
+			}
+		}
+	}
+	if single > double {
+		return '\''
+	}
+	return '"'
+}
+
+// sniffDelimiter picks the candidate that appears the same non-zero number of
+// times on the most lines, breaking ties by how often it appears.
+func sniffDelimiter(text string, quote rune) rune {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 50 {
+		lines = lines[:50]
+	}
+
+	best, bestScore, bestCount := ',', 0, 0
+	for _, delim := range sniffDelimiters {
+		counts := make(map[int]int)
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			counts[countOutsideQuotes(line, delim, quote)]++
+		}
+
+		mode, score := 0, 0
+		for count, n := range counts {
+			if count > 0 && (n > score || n == score && count > mode) {
+				mode, score = count, n
+			}
+		}
+		if score > bestScore || score == bestScore && mode > bestCount {
+			best, bestScore, bestCount = delim, score, mode
+		}
+	}
+	return best
+}
+
+func countOutsideQuotes(line string, delim, quote rune) int {
+	count := 0
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == quote:
+			quoted = !quoted
+		case r == delim && !quoted:
+			count++
+		}
+	}
+	return count
+}
+
+// sniffHeader votes column by column: a first-row string above numeric or
+// boolean values suggests a header, a first row of the same kind suggests
+// data. Columns of strings throughout are inconclusive, and ties keep the
+// header.
+func sniffHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return true
+	}
+
+	votes := 0
+	for col := range records[0] {
+		kind := ""
+		consistent := true
+		for _, record := range records[1:] {
+			if col >= len(record) || strings.TrimSpace(record[col]) == "" {
+				continue
+			}
+			k := fmt.Sprintf("%T", inferType(record[col]))
+			if kind == "" {
+				kind = k
+			} else if k != kind {
+				consistent = false
+				break
+			}
+		}
+		if !consistent || kind == "" || kind == "string" {
+			continue
+		}
+
+		if fmt.Sprintf("%T", inferType(records[0][col])) == kind {
+			votes--
+		} else {
+			votes++
+		}
+	}
+	return votes >= 0
+}
+
+func newDialectReader(r io.Reader, delimiter, quote rune) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	if quote != '"' {
+		// encoding/csv only understands double quotes; other quotes are
+		// stripped from the fields after reading
+		reader.LazyQuotes = true
+	}
+	return reader
+}
+
+func readDialectRecords(reader *csv.Reader, quote rune) ([][]string, error) {
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if quote != '"' {
+		for _, record := range records {
+			unquoteFields(record, quote)
+		}
+	}
+	return records, nil
+}
+
+func unquoteFields(record []string, quote rune) {
+	q := string(quote)
+	for i, field := range record {
+		if len(field) >= 2 && strings.HasPrefix(field, q) && strings.HasSuffix(field, q) {
+			record[i] = strings.ReplaceAll(field[1:len(field)-1], q+q, q)
+		}
+	}
+}
+
+// detectDialect sniffs r when auto-detection is enabled, updating config and
+// returning a reader that still yields the sampled bytes.
+func (config *CSVConfig) detectDialect(r io.Reader) (io.Reader, error) {
+	if !config.AutoDetect {
+		return r, nil
+	}
+
+	buffered := bufio.NewReaderSize(r, sniffSampleSize)
+	sample, err := buffered.Peek(sniffSampleSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read sample: %w", err)
+	}
+	if len(bytes.TrimSpace(sample)) == 0 {
+		return buffered, nil
+	}
+
+	dialect, err := sniffSample(sample, len(sample) == sniffSampleSize)
+	if err != nil {
+		return nil, err
+	}
+	config.Delimiter = dialect.Delimiter
+	config.Quote = dialect.Quote
+	config.HasHeader = dialect.HasHeader
+	return buffered, nil
+}
+
+// WithAutoDetect sniffs the delimiter, quote character and header presence
+// from the start of the file, overriding WithDelimiter and WithHeader.
+func WithAutoDetect() CSVOption {
+	return func(c *CSVConfig) {
+		c.AutoDetect = true
+	}
+}
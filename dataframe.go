@@ -6,16 +6,23 @@ import (
 )
 
 type DataFrame struct {
-	columns []string
-	data    [][]interface{}
-	index   []interface{}
+	columns    []string
+	data       [][]interface{}
+	index      []interface{}
+	name       string
+	attrs      map[string]interface{}
+	lineage    []LineageEntry
+	columnMeta map[string]map[string]interface{}
+	sortedBy   string
+	indexes    map[string]hashIndex
 }
 
 type Series struct {
-	name   string
-	data   []interface{}
-	dtype  reflect.Type
-	index  []interface{}
+	name  string
+	data  []interface{}
+	dtype reflect.Type
+	index []interface{}
+	unit  string
 }
 
 func NewDataFrame(columns []string) *DataFrame {
@@ -31,12 +38,12 @@ func NewSeries(name string, data []interface{}) *Series {
 	if len(data) > 0 {
 		dtype = reflect.TypeOf(data[0])
 	}
-	
+
 	index := make([]interface{}, len(data))
 	for i := range index {
 		index[i] = i
 	}
-	
+
 	return &Series{
 		name:  name,
 		data:  data,
@@ -57,22 +64,24 @@ func (df *DataFrame) Head(n int) *DataFrame {
 	if n > len(df.data) {
 		n = len(df.data)
 	}
-	
+
 	result := NewDataFrame(df.columns)
-	result.data = df.data[:n]
-	result.index = df.index[:n]
-	
-	return result
+	result.data = df.data[:n:n]
+	result.index = df.index[:n:n]
+
+	return df.derive(result, "head", map[string]interface{}{"n": n})
 }
 
 func (df *DataFrame) AddRow(row []interface{}) error {
 	if len(row) != len(df.columns) {
 		return fmt.Errorf("row length %d does not match columns length %d", len(row), len(df.columns))
 	}
-	
+
+	df.keepSorted(row)
 	df.data = append(df.data, row)
 	df.index = append(df.index, len(df.data)-1)
-	
+	df.indexAppended()
+
 	return nil
 }
 
@@ -84,38 +93,43 @@ func (df *DataFrame) GetColumn(name string) (*Series, error) {
 			break
 		}
 	}
-	
+
 	if colIndex == -1 {
 		return nil, fmt.Errorf("column '%s' not found", name)
 	}
-	
+
 	columnData := make([]interface{}, len(df.data))
 	for i, row := range df.data {
 		columnData[i] = row[colIndex]
 	}
-	
-	return NewSeries(name, columnData), nil
+
+	series := NewSeries(name, columnData)
+	if unit, ok := df.columnMeta[name]["unit"].(string); ok {
+		series.unit = unit
+	}
+
+	return series, nil
 }
 
 func (df *DataFrame) String() string {
 	result := ""
-	
+
 	for _, col := range df.columns {
 		result += fmt.Sprintf("%-15s", col)
 	}
 	result += "\n"
-	
+
 	for i := 0; i < len(df.columns)*15; i++ {
 		result += "-"
 	}
 	result += "\n"
-	
+
 	for _, row := range df.data {
 		for _, val := range row {
 			result += fmt.Sprintf("%-15v", val)
 		}
 		result += "\n"
 	}
-	
+
 	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,212 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Decimal struct {
+	rat   *big.Rat
+	scale int
+}
+
+func NewDecimal(value int64, scale int) Decimal {
+	rat := new(big.Rat).SetInt64(value)
+	if scale > 0 {
+		rat.Quo(rat, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	}
+	return Decimal{rat: rat, scale: scale}
+}
+
+func ParseDecimal(value string) (Decimal, error) {
+	cleaned := strings.TrimSpace(value)
+	negative := false
+
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = strings.TrimSpace(cleaned[1 : len(cleaned)-1])
+	}
+	if strings.HasPrefix(cleaned, "-") {
+		negative = !negative
+		cleaned = strings.TrimSpace(cleaned[1:])
+	}
+
+	cleaned = strings.Map(func(r rune) rune {
+		switch r {
+		case '$', '€', '£', '¥', '₩', ',', ' ', '_':
+			return -1
+		}
+		return r
+	}, cleaned)
+
+	if strings.HasPrefix(cleaned, "-") {
+		negative = !negative
+		cleaned = cleaned[1:]
+	}
+
+	if cleaned == "" || strings.ContainsAny(cleaned, "/eE") {
+		return Decimal{}, fmt.Errorf("invalid decimal '%s'", value)
+	}
+
+	rat, ok := new(big.Rat).SetString(cleaned)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal '%s'", value)
+	}
+	if negative {
+		rat.Neg(rat)
+	}
+
+	scale := 0
+	if dot := strings.IndexByte(cleaned, '.'); dot != -1 {
+		scale = len(cleaned) - dot - 1
+	}
+
+	return Decimal{rat: rat, scale: scale}, nil
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Add(d.value(), other.value()), scale: maxInt(d.scale, other.scale)}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Sub(d.value(), other.value()), scale: maxInt(d.scale, other.scale)}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.value(), other.value()), scale: d.scale + other.scale}
+}
+
+func (d Decimal) Div(other Decimal) (Decimal, error) {
+	if other.value().Sign() == 0 {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+	return Decimal{rat: new(big.Rat).Quo(d.value(), other.value()), scale: maxInt(d.scale, other.scale)}, nil
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	return d.value().Cmp(other.value())
+}
+
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+func (d Decimal) Round(scale int) Decimal {
+	rounded, _ := new(big.Rat).SetString(d.value().FloatString(scale))
+	return Decimal{rat: rounded, scale: scale}
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := d.value().Float64()
+	return f
+}
+
+func (d Decimal) String() string {
+	return d.value().FloatString(d.scale)
+}
+
+func (s *Series) AsDecimal() (*Series, error) {
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+		d, err := toDecimal(val)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		result[i] = d
+	}
+	return s.withData(s.name, result), nil
+}
+
+func (s *Series) DecimalSum() (Decimal, error) {
+	sum, count, err := s.decimalTotal()
+	if err != nil {
+		return Decimal{}, err
+	}
+	if count == 0 {
+		return Decimal{}, fmt.Errorf("no numeric values found")
+	}
+	return sum, nil
+}
+
+func (s *Series) DecimalMean() (Decimal, error) {
+	sum, count, err := s.decimalTotal()
+	if err != nil {
+		return Decimal{}, err
+	}
+	if count == 0 {
+		return Decimal{}, fmt.Errorf("no numeric values found")
+	}
+	return sum.Div(NewDecimal(int64(count), 0))
+}
+
+func (s *Series) decimalTotal() (Decimal, int, error) {
+	if len(s.data) == 0 {
+		return Decimal{}, 0, fmt.Errorf("series is empty")
+	}
+
+	sum := NewDecimal(0, 0)
+	count := 0
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+		d, err := toDecimal(val)
+		if err != nil {
+			return Decimal{}, 0, fmt.Errorf("row %d: %w", i, err)
+		}
+		sum = sum.Add(d)
+		count++
+	}
+	return sum, count, nil
+}
+
+func toDecimal(val interface{}) (Decimal, error) {
+	switch v := val.(type) {
+	case Decimal:
+		return v, nil
+	case string:
+		return ParseDecimal(v)
+	case int:
+		return NewDecimal(int64(v), 0), nil
+	case int64:
+		return NewDecimal(v, 0), nil
+	case float64:
+		return floatToDecimal(v, 64)
+	case float32:
+		return floatToDecimal(float64(v), 32)
+	}
+	return Decimal{}, fmt.Errorf("cannot convert %T to decimal", val)
+}
+
+// floatToDecimal goes through the shortest plain decimal representation,
+// which avoids binary noise; %v would give exponents like 1e-05.
+func floatToDecimal(f float64, bitSize int) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("cannot convert %v to decimal", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, bitSize))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
@@ -0,0 +1,296 @@
+package gopandas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParquetDecoder decodes the bytes of one Parquet file into a frame. This
+// package has no Parquet reader of its own; wrap the Parquet library of
+// your choice, as with KafkaConsumer.
+type ParquetDecoder func(data []byte) (*DataFrame, error)
+
+// DeltaOption configures ReadDelta.
+type DeltaOption func(*deltaConfig)
+
+type deltaConfig struct {
+	version int64
+}
+
+// WithDeltaVersion reads the table as of version instead of the latest one
+// (time travel).
+func WithDeltaVersion(version int64) DeltaOption {
+	return func(c *deltaConfig) {
+		c.version = version
+	}
+}
+
+// ReadDelta loads a Delta Lake table from its directory (local or any
+// registered file system, such as s3://bucket/table). It replays the JSON
+// commits in _delta_log to find the table's schema and live data files,
+// decodes each file with decoder and fills partition columns from the log.
+// Columns follow the table schema. Tables whose log has been truncated to a
+// checkpoint, and tables using deletion vectors or column mapping, are not
+// supported.
+func ReadDelta(table string, decoder ParquetDecoder, options ...DeltaOption) (*DataFrame, error) {
+	if decoder == nil {
+		return nil, fmt.Errorf("a Parquet decoder is required to read Delta data files")
+	}
+	config := &deltaConfig{version: -1}
+	for _, option := range options {
+		option(config)
+	}
+
+	table = strings.TrimSuffix(table, "/")
+	snapshot, err := replayDeltaLog(table, config.version)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewDataFrame(snapshot.columns())
+	for _, file := range snapshot.files {
+		data, err := readAllPath(deltaFilePath(table, file.path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data file '%s': %w", file.path, err)
+		}
+		part, err := decoder(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode data file '%s': %w", file.path, err)
+		}
+
+		positions := make([]int, len(result.columns))
+		partition := make([]interface{}, len(result.columns))
+		for j, col := range result.columns {
+			positions[j] = part.columnIndex(col)
+			if raw, ok := file.partitionValues[col]; ok {
+				if partition[j], err = snapshot.partitionValue(col, raw); err != nil {
+					return nil, fmt.Errorf("data file '%s': %w", file.path, err)
+				}
+			}
+		}
+		for _, row := range part.data {
+			newRow := make([]interface{}, len(result.columns))
+			for j, k := range positions {
+				if k >= 0 {
+					newRow[j] = row[k]
+				} else {
+					newRow[j] = partition[j]
+				}
+			}
+			result.data = append(result.data, newRow)
+			result.index = append(result.index, len(result.index))
+		}
+	}
+
+	result.record("read_delta", map[string]interface{}{"source": table, "version": snapshot.version, "files": len(snapshot.files)})
+	return result, nil
+}
+
+// deltaReaderFeatures are the table features ReadDelta can handle. Column
+// mapping and deletion vectors are only rejected once actually in use.
+var deltaReaderFeatures = map[string]bool{
+	"columnMapping":       true,
+	"deletionVectors":     true,
+	"timestampNtz":        true,
+	"v2Checkpoint":        true,
+	"vacuumProtocolCheck": true,
+}
+
+type deltaField struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+type deltaFile struct {
+	path            string
+	partitionValues map[string]*string
+}
+
+type deltaSnapshot struct {
+	version    int64
+	fields     []deltaField
+	partitions []string
+	files      []deltaFile
+}
+
+type deltaAction struct {
+	Protocol *struct {
+		ReaderFeatures []string `json:"readerFeatures"`
+	} `json:"protocol"`
+	MetaData *struct {
+		SchemaString     string            `json:"schemaString"`
+		PartitionColumns []string          `json:"partitionColumns"`
+		Configuration    map[string]string `json:"configuration"`
+	} `json:"metaData"`
+	Add *struct {
+		Path            string             `json:"path"`
+		PartitionValues map[string]*string `json:"partitionValues"`
+		DeletionVector  json.RawMessage    `json:"deletionVector"`
+	} `json:"add"`
+	Remove *struct {
+		Path string `json:"path"`
+	} `json:"remove"`
+}
+
+// replayDeltaLog applies the commits up to version (the latest when
+// negative) and returns the resulting table state.
+func replayDeltaLog(table string, version int64) (*deltaSnapshot, error) {
+	snapshot := &deltaSnapshot{version: -1}
+	live := make(map[string]int)
+	for v := int64(0); version < 0 || v <= version; v++ {
+		data, err := readAllPath(fmt.Sprintf("%s/_delta_log/%020d.json", table, v))
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Delta commit %d: %w", v, err)
+		}
+		if err := snapshot.apply(data, live); err != nil {
+			return nil, fmt.Errorf("Delta commit %d: %w", v, err)
+		}
+		snapshot.version = v
+	}
+
+	switch {
+	case snapshot.version == -1:
+		if _, err := readAllPath(table + "/_delta_log/_last_checkpoint"); err == nil {
+			return nil, fmt.Errorf("Delta log of '%s' starts at a checkpoint, which is not supported", table)
+		}
+		return nil, fmt.Errorf("'%s' is not a Delta table: no commits in _delta_log", table)
+	case version >= 0 && snapshot.version < version:
+		return nil, fmt.Errorf("Delta table '%s' has no version %d (latest is %d)", table, version, snapshot.version)
+	case snapshot.fields == nil:
+		return nil, fmt.Errorf("Delta log of '%s' has no table metadata", table)
+	}
+
+	files := make([]deltaFile, 0, len(live))
+	for i, file := range snapshot.files {
+		if j, ok := live[file.path]; ok && j == i {
+			files = append(files, file)
+		}
+	}
+	snapshot.files = files
+	return snapshot, nil
+}
+
+// apply replays the actions of one commit. live maps each added path to
+// its latest entry in files; removed paths are dropped from it.
+func (s *deltaSnapshot) apply(commit []byte, live map[string]int) error {
+	scanner := bufio.NewScanner(bytes.NewReader(commit))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var action deltaAction
+		if err := json.Unmarshal(line, &action); err != nil {
+			return fmt.Errorf("invalid action: %w", err)
+		}
+
+		switch {
+		case action.Protocol != nil:
+			for _, feature := range action.Protocol.ReaderFeatures {
+				if !deltaReaderFeatures[feature] {
+					return fmt.Errorf("reader feature '%s' is not supported", feature)
+				}
+			}
+		case action.MetaData != nil:
+			if mode := action.MetaData.Configuration["delta.columnMapping.mode"]; mode != "" && mode != "none" {
+				return fmt.Errorf("column mapping mode '%s' is not supported", mode)
+			}
+			var schema struct {
+				Fields []deltaField `json:"fields"`
+			}
+			if err := json.Unmarshal([]byte(action.MetaData.SchemaString), &schema); err != nil {
+				return fmt.Errorf("invalid table schema: %w", err)
+			}
+			s.fields = schema.Fields
+			s.partitions = action.MetaData.PartitionColumns
+		case action.Add != nil:
+			if len(action.Add.DeletionVector) > 0 && string(action.Add.DeletionVector) != "null" {
+				return fmt.Errorf("data file '%s' has a deletion vector, which is not supported", action.Add.Path)
+			}
+			path, err := url.PathUnescape(action.Add.Path)
+			if err != nil {
+				return fmt.Errorf("invalid data file path '%s': %w", action.Add.Path, err)
+			}
+			live[path] = len(s.files)
+			s.files = append(s.files, deltaFile{path: path, partitionValues: action.Add.PartitionValues})
+		case action.Remove != nil:
+			path, err := url.PathUnescape(action.Remove.Path)
+			if err != nil {
+				return fmt.Errorf("invalid data file path '%s': %w", action.Remove.Path, err)
+			}
+			delete(live, path)
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *deltaSnapshot) columns() []string {
+	columns := make([]string, len(s.fields))
+	for j, field := range s.fields {
+		columns[j] = field.Name
+	}
+	return columns
+}
+
+// partitionValue converts the text of a partition value to the column's
+// schema type; a JSON null or an empty string, which Delta writers use for
+// a null partition, is nil.
+func (s *deltaSnapshot) partitionValue(column string, raw *string) (interface{}, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+	var typeName string
+	for _, field := range s.fields {
+		if field.Name == column {
+			json.Unmarshal(field.Type, &typeName)
+		}
+	}
+
+	text := *raw
+	var val interface{}
+	var err error
+	switch {
+	case typeName == "string":
+		return text, nil
+	case typeName == "long" || typeName == "integer" || typeName == "short" || typeName == "byte":
+		val, err = strconv.Atoi(text)
+	case typeName == "double" || typeName == "float":
+		val, err = strconv.ParseFloat(text, 64)
+	case typeName == "boolean":
+		val, err = strconv.ParseBool(text)
+	case typeName == "date":
+		val, err = time.Parse("2006-01-02", text)
+	case typeName == "timestamp" || typeName == "timestamp_ntz":
+		if val, err = time.Parse("2006-01-02 15:04:05.999999999", text); err != nil {
+			val, err = time.Parse(time.RFC3339Nano, text)
+		}
+	case strings.HasPrefix(typeName, "decimal"):
+		val, err = ParseDecimal(text)
+	default:
+		return text, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s partition value '%s' for column '%s'", typeName, text, column)
+	}
+	return val, nil
+}
+
+// deltaFilePath resolves a data file path from the log against the table.
+func deltaFilePath(table, path string) string {
+	if strings.Contains(path, "://") || strings.HasPrefix(path, "/") {
+		return path
+	}
+	return table + "/" + path
+}
@@ -0,0 +1,270 @@
+package gopandas
+
+import (
+	"math"
+	"sort"
+)
+
+// DriftPSIThreshold is the population stability index above which a column
+// counts as drifted; by convention under 0.1 is stable and 0.1 to 0.2 a
+// moderate shift.
+const DriftPSIThreshold = 0.2
+
+// DriftResult compares a current dataset with a baseline.
+type DriftResult struct {
+	Schema  []SchemaChange
+	Columns []ColumnDrift
+}
+
+// SchemaChange is a column added, removed or changed in type ("added",
+// "removed" or "type"). Types are those of Conform's schema, or "mixed".
+type SchemaChange struct {
+	Column   string
+	Change   string
+	Baseline string
+	Current  string
+}
+
+// ColumnDrift compares one column present in both frames. Kind is "numeric"
+// or "categorical". KS, the largest gap between the two cumulative
+// distributions, is set for numeric columns; Shifts lists the categories
+// whose share changed most, at most five, for categorical ones. Columns
+// without values on both sides, or whose values changed between numeric and
+// non-numeric, have no distribution statistics.
+type ColumnDrift struct {
+	Column           string
+	Kind             string
+	PSI              float64
+	KS               float64
+	BaselineNullRate float64
+	CurrentNullRate  float64
+	Shifts           []CategoryShift
+	Drifted          bool
+}
+
+// CategoryShift is one category's share of non-null values in each frame.
+type CategoryShift struct {
+	Value    interface{}
+	Baseline float64
+	Current  float64
+}
+
+// DriftReport compares each column's distribution in current against
+// baseline, for monitoring production data: the population stability index
+// over baseline deciles (numbers) or categories, the Kolmogorov-Smirnov
+// statistic, category frequency shifts and null rates, plus schema changes.
+func DriftReport(baseline, current *DataFrame) *DriftResult {
+	result := &DriftResult{Schema: make([]SchemaChange, 0), Columns: make([]ColumnDrift, 0)}
+	for j, col := range baseline.columns {
+		k := current.columnIndex(col)
+		baseKind := columnKind(baseline, j)
+		if k == -1 {
+			result.Schema = append(result.Schema, SchemaChange{Column: col, Change: "removed", Baseline: baseKind})
+			continue
+		}
+		curKind := columnKind(current, k)
+		if baseKind != curKind && baseKind != "" && curKind != "" {
+			result.Schema = append(result.Schema, SchemaChange{Column: col, Change: "type", Baseline: baseKind, Current: curKind})
+		}
+		result.Columns = append(result.Columns, columnDrift(col, columnValues(baseline, j), columnValues(current, k)))
+	}
+	for k, col := range current.columns {
+		if baseline.columnIndex(col) == -1 {
+			result.Schema = append(result.Schema, SchemaChange{Column: col, Change: "added", Current: columnKind(current, k)})
+		}
+	}
+	return result
+}
+
+// Drifted returns the names of the columns whose PSI passed the threshold.
+func (r *DriftResult) Drifted() []string {
+	names := make([]string, 0)
+	for _, col := range r.Columns {
+		if col.Drifted {
+			names = append(names, col.Column)
+		}
+	}
+	return names
+}
+
+// ToDataFrame returns one row per compared column, for storing or
+// exporting the report.
+func (r *DriftResult) ToDataFrame() *DataFrame {
+	df := NewDataFrame([]string{"column", "kind", "psi", "ks", "baseline_null_rate", "current_null_rate", "drifted"})
+	for _, col := range r.Columns {
+		df.AddRow([]interface{}{col.Column, col.Kind, col.PSI, col.KS, col.BaselineNullRate, col.CurrentNullRate, col.Drifted})
+	}
+	return df
+}
+
+// columnKind names the type of column j's values, treating ints mixed with
+// floats as floats; "" when every value is null.
+func columnKind(df *DataFrame, j int) string {
+	kinds := make(map[string]bool)
+	for _, row := range df.data {
+		if row[j] != nil {
+			kinds[valueType(row[j])] = true
+		}
+	}
+	if kinds["int"] && kinds["float"] {
+		delete(kinds, "int")
+	}
+	switch len(kinds) {
+	case 0:
+		return ""
+	case 1:
+		for kind := range kinds {
+			return kind
+		}
+	}
+	return "mixed"
+}
+
+func columnValues(df *DataFrame, j int) []interface{} {
+	values := make([]interface{}, len(df.data))
+	for i, row := range df.data {
+		values[i] = row[j]
+	}
+	return values
+}
+
+func columnDrift(col string, base, cur []interface{}) ColumnDrift {
+	drift := ColumnDrift{Column: col, Kind: "categorical", Shifts: make([]CategoryShift, 0)}
+	baseValues, baseNumeric := driftValues(base)
+	curValues, curNumeric := driftValues(cur)
+	drift.BaselineNullRate = nullRate(len(baseValues), len(base))
+	drift.CurrentNullRate = nullRate(len(curValues), len(cur))
+	if baseNumeric && curNumeric {
+		drift.Kind = "numeric"
+	}
+	if len(baseValues) == 0 || len(curValues) == 0 || baseNumeric != curNumeric {
+		return drift
+	}
+
+	if drift.Kind == "numeric" {
+		baseNumbers, curNumbers := numericValues(baseValues), numericValues(curValues)
+		sort.Float64s(baseNumbers)
+		sort.Float64s(curNumbers)
+		edges := make([]float64, 0, 9)
+		for q := 1; q < 10; q++ {
+			edge := quantileSorted(baseNumbers, float64(q)/10)
+			if len(edges) == 0 || edge > edges[len(edges)-1] {
+				edges = append(edges, edge)
+			}
+		}
+		drift.PSI = psi(binShares(baseNumbers, edges), binShares(curNumbers, edges))
+		drift.KS = ksStatistic(baseNumbers, curNumbers)
+	} else {
+		baseShares, values := categoryShares(baseValues)
+		curShares, curOnly := categoryShares(curValues)
+		for key, val := range curOnly {
+			values[key] = val
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b, c := make([]float64, len(keys)), make([]float64, len(keys))
+		for i, key := range keys {
+			b[i], c[i] = baseShares[key], curShares[key]
+			drift.Shifts = append(drift.Shifts, CategoryShift{Value: values[key], Baseline: b[i], Current: c[i]})
+		}
+		drift.PSI = psi(b, c)
+		sort.SliceStable(drift.Shifts, func(x, y int) bool {
+			return math.Abs(drift.Shifts[x].Current-drift.Shifts[x].Baseline) > math.Abs(drift.Shifts[y].Current-drift.Shifts[y].Baseline)
+		})
+		if len(drift.Shifts) > 5 {
+			drift.Shifts = drift.Shifts[:5]
+		}
+	}
+	drift.Drifted = drift.PSI >= DriftPSIThreshold
+	return drift
+}
+
+// driftValues drops nulls, counting NaN as null, and reports whether every
+// remaining value is a number.
+func driftValues(values []interface{}) ([]interface{}, bool) {
+	kept := make([]interface{}, 0, len(values))
+	numeric := true
+	for _, val := range values {
+		if val == nil {
+			continue
+		}
+		if _, ok := val.(bool); ok {
+			numeric = false
+		} else if f, ok := toFloat64(val); !ok {
+			numeric = false
+		} else if math.IsNaN(f) {
+			continue
+		}
+		kept = append(kept, val)
+	}
+	return kept, numeric
+}
+
+func nullRate(nonNull, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(total-nonNull) / float64(total)
+}
+
+// binShares returns the share of sorted numbers in each bin bounded above
+// by edges, with a last bin for values past the final edge.
+func binShares(numbers []float64, edges []float64) []float64 {
+	shares := make([]float64, len(edges)+1)
+	for _, f := range numbers {
+		shares[sort.SearchFloat64s(edges, f)]++
+	}
+	for i := range shares {
+		shares[i] /= float64(len(numbers))
+	}
+	return shares
+}
+
+func categoryShares(values []interface{}) (map[string]float64, map[string]interface{}) {
+	shares := make(map[string]float64)
+	originals := make(map[string]interface{})
+	for _, val := range values {
+		key := indexKey(val)
+		shares[key]++
+		if _, ok := originals[key]; !ok {
+			originals[key] = val
+		}
+	}
+	for key := range shares {
+		shares[key] /= float64(len(values))
+	}
+	return shares, originals
+}
+
+// psi sums (c-b)*ln(c/b) over the bins, flooring empty bins at 0.01% so a
+// category missing on one side contributes a large but finite amount.
+func psi(base, cur []float64) float64 {
+	total := 0.0
+	for i := range base {
+		b, c := math.Max(base[i], 1e-4), math.Max(cur[i], 1e-4)
+		total += (c - b) * math.Log(c/b)
+	}
+	return total
+}
+
+// ksStatistic is the largest distance between the empirical distribution
+// functions of two sorted samples.
+func ksStatistic(a, b []float64) float64 {
+	i, j := 0, 0
+	maxGap := 0.0
+	for i < len(a) && j < len(b) {
+		x := math.Min(a[i], b[j])
+		for i < len(a) && a[i] <= x {
+			i++
+		}
+		for j < len(b) && b[j] <= x {
+			j++
+		}
+		gap := math.Abs(float64(i)/float64(len(a)) - float64(j)/float64(len(b)))
+		maxGap = math.Max(maxGap, gap)
+	}
+	return maxGap
+}
@@ -0,0 +1,168 @@
+package gopandas
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DuckDB runs SQL over frames in a DuckDB database, for joins and
+// aggregations too heavy to do row by row. It works through database/sql,
+// so the DuckDB driver stays out of this package's dependencies: open the
+// database with the driver of your choice, e.g. github.com/marcboeker/go-duckdb,
+//
+//	db, err := sql.Open("duckdb", "") // in-memory
+//	duck := gopandas.NewDuckDB(db)
+//	duck.Register("sales", df)
+//	top, err := duck.Query("SELECT region, sum(amount) AS total FROM sales GROUP BY region")
+//
+// Frames are copied into regular tables rather than temporary ones, which
+// would only be visible to one connection of the pool.
+type DuckDB struct {
+	db *sql.DB
+}
+
+// duckDBBatchRows bounds how many rows go into one INSERT statement.
+const duckDBBatchRows = 500
+
+// NewDuckDB wraps a DuckDB database opened with database/sql.
+func NewDuckDB(db *sql.DB) *DuckDB {
+	return &DuckDB{db: db}
+}
+
+// Register copies df into a table called name, replacing any table of that
+// name. Column types follow the values: BIGINT, DOUBLE (ints mixed with
+// floats too), BOOLEAN, TIMESTAMP, DECIMAL, and VARCHAR for the rest.
+func (d *DuckDB) Register(name string, df *DataFrame) error {
+	return d.RegisterContext(context.Background(), name, df)
+}
+
+// RegisterContext is Register with a context.
+func (d *DuckDB) RegisterContext(ctx context.Context, name string, df *DataFrame) error {
+	types := make([]string, len(df.columns))
+	definitions := make([]string, len(df.columns))
+	for j, col := range df.columns {
+		types[j] = duckDBType(df, j)
+		definitions[j] = quoteIdentifier(col) + " " + types[j]
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	create := fmt.Sprintf("CREATE OR REPLACE TABLE %s (%s)", quoteIdentifier(name), strings.Join(definitions, ", "))
+	if _, err := tx.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("failed to create table '%s': %w", name, err)
+	}
+
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(df.columns)), ", ") + ")"
+	for start := 0; start < len(df.data) && len(df.columns) > 0; start += duckDBBatchRows {
+		end := min(start+duckDBBatchRows, len(df.data))
+		groups := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(df.columns))
+		for _, row := range df.data[start:end] {
+			groups = append(groups, placeholders)
+			for j, val := range row {
+				args = append(args, duckDBValue(val, types[j]))
+			}
+		}
+		insert := fmt.Sprintf("INSERT INTO %s VALUES %s", quoteIdentifier(name), strings.Join(groups, ", "))
+		if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+			return fmt.Errorf("failed to insert rows %d-%d into '%s': %w", start, end-1, name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit table '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Unregister drops the table called name, if it exists.
+func (d *DuckDB) Unregister(name string) error {
+	if _, err := d.db.Exec("DROP TABLE IF EXISTS " + quoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to drop table '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Query runs query and returns its result as a frame, converting values as
+// FromSQLRows does.
+func (d *DuckDB) Query(query string, args ...interface{}) (*DataFrame, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext is Query with a context.
+func (d *DuckDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*DataFrame, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+	df, err := FromSQLRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	df.record("duckdb_query", map[string]interface{}{"query": query})
+	return df, nil
+}
+
+// duckDBType picks a column type for the values in column j.
+func duckDBType(df *DataFrame, j int) string {
+	kinds := make(map[string]bool)
+	scale := 0
+	for _, row := range df.data {
+		if row[j] == nil {
+			continue
+		}
+		kinds[valueType(row[j])] = true
+		if d, ok := row[j].(Decimal); ok {
+			scale = max(scale, d.scale)
+		}
+	}
+	if kinds["int"] && kinds["float"] {
+		delete(kinds, "int")
+	}
+	if len(kinds) != 1 {
+		return "VARCHAR"
+	}
+	switch {
+	case kinds["int"]:
+		return "BIGINT"
+	case kinds["float"]:
+		return "DOUBLE"
+	case kinds["bool"]:
+		return "BOOLEAN"
+	case kinds["time"]:
+		return "TIMESTAMP"
+	case kinds["decimal"]:
+		return fmt.Sprintf("DECIMAL(38, %d)", min(scale, 37))
+	}
+	return "VARCHAR"
+}
+
+// duckDBValue converts a cell to a driver argument for a column of
+// columnType, which duckDBType chose to fit every value.
+func duckDBValue(val interface{}, columnType string) interface{} {
+	if val == nil {
+		return nil
+	}
+	switch columnType {
+	case "BIGINT":
+		return int64(val.(int))
+	case "DOUBLE":
+		f, _ := toFloat64(val)
+		return f
+	case "BOOLEAN", "TIMESTAMP":
+		return val
+	}
+	return fmt.Sprint(val)
+}
+
+// quoteIdentifier quotes a table or column name for SQL.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,96 @@
+package gopandas
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DuplicatePolicy says what a reader does with repeated header names.
+type DuplicatePolicy int
+
+const (
+	// KeepDuplicates leaves repeated names as they are; GetColumn then
+	// returns the first, and ColumnAt reaches the others.
+	KeepDuplicates DuplicatePolicy = iota
+	// MangleDuplicates renames repeats to name.1, name.2, ... like pandas.
+	MangleDuplicates
+	// ErrorOnDuplicates fails the read.
+	ErrorOnDuplicates
+)
+
+// WithDuplicateColumns sets how ReadCSV handles repeated header names.
+func WithDuplicateColumns(policy DuplicatePolicy) CSVOption {
+	return func(c *CSVConfig) {
+		c.Duplicates = policy
+	}
+}
+
+// WithExcelDuplicateColumns sets how ReadExcel handles repeated header names.
+func WithExcelDuplicateColumns(policy DuplicatePolicy) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.Duplicates = policy
+	}
+}
+
+// resolveDuplicates applies policy to a header, returning the names to use.
+func resolveDuplicates(columns []string, policy DuplicatePolicy) ([]string, error) {
+	if policy == KeepDuplicates {
+		return columns, nil
+	}
+
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if seen[col] && policy == ErrorOnDuplicates {
+			return nil, fmt.Errorf("duplicate column '%s'", col)
+		}
+		seen[col] = true
+	}
+	if len(seen) == len(columns) {
+		return columns, nil
+	}
+	return mangleDuplicates(columns, "."), nil
+}
+
+// mangleDuplicates suffixes repeated names with sep and a counter.
+func mangleDuplicates(columns []string, sep string) []string {
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		seen[col] = true
+	}
+
+	// original names keep priority, so a mangled name never takes one
+	// that appears later in the header
+	result := make([]string, len(columns))
+	used := make(map[string]bool, len(columns))
+	counts := make(map[string]int)
+	for j, col := range columns {
+		if !used[col] {
+			result[j] = col
+			used[col] = true
+			continue
+		}
+		for {
+			counts[col]++
+			name := col + sep + strconv.Itoa(counts[col])
+			if !used[name] && !seen[name] {
+				result[j] = name
+				used[name] = true
+				break
+			}
+		}
+	}
+	return result
+}
+
+// ColumnAt returns the column at position i, which reaches columns that
+// share a name with an earlier one.
+func (df *DataFrame) ColumnAt(i int) (*Series, error) {
+	if i < 0 || i >= len(df.columns) {
+		return nil, fmt.Errorf("column position %d out of range (frame has %d columns)", i, len(df.columns))
+	}
+	values := make([]interface{}, len(df.data))
+	for r, row := range df.data {
+		values[r] = row[i]
+	}
+	return NewSeries(df.columns[i], values), nil
+}
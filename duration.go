@@ -0,0 +1,160 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (s *Series) AsDuration() (*Series, error) {
+	scale := 0.0
+	if s.unit != "" {
+		factor, err := unitFactor(s.unit, "ns")
+		if err != nil {
+			return nil, err
+		}
+		scale = factor
+	}
+
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case time.Duration:
+			result[i] = v
+		case string:
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid duration '%s'", i, v)
+			}
+			result[i] = d
+		default:
+			f, ok := toFloat64(val)
+			if !ok || scale == 0 {
+				return nil, fmt.Errorf("row %d: cannot convert '%v' to duration", i, val)
+			}
+			result[i] = time.Duration(math.Round(f * scale))
+		}
+	}
+
+	return s.withData(s.name, result), nil
+}
+
+func (s *Series) subTimes(other *Series) (*Series, error) {
+	if len(s.data) != len(other.data) {
+		return nil, fmt.Errorf("series length %d does not match length %d", len(s.data), len(other.data))
+	}
+
+	result := make([]interface{}, len(s.data))
+	for i := range s.data {
+		a, ok1 := s.data[i].(time.Time)
+		b, ok2 := other.data[i].(time.Time)
+		if !ok1 || !ok2 {
+			continue
+		}
+		result[i] = a.Sub(b)
+	}
+
+	return s.withData(s.name, result), nil
+}
+
+func (s *Series) durations() []time.Duration {
+	values := make([]time.Duration, 0, len(s.data))
+	for _, val := range s.data {
+		if d, ok := val.(time.Duration); ok {
+			values = append(values, d)
+		}
+	}
+	return values
+}
+
+func (s *Series) DurationSum() (time.Duration, error) {
+	values := s.durations()
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no duration values found")
+	}
+
+	var sum time.Duration
+	for _, d := range values {
+		sum += d
+	}
+	return sum, nil
+}
+
+func (s *Series) DurationMean() (time.Duration, error) {
+	sum, err := s.DurationSum()
+	if err != nil {
+		return 0, err
+	}
+	return sum / time.Duration(len(s.durations())), nil
+}
+
+func (s *Series) DurationPercentile(p float64) (time.Duration, error) {
+	if p < 0 || p > 100 {
+		return 0, fmt.Errorf("percentile %v out of range [0, 100]", p)
+	}
+
+	values := s.durations()
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no duration values found")
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+
+	return values[lower] + time.Duration(weight*float64(values[upper]-values[lower])), nil
+}
+
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	if d < time.Second {
+		return sign + d.String()
+	}
+
+	parts := make([]string, 0, 4)
+	units := []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	for _, u := range units {
+		if d >= u.size {
+			parts = append(parts, fmt.Sprintf("%d%s", d/u.size, u.suffix))
+			d %= u.size
+		}
+	}
+	if d >= time.Millisecond {
+		parts = append(parts, fmt.Sprintf("%dms", d/time.Millisecond))
+	}
+
+	return sign + strings.Join(parts, " ")
+}
+
+func (s *Series) FormatDurations() *Series {
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if d, ok := val.(time.Duration); ok {
+			result[i] = FormatDuration(d)
+		}
+	}
+	return s.withData(s.name, result)
+}
@@ -0,0 +1,204 @@
+package gopandas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+type encodedFrame struct {
+	Columns []string         `json:"columns"`
+	Index   []encodedValue   `json:"index"`
+	Data    [][]encodedValue `json:"data"`
+}
+
+type encodedValue struct {
+	Type  string          `json:"t,omitempty"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+func (df *DataFrame) MarshalBinary() ([]byte, error) {
+	frame := encodedFrame{
+		Columns: df.columns,
+		Index:   make([]encodedValue, len(df.index)),
+		Data:    make([][]encodedValue, len(df.data)),
+	}
+
+	for i, val := range df.index {
+		encoded, err := encodeValue(val)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		frame.Index[i] = encoded
+	}
+
+	for i, row := range df.data {
+		frame.Data[i] = make([]encodedValue, len(row))
+		for j, val := range row {
+			encoded, err := encodeValue(val)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column '%s': %w", i, df.columns[j], err)
+			}
+			frame.Data[i][j] = encoded
+		}
+	}
+
+	return json.Marshal(frame)
+}
+
+func (df *DataFrame) UnmarshalBinary(data []byte) error {
+	var frame encodedFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return fmt.Errorf("failed to decode frame: %w", err)
+	}
+
+	df.columns = frame.Columns
+	df.sortedBy = ""
+	df.indexes = nil
+	df.index = make([]interface{}, len(frame.Index))
+	df.data = make([][]interface{}, len(frame.Data))
+
+	for i, encoded := range frame.Index {
+		val, err := decodeValue(encoded)
+		if err != nil {
+			return fmt.Errorf("index %d: %w", i, err)
+		}
+		df.index[i] = val
+	}
+
+	for i, encodedRow := range frame.Data {
+		if len(encodedRow) != len(df.columns) {
+			return fmt.Errorf("row %d length %d does not match columns length %d", i, len(encodedRow), len(df.columns))
+		}
+		row := make([]interface{}, len(encodedRow))
+		for j, encoded := range encodedRow {
+			val, err := decodeValue(encoded)
+			if err != nil {
+				return fmt.Errorf("row %d, column '%s': %w", i, df.columns[j], err)
+			}
+			row[j] = val
+		}
+		df.data[i] = row
+	}
+
+	if len(df.index) != len(df.data) {
+		df.index = make([]interface{}, len(df.data))
+		for i := range df.index {
+			df.index[i] = i
+		}
+	}
+
+	return nil
+}
+
+func encodeValue(val interface{}) (encodedValue, error) {
+	var typeName string
+	var payload interface{}
+
+	switch v := val.(type) {
+	case nil:
+		return encodedValue{}, nil
+	case int:
+		typeName, payload = "int", v
+	case int64:
+		typeName, payload = "int64", v
+	case float64:
+		typeName, payload = encodeFloat(v)
+	case float32:
+		typeName, payload = encodeFloat(float64(v))
+	case string:
+		typeName, payload = "string", v
+	case bool:
+		typeName, payload = "bool", v
+	case time.Time:
+		typeName, payload = "time", v.Format(time.RFC3339Nano)
+	case time.Duration:
+		typeName, payload = "duration", int64(v)
+	case Decimal:
+		typeName, payload = "decimal", v.String()
+	case netip.Addr:
+		typeName, payload = "ip", v.String()
+	case Period:
+		typeName, payload = "period", v
+	default:
+		return encodedValue{}, fmt.Errorf("unsupported value type %T", val)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return encodedValue{}, err
+	}
+	return encodedValue{Type: typeName, Value: raw}, nil
+}
+
+// encodeFloat tags NaN and the infinities, which JSON numbers cannot hold,
+// and stores them as "NaN", "+Inf" and "-Inf".
+func encodeFloat(f float64) (string, interface{}) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "float64special", strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return "float64", f
+}
+
+func decodeValue(encoded encodedValue) (interface{}, error) {
+	switch encoded.Type {
+	case "":
+		return nil, nil
+	case "int":
+		var v int
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	case "int64":
+		var v int64
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	case "float64":
+		var v float64
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	case "float64special":
+		var s string
+		if err := json.Unmarshal(encoded.Value, &s); err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(s, 64)
+	case "string":
+		var v string
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	case "bool":
+		var v bool
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	case "time":
+		var s string
+		if err := json.Unmarshal(encoded.Value, &s); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case "duration":
+		var v int64
+		err := json.Unmarshal(encoded.Value, &v)
+		return time.Duration(v), err
+	case "decimal":
+		var s string
+		if err := json.Unmarshal(encoded.Value, &s); err != nil {
+			return nil, err
+		}
+		return ParseDecimal(s)
+	case "ip":
+		var s string
+		if err := json.Unmarshal(encoded.Value, &s); err != nil {
+			return nil, err
+		}
+		return netip.ParseAddr(s)
+	case "period":
+		var v Period
+		err := json.Unmarshal(encoded.Value, &v)
+		return v, err
+	}
+	return nil, fmt.Errorf("unknown value type '%s'", encoded.Type)
+}
@@ -0,0 +1,251 @@
+package gopandas
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EnrichFunc looks up one key in an external service and returns the
+// columns to add for it. Columns missing from a result are left nil.
+type EnrichFunc func(key interface{}) (map[string]interface{}, error)
+
+// EnrichOption configures Enrich.
+type EnrichOption func(*enrichConfig)
+
+type enrichConfig struct {
+	store       KVStore
+	ttl         time.Duration
+	qps         float64
+	concurrency int
+}
+
+// WithCache keeps lookup results in store for ttl (0 keeps them until the
+// store evicts them), so later runs skip keys already looked up. Entries
+// are keyed by the enriched column's name and the key value.
+func WithCache(store KVStore, ttl time.Duration) EnrichOption {
+	return func(c *enrichConfig) {
+		c.store = store
+		c.ttl = ttl
+	}
+}
+
+// WithRateLimit spaces lookups so no more than qps start per second.
+// Cached keys do not count.
+func WithRateLimit(qps float64) EnrichOption {
+	return func(c *enrichConfig) {
+		c.qps = qps
+	}
+}
+
+// WithConcurrency runs up to n lookups at once; the default is one.
+func WithConcurrency(n int) EnrichOption {
+	return func(c *enrichConfig) {
+		c.concurrency = n
+	}
+}
+
+// Enrich adds the columns returned by lookup for the value in column,
+// calling lookup once per distinct non-null value (3 and 3.0 are the same
+// key). New columns are appended in name order and columns that already
+// exist are replaced, as with Assign. The first failed lookup aborts the
+// call.
+func (df *DataFrame) Enrich(column string, lookup EnrichFunc, options ...EnrichOption) (*DataFrame, error) {
+	j := df.columnIndex(column)
+	if j == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	config := &enrichConfig{concurrency: 1}
+	for _, option := range options {
+		option(config)
+	}
+	if config.concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be positive, got %d", config.concurrency)
+	}
+	if config.qps < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative, got %v", config.qps)
+	}
+
+	keys := make([]string, 0)
+	values := make(map[string]interface{})
+	for _, row := range df.data {
+		if row[j] == nil {
+			continue
+		}
+		key := indexKey(row[j])
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+			values[key] = row[j]
+		}
+	}
+
+	results := make(map[string]map[string]interface{}, len(keys))
+	pending := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if cached, ok := config.load(column, key); ok {
+			results[key] = cached
+		} else {
+			pending = append(pending, key)
+		}
+	}
+
+	limiter := newRateLimiter(config.qps)
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < config.concurrency && w < len(pending); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue
+				}
+
+				limiter.wait()
+				result, err := lookup(values[key])
+				if err == nil {
+					err = config.save(column, key, result)
+				}
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("lookup of %v: %w", values[key], err)
+				}
+				results[key] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, key := range pending {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, result := range results {
+		for name := range result {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	resultCols := append([]string{}, df.columns...)
+	targets := make([]int, len(names))
+	for k, name := range names {
+		targets[k] = df.columnIndex(name)
+		if targets[k] == -1 {
+			targets[k] = len(resultCols)
+			resultCols = append(resultCols, name)
+		}
+	}
+
+	result := df.derive(NewDataFrame(resultCols), "enrich", map[string]interface{}{
+		"column": column, "keys": len(keys), "lookups": len(pending), "columns": names,
+	})
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(resultCols))
+		copy(newRow, row)
+		if row[j] != nil {
+			found := results[indexKey(row[j])]
+			for k, name := range names {
+				newRow[targets[k]] = found[name]
+			}
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+func (c *enrichConfig) cacheKey(column, key string) string {
+	return "enrich:" + column + ":" + key
+}
+
+// load returns a stored result; unreadable entries count as misses.
+func (c *enrichConfig) load(column, key string) (map[string]interface{}, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	data, ok, err := c.store.Get(c.cacheKey(column, key))
+	if err != nil || !ok {
+		return nil, false
+	}
+	var encoded map[string]encodedValue
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, false
+	}
+	result := make(map[string]interface{}, len(encoded))
+	for name, val := range encoded {
+		decoded, err := decodeValue(val)
+		if err != nil {
+			return nil, false
+		}
+		result[name] = decoded
+	}
+	return result, true
+}
+
+func (c *enrichConfig) save(column, key string, result map[string]interface{}) error {
+	if c.store == nil {
+		return nil
+	}
+	encoded := make(map[string]encodedValue, len(result))
+	for name, val := range result {
+		e, err := encodeValue(val)
+		if err != nil {
+			return fmt.Errorf("failed to encode '%s' for cache: %w", name, err)
+		}
+		encoded[name] = e
+	}
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+	if err := c.store.Set(c.cacheKey(column, key), data, c.ttl); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// rateLimiter hands out evenly spaced start times; a zero rate never waits.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(qps float64) *rateLimiter {
+	if qps <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / qps)}
+}
+
+func (l *rateLimiter) wait() {
+	if l.interval == 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	start := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(time.Until(start))
+}
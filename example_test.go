@@ -1,8 +1,39 @@
 package gopandas
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf16"
 )
 
 func TestReadExcel(t *testing.T) {
@@ -169,3 +200,4632 @@ func TestGroupBy(t *testing.T) {
 		t.Errorf("Expected Engineering group shape (2, 2), got (%d, %d)", rows, cols)
 	}
 }
+
+func TestSplitAndCombineColumns(t *testing.T) {
+	df := NewDataFrame([]string{"id", "full_name"})
+	df.AddRow([]interface{}{1, "Alice Smith"})
+	df.AddRow([]interface{}{2, "Bob"})
+
+	split, err := df.SplitColumn("full_name", " ", "first", "last")
+	if err != nil {
+		t.Fatalf("Failed to split column: %v", err)
+	}
+
+	rows, cols := split.Shape()
+	if rows != 2 || cols != 3 {
+		t.Errorf("Expected split shape (2, 3), got (%d, %d)", rows, cols)
+	}
+	if split.data[0][2] != "Smith" || split.data[1][2] != nil {
+		t.Errorf("Unexpected last names: %v, %v", split.data[0][2], split.data[1][2])
+	}
+
+	combined, err := split.CombineColumns("name", " ", "first", "last")
+	if err != nil {
+		t.Fatalf("Failed to combine columns: %v", err)
+	}
+	if combined.data[0][3] != "Alice Smith" || combined.data[1][3] != "Bob" {
+		t.Errorf("Unexpected combined names: %v, %v", combined.data[0][3], combined.data[1][3])
+	}
+}
+
+func TestParseJSONColumn(t *testing.T) {
+	df := NewDataFrame([]string{"event", "payload"})
+	df.AddRow([]interface{}{"click", `{"user": {"id": 7}, "price": 9.5}`})
+	df.AddRow([]interface{}{"view", nil})
+
+	parsed, err := df.ParseJSONColumn("payload", "user.id", "price")
+	if err != nil {
+		t.Fatalf("Failed to parse JSON column: %v", err)
+	}
+
+	rows, cols := parsed.Shape()
+	if rows != 2 || cols != 4 {
+		t.Errorf("Expected shape (2, 4), got (%d, %d)", rows, cols)
+	}
+	if parsed.data[0][2] != 7 || parsed.data[0][3] != 9.5 {
+		t.Errorf("Unexpected parsed values: %v", parsed.data[0])
+	}
+	if parsed.data[1][2] != nil {
+		t.Errorf("Expected nil for missing payload, got %v", parsed.data[1][2])
+	}
+}
+
+func TestStringAccessorParsing(t *testing.T) {
+	urls := NewSeries("url", []interface{}{"https://example.com:8080/a/b?q=go&page=2", nil})
+	parts := urls.Str().ParseURL()
+
+	if parts.Host.Data()[0] != "example.com" || parts.Path.Data()[0] != "/a/b" || parts.Port.Data()[0] != "8080" {
+		t.Errorf("Unexpected URL components: %v %v %v", parts.Host.Data()[0], parts.Path.Data()[0], parts.Port.Data()[0])
+	}
+	if parts.Host.Data()[1] != nil {
+		t.Errorf("Expected nil host for nil URL, got %v", parts.Host.Data()[1])
+	}
+	if page := urls.Str().QueryParam("page").Data()[0]; page != "2" {
+		t.Errorf("Expected page 2, got %v", page)
+	}
+
+	agents := NewSeries("ua", []interface{}{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+	})
+	ua := agents.Str().ParseUserAgent()
+	if ua.Browser.Data()[0] != "Safari" || ua.OS.Data()[0] != "iOS" || ua.Device.Data()[0] != "mobile" {
+		t.Errorf("Unexpected user agent parse: %v %v %v", ua.Browser.Data()[0], ua.OS.Data()[0], ua.Device.Data()[0])
+	}
+}
+
+func TestIPOperations(t *testing.T) {
+	df := NewDataFrame([]string{"ip", "bytes"})
+	df.AddRow([]interface{}{"192.168.1.20", 100})
+	df.AddRow([]interface{}{"8.8.8.8", 200})
+	df.AddRow([]interface{}{"10.0.0.5", 300})
+
+	ips, _ := df.GetColumn("ip")
+	private := ips.IP().IsPrivate()
+	if private.Data()[0] != true || private.Data()[1] != false {
+		t.Errorf("Unexpected IsPrivate result: %v", private.Data())
+	}
+
+	inSubnet, err := df.FilterCIDR("ip", "192.168.0.0/16")
+	if err != nil {
+		t.Fatalf("Failed to filter by CIDR: %v", err)
+	}
+	if rows, _ := inSubnet.Shape(); rows != 1 {
+		t.Errorf("Expected 1 row in subnet, got %d", rows)
+	}
+
+	typed, err := ips.AsIP()
+	if err != nil {
+		t.Fatalf("Failed to convert to IP: %v", err)
+	}
+	if err := df.SetColumn("ip", typed.Data()); err != nil {
+		t.Fatalf("Failed to set column: %v", err)
+	}
+	sorted, _ := df.Sort("ip", true)
+	if sorted.data[0][1] != 200 || sorted.data[2][1] != 100 {
+		t.Errorf("Expected numeric IP ordering, got %v", sorted.data)
+	}
+}
+
+func TestGeoHelpers(t *testing.T) {
+	df := NewDataFrame([]string{"city", "lat", "lon"})
+	df.AddRow([]interface{}{"Seoul", 37.5665, 126.9780})
+	df.AddRow([]interface{}{"Busan", 35.1796, 129.0756})
+	df.AddRow([]interface{}{"Tokyo", 35.6762, 139.6503})
+
+	lat, _ := df.GetColumn("lat")
+	lon, _ := df.GetColumn("lon")
+	seoulLat := NewSeries("lat", []interface{}{37.5665, 37.5665, 37.5665})
+	seoulLon := NewSeries("lon", []interface{}{126.9780, 126.9780, 126.9780})
+
+	dist, err := Haversine(seoulLat, seoulLon, lat, lon)
+	if err != nil {
+		t.Fatalf("Failed to compute distances: %v", err)
+	}
+	busan := dist.Data()[1].(float64)
+	if busan < 320 || busan > 330 {
+		t.Errorf("Expected Seoul-Busan around 325km, got %.1f", busan)
+	}
+	if d := HaversineKm(70.73081465945947, -43.92278394488389, -70.73081465977823, 136.0772160551161); math.IsNaN(d) || math.Abs(d-math.Pi*earthRadiusKm) > 1e-3 {
+		t.Errorf("Expected nearly antipodal points half the circumference apart, got %v", d)
+	}
+
+	korea, err := df.FilterBBox("lat", "lon", BoundingBox{MinLat: 33, MinLon: 124, MaxLat: 39, MaxLon: 132})
+	if err != nil {
+		t.Fatalf("Failed to filter by bbox: %v", err)
+	}
+	if rows, _ := korea.Shape(); rows != 2 {
+		t.Errorf("Expected 2 rows in bbox, got %d", rows)
+	}
+}
+
+func TestDecimalColumn(t *testing.T) {
+	prices := NewSeries("price", []interface{}{"$1,234.56", "0.10", "0.20", nil, "(4.86)"})
+
+	decimals, err := prices.AsDecimal()
+	if err != nil {
+		t.Fatalf("Failed to convert to decimal: %v", err)
+	}
+
+	sum, err := decimals.DecimalSum()
+	if err != nil {
+		t.Fatalf("Failed to sum decimals: %v", err)
+	}
+	if sum.String() != "1230.00" {
+		t.Errorf("Expected sum 1230.00, got %s", sum)
+	}
+
+	mean, err := decimals.DecimalMean()
+	if err != nil {
+		t.Fatalf("Failed to average decimals: %v", err)
+	}
+	if mean.String() != "307.50" {
+		t.Errorf("Expected mean 307.50, got %s", mean)
+	}
+
+	floats, err := NewSeries("rate", []interface{}{0.00001, 1e21, float32(0.1)}).AsDecimal()
+	if err != nil {
+		t.Fatalf("Failed to convert floats to decimal: %v", err)
+	}
+	if fmt.Sprint(floats.Data()) != "[0.00001 1000000000000000000000 0.1]" {
+		t.Errorf("Unexpected float decimals: %v", floats.Data())
+	}
+	if _, err := NewSeries("rate", []interface{}{math.NaN()}).AsDecimal(); err == nil {
+		t.Error("Expected NaN to be rejected")
+	}
+	if _, err := NewSeries("rate", []interface{}{math.Inf(1)}).AsDecimal(); err == nil {
+		t.Error("Expected +Inf to be rejected")
+	}
+}
+
+func TestUnitAwareSeries(t *testing.T) {
+	latency := NewSeries("latency", []interface{}{1500, 250, nil}).SetUnit("ms")
+
+	seconds, err := latency.ConvertUnit("s")
+	if err != nil {
+		t.Fatalf("Failed to convert unit: %v", err)
+	}
+	if seconds.Data()[0] != 1.5 || seconds.Unit() != "s" {
+		t.Errorf("Expected 1.5 s, got %v %s", seconds.Data()[0], seconds.Unit())
+	}
+
+	total, err := latency.Add(seconds)
+	if err != nil {
+		t.Fatalf("Failed to add compatible units: %v", err)
+	}
+	if total.Data()[0] != 3000.0 {
+		t.Errorf("Expected 3000 ms, got %v", total.Data()[0])
+	}
+
+	weight := NewSeries("weight", []interface{}{1, 2, 3}).SetUnit("kg")
+	if _, err := latency.Add(weight); err == nil {
+		t.Error("Expected error when adding ms to kg")
+	}
+
+	// registering while other goroutines convert must be safe (go test -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			RegisterUnit(fmt.Sprintf("fortnight%d", i), "time", 1209600)
+			latency.ConvertUnit("s")
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestLocaleCSV(t *testing.T) {
+	file, err := os.CreateTemp("", "locale*.csv")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("name;amount;date\nAlice;1.234,56;31.12.2024\n")
+	file.Close()
+
+	df, err := ReadCSV(file.Name(), WithDelimiter(';'), WithLocale("de-DE"))
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if df.data[0][1] != 1234.56 {
+		t.Errorf("Expected 1234.56, got %v", df.data[0][1])
+	}
+	if date, ok := df.data[0][2].(time.Time); !ok || date.Month() != time.December || date.Day() != 31 {
+		t.Errorf("Expected 2024-12-31, got %v", df.data[0][2])
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			RegisterLocale(&Locale{Name: fmt.Sprintf("test-%d", i), DecimalSep: ".", ThousandsSep: ","})
+			LookupLocale("de-DE")
+		}(i)
+	}
+	wg.Wait()
+
+	de, _ := LookupLocale("de-DE")
+	if got := de.ParseValue("1.50"); got != "1.50" {
+		t.Errorf("Expected de-DE to keep 1.50 as text, got %v (%T)", got, got)
+	}
+	if got := de.ParseValue("true"); got != true {
+		t.Errorf("Expected booleans to still parse under a locale, got %v", got)
+	}
+
+	if err := df.ToCSV(file.Name(), WithDelimiter(';'), WithLocale("de-DE")); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	written, _ := os.ReadFile(file.Name())
+	if string(written) != "name;amount;date\nAlice;1234,56;31.12.2024\n" {
+		t.Errorf("Unexpected locale output: %q", written)
+	}
+}
+
+func TestDurationSeries(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := NewSeries("end", []interface{}{base.Add(2 * time.Second), base.Add(4 * time.Second), nil})
+	start := NewSeries("start", []interface{}{base, base, base})
+
+	latency, err := end.Sub(start)
+	if err != nil {
+		t.Fatalf("Failed to subtract times: %v", err)
+	}
+	if latency.Data()[0] != 2*time.Second || latency.Data()[2] != nil {
+		t.Errorf("Unexpected durations: %v", latency.Data())
+	}
+
+	mean, _ := latency.DurationMean()
+	if mean != 3*time.Second {
+		t.Errorf("Expected mean 3s, got %v", mean)
+	}
+	p50, _ := latency.DurationPercentile(50)
+	if p50 != 3*time.Second {
+		t.Errorf("Expected p50 3s, got %v", p50)
+	}
+
+	if got := FormatDuration(26*time.Hour + 90*time.Second); got != "1d 2h 1m 30s" {
+		t.Errorf("Unexpected formatted duration: %s", got)
+	}
+
+	millis, err := NewSeries("latency", []interface{}{250}).SetUnit("ms").AsDuration()
+	if err != nil || millis.Data()[0] != 250*time.Millisecond {
+		t.Errorf("Expected 250ms, got %v (%v)", millis.Data(), err)
+	}
+	for unit, want := range map[string]time.Duration{"s": time.Second, "us": time.Microsecond} {
+		got, err := NewSeries("latency", []interface{}{1}).SetUnit(unit).AsDuration()
+		if err != nil || got.Data()[0] != want {
+			t.Errorf("Expected 1%s to be %v, got %v (%v)", unit, want, got.Data(), err)
+		}
+	}
+}
+
+func TestBusinessDayCalendar(t *testing.T) {
+	friday := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	cal := NewCalendar(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC))
+
+	if next, err := cal.AddBusinessDays(friday, 3); err != nil || next.Day() != 26 {
+		t.Errorf("Expected Dec 26 skipping weekend and Christmas, got %v (%v)", next, err)
+	}
+
+	closed := NewCalendar()
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		closed.Weekend[day] = true
+	}
+	if _, err := closed.AddBusinessDays(friday, 1); err == nil {
+		t.Error("Expected an error from a calendar with no business days")
+	}
+	if _, err := NewSeries("due", []interface{}{friday}).AddBusinessDaysWith(closed, 1); err == nil {
+		t.Error("Expected an error shifting a series on a calendar with no business days")
+	}
+	if next := (Frequency{N: 1, Unit: "B", Calendar: closed}).Next(friday); !next.Equal(friday.AddDate(0, 0, 1)) {
+		t.Errorf("Expected Next to fall back to calendar days, got %v", next)
+	}
+
+	dates, err := DateRange(friday, friday.AddDate(0, 0, 7), "B")
+	if err != nil {
+		t.Fatalf("Failed to build date range: %v", err)
+	}
+	if len(dates) != 6 {
+		t.Errorf("Expected 6 business days, got %d", len(dates))
+	}
+
+	df := NewDataFrame([]string{"date", "amount"})
+	df.AddRow([]interface{}{friday, 10})
+	df.AddRow([]interface{}{friday.AddDate(0, 0, 1), 5})
+	df.AddRow([]interface{}{friday.AddDate(0, 0, 3), 7})
+
+	daily, err := df.Resample("date", "B", "sum")
+	if err != nil {
+		t.Fatalf("Failed to resample: %v", err)
+	}
+	if rows, _ := daily.Shape(); rows != 2 {
+		t.Errorf("Expected 2 business-day buckets, got %d", rows)
+	}
+	if daily.data[0][1] != 15.0 || daily.data[1][1] != 7.0 {
+		t.Errorf("Expected Saturday folded into Friday, got %v", daily.data)
+	}
+
+	// 2024-01-01 is an odd number of days after the epoch, so "2D" buckets
+	// start on Dec 31 and Jan 2, and the Jan 3 row must not be dropped.
+	pairs := NewDataFrame([]string{"date", "amount"})
+	for day := 1; day <= 4; day++ {
+		pairs.AddRow([]interface{}{time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 1})
+	}
+	twoDay, err := pairs.Resample("date", "2D", "sum")
+	if err != nil {
+		t.Fatalf("Failed to resample every 2 days: %v", err)
+	}
+	total := 0.0
+	for _, row := range twoDay.data {
+		total += row[1].(float64)
+	}
+	if total != 4 || len(twoDay.data) != 3 {
+		t.Errorf("Expected 4 rows in 3 two-day buckets, got %v", twoDay.data)
+	}
+	if !twoDay.data[0][0].(time.Time).Equal(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected buckets aligned to the epoch, got %v", twoDay.data[0][0])
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	p, err := ParsePeriod("2024Q3")
+	if err != nil {
+		t.Fatalf("Failed to parse period: %v", err)
+	}
+	if !p.Start().Equal(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)) || p.Next().String() != "2024Q4" {
+		t.Errorf("Unexpected period bounds: %v %v", p.Start(), p.Next())
+	}
+
+	df := NewDataFrame([]string{"date", "revenue"})
+	df.AddRow([]interface{}{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 100})
+	df.AddRow([]interface{}{time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), 200})
+	df.AddRow([]interface{}{time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), 300})
+
+	groups, err := df.GroupByPeriod("date", "Q", time.April)
+	if err != nil {
+		t.Fatalf("Failed to group by fiscal period: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("Expected 2 fiscal quarters, got %d", len(groups))
+	}
+	q1 := Period{Year: 2025, Freq: "Q", Num: 1, FiscalStart: time.April}
+	if rows, _ := groups[q1].Shape(); rows != 2 {
+		t.Errorf("Expected 2 rows in %s, got %d", q1, rows)
+	}
+}
+
+func TestAsFreqAndGaps(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	df := NewDataFrame([]string{"ts", "value"})
+	df.AddRow([]interface{}{base, 1.0})
+	df.AddRow([]interface{}{base.Add(5 * time.Minute), 2.0})
+	df.AddRow([]interface{}{base.Add(20 * time.Minute), 5.0})
+
+	regular, err := df.AsFreq("ts", "5m", FillForward)
+	if err != nil {
+		t.Fatalf("Failed to reindex: %v", err)
+	}
+	if rows, _ := regular.Shape(); rows != 5 {
+		t.Errorf("Expected 5 rows, got %d", rows)
+	}
+	if regular.data[2][1] != 2.0 || regular.data[4][1] != 5.0 {
+		t.Errorf("Unexpected forward fill: %v", regular.data)
+	}
+
+	gaps, err := df.ReportGaps("ts", "5m")
+	if err != nil {
+		t.Fatalf("Failed to report gaps: %v", err)
+	}
+	if rows, _ := gaps.Shape(); rows != 1 || gaps.data[0][2] != 2 {
+		t.Errorf("Expected a single gap of 2 intervals, got %v", gaps.data)
+	}
+}
+
+func TestSeasonalDiagnostics(t *testing.T) {
+	pattern := []float64{10, 20, 30, 20}
+	data := make([]interface{}, 0, 16)
+	for i := 0; i < 16; i++ {
+		data = append(data, pattern[i%4]+float64(i))
+	}
+	series := NewSeries("sales", data)
+
+	acf, err := series.ACF(4)
+	if err != nil {
+		t.Fatalf("Failed to compute ACF: %v", err)
+	}
+	if acf[0] != 1 || acf[4] <= acf[2] {
+		t.Errorf("Expected seasonal peak at lag 4, got %v", acf)
+	}
+
+	pacf, err := series.PACF(2)
+	if err != nil || len(pacf) != 3 || pacf[1] != acf[1] {
+		t.Errorf("Unexpected PACF %v (%v)", pacf, err)
+	}
+
+	dec, err := series.Decompose(4)
+	if err != nil {
+		t.Fatalf("Failed to decompose: %v", err)
+	}
+	if trend := dec.Trend.Data()[4].(float64); trend != 24 {
+		t.Errorf("Expected trend 24 at t=4, got %v", trend)
+	}
+	if seasonal := dec.Seasonal.Data()[2].(float64); seasonal != 10 {
+		t.Errorf("Expected seasonal component 10, got %v", seasonal)
+	}
+}
+
+func TestForecasting(t *testing.T) {
+	flat := NewSeries("load", []interface{}{10, 10, 10, 10})
+	_, forecast, err := flat.ExponentialSmoothing(0.5, 3)
+	if err != nil {
+		t.Fatalf("Failed to smooth: %v", err)
+	}
+	if forecast.Len() != 3 || forecast.Data()[2] != 10.0 {
+		t.Errorf("Unexpected flat forecast: %v", forecast.Data())
+	}
+
+	pattern := []float64{10, 20, 30, 20}
+	data := make([]interface{}, 0, 16)
+	for i := 0; i < 16; i++ {
+		data = append(data, pattern[i%4]+float64(i))
+	}
+	fitted, forecast, err := NewSeries("sales", data).HoltWinters(0.5, 0.3, 0.3, 4, 4)
+	if err != nil {
+		t.Fatalf("Failed to fit Holt-Winters: %v", err)
+	}
+	if fitted.Len() != 16 || forecast.Len() != 4 {
+		t.Errorf("Unexpected output lengths %d and %d", fitted.Len(), forecast.Len())
+	}
+	if next := forecast.Data()[2].(float64); next < 42 || next > 50 {
+		t.Errorf("Expected seasonal peak near 48, got %v", next)
+	}
+	if _, _, err := flat.ExponentialSmoothing(0.5, -1); err == nil {
+		t.Error("Expected a negative horizon to be rejected")
+	}
+	if _, _, err := NewSeries("sales", data).HoltWinters(0.5, 0.3, 0.3, 4, -1); err == nil {
+		t.Error("Expected a negative horizon to be rejected by Holt-Winters")
+	}
+}
+
+func TestStreamingFrame(t *testing.T) {
+	sf, err := NewStreamingFrame([]string{"region", "latency"}, []string{"region"},
+		StreamAgg{Column: "latency", Func: "count"},
+		StreamAgg{Column: "latency", Func: "mean"},
+		StreamAgg{Column: "latency", Func: "quantile", Quantile: 0.5},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create streaming frame: %v", err)
+	}
+
+	rows := make(chan []interface{})
+	go func() {
+		for i := 1; i <= 5; i++ {
+			rows <- []interface{}{"eu", i * 10}
+		}
+		rows <- []interface{}{"us", 100}
+		close(rows)
+	}()
+	if err := sf.Consume(context.Background(), rows); err != nil {
+		t.Fatalf("Failed to consume rows: %v", err)
+	}
+
+	if err := sf.ConsumeCSV(strings.NewReader("region,latency\nus,200\n")); err != nil {
+		t.Fatalf("Failed to consume CSV: %v", err)
+	}
+
+	snapshot := sf.Snapshot()
+	if rows, cols := snapshot.Shape(); rows != 2 || cols != 4 {
+		t.Fatalf("Expected snapshot shape (2, 4), got (%d, %d)", rows, cols)
+	}
+	if snapshot.data[0][1] != 5 || snapshot.data[0][2] != 30.0 || snapshot.data[0][3] != 30.0 {
+		t.Errorf("Unexpected eu aggregates: %v", snapshot.data[0])
+	}
+	if snapshot.data[1][2] != 150.0 {
+		t.Errorf("Unexpected us mean: %v", snapshot.data[1])
+	}
+}
+
+func TestApproximateSketches(t *testing.T) {
+	data := make([]interface{}, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		data = append(data, i%5000)
+	}
+	series := NewSeries("ids", data)
+
+	distinct, err := series.ApproxNUnique(0.01)
+	if err != nil {
+		t.Fatalf("Failed to estimate distinct count: %v", err)
+	}
+	if distinct < 4800 || distinct > 5200 {
+		t.Errorf("Expected about 5000 distinct values, got %d", distinct)
+	}
+
+	median, err := series.ApproxQuantile(0.5, 100)
+	if err != nil {
+		t.Fatalf("Failed to estimate quantile: %v", err)
+	}
+	if median < 2400 || median > 2600 {
+		t.Errorf("Expected median near 2500, got %v", median)
+	}
+}
+
+type memoryKafka struct {
+	messages []KafkaMessage
+	offset   int
+}
+
+func (m *memoryKafka) Fetch(ctx context.Context) (KafkaMessage, error) {
+	if m.offset >= len(m.messages) {
+		<-ctx.Done()
+		return KafkaMessage{}, ctx.Err()
+	}
+	m.offset++
+	return m.messages[m.offset-1], nil
+}
+
+func (m *memoryKafka) Produce(ctx context.Context, messages []KafkaMessage) error {
+	m.messages = append(m.messages, messages...)
+	return nil
+}
+
+func TestKafkaConnectors(t *testing.T) {
+	df := NewDataFrame([]string{"user", "clicks"})
+	df.AddRow([]interface{}{"alice", 3})
+	df.AddRow([]interface{}{"bob", 5})
+
+	broker := &memoryKafka{}
+	if err := df.ToKafka(context.Background(), broker, "clicks", JSONMessageEncoder("user")); err != nil {
+		t.Fatalf("Failed to produce: %v", err)
+	}
+	if string(broker.messages[1].Key) != "bob" {
+		t.Errorf("Expected key bob, got %s", broker.messages[1].Key)
+	}
+
+	read, err := ReadKafka(context.Background(), broker, JSONMessageDecoder, KafkaReadOptions{Window: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to consume: %v", err)
+	}
+	if rows, cols := read.Shape(); rows != 2 || cols != 2 {
+		t.Errorf("Expected shape (2, 2), got (%d, %d)", rows, cols)
+	}
+	if read.data[1][0] != 5 {
+		t.Errorf("Expected clicks 5, got %v", read.data[1])
+	}
+}
+
+func TestCloudFileSystemFromEnv(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/bucket/o/data.csv" || r.Header.Get("Authorization") != "Bearer late-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("id\n1\n"))
+	}))
+	defer server.Close()
+
+	// the default gs file system reads the environment on first use, not
+	// when the package is initialized
+	t.Setenv("STORAGE_EMULATOR_HOST", server.URL)
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "late-token")
+	df, err := ReadCSV("gs://bucket/data.csv")
+	if err != nil {
+		t.Fatalf("Failed to read with credentials set after start-up: %v", err)
+	}
+	if rows, _ := df.Shape(); rows != 1 {
+		t.Errorf("Expected 1 row, got %d", rows)
+	}
+}
+
+func TestS3FileSystem(t *testing.T) {
+	objects := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	RegisterFileSystem("s3", &S3FileSystem{Region: "us-east-1", Endpoint: server.URL, AccessKey: "AKID", SecretKey: "secret"})
+	defer RegisterFileSystem("s3", NewS3FileSystemFromEnv())
+
+	df := NewDataFrame([]string{"name", "age"})
+	df.AddRow([]interface{}{"Alice", 25})
+	if err := df.ToCSV("s3://bucket/people/data.csv"); err != nil {
+		t.Fatalf("Failed to write to S3: %v", err)
+	}
+	if _, ok := objects["/bucket/people/data.csv"]; !ok {
+		t.Fatalf("Expected object to be uploaded, got %v", objects)
+	}
+
+	read, err := ReadCSV("s3://bucket/people/data.csv")
+	if err != nil {
+		t.Fatalf("Failed to read from S3: %v", err)
+	}
+	if rows, cols := read.Shape(); rows != 1 || cols != 2 {
+		t.Errorf("Expected shape (1, 2), got (%d, %d)", rows, cols)
+	}
+}
+
+type sliceRowSource struct {
+	rows [][]interface{}
+}
+
+func (s *sliceRowSource) Columns() []string {
+	return []string{"id", "amount", "created_at"}
+}
+
+func (s *sliceRowSource) Next() ([]interface{}, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+func TestSQLIngestion(t *testing.T) {
+	amount, err := convertSQLValue([]byte("1234.50"), "NUMERIC")
+	if err != nil {
+		t.Fatalf("Failed to convert NUMERIC: %v", err)
+	}
+	if d, ok := amount.(Decimal); !ok || d.String() != "1234.50" {
+		t.Errorf("Expected Decimal 1234.50, got %v", amount)
+	}
+
+	ts, err := convertSQLValue("2024-05-01 10:30:00", "TIMESTAMP")
+	if err != nil {
+		t.Fatalf("Failed to convert TIMESTAMP: %v", err)
+	}
+	if v, ok := ts.(time.Time); !ok || v.Hour() != 10 {
+		t.Errorf("Expected time.Time, got %v", ts)
+	}
+
+	for typeName, want := range map[string]interface{}{"INT8": 42, "UNSIGNED BIGINT": 42, "INTERVAL": "42", "POINT": "42"} {
+		if got, err := convertSQLValue([]byte("42"), typeName); err != nil || got != want {
+			t.Errorf("Expected %s to convert to %v (%T), got %v (%T), %v", typeName, want, want, got, got, err)
+		}
+	}
+	if got, err := convertSQLValue("1 day", "INTERVAL"); err != nil || got != "1 day" {
+		t.Errorf("Expected INTERVAL text to pass through, got %v, %v", got, err)
+	}
+
+	df, err := FromRowSource(&sliceRowSource{rows: [][]interface{}{
+		{int64(1), 9.5, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{int64(2), nil, nil},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to read row source: %v", err)
+	}
+	if rows, _ := df.Shape(); rows != 2 || df.data[1][0] != 2 {
+		t.Errorf("Unexpected frame from row source: %v", df.data)
+	}
+}
+
+func TestCachedLoader(t *testing.T) {
+	store := NewMemoryStore()
+	loads := 0
+	loader := func() (*DataFrame, error) {
+		loads++
+		df := NewDataFrame([]string{"when", "amount"})
+		df.AddRow([]interface{}{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), 42})
+		return df, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		df, err := Cached(loader, "daily", time.Minute, store)
+		if err != nil {
+			t.Fatalf("Failed to load cached frame: %v", err)
+		}
+		if df.data[0][1] != 42 {
+			t.Errorf("Expected int 42 to survive the cache, got %#v", df.data[0][1])
+		}
+		if _, ok := df.data[0][0].(time.Time); !ok {
+			t.Errorf("Expected time.Time to survive the cache, got %T", df.data[0][0])
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected loader to run once, ran %d times", loads)
+	}
+
+	special := func() (*DataFrame, error) {
+		df := NewDataFrame([]string{"ratio"})
+		df.AddRow([]interface{}{math.NaN()})
+		df.AddRow([]interface{}{math.Inf(1)})
+		df.AddRow([]interface{}{math.Inf(-1)})
+		return df, nil
+	}
+	for i := 0; i < 2; i++ {
+		df, err := Cached(special, "ratios", time.Minute, store)
+		if err != nil {
+			t.Fatalf("Failed to cache NaN and Inf: %v", err)
+		}
+		ratio := df.data[0][0].(float64)
+		if !math.IsNaN(ratio) || df.data[1][0] != math.Inf(1) || df.data[2][0] != math.Inf(-1) {
+			t.Errorf("Expected NaN and infinities to survive the cache, got %v", df.data)
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	df := NewDataFrame([]string{"name", "age", "city"})
+	df.AddRow([]interface{}{"Alice", 25, "New York"})
+	df.AddRow([]interface{}{"Bob", 30, "London"})
+	df.AddRow([]interface{}{"Charlie", 35, "Paris"})
+
+	server := httptest.NewServer(Handler(df))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?columns=name,age&offset=1&limit=1")
+	if err != nil {
+		t.Fatalf("Failed to request JSON: %v", err)
+	}
+	var payload struct {
+		Data  []map[string]interface{} `json:"data"`
+		Total int                      `json:"total"`
+	}
+	json.NewDecoder(resp.Body).Decode(&payload)
+	resp.Body.Close()
+	if payload.Total != 3 || len(payload.Data) != 1 || payload.Data[0]["name"] != "Bob" {
+		t.Errorf("Unexpected JSON payload: %+v", payload)
+	}
+	if _, ok := payload.Data[0]["city"]; ok {
+		t.Error("Expected city column to be excluded")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"?limit=1", nil)
+	req.Header.Set("Accept", "text/csv")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to request CSV: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "name,age,city\nAlice,25,New York\n" {
+		t.Errorf("Unexpected CSV body: %q", body)
+	}
+
+	resp, err = http.Get(server.URL + "?offset=2&limit=" + strconv.Itoa(math.MaxInt))
+	if err != nil {
+		t.Fatalf("Failed to request a huge page: %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&payload)
+	resp.Body.Close()
+	if len(payload.Data) != 1 || payload.Data[0]["name"] != "Charlie" {
+		t.Errorf("Expected an overflowing limit to stop at the last row, got %+v", payload)
+	}
+
+	if rows, _ := df.Slice(0, -1).Shape(); rows != 0 {
+		t.Errorf("Expected a negative end to give no rows, got %d", rows)
+	}
+	if rows, _ := df.Slice(5, 10).Shape(); rows != 0 {
+		t.Errorf("Expected a start past the end to give no rows, got %d", rows)
+	}
+	df.Slice(0, 2).AddRow([]interface{}{"Dan", 40, "Oslo"})
+	df.Head(2).AddRow([]interface{}{"Eve", 41, "Rome"})
+	if df.data[2][0] != "Charlie" {
+		t.Errorf("Expected appending to a slice to leave the parent intact, got %v", df.data[2])
+	}
+
+	nan := NewDataFrame([]string{"ratio"})
+	nan.AddRow([]interface{}{math.NaN()})
+	nanServer := httptest.NewServer(Handler(nan))
+	defer nanServer.Close()
+	resp, err = http.Get(nanServer.URL)
+	if err != nil {
+		t.Fatalf("Failed to request NaN frame: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected a JSON encoding error to give 500, got %d", resp.StatusCode)
+	}
+}
+
+func TestMIMEBundle(t *testing.T) {
+	df := NewDataFrame([]string{"name", "note"})
+	df.AddRow([]interface{}{"Alice", "<b>vip</b>"})
+
+	bundle := df.MIMEBundle()
+	htmlOut, ok := bundle["text/html"].(string)
+	if !ok || !strings.Contains(htmlOut, "<th>name</th>") || !strings.Contains(htmlOut, "&lt;b&gt;vip&lt;/b&gt;") {
+		t.Errorf("Unexpected HTML rendering: %v", bundle["text/html"])
+	}
+	if text, _ := bundle["text/plain"].(string); !strings.Contains(text, "1 rows × 2 columns") {
+		t.Errorf("Unexpected text rendering: %q", text)
+	}
+}
+
+func writeTestXLSX(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	file, err := os.CreateTemp("", "test*.xlsx")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer file.Close()
+
+	file.Write(buildTestXLSX(t, files))
+	return file.Name()
+}
+
+func buildTestXLSX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadExcelChunks(t *testing.T) {
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet><dimension ref="A1:B6"/><sheetData>`)
+	sheet.WriteString(`<row r="1"><c r="A1" t="inlineStr"><is><t>id</t></is></c><c r="B1" t="s"><v>0</v></c></row>`)
+	for i := 2; i <= 6; i++ {
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="A%d"><v>%d</v></c><c r="B%d"><v>%d.5</v></c></row>`, i, i, i-1, i, i)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	path := writeTestXLSX(t, map[string]string{
+		"xl/sharedStrings.xml":     `<sst><si><t>value</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": sheet.String(),
+	})
+	defer os.Remove(path)
+
+	sizes := make([]int, 0)
+	err := ReadExcelChunks(path, 2, func(chunk *DataFrame) error {
+		rows, _ := chunk.Shape()
+		sizes = append(sizes, rows)
+		if chunk.Columns()[1] != "value" {
+			t.Errorf("Expected shared-string header, got %v", chunk.Columns())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream workbook: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("Expected chunks [2 2 1], got %v", sizes)
+	}
+
+	df, err := ReadExcel(path)
+	if err != nil {
+		t.Fatalf("Failed to read workbook: %v", err)
+	}
+	if rows, cols := df.Shape(); rows != 5 || cols != 2 || df.data[4][1] != 6.5 {
+		t.Errorf("Unexpected workbook contents: %v", df.data)
+	}
+}
+
+func TestReadExcelOptions(t *testing.T) {
+	report := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Quarterly report</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>ignored</t></is></c><c r="B2" t="inlineStr"><is><t>region</t></is></c><c r="C2" t="inlineStr"><is><t>sales</t></is></c></row>
+<row r="3"><c r="A3"><v>1</v></c><c r="B3" t="inlineStr"><is><t>EU</t></is></c><c r="C3"><v>100</v></c></row>
+<row r="4"><c r="A4"><v>2</v></c><c r="B4" t="inlineStr"><is><t>US</t></is></c><c r="C4"><v>200</v></c></row>
+<row r="5"><c r="A5" t="inlineStr"><is><t>Total</t></is></c><c r="C5"><v>300</v></c></row>
+</sheetData></worksheet>`
+
+	path := writeTestXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>
+<sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Report" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml":   report,
+	})
+	defer os.Remove(path)
+
+	df, err := ReadExcel(path, WithSheet("Report"), WithRange("B2:C4"))
+	if err != nil {
+		t.Fatalf("Failed to read range: %v", err)
+	}
+	if rows, cols := df.Shape(); rows != 2 || cols != 2 || df.Columns()[0] != "region" || df.data[1][1] != 200 {
+		t.Errorf("Unexpected ranged read: %v %v", df.Columns(), df.data)
+	}
+
+	df, err = ReadExcel(path, WithSheetIndex(1), WithSkipRows(1), WithHeaderRow(0))
+	if err != nil {
+		t.Fatalf("Failed to read with skip rows: %v", err)
+	}
+	if rows, cols := df.Shape(); rows != 3 || cols != 3 || df.Columns()[2] != "sales" {
+		t.Errorf("Unexpected header-row read: %v %v", df.Columns(), df.data)
+	}
+
+	if _, err := ReadExcel(path, WithSheetIndex(5)); err == nil {
+		t.Error("Expected error for out-of-range sheet index")
+	}
+}
+
+func TestReadExcelSparseCells(t *testing.T) {
+	path := writeTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>a</t></is></c><c r="B1" t="inlineStr"><is><t>b</t></is></c><c r="C1" t="inlineStr"><is><t>c</t></is></c><c r="D1" t="inlineStr"><is><t>d</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="D2"><v>4</v></c></row>
+<row r="3"><c r="C3"><v>3</v></c></row>
+</sheetData></worksheet>`,
+	})
+	defer os.Remove(path)
+
+	df, err := ReadExcel(path)
+	if err != nil {
+		t.Fatalf("Failed to read sparse sheet: %v", err)
+	}
+	if df.data[0][1] != nil || df.data[0][3] != 4 {
+		t.Errorf("Expected D2 in column d, got %v", df.data[0])
+	}
+	if df.data[1][0] != nil || df.data[1][2] != 3 {
+		t.Errorf("Expected C3 in column c, got %v", df.data[1])
+	}
+}
+
+func TestReadExcelEvaluateFormulas(t *testing.T) {
+	path := writeTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>qty</t></is></c><c r="B1" t="inlineStr"><is><t>price</t></is></c><c r="C1" t="inlineStr"><is><t>total</t></is></c><c r="D1" t="inlineStr"><is><t>label</t></is></c><c r="E1" t="inlineStr"><is><t>guarded</t></is></c></row>
+<row r="2"><c r="A2"><v>2</v></c><c r="B2"><v>1.5</v></c><c r="C2"><f>A2*B2</f></c><c r="D2"><f>IF(C2&gt;5,"big","small")</f></c><c r="E2"><f>IF(A2=2,0,A2/(A2-2))</f></c></row>
+<row r="3"><c r="A3"><v>4</v></c><c r="B3"><v>2</v></c><c r="C3"><f>A3*B3</f></c><c r="D3"><f>IF(C3&gt;5,"big","small")</f></c><c r="E3"><f>IF(A3=2,SUM(1,(2),A3/0),A3/(A3-2))</f></c></row>
+<row r="4"><c r="A4"><f>VLOOKUP(4,A2:C3,3,FALSE)</f></c><c r="C4"><f>SUM(C2:C3)</f></c><c r="D4"><f>"n="&amp;COUNT(A2:A3)</f></c></row>
+</sheetData></worksheet>`,
+	})
+	defer os.Remove(path)
+
+	df, err := ReadExcel(path, WithEvaluateFormulas())
+	if err != nil {
+		t.Fatalf("Failed to read workbook: %v", err)
+	}
+	if df.data[0][2] != 3 || df.data[1][2] != 8 || df.data[2][2] != 11 {
+		t.Errorf("Unexpected totals: %v", df.data)
+	}
+	if df.data[0][3] != "small" || df.data[1][3] != "big" || df.data[2][3] != "n=2" {
+		t.Errorf("Unexpected labels: %v", df.data)
+	}
+	if df.data[2][0] != 8 {
+		t.Errorf("Expected VLOOKUP result 8, got %v", df.data[2][0])
+	}
+	if df.data[0][4] != 0 || df.data[1][4] != 2 {
+		t.Errorf("Expected IF to evaluate only the branch it takes, got %v and %v", df.data[0][4], df.data[1][4])
+	}
+
+	raw, err := ReadExcel(path)
+	if err != nil {
+		t.Fatalf("Failed to read workbook: %v", err)
+	}
+	if raw.data[0][2] != nil {
+		t.Errorf("Expected unevaluated formula to stay empty, got %v", raw.data[0][2])
+	}
+
+	var chain strings.Builder
+	chain.WriteString(`<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>n</t></is></c><c r="B1" t="inlineStr"><is><t>loop</t></is></c></row><row r="2"><c r="A2"><v>1</v></c><c r="B2"><f>B3</f></c></row><row r="3"><c r="B3"><f>B2</f></c></row>`)
+	for r := 3; r <= 2000; r++ {
+		fmt.Fprintf(&chain, `<row r="%d"><c r="A%d"><f>A%d+1</f></c></row>`, r, r, r-1)
+	}
+	chain.WriteString(`</sheetData></worksheet>`)
+	path = writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": chain.String()})
+	defer os.Remove(path)
+
+	df, err = ReadExcel(path, WithEvaluateFormulas())
+	if err != nil {
+		t.Fatalf("Failed to read formula chain: %v", err)
+	}
+	if last := df.data[len(df.data)-1][0]; last != 1999 {
+		t.Errorf("Expected a 1999-step chain to evaluate, got %v", last)
+	}
+	if df.data[0][1] != "#REF!" || df.data[1][1] != "#REF!" {
+		t.Errorf("Expected a cycle to give #REF!, got %v %v", df.data[0][1], df.data[1][1])
+	}
+
+	path = writeTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>total</t></is></c></row><row r="2"><c r="A2"><f>SUM(B1:XFD1048576)</f></c></row></sheetData></worksheet>`,
+	})
+	defer os.Remove(path)
+	if _, err := ReadExcel(path, WithEvaluateFormulas()); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected a whole-sheet range to exceed the limit, got %v", err)
+	}
+	if _, err := ReadExcel(path, WithEvaluateFormulas(), WithExcelHardening(Hardening{MaxCells: 100})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected MaxCells to cap formula ranges, got %v", err)
+	}
+}
+
+func encryptTestCBC(t *testing.T, key, iv, data []byte) []byte {
+	t.Helper()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	padded := make([]byte, (len(data)+15)/16*16)
+	copy(padded, data)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+	return padded
+}
+
+// encryptTestXLSX wraps an xlsx package the way Excel does for agile
+// password encryption: an EncryptionInfo and an EncryptedPackage stream
+// inside a compound file.
+func encryptTestXLSX(t *testing.T, pkg []byte, password string) []byte {
+	t.Helper()
+
+	keySalt := bytes.Repeat([]byte{0x11}, 16)
+	passwordSalt := bytes.Repeat([]byte{0x22}, 16)
+	secret := bytes.Repeat([]byte{0x33}, 32)
+	verifier := bytes.Repeat([]byte{0x44}, 16)
+	spinCount := 1000
+
+	encrypted := binary.LittleEndian.AppendUint64(nil, uint64(len(pkg)))
+	for i := 0; i*encryptedSegmentSize < len(pkg); i++ {
+		end := (i + 1) * encryptedSegmentSize
+		if end > len(pkg) {
+			end = len(pkg)
+		}
+		iv := agileDigest(sha512.New, keySalt, binary.LittleEndian.AppendUint32(nil, uint32(i)))[:16]
+		encrypted = append(encrypted, encryptTestCBC(t, secret, iv, pkg[i*encryptedSegmentSize:end])...)
+	}
+
+	var passwordBytes []byte
+	for _, u := range utf16.Encode([]rune(password)) {
+		passwordBytes = binary.LittleEndian.AppendUint16(passwordBytes, u)
+	}
+	h := agileDigest(sha512.New, passwordSalt, passwordBytes)
+	for i := 0; i < spinCount; i++ {
+		h = agileDigest(sha512.New, binary.LittleEndian.AppendUint32(nil, uint32(i)), h)
+	}
+	derive := func(block []byte) []byte {
+		return agileDigest(sha512.New, h, block)[:32]
+	}
+	b64 := base64.StdEncoding.EncodeToString
+
+	info := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<encryption xmlns="http://schemas.microsoft.com/office/2006/encryption" xmlns:p="http://schemas.microsoft.com/office/2006/keyEncryptor/password">
+<keyData saltSize="16" blockSize="16" keyBits="256" hashSize="64" cipherAlgorithm="AES" cipherChaining="ChainingModeCBC" hashAlgorithm="SHA512" saltValue="%s"/>
+<keyEncryptors><keyEncryptor uri="http://schemas.microsoft.com/office/2006/keyEncryptor/password">
+<p:encryptedKey spinCount="%d" saltSize="16" blockSize="16" keyBits="256" hashSize="64" cipherAlgorithm="AES" cipherChaining="ChainingModeCBC" hashAlgorithm="SHA512" saltValue="%s" encryptedVerifierHashInput="%s" encryptedVerifierHashValue="%s" encryptedKeyValue="%s"/>
+</keyEncryptor></keyEncryptors></encryption>`,
+		b64(keySalt), spinCount, b64(passwordSalt),
+		b64(encryptTestCBC(t, derive(agileVerifierInputBlock), passwordSalt, verifier)),
+		b64(encryptTestCBC(t, derive(agileVerifierValueBlock), passwordSalt, agileDigest(sha512.New, verifier))),
+		b64(encryptTestCBC(t, derive(agileKeyValueBlock), passwordSalt, secret)))
+	infoStream := append([]byte{4, 0, 4, 0, 0x40, 0, 0, 0}, info...)
+
+	return buildTestCompoundFile(map[string][]byte{
+		"EncryptionInfo":   infoStream,
+		"EncryptedPackage": encrypted,
+	})
+}
+
+// buildTestCompoundFile writes a version 3 compound file with a single FAT
+// sector. The mini stream cutoff is zero so every stream uses full sectors.
+func buildTestCompoundFile(streams map[string][]byte) []byte {
+	const sectorSize = 512
+	names := make([]string, 0, len(streams))
+	for name := range streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fat := []uint32{0xFFFFFFFD, cfbEndOfChain}
+	dir := make([]byte, sectorSize)
+	var body []byte
+
+	writeEntry := func(i int, name string, kind byte, start uint32, size int) {
+		raw := dir[i*128 : (i+1)*128]
+		units := utf16.Encode([]rune(name))
+		for j, u := range units {
+			binary.LittleEndian.PutUint16(raw[2*j:], u)
+		}
+		binary.LittleEndian.PutUint16(raw[64:], uint16(2*len(units)+2))
+		raw[66] = kind
+		binary.LittleEndian.PutUint32(raw[68:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(raw[72:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(raw[76:], 0xFFFFFFFF)
+		binary.LittleEndian.PutUint32(raw[116:], start)
+		binary.LittleEndian.PutUint64(raw[120:], uint64(size))
+	}
+	writeEntry(0, "Root Entry", 5, cfbEndOfChain, 0)
+
+	for i, name := range names {
+		data := streams[name]
+		start := uint32(len(fat))
+		sectors := (len(data) + sectorSize - 1) / sectorSize
+		for s := 0; s < sectors; s++ {
+			fat = append(fat, uint32(len(fat)+1))
+		}
+		fat[len(fat)-1] = cfbEndOfChain
+		padded := make([]byte, sectors*sectorSize)
+		copy(padded, data)
+		body = append(body, padded...)
+		writeEntry(i+1, name, 2, start, len(data))
+	}
+
+	header := make([]byte, sectorSize)
+	copy(header, cfbSignature)
+	binary.LittleEndian.PutUint16(header[0x18:], 0x3E)
+	binary.LittleEndian.PutUint16(header[0x1A:], 3)
+	binary.LittleEndian.PutUint16(header[0x1C:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[0x1E:], 9)
+	binary.LittleEndian.PutUint16(header[0x20:], 6)
+	binary.LittleEndian.PutUint32(header[0x2C:], 1)
+	binary.LittleEndian.PutUint32(header[0x30:], 1)
+	binary.LittleEndian.PutUint32(header[0x3C:], cfbEndOfChain)
+	binary.LittleEndian.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := 0; i < 109; i++ {
+		binary.LittleEndian.PutUint32(header[0x4C+4*i:], cfbFreeSector)
+	}
+	binary.LittleEndian.PutUint32(header[0x4C:], 0)
+
+	fatSector := make([]byte, sectorSize)
+	for i := 0; i < sectorSize/4; i++ {
+		entry := uint32(cfbFreeSector)
+		if i < len(fat) {
+			entry = fat[i]
+		}
+		binary.LittleEndian.PutUint32(fatSector[4*i:], entry)
+	}
+
+	out := append(header, fatSector...)
+	out = append(out, dir...)
+	return append(out, body...)
+}
+
+func TestReadExcelWithPassword(t *testing.T) {
+	pkg := buildTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>name</t></is></c><c r="B1" t="inlineStr"><is><t>salary</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>Kim</t></is></c><c r="B2"><v>5200</v></c></row>
+</sheetData></worksheet>`,
+	})
+
+	file, err := os.CreateTemp("", "encrypted*.xlsx")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	file.Write(encryptTestXLSX(t, pkg, "s3cret"))
+	file.Close()
+	defer os.Remove(file.Name())
+
+	df, err := ReadExcel(file.Name(), WithPassword("s3cret"))
+	if err != nil {
+		t.Fatalf("Failed to read encrypted workbook: %v", err)
+	}
+	if df.data[0][0] != "Kim" || df.data[0][1] != 5200 {
+		t.Errorf("Unexpected data: %v", df.data)
+	}
+
+	if _, err := ReadExcel(file.Name(), WithPassword("wrong")); err == nil || !strings.Contains(err.Error(), "incorrect password") {
+		t.Errorf("Expected incorrect password error, got %v", err)
+	}
+	if _, err := ReadExcel(file.Name()); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("Expected encrypted workbook error, got %v", err)
+	}
+
+	valid := agileEncryptedKey{
+		agileKeyData: agileKeyData{SaltSize: 16, BlockSize: 16, KeyBits: 256, CipherAlgorithm: "AES", CipherChaining: "ChainingModeCBC", HashAlgorithm: "SHA512"},
+	}
+	for name, mutate := range map[string]func(k *agileEncryptedKey){
+		"spin count": func(k *agileEncryptedKey) { k.SpinCount = 2_000_000_000 },
+		"block size": func(k *agileEncryptedKey) { k.BlockSize = 1 << 30 },
+		"key size":   func(k *agileEncryptedKey) { k.KeyBits = -8 },
+		"salt size":  func(k *agileEncryptedKey) { k.SaltSize = -1 },
+	} {
+		key := valid
+		mutate(&key)
+		if _, err := agileSecretKey(&key, "s3cret"); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestReadExcelMetadata(t *testing.T) {
+	path := writeTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>region</t></is></c><c r="B1" t="inlineStr"><is><t>store</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>North</t></is></c><c r="B2" t="inlineStr"><is><t>Seoul</t></is></c></row>
+<row r="3"><c r="A3"/><c r="B3" t="inlineStr"><is><t>Incheon</t></is></c></row>
+</sheetData>
+<mergeCells count="1"><mergeCell ref="A2:A3"/></mergeCells>
+<hyperlinks><hyperlink ref="B2" r:id="rId1"/><hyperlink ref="B3" location="Sheet2!A1"/></hyperlinks>
+</worksheet>`,
+		"xl/worksheets/_rels/sheet1.xml.rels": `<Relationships>
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/seoul" TargetMode="External"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments" Target="../comments1.xml"/>
+</Relationships>`,
+		"xl/comments1.xml": `<comments><authors><author>kim</author></authors><commentList>
+<comment ref="A2" authorId="0"><text><r><t>kim:</t></r><r><t> check totals</t></r></text></comment>
+</commentList></comments>`,
+	})
+	defer os.Remove(path)
+
+	df, err := ReadExcel(path, WithFillMerged())
+	if err != nil {
+		t.Fatalf("Failed to read workbook: %v", err)
+	}
+	if df.data[1][0] != "North" {
+		t.Errorf("Expected merged region to fill down, got %v", df.data[1])
+	}
+
+	plain, _ := ReadExcel(path)
+	if plain.data[1][0] != nil {
+		t.Errorf("Expected merged cell to stay empty without WithFillMerged, got %v", plain.data[1][0])
+	}
+
+	meta, err := ReadExcelMetadata(path)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	cells := make(map[string][]interface{})
+	for _, row := range meta.data {
+		cells[row[0].(string)] = row
+	}
+	if cells["B2"][4] != "https://example.com/seoul" || cells["B3"][4] != "#Sheet2!A1" {
+		t.Errorf("Unexpected hyperlinks: %v %v", cells["B2"], cells["B3"])
+	}
+	if cells["A2"][5] != "kim: check totals" {
+		t.Errorf("Unexpected comment: %v", cells["A2"])
+	}
+	if cells["A3"][6] != "A2:A3" || cells["A3"][1] != 3 || cells["A3"][2] != 1 {
+		t.Errorf("Unexpected merged cell metadata: %v", cells["A3"])
+	}
+
+	huge := writeTestXLSX(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>k</t></is></c><c r="B1" t="inlineStr"><is><t>v</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>x</t></is></c></row>
+<row r="3"><c r="B3"><v>1</v></c></row>
+</sheetData>
+<mergeCells count="1"><mergeCell ref="A2:XFD1048576"/></mergeCells>
+<hyperlinks><hyperlink ref="A1:XFD1048576" location="Sheet2!A1"/></hyperlinks>
+</worksheet>`,
+	})
+	defer os.Remove(huge)
+	filled, err := ReadExcel(huge, WithFillMerged())
+	if err != nil || fmt.Sprint(filled.data) != "[[x x] [x x]]" {
+		t.Errorf("Expected a sheet-sized merge to fill only the data, got %v (%v)", filled, err)
+	}
+	hugeMeta, err := ReadExcelMetadata(huge)
+	if err != nil || len(hugeMeta.data) != 4 || hugeMeta.data[3][4] != "#Sheet2!A1" || hugeMeta.data[3][6] != "A2:XFD1048576" {
+		t.Errorf("Expected sheet-sized ranges to be resolved per cell, got %v (%v)", hugeMeta, err)
+	}
+}
+
+func TestSniffCSV(t *testing.T) {
+	dialect, err := SniffCSV(strings.NewReader("name;age;city\nKim;31;\"Seoul; KR\"\nLee;28;Busan\n"))
+	if err != nil {
+		t.Fatalf("Failed to sniff: %v", err)
+	}
+	if dialect.Delimiter != ';' || dialect.Quote != '"' || !dialect.HasHeader {
+		t.Errorf("Unexpected dialect: %+v", dialect)
+	}
+
+	dialect, err = SniffCSV(strings.NewReader("1|'a'|2.5\n2|'b'|3.5\n3|'c'|4.5\n"))
+	if err != nil {
+		t.Fatalf("Failed to sniff: %v", err)
+	}
+	if dialect.Delimiter != '|' || dialect.Quote != '\'' || dialect.HasHeader {
+		t.Errorf("Unexpected dialect: %+v", dialect)
+	}
+
+	// commas inside a quoted field must not split it when judging the quote
+	dialect, err = SniffCSV(strings.NewReader("id;note\n1;\"x, 'a', y\"\n2;\"z, 'b', w\"\n"))
+	if err != nil {
+		t.Fatalf("Failed to sniff: %v", err)
+	}
+	if dialect.Delimiter != ';' || dialect.Quote != '"' {
+		t.Errorf("Unexpected dialect: %+v", dialect)
+	}
+
+	// a sample cut inside a multi-line quoted field still sniffs
+	dialect, err = sniffSample([]byte("id,note\n1,\"one\nline\"\n2,\"cut\nhere\n"), true)
+	if err != nil {
+		t.Fatalf("Failed to sniff a truncated sample: %v", err)
+	}
+	if dialect.Delimiter != ',' || dialect.Quote != '"' {
+		t.Errorf("Unexpected dialect: %+v", dialect)
+	}
+
+	file, err := os.CreateTemp("", "sniff*.csv")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	file.WriteString("id\tscore\n1\t9.5\n2\t7.0\n")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	df, err := ReadCSV(file.Name(), WithAutoDetect())
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if df.columns[1] != "score" || df.data[1][1] != 7.0 {
+		t.Errorf("Unexpected frame: %v %v", df.columns, df.data)
+	}
+}
+
+func TestReadCSVGlob(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/2024-01.csv", []byte("id,amount\n1,10\n2,20\n"), 0o644)
+	os.WriteFile(dir+"/2024-02.csv", []byte("amount,id,note\n30,3,late\n"), 0o644)
+	os.WriteFile(dir+"/2023-12.csv", []byte("id,amount\n0,5\n"), 0o644)
+
+	df, err := ReadCSVGlob(dir+"/2024-*.csv", WithSourceColumn())
+	if err != nil {
+		t.Fatalf("Failed to read glob: %v", err)
+	}
+
+	expected := []string{"id", "amount", SourceFileColumn, "note"}
+	if fmt.Sprint(df.columns) != fmt.Sprint(expected) {
+		t.Errorf("Expected columns %v, got %v", expected, df.columns)
+	}
+	if len(df.data) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(df.data))
+	}
+	last := df.data[2]
+	if last[0] != 3 || last[1] != 30 || last[2] != dir+"/2024-02.csv" || last[3] != "late" {
+		t.Errorf("Unexpected aligned row: %v", last)
+	}
+	if df.data[0][3] != nil {
+		t.Errorf("Expected missing note to be nil, got %v", df.data[0][3])
+	}
+
+	if _, err := ReadCSVGlob(dir + "/2025-*.csv"); err == nil {
+		t.Error("Expected error when nothing matches")
+	}
+}
+
+func TestToPartitioned(t *testing.T) {
+	df := NewDataFrame([]string{"dept", "date", "amount"})
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	df.AddRow([]interface{}{"Sales", day1, 10})
+	df.AddRow([]interface{}{"Sales", day2, 20})
+	df.AddRow([]interface{}{"R&D", day1, 30})
+	df.AddRow([]interface{}{nil, day1, 40})
+	df.AddRow([]interface{}{"Sales", day1, 50})
+
+	dir := t.TempDir()
+	if err := df.ToPartitioned(dir, "csv", "dept", "date"); err != nil {
+		t.Fatalf("Failed to write partitions: %v", err)
+	}
+
+	part, err := ReadCSV(dir + "/dept=Sales/date=2024-01-01/part-0.csv")
+	if err != nil {
+		t.Fatalf("Failed to read partition: %v", err)
+	}
+	if fmt.Sprint(part.columns) != "[amount]" || len(part.data) != 2 || part.data[1][0] != 50 {
+		t.Errorf("Unexpected partition: %v %v", part.columns, part.data)
+	}
+	for _, p := range []string{"dept=R&D/date=2024-01-01", "dept=__HIVE_DEFAULT_PARTITION__/date=2024-01-01", "dept=Sales/date=2024-01-02"} {
+		if _, err := os.Stat(dir + "/" + p + "/part-0.csv"); err != nil {
+			t.Errorf("Expected partition %s: %v", p, err)
+		}
+	}
+
+	if err := df.ToPartitioned(dir, "json", "missing"); err == nil {
+		t.Error("Expected error for missing partition column")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	cp, err := NewCheckpoint(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create checkpoint: %v", err)
+	}
+
+	raw := NewDataFrame([]string{"id", "amount"})
+	raw.AddRow([]interface{}{1, 10.5})
+	raw.AddRow([]interface{}{2, -3.0})
+
+	runs := 0
+	clean := func() (*DataFrame, error) {
+		runs++
+		return raw.Filter(func(row []interface{}) bool {
+			return row[1].(float64) > 0
+		}), nil
+	}
+
+	first, err := cp.Step("clean", clean, raw)
+	if err != nil {
+		t.Fatalf("Step failed: %v", err)
+	}
+	second, err := cp.Step("clean", clean, raw)
+	if err != nil {
+		t.Fatalf("Step failed: %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("Expected the step to run once, ran %d times", runs)
+	}
+	if len(second.data) != 1 || second.data[0][1] != first.data[0][1] {
+		t.Errorf("Expected restored checkpoint to match, got %v", second.data)
+	}
+
+	raw.AddRow([]interface{}{3, 7.0})
+	if _, err := cp.Step("clean", clean, raw); err != nil {
+		t.Fatalf("Step failed: %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("Expected changed input to rerun the step, ran %d times", runs)
+	}
+
+	if err := cp.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	cp.Step("clean", clean, raw)
+	if runs != 3 {
+		t.Errorf("Expected cleared checkpoint to rerun the step, ran %d times", runs)
+	}
+}
+
+func TestLineage(t *testing.T) {
+	SetLineageTracking(true)
+	defer SetLineageTracking(false)
+
+	dir := t.TempDir()
+	path := dir + "/sales.csv"
+	os.WriteFile(path, []byte("region,amount\nNorth,10\nSouth,-2\nNorth,7\n"), 0o644)
+
+	df, err := ReadCSV(path)
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	positive := df.Filter(func(row []interface{}) bool { return row[1].(int) > 0 })
+	sorted, _ := positive.Sort("amount", true)
+	sorted.SetColumn("doubled", []interface{}{14, 20})
+
+	history := sorted.History()
+	ops := make([]string, len(history))
+	for i, entry := range history {
+		ops[i] = entry.Operation
+	}
+	if fmt.Sprint(ops) != "[read_csv filter sort set_column]" {
+		t.Errorf("Unexpected history: %v", ops)
+	}
+	if history[0].Details["source"] != path || history[1].Details["rows_out"] != 2 {
+		t.Errorf("Unexpected details: %v %v", history[0].Details, history[1].Details)
+	}
+	if len(df.History()) != 1 {
+		t.Errorf("Expected source frame history to be unchanged, got %d entries", len(df.History()))
+	}
+
+	combined := Concat(df, nil, sorted)
+	if h := combined.History(); len(h) != 1 || len(h[0].Inputs) != 2 || len(h[0].Inputs[1]) != 4 {
+		t.Errorf("Unexpected concat history: %+v", h)
+	}
+	if len(combined.data) != len(df.data)+len(sorted.data) {
+		t.Errorf("Expected nil frames to be skipped, got %d rows", len(combined.data))
+	}
+
+	data, err := sorted.HistoryJSON()
+	if err != nil || !strings.Contains(string(data), `"operation": "sort"`) {
+		t.Errorf("Unexpected history JSON: %s %v", data, err)
+	}
+
+	SetLineageTracking(false)
+	if len(NewDataFrame([]string{"a"}).Head(1).History()) != 0 {
+		t.Error("Expected no history when tracking is off")
+	}
+}
+
+func TestColumnMeta(t *testing.T) {
+	df := NewDataFrame([]string{"name", "weight"})
+	df.AddRow([]interface{}{"a", 1.0})
+	df.AddRow([]interface{}{"b", 2.0})
+	df.AddRow([]interface{}{"c", 3.0})
+	df.AddRow([]interface{}{"d", 4.0})
+
+	if err := df.SetColumnMeta("weight", "unit", "kg"); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+	df.SetColumnMeta("weight", "description", "Net weight")
+	if err := df.SetColumnMeta("missing", "unit", "kg"); err == nil {
+		t.Error("Expected error for missing column")
+	}
+
+	selected, _ := df.Select("weight")
+	if unit, ok := selected.GetColumnMeta("weight", "unit"); !ok || unit != "kg" {
+		t.Errorf("Expected meta to follow Select, got %v", unit)
+	}
+	if series, _ := selected.GetColumn("weight"); series.Unit() != "kg" {
+		t.Errorf("Expected series unit kg, got %q", series.Unit())
+	}
+
+	desc := df.Describe()
+	if fmt.Sprint(desc.columns) != "[statistic weight]" {
+		t.Fatalf("Unexpected describe columns: %v", desc.columns)
+	}
+	stats := make(map[string]interface{})
+	for _, row := range desc.data {
+		stats[row[0].(string)] = row[1]
+	}
+	if stats["count"] != 4 || stats["mean"] != 2.5 || stats["25%"] != 1.75 || stats["unit"] != "kg" {
+		t.Errorf("Unexpected describe output: %v", stats)
+	}
+
+	info := df.Info()
+	if !strings.Contains(info, "description=Net weight, unit=kg") || !strings.Contains(info, "4 non-null") {
+		t.Errorf("Unexpected info:\n%s", info)
+	}
+	if !strings.Contains(df.ToHTML(), `<th title="description=Net weight, unit=kg">weight</th>`) {
+		t.Errorf("Expected meta in HTML header:\n%s", df.ToHTML())
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	df := NewDataFrame([]string{"id"})
+	df.AddRow([]interface{}{1})
+	df.AddRow([]interface{}{2})
+	df.SetName("orders").Attrs()["run_id"] = "r-42"
+
+	head := df.Head(1)
+	if head.Name() != "orders" || head.Attrs()["run_id"] != "r-42" {
+		t.Errorf("Expected name and attrs on derived frame, got %q %v", head.Name(), head.Attrs())
+	}
+	head.Attrs()["run_id"] = "changed"
+	if df.Attrs()["run_id"] != "r-42" {
+		t.Error("Expected derived attrs to be a copy")
+	}
+
+	if combined := Concat(df, df.Head(1)); combined.Attrs()["run_id"] != "r-42" {
+		t.Errorf("Expected matching attrs to survive Concat, got %v", combined.Attrs())
+	}
+	if combined := Concat(df, head); len(combined.Attrs()) != 0 {
+		t.Errorf("Expected conflicting attrs to be dropped, got %v", combined.Attrs())
+	}
+}
+
+func TestValidateRows(t *testing.T) {
+	df := NewDataFrame([]string{"email", "age", "status", "start", "end"})
+	df.AddRow([]interface{}{"a@example.com", 34, "active", 1, 5})
+	df.AddRow([]interface{}{nil, 150, "active", 1, 5})
+	df.AddRow([]interface{}{"bad-address", 20, "deleted", 9, 2})
+
+	valid, rejected, err := df.ValidateRows(
+		NotNull("email"),
+		InRange("age", 0, 120),
+		OneOf("status", "active", "inactive"),
+		MatchesPattern("email", regexp.MustCompile(`^[^@]+@[^@]+$`)),
+		RowRule("start after end", func(row map[string]interface{}) bool {
+			return row["start"].(int) <= row["end"].(int)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("ValidateRows failed: %v", err)
+	}
+	if len(valid.data) != 1 || valid.data[0][0] != "a@example.com" {
+		t.Errorf("Unexpected valid rows: %v", valid.data)
+	}
+	if len(rejected.data) != 2 || rejected.columns[5] != RejectReasonColumn {
+		t.Fatalf("Unexpected rejected frame: %v %v", rejected.columns, rejected.data)
+	}
+	if reason := rejected.data[0][5]; reason != "email is null; age not in [0, 120]" {
+		t.Errorf("Unexpected reason: %v", reason)
+	}
+	if reason := rejected.data[1][5].(string); !strings.Contains(reason, "status not one of") || !strings.Contains(reason, "start after end") {
+		t.Errorf("Unexpected reason: %v", reason)
+	}
+
+	if _, _, err := df.ValidateRows(NotNull("missing")); err == nil {
+		t.Error("Expected error for missing column")
+	}
+}
+
+func TestConform(t *testing.T) {
+	df := NewDataFrame([]string{"cust_id", "amount", "signup", "legacy_flag"})
+	df.AddRow([]interface{}{"7", 10, "2024-03-01", true})
+	df.AddRow([]interface{}{"8", "n/a", "2024-03-02", false})
+
+	target := []ColumnSchema{
+		{Name: "customer_id", Type: "int"},
+		{Name: "amount", Type: "float", Default: 0.0},
+		{Name: "signup", Type: "time"},
+		{Name: "country", Type: "string", Default: "KR"},
+	}
+	policy := ConformPolicy{Rename: map[string]string{"cust_id": "customer_id"}, DropExtra: true}
+
+	result, report, err := Conform(df, target, policy)
+	if err != nil {
+		t.Fatalf("Conform failed: %v", err)
+	}
+	if fmt.Sprint(result.columns) != "[customer_id amount signup country]" {
+		t.Errorf("Unexpected columns: %v", result.columns)
+	}
+	first := result.data[0]
+	if first[0] != 7 || first[1] != 10.0 || first[2] != time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) || first[3] != "KR" {
+		t.Errorf("Unexpected row: %v", first)
+	}
+	if result.data[1][1] != 0.0 || report.Failed["amount"] != 1 {
+		t.Errorf("Expected failed cast to fall back to default, got %v (report %v)", result.data[1][1], report.Failed)
+	}
+	if fmt.Sprint(report.Added, report.Dropped, report.Renamed, report.Cast) != "[country] [legacy_flag] map[cust_id:customer_id] [customer_id amount signup]" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	if _, _, err := Conform(df, target, ConformPolicy{Rename: policy.Rename, Strict: true}); err == nil {
+		t.Error("Expected strict policy to fail on uncastable value")
+	}
+	for _, val := range []interface{}{math.Inf(1), math.Inf(-1), math.NaN(), 1e19, -1e19, "inf"} {
+		if got, ok := castValue(val, "int"); ok {
+			t.Errorf("Expected %v not to cast to int, got %v", val, got)
+		}
+	}
+	if got, ok := castValue(-9.0, "int"); !ok || got != -9 {
+		t.Errorf("Unexpected int cast: %v %v", got, ok)
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	df := NewDataFrame([]string{"name", "card", "age", "email"})
+	df.AddRow([]interface{}{"Kim Minji", "4111111111111111", 34, "minji@corp.kr"})
+	df.AddRow([]interface{}{"Lee Jun", "5500000000000004", 58, "minji@corp.kr"})
+	df.AddRow([]interface{}{nil, nil, nil, nil})
+
+	result, err := df.Anonymize(map[string]AnonymizeStrategy{
+		"name":  AnonymizeFake("name"),
+		"card":  AnonymizeMask(4),
+		"age":   AnonymizeGeneralize(10),
+		"email": AnonymizeHash("pepper"),
+	})
+	if err != nil {
+		t.Fatalf("Anonymize failed: %v", err)
+	}
+
+	first := result.data[0]
+	if first[0] == "Kim Minji" || !strings.Contains(first[0].(string), " ") {
+		t.Errorf("Expected fake name, got %v", first[0])
+	}
+	if first[1] != "************1111" || first[2] != "30-39" || result.data[1][2] != "50-59" {
+		t.Errorf("Unexpected mask or bins: %v", first)
+	}
+	if first[3] != result.data[1][3] || first[3] == "minji@corp.kr" || len(first[3].(string)) != 16 {
+		t.Errorf("Expected equal salted hashes, got %v and %v", first[3], result.data[1][3])
+	}
+	if result.data[2][0] != nil || df.data[0][1] != "4111111111111111" {
+		t.Error("Expected nils to pass through and the source to stay unchanged")
+	}
+
+	again, _ := df.Anonymize(map[string]AnonymizeStrategy{"name": AnonymizeFake("name")})
+	if again.data[0][0] != first[0] {
+		t.Errorf("Expected deterministic fakes, got %v and %v", again.data[0][0], first[0])
+	}
+	if _, err := df.Anonymize(map[string]AnonymizeStrategy{"missing": AnonymizeMask(4)}); err == nil {
+		t.Error("Expected error for missing column")
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	df := NewDataFrame([]string{"customer", "age", "income", "segment"})
+	for i := 0; i < 300; i++ {
+		age := 20 + rng.Intn(50)
+		income := float64(age)*1000 + rng.NormFloat64()*2000
+		segment := "retail"
+		if i%4 == 0 {
+			segment = "business"
+		}
+		df.AddRow([]interface{}{fmt.Sprintf("cust-%d", i), age, income, segment})
+	}
+
+	synth, err := synthesize(df, 2000, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatalf("Synthesize failed: %v", err)
+	}
+	if len(synth.data) != 2000 {
+		t.Fatalf("Expected 2000 rows, got %d", len(synth.data))
+	}
+
+	var ages, incomes []float64
+	business := 0
+	for _, row := range synth.data {
+		if strings.HasPrefix(row[0].(string), "cust-") {
+			t.Fatalf("Expected identifiers to be replaced, got %v", row[0])
+		}
+		age := row[1].(int)
+		if age < 20 || age > 69 {
+			t.Fatalf("Age %d outside the observed range", age)
+		}
+		ages = append(ages, float64(age))
+		incomes = append(incomes, row[2].(float64))
+		if row[3] == "business" {
+			business++
+		}
+	}
+
+	if share := float64(business) / 2000; share < 0.2 || share > 0.3 {
+		t.Errorf("Expected about 25%% business rows, got %.2f", share)
+	}
+	center := func(xs []float64) []float64 {
+		var mean float64
+		for _, x := range xs {
+			mean += x / float64(len(xs))
+		}
+		out := make([]float64, len(xs))
+		for i, x := range xs {
+			out[i] = x - mean
+		}
+		return out
+	}
+	if corr := correlationMatrix([][]float64{center(ages), center(incomes)})[0][1]; corr < 0.8 {
+		t.Errorf("Expected age and income to stay correlated, got %.2f", corr)
+	}
+
+	if _, err := Synthesize(NewDataFrame([]string{"a"}), 5); err == nil {
+		t.Error("Expected error for an empty frame")
+	}
+}
+
+func TestGen(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	ages, err := IntRange(18, 65)
+	if err != nil {
+		t.Fatalf("IntRange failed: %v", err)
+	}
+	tiers, err := Choice("gold", "silver")
+	if err != nil {
+		t.Fatalf("Choice failed: %v", err)
+	}
+	if _, err := IntRange(5, 4); err == nil {
+		t.Error("Expected max below min to be rejected")
+	}
+	if _, err := Choice(); err == nil {
+		t.Error("Expected a choice without values to be rejected")
+	}
+	build := func() *DataFrame {
+		return Gen().
+			Col("id", Sequence(100)).
+			Col("name", StringRand(10)).
+			Col("age", ages).
+			Col("ts", TimeRange(start, end)).
+			Col("tier", WithNulls(tiers, 0.5)).
+			Rows(1000).
+			Build()
+	}
+
+	df := build()
+	if rows, cols := df.Shape(); rows != 1000 || cols != 5 {
+		t.Fatalf("Expected 1000x5, got %dx%d", rows, cols)
+	}
+
+	nulls := 0
+	for i, row := range df.data {
+		if row[0] != 100+i || len(row[1].(string)) != 10 {
+			t.Fatalf("Unexpected row %d: %v", i, row)
+		}
+		if age := row[2].(int); age < 18 || age > 65 {
+			t.Fatalf("Age %d out of range", age)
+		}
+		if ts := row[3].(time.Time); ts.Before(start) || !ts.Before(end) {
+			t.Fatalf("Time %v out of range", ts)
+		}
+		if row[4] == nil {
+			nulls++
+		}
+	}
+	if nulls < 400 || nulls > 600 {
+		t.Errorf("Expected about half nil tiers, got %d", nulls)
+	}
+
+	if fmt.Sprint(build().data[:5]) != fmt.Sprint(df.data[:5]) {
+		t.Error("Expected the same seed to reproduce the frame")
+	}
+}
+
+type recordingT struct {
+	errors []string
+	logs   []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func TestSnapshotTest(t *testing.T) {
+	golden := t.TempDir() + "/golden/expected.csv"
+	df := NewDataFrame([]string{"name", "score"})
+	df.AddRow([]interface{}{"a", 0.1 + 0.2})
+	df.AddRow([]interface{}{"b", 2.0})
+
+	rec := &recordingT{}
+	SnapshotTest(rec, df, golden)
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "does not exist") {
+		t.Fatalf("Expected a missing golden file to fail, got %v", rec.errors)
+	}
+	t.Setenv(UpdateSnapshotsEnv, "1")
+	rec = &recordingT{}
+	SnapshotTest(rec, df, golden)
+	if len(rec.errors) != 0 || len(rec.logs) != 1 {
+		t.Fatalf("Expected golden file to be created, got %v %v", rec.errors, rec.logs)
+	}
+	os.Unsetenv(UpdateSnapshotsEnv)
+
+	os.WriteFile(golden, []byte("name,score\na,0.3\nb,2\n"), 0o644)
+	rec = &recordingT{}
+	SnapshotTest(rec, df, golden)
+	if len(rec.errors) != 0 {
+		t.Errorf("Expected match within tolerance, got %v", rec.errors)
+	}
+
+	df.data[1][1] = 2.5
+	rec = &recordingT{}
+	SnapshotTest(rec, df, golden)
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `row 1, column 'score': expected "2", got "2.5"`) {
+		t.Errorf("Expected readable diff, got %v", rec.errors)
+	}
+	if !cellsMatch("0", "1e-15", 1e-9) || cellsMatch("1e-9", "2e-9", 1e-9) || !cellsMatch("3e-12", "3.5e-12", 1e-9) {
+		t.Error("Expected a relative tolerance with an absolute floor near zero")
+	}
+}
+
+func TestBroadcastSeries(t *testing.T) {
+	df := NewDataFrame([]string{"name", "x", "y"})
+	df.AddRow([]interface{}{"a", 1, 10.0})
+	df.AddRow([]interface{}{"b", 3, 30.0})
+
+	means, err := df.Mean(0)
+	if err != nil {
+		t.Fatalf("Mean failed: %v", err)
+	}
+	if fmt.Sprint(means.index, means.data) != "[x y] [2 20]" {
+		t.Errorf("Unexpected column means: %v %v", means.index, means.data)
+	}
+
+	centered, err := df.SubSeries(means, 1)
+	if err != nil {
+		t.Fatalf("SubSeries failed: %v", err)
+	}
+	if centered.data[0][0] != nil || centered.data[0][1] != -1.0 || centered.data[1][2] != 10.0 {
+		t.Errorf("Unexpected centered frame: %v", centered.data)
+	}
+
+	scale := NewSeries("scale", []interface{}{2, 10})
+	scaled, err := df.MulSeries(scale, 0)
+	if err != nil {
+		t.Fatalf("MulSeries failed: %v", err)
+	}
+	if scaled.data[0][1] != 2 || scaled.data[1][2] != 300.0 {
+		t.Errorf("Unexpected scaled frame: %v", scaled.data)
+	}
+
+	rowMeans, _ := df.Mean(1)
+	if rowMeans.data[1] != 16.5 {
+		t.Errorf("Unexpected row mean: %v", rowMeans.data)
+	}
+
+	if _, err := df.AddSeries(scale, 1); err == nil {
+		t.Error("Expected length mismatch error for positional series")
+	}
+	if _, err := df.DivSeries(NewSeries("one", []interface{}{1}), 0); err == nil {
+		t.Error("Expected length mismatch error for row series")
+	}
+}
+
+func TestPipe(t *testing.T) {
+	df := NewDataFrame([]string{"name", "score"})
+	df.AddRow([]interface{}{"a", 3})
+	df.AddRow([]interface{}{"b", 9})
+	df.AddRow([]interface{}{"c", 6})
+
+	dropBelow := func(df *DataFrame, args ...interface{}) (*DataFrame, error) {
+		limit := args[0].(int)
+		return df.Filter(func(row []interface{}) bool { return row[1].(int) >= limit }), nil
+	}
+	sortByScore := func(df *DataFrame) (*DataFrame, error) {
+		return df.Sort("score", false)
+	}
+
+	result, err := df.Pipe(WithArgs(dropBelow, 5), sortByScore)
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	if len(result.data) != 2 || result.data[0][0] != "b" {
+		t.Errorf("Unexpected result: %v", result.data)
+	}
+
+	_, err = df.Pipe(sortByScore, func(df *DataFrame) (*DataFrame, error) {
+		return df.Select("missing")
+	})
+	if err == nil || !strings.Contains(err.Error(), "pipe step 2") {
+		t.Errorf("Expected step error, got %v", err)
+	}
+}
+
+func TestLazyExplain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.csv")
+	os.WriteFile(path, []byte("id,region,amount,note\n1,east,50,a\n2,west,150,b\n3,east,300,c\n4,west,120,d\n"), 0o644)
+
+	lazy := ScanCSV(path).Sort("amount", false).Query("amount > 100").Select("region", "amount").Head(2)
+	plan, err := lazy.Explain()
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	want := `scan csv "` + path + `" columns=[region, amount] (rows 4)
+query amount > 100 (rows <=4)
+sort amount desc (rows <=4)
+select region, amount (rows <=4)
+head 2 (rows <=2)
+optimizations:
+  predicate pushdown: query amount > 100 moved before sort amount desc
+  projection pushdown: scan reads 2 of 4 columns
+`
+	if plan != want {
+		t.Errorf("Unexpected plan:\n%s", plan)
+	}
+
+	df, err := lazy.Collect()
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if fmt.Sprint(df.columns, df.data) != "[region amount] [[east 300] [west 150]]" {
+		t.Errorf("Unexpected result: %v %v", df.columns, df.data)
+	}
+
+	frame := NewDataFrame([]string{"price", "qty"})
+	frame.AddRow([]interface{}{2.0, 3})
+	frame.AddRow([]interface{}{5.0, 1})
+	evaluated := frame.Lazy().Eval("total = price * qty").Query("total > 5")
+	plan, err = evaluated.Explain()
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if strings.Contains(plan, "pushdown") || !strings.HasPrefix(plan, "scan frame (rows 2)") {
+		t.Errorf("Expected a query on an evaluated column to stay put, got:\n%s", plan)
+	}
+	if df, err := evaluated.Collect(); err != nil || len(df.data) != 1 || df.data[0][2] != 6.0 {
+		t.Errorf("Unexpected lazy eval result: %v %v", df, err)
+	}
+
+	if _, err := frame.Lazy().Select("price").Query("qty > 1").Collect(); err == nil {
+		t.Error("Expected a query on a dropped column to fail as it does eagerly")
+	}
+	if _, err := frame.Lazy().Query("qty >").Collect(); err == nil || !strings.Contains(err.Error(), "lazy step 1") {
+		t.Errorf("Expected a parse error from the first step, got %v", err)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	df := NewDataFrame([]string{"price", "qty"})
+	df.AddRow([]interface{}{2.5, 4})
+	df.AddRow([]interface{}{10.0, 1})
+
+	result := df.Assign(map[string]func(Row) interface{}{
+		"total": func(r Row) interface{} {
+			price, _ := r.Float("price")
+			qty, _ := r.Float("qty")
+			return price * qty
+		},
+		"bulk": func(r Row) interface{} { return r.Get("qty").(int) > 2 },
+		"qty":  func(r Row) interface{} { return r.Get("qty").(int) * 10 },
+	})
+
+	if fmt.Sprint(result.columns) != "[price qty bulk total]" {
+		t.Errorf("Unexpected columns: %v", result.columns)
+	}
+	if fmt.Sprint(result.data[0]) != "[2.5 40 true 10]" || fmt.Sprint(result.data[1]) != "[10 10 false 10]" {
+		t.Errorf("Unexpected rows: %v", result.data)
+	}
+	if df.data[0][1] != 4 || len(df.columns) != 2 {
+		t.Error("Expected the source frame to stay unchanged")
+	}
+}
+
+func TestEval(t *testing.T) {
+	df := NewDataFrame([]string{"revenue", "cost", "unit price"})
+	df.AddRow([]interface{}{100, 60, 2.5})
+	df.AddRow([]interface{}{80, 90, 4.0})
+	df.AddRow([]interface{}{50, nil, 1.0})
+
+	result, err := df.Eval("profit = revenue - cost\nmargin = profit / revenue; `unit price` = round(`unit price` * 2)")
+	if err != nil {
+		t.Fatalf("Eval failed: %v", err)
+	}
+	if fmt.Sprint(result.columns) != "[revenue cost unit price profit margin]" {
+		t.Errorf("Unexpected columns: %v", result.columns)
+	}
+	if fmt.Sprint(result.data[0]) != "[100 60 5 40 0.4]" || fmt.Sprint(result.data[2]) != "[50 <nil> 2 <nil> <nil>]" {
+		t.Errorf("Unexpected rows: %v", result.data)
+	}
+	if df.data[0][2] != 2.5 {
+		t.Error("Expected the source frame to stay unchanged")
+	}
+
+	flags, err := df.EvalSeries("revenue > 60 and not (cost >= 90) or revenue ** 2 == 2500")
+	if err != nil {
+		t.Fatalf("EvalSeries failed: %v", err)
+	}
+	if fmt.Sprint(flags.data) != "[true false true]" {
+		t.Errorf("Unexpected flags: %v", flags.data)
+	}
+
+	quoted, err := df.Eval("label = 'a;b'\nnote = \"x\ny\"")
+	if err != nil || quoted.data[0][len(quoted.columns)-2] != "a;b" || quoted.data[0][len(quoted.columns)-1] != "x\ny" {
+		t.Errorf("Expected separators inside quotes to be kept: %v", err)
+	}
+	if _, err := df.Eval("revenue - cost"); err == nil {
+		t.Error("Expected an error for an expression without assignment")
+	}
+	if _, err := df.Eval("x = missing + 1"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+}
+
+func TestCaseWhen(t *testing.T) {
+	df := NewDataFrame([]string{"name", "salary"})
+	df.AddRow([]interface{}{"ann", 120000})
+	df.AddRow([]interface{}{"bob", 65000})
+	df.AddRow([]interface{}{"cy", 30000})
+	df.AddRow([]interface{}{"di", nil})
+
+	above := func(limit float64) func(Row) bool {
+		return func(r Row) bool {
+			salary, ok := r.Float("salary")
+			return ok && salary >= limit
+		}
+	}
+	bands := When(above(100000), "high").When(above(50000), "mid").Else("low")
+
+	result := df.Assign(map[string]func(Row) interface{}{"band": bands})
+	band, _ := result.GetColumn("band")
+	if fmt.Sprint(band.data) != "[high mid low low]" {
+		t.Errorf("Unexpected bands: %v", band.data)
+	}
+
+	if err := df.SetColumn("band", bands.Values(df)); err != nil {
+		t.Fatalf("SetColumn failed: %v", err)
+	}
+	if df.data[1][2] != "mid" {
+		t.Errorf("Unexpected rows: %v", df.data)
+	}
+}
+
+func TestAggIf(t *testing.T) {
+	df := NewDataFrame([]string{"region", "amount"})
+	df.AddRow([]interface{}{"east", 150.0})
+	df.AddRow([]interface{}{"west", 40.0})
+	df.AddRow([]interface{}{"east", 90.0})
+	df.AddRow([]interface{}{"east", 300.0})
+	df.AddRow([]interface{}{"west", 60.0})
+
+	g, err := df.GroupByColumns("region")
+	if err != nil {
+		t.Fatalf("GroupByColumns failed: %v", err)
+	}
+	large := func(r Row) bool {
+		amount, _ := r.Float("amount")
+		return amount > 100
+	}
+
+	sums, err := g.AggIf("amount", "sum", large)
+	if err != nil {
+		t.Fatalf("AggIf failed: %v", err)
+	}
+	if fmt.Sprint(sums.columns) != "[region amount_sum]" || fmt.Sprint(sums.data) != "[[east 450] [west <nil>]]" {
+		t.Errorf("Unexpected sums: %v %v", sums.columns, sums.data)
+	}
+	counts, _ := g.AggIf("amount", "count", large)
+	if fmt.Sprint(counts.data) != "[[east 2] [west 0]]" {
+		t.Errorf("Unexpected counts: %v", counts.data)
+	}
+	if _, err := g.Agg("amount", "bogus"); err == nil {
+		t.Error("Expected an error for an unknown aggregation")
+	}
+
+	amount, _ := df.GetColumn("amount")
+	mask, _ := df.EvalSeries("amount > 50")
+	sum, err := amount.SumWhere(mask)
+	if err != nil || sum != 600 {
+		t.Errorf("SumWhere = %v, %v", sum, err)
+	}
+	count, _ := amount.CountWhere(mask)
+	if count != 4 {
+		t.Errorf("CountWhere = %d", count)
+	}
+}
+
+func TestGroupedToDataFrame(t *testing.T) {
+	df := NewDataFrame([]string{"store", "year", "sales"})
+	df.AddRow([]interface{}{"b", 2024, 10})
+	df.AddRow([]interface{}{"a", 2024, 5})
+	df.AddRow([]interface{}{"b", 2023, 7})
+	df.AddRow([]interface{}{"b", 2024, 3})
+
+	g, err := df.GroupByColumns("store", "year")
+	if err != nil {
+		t.Fatalf("GroupByColumns failed: %v", err)
+	}
+
+	result, err := g.ToDataFrame(
+		Aggregation{Column: "sales", Func: "sum", As: "total"},
+		Aggregation{Column: "sales", Func: "count"},
+	)
+	if err != nil {
+		t.Fatalf("ToDataFrame failed: %v", err)
+	}
+	if fmt.Sprint(result.columns) != "[store year total sales_count]" {
+		t.Errorf("Unexpected columns: %v", result.columns)
+	}
+	if fmt.Sprint(result.data) != "[[b 2024 13 2] [a 2024 5 1] [b 2023 7 1]]" {
+		t.Errorf("Unexpected rows: %v", result.data)
+	}
+	if _, ok := result.data[0][1].(int); !ok {
+		t.Errorf("Expected int key, got %T", result.data[0][1])
+	}
+
+	sorted, _ := g.Sorted().ToDataFrame()
+	if fmt.Sprint(sorted.data) != "[[a 2024] [b 2023] [b 2024]]" {
+		t.Errorf("Unexpected sorted keys: %v", sorted.data)
+	}
+}
+
+func TestGroupedFirstLast(t *testing.T) {
+	df := NewDataFrame([]string{"user", "ts", "event"})
+	df.AddRow([]interface{}{"u1", 1, "login"})
+	df.AddRow([]interface{}{"u2", 2, "login"})
+	df.AddRow([]interface{}{"u1", 3, "view"})
+	df.AddRow([]interface{}{"u1", 4, "logout"})
+	df.AddRow([]interface{}{"u2", 5, nil})
+
+	g, _ := df.GroupByColumns("user")
+
+	if fmt.Sprint(g.First().data) != "[[u1 1 login] [u2 2 login]]" {
+		t.Errorf("Unexpected First: %v", g.First().data)
+	}
+	if fmt.Sprint(g.Last().data) != "[[u1 4 logout] [u2 5 <nil>]]" {
+		t.Errorf("Unexpected Last: %v", g.Last().data)
+	}
+	if fmt.Sprint(g.Nth(2).data) != "[[u1 4 logout]]" {
+		t.Errorf("Unexpected Nth: %v", g.Nth(2).data)
+	}
+	head := g.Head(1)
+	if fmt.Sprint(head.index) != "[0 1]" {
+		t.Errorf("Unexpected Head index: %v", head.index)
+	}
+	tail := g.Tail(2)
+	if fmt.Sprint(tail.index) != "[1 2 3 4]" {
+		t.Errorf("Unexpected Tail index: %v", tail.index)
+	}
+	if index := g.Head(-1).index; fmt.Sprint(index) != "[0 1 2]" {
+		t.Errorf("Expected Head(-1) to drop each group's last row, got %v", index)
+	}
+	if index := g.Tail(-1).index; fmt.Sprint(index) != "[2 3 4]" {
+		t.Errorf("Expected Tail(-1) to drop each group's first row, got %v", index)
+	}
+	if rows, _ := g.Head(-10).Shape(); rows != 0 {
+		t.Errorf("Expected Head(-10) to drop every row, got %d", rows)
+	}
+}
+
+func TestGroupedSizeNgroup(t *testing.T) {
+	df := NewDataFrame([]string{"team", "score"})
+	df.AddRow([]interface{}{"red", 1})
+	df.AddRow([]interface{}{"blue", 2})
+	df.AddRow([]interface{}{"red", 3})
+
+	g, _ := df.GroupByColumns("team")
+	size := g.Size()
+	if fmt.Sprint(size.data) != "[2 1]" || fmt.Sprint(size.index) != "[red blue]" {
+		t.Errorf("Unexpected Size: %v %v", size.data, size.index)
+	}
+	if fmt.Sprint(g.Ngroup().data) != "[0 1 0]" {
+		t.Errorf("Unexpected Ngroup: %v", g.Ngroup().data)
+	}
+	if fmt.Sprint(g.Sorted().Ngroup().data) != "[1 0 1]" {
+		t.Errorf("Unexpected sorted Ngroup: %v", g.Sorted().Ngroup().data)
+	}
+}
+
+func TestGroupAggParallel(t *testing.T) {
+	df := NewDataFrame([]string{"shard", "value"})
+	for i := 0; i < 1000; i++ {
+		var value interface{} = i
+		if i%7 == 0 {
+			value = nil
+		}
+		df.AddRow([]interface{}{fmt.Sprintf("s%d", i%5), value})
+	}
+	aggs := []Aggregation{
+		{Column: "value", Func: "sum"},
+		{Column: "value", Func: "count"},
+		{Column: "value", Func: "min"},
+		{Column: "value", Func: "max"},
+		{Column: "value", Func: "mean"},
+		{Column: "value", Func: "count", As: "odd", Where: func(r Row) bool {
+			v, ok := r.Get("value").(int)
+			return ok && v%2 == 1
+		}},
+	}
+
+	parallel, err := df.GroupAggParallel(4, []string{"shard"}, aggs...)
+	if err != nil {
+		t.Fatalf("GroupAggParallel failed: %v", err)
+	}
+	g, _ := df.GroupByColumns("shard")
+	serial, _ := g.ToDataFrame(aggs...)
+	if fmt.Sprint(parallel.columns) != fmt.Sprint(serial.columns) || fmt.Sprint(parallel.data) != fmt.Sprint(serial.data) {
+		t.Errorf("Parallel result differs:\n%v\n%v", parallel.data, serial.data)
+	}
+
+	if _, err := df.GroupAggParallel(2, []string{"shard"}, Aggregation{Column: "value", Func: "median"}); err == nil {
+		t.Error("Expected an error for a non-algebraic aggregation")
+	}
+}
+
+func TestSetSorted(t *testing.T) {
+	df := NewDataFrame([]string{"ts", "value"})
+	for i, ts := range []interface{}{nil, 1, 3, 3, 5, 8} {
+		df.AddRow([]interface{}{ts, i})
+	}
+	if err := df.SetSorted("value"); err != nil {
+		t.Fatalf("SetSorted failed: %v", err)
+	}
+	if err := df.SetSorted("ts"); err != nil {
+		t.Fatalf("SetSorted failed: %v", err)
+	}
+
+	fast, _ := df.FilterRange("ts", 2, 5.0)
+	df.sortedBy = ""
+	slow, _ := df.FilterRange("ts", 2, 5.0)
+	if fmt.Sprint(fast.data) != "[[3 2] [3 3] [5 4]]" || fmt.Sprint(fast.data) != fmt.Sprint(slow.data) {
+		t.Errorf("Unexpected range: %v vs %v", fast.data, slow.data)
+	}
+	open, _ := slow.FilterRange("ts", nil, 3)
+	if len(open.data) != 2 {
+		t.Errorf("Unexpected open range: %v", open.data)
+	}
+
+	sorted, _ := df.Sort("ts", true)
+	if sorted.SortedBy() != "ts" {
+		t.Error("Expected ascending Sort to mark the frame sorted")
+	}
+	g, _ := sorted.GroupByColumns("ts")
+	if fmt.Sprint(g.Size().data) != "[1 1 2 1 1]" {
+		t.Errorf("Unexpected run groups: %v", g.Size().data)
+	}
+	sorted.AddRow([]interface{}{9, 6})
+	if sorted.SortedBy() != "ts" {
+		t.Error("Expected an in-order append to keep the flag")
+	}
+	sorted.AddRow([]interface{}{2, 7})
+	if sorted.SortedBy() != "" {
+		t.Error("Expected an out-of-order append to clear the flag")
+	}
+
+	if err := df.SetSorted("missing"); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+	unsorted := NewDataFrame([]string{"x"})
+	unsorted.AddRow([]interface{}{2})
+	unsorted.AddRow([]interface{}{1})
+	if err := unsorted.SetSorted("x"); err == nil {
+		t.Error("Expected an error for an unsorted column")
+	}
+}
+
+func TestCreateIndex(t *testing.T) {
+	orders := NewDataFrame([]string{"order", "customer", "amount"})
+	orders.AddRow([]interface{}{1, "c1", 10})
+	orders.AddRow([]interface{}{2, "c2", 20})
+	orders.AddRow([]interface{}{3, "c1", 30})
+	orders.AddRow([]interface{}{4, "c9", 40})
+
+	if err := orders.CreateIndex("customer"); err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+	orders.AddRow([]interface{}{5, "c2", 50})
+
+	c1, _ := orders.Loc("customer", "c1")
+	if fmt.Sprint(c1.index) != "[0 2]" {
+		t.Errorf("Unexpected Loc rows: %v", c1.data)
+	}
+	in, _ := orders.IsIn("customer", []interface{}{"c2", "c1"})
+	if fmt.Sprint(in.index) != "[0 1 2 4]" {
+		t.Errorf("Unexpected IsIn rows: %v", in.index)
+	}
+	byOrder, _ := orders.Loc("order", 3.0)
+	if len(byOrder.data) != 1 {
+		t.Errorf("Expected numeric lookup to match across int and float: %v", byOrder.data)
+	}
+
+	orders.SetColumn("customer", []interface{}{"c1", "c1", "c1", "c1", "c1"})
+	if orders.HasIndex("customer") {
+		t.Error("Expected SetColumn to drop the index")
+	}
+	orders.SetColumn("customer", []interface{}{"c1", "c2", "c1", "c9", "c2"})
+
+	derived := orders.Filter(func([]interface{}) bool { return true })
+	derived.SetColumn("order", []interface{}{9, 9, 9, 9, 9})
+	orders.CreateIndex("order")
+	derived.SetColumn("customer", []interface{}{"x", "x", "x", "x", "x"})
+	if hit, _ := orders.Loc("order", 1); len(hit.data) != 1 || hit.data[0][1] != "c1" {
+		t.Errorf("Expected writes to a derived frame to leave the parent intact, got %v", hit.data)
+	}
+
+	spare := NewDataFrame([]string{"id"})
+	for i := 0; i < 3; i++ {
+		row := make([]interface{}, 1, 4)
+		row[0] = i
+		spare.AddRow(row)
+	}
+	all := func([]interface{}) bool { return true }
+	first, second := spare.Filter(all), spare.Filter(all)
+	first.SetColumn("tag", []interface{}{"l", "l", "l"})
+	second.SetColumn("tag", []interface{}{"r", "r", "r"})
+	if fmt.Sprint(first.data, second.data) != "[[0 l] [1 l] [2 l]] [[0 r] [1 r] [2 r]]" {
+		t.Errorf("Expected a new column on one derived frame to leave its sibling intact, got %v %v", first.data, second.data)
+	}
+
+	customers := NewDataFrame([]string{"customer", "name", "amount"})
+	customers.AddRow([]interface{}{"c1", "Ann", 0})
+	customers.AddRow([]interface{}{"c2", "Bob", 0})
+	customers.CreateIndex("customer")
+
+	inner, err := orders.Join(customers, "customer", "inner")
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if fmt.Sprint(inner.columns) != "[order customer amount name amount_right]" || len(inner.data) != 4 {
+		t.Errorf("Unexpected inner join: %v %v", inner.columns, inner.data)
+	}
+	left, _ := orders.Join(customers, "customer", "left")
+	if fmt.Sprint(left.data[3]) != "[4 c9 40 <nil> <nil>]" {
+		t.Errorf("Unexpected left join row: %v", left.data[3])
+	}
+	anti, _ := orders.Join(customers, "customer", "anti")
+	if fmt.Sprint(anti.data) != "[[4 c9 40]]" {
+		t.Errorf("Unexpected anti join: %v", anti.data)
+	}
+	if _, err := orders.Join(customers, "customer", "outer"); err == nil {
+		t.Error("Expected an error for an unsupported join type")
+	}
+}
+
+func TestMmap(t *testing.T) {
+	dir := t.TempDir()
+	when := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	df := NewDataFrame([]string{"id", "price", "name", "seen"})
+	df.AddRow([]interface{}{1, 2.5, "apple", when})
+	df.AddRow([]interface{}{2, nil, "", nil})
+	df.AddRow([]interface{}{-3, 4, nil, when.Add(time.Hour)})
+
+	if err := df.WriteMmap(dir); err != nil {
+		t.Fatalf("WriteMmap failed: %v", err)
+	}
+	frame, err := OpenMmap(dir)
+	if err != nil {
+		t.Fatalf("OpenMmap failed: %v", err)
+	}
+	defer frame.Close()
+
+	price, _ := frame.Column("price")
+	sum, err := price.Sum()
+	if err != nil || sum != 6.5 {
+		t.Errorf("Sum = %v, %v", sum, err)
+	}
+	if !price.IsNull(1) {
+		t.Error("Expected row 1 price to be null")
+	}
+
+	loaded, err := frame.ToDataFrame()
+	if err != nil {
+		t.Fatalf("ToDataFrame failed: %v", err)
+	}
+	if fmt.Sprint(loaded.data) != fmt.Sprint([][]interface{}{
+		{1, 2.5, "apple", when}, {2, nil, "", nil}, {-3, 4.0, nil, when.Add(time.Hour)},
+	}) {
+		t.Errorf("Unexpected round trip: %v", loaded.data)
+	}
+
+	header := make([]byte, mmapHeaderSize+8)
+	copy(header, mmapMagic)
+	header[8] = mmapString
+	binary.LittleEndian.PutUint64(header[16:], math.MaxUint64)
+	if _, err := newMappedColumn("bad", header); err == nil {
+		t.Error("Expected a row count larger than the file to be rejected")
+	}
+
+	frame.Close()
+	if _, err := price.Value(0); err == nil {
+		t.Error("Expected Value to fail after Close")
+	}
+	if _, err := price.Sum(); err == nil {
+		t.Error("Expected Sum to fail after Close")
+	}
+	if _, ok := price.Float(0); ok {
+		t.Error("Expected Float to report no value after Close")
+	}
+
+	data, _ := df.MarshalBinary()
+	path := filepath.Join(dir, "frame.gpd")
+	os.WriteFile(path, data, 0o644)
+	decoded, err := ReadBinaryFile(path)
+	if err != nil || len(decoded.data) != 3 || decoded.data[0][2] != "apple" {
+		t.Errorf("ReadBinaryFile = %v, %v", decoded, err)
+	}
+}
+
+func TestIngestRowBuilder(t *testing.T) {
+	cells := []string{"42", "-7", "3.5", "inf", "nan", "true", "F", "text", "", " 12 ", "1e3", "Nancy", "info", "+8"}
+	builder := newRowBuilder(len(cells))
+	for round := 0; round < 2; round++ {
+		row := builder.build(cells, len(cells)+1)
+		for j, cell := range cells {
+			want, got := inferType(cell), row[j]
+			if fmt.Sprintf("%T %v", want, want) != fmt.Sprintf("%T %v", got, got) {
+				t.Errorf("Cell %q: got %T %v, want %T %v", cell, got, got, want, want)
+			}
+		}
+		if row[len(cells)] != nil {
+			t.Error("Expected missing cells to be nil")
+		}
+	}
+	if inferType("Nancy") != "Nancy" || inferType("inf") != math.Inf(1) || inferType("1") != 1 {
+		t.Error("Unexpected inferType results")
+	}
+
+	arena := rowArena{}
+	a, b := arena.alloc(3), arena.alloc(3)
+	a = append(a, "extra")
+	if b[0] != nil {
+		t.Error("Expected arena rows not to overlap on append")
+	}
+}
+
+func TestKernels(t *testing.T) {
+	xs := []float64{3, -1, 4, 1, 5, 9, 2}
+	if sumFloat64(xs) != 23 || sumFloat64(nil) != 0 {
+		t.Errorf("Unexpected sum: %v", sumFloat64(xs))
+	}
+	if lo, hi := minMaxFloat64(xs); lo != -1 || hi != 9 {
+		t.Errorf("Unexpected min/max: %v %v", lo, hi)
+	}
+	mask := make([]bool, len(xs))
+	maskFloat64(xs, ">=", 4, mask)
+	if fmt.Sprint(mask) != "[false false true false true true false]" {
+		t.Errorf("Unexpected mask: %v", mask)
+	}
+	if err := maskFloat64(xs, "~", 4, mask); err == nil {
+		t.Error("Expected an error for an unknown comparison")
+	}
+
+	df := NewDataFrame([]string{"v"})
+	for i := 0; i < 10000; i++ {
+		var v interface{} = float64(i % 100)
+		if i%10 == 0 {
+			v = nil
+		}
+		df.AddRow([]interface{}{v})
+	}
+	dir := t.TempDir()
+	df.WriteMmap(dir)
+	frame, _ := OpenMmap(dir)
+	defer frame.Close()
+	col, _ := frame.Column("v")
+
+	lo, hi, ok, err := col.MinMax()
+	if err != nil || !ok || lo != 1 || hi != 99 {
+		t.Errorf("MinMax = %v %v %v %v", lo, hi, ok, err)
+	}
+	over, _ := col.Mask(">", 95)
+	count := 0
+	for _, b := range over {
+		if b {
+			count++
+		}
+	}
+	if count != 400 || over[0] {
+		t.Errorf("Unexpected mask count %d", count)
+	}
+}
+
+func benchmarkValues() []float64 {
+	xs := make([]float64, 1<<16)
+	for i := range xs {
+		xs[i] = float64(i%1000) * 0.5
+	}
+	return xs
+}
+
+func BenchmarkSumKernel(b *testing.B) {
+	xs := benchmarkValues()
+	for i := 0; i < b.N; i++ {
+		sumFloat64(xs)
+	}
+}
+
+func BenchmarkSumNaive(b *testing.B) {
+	xs := benchmarkValues()
+	for i := 0; i < b.N; i++ {
+		var sum float64
+		for _, x := range xs {
+			sum += x
+		}
+		_ = sum
+	}
+}
+
+func BenchmarkSumInterface(b *testing.B) {
+	xs := benchmarkValues()
+	values := make([]interface{}, len(xs))
+	for i, x := range xs {
+		values[i] = x
+	}
+	for i := 0; i < b.N; i++ {
+		aggregateValues("sum", values)
+	}
+}
+
+func BenchmarkMinMaxKernel(b *testing.B) {
+	xs := benchmarkValues()
+	for i := 0; i < b.N; i++ {
+		minMaxFloat64(xs)
+	}
+}
+
+func BenchmarkMaskKernel(b *testing.B) {
+	xs := benchmarkValues()
+	mask := make([]bool, len(xs))
+	for i := 0; i < b.N; i++ {
+		maskFloat64(xs, ">", 250, mask)
+	}
+}
+
+func BenchmarkMaskNaive(b *testing.B) {
+	xs := benchmarkValues()
+	mask := make([]bool, len(xs))
+	values := make([]interface{}, len(xs))
+	for i, x := range xs {
+		values[i] = x
+	}
+	for i := 0; i < b.N; i++ {
+		for j, val := range values {
+			mask[j] = compareExprValues(val, 250) > 0
+		}
+	}
+}
+
+func TestRegisterAggregation(t *testing.T) {
+	err := RegisterAggregation("p90", func(values []interface{}) (interface{}, error) {
+		numbers := numericValues(values)
+		if len(numbers) == 0 {
+			return nil, nil
+		}
+		sort.Float64s(numbers)
+		return numbers[int(0.9*float64(len(numbers)-1))], nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterAggregation failed: %v", err)
+	}
+	if err := RegisterAggregation("sum", func([]interface{}) (interface{}, error) { return 0, nil }); err == nil {
+		t.Error("Expected a built-in name to be rejected")
+	}
+	if err := RegisterAggregation("p50", nil); err == nil {
+		t.Error("Expected a nil function to be rejected")
+	}
+	defer func() {
+		aggregationsMu.Lock()
+		delete(aggregations, "p90")
+		aggregationsMu.Unlock()
+	}()
+
+	df := NewDataFrame([]string{"k", "v"})
+	for i := 1; i <= 20; i++ {
+		df.AddRow([]interface{}{i % 2, i})
+	}
+	g, _ := df.GroupByColumns("k")
+	result, err := g.Agg("v", "p90")
+	if err != nil {
+		t.Fatalf("Agg failed: %v", err)
+	}
+	if fmt.Sprint(result.data) != "[[1 17] [0 18]]" {
+		t.Errorf("Unexpected p90: %v", result.data)
+	}
+	if !strings.Contains(strings.Join(Aggregations(), " "), "p90") {
+		t.Errorf("Expected p90 in %v", Aggregations())
+	}
+	if _, err := df.GroupAggParallel(2, []string{"k"}, Aggregation{Column: "v", Func: "p90"}); err == nil {
+		t.Error("Expected registered aggregations to be rejected by the parallel path")
+	}
+}
+
+func TestReadAuto(t *testing.T) {
+	RegisterFormat("pipe", func(r io.Reader) (*DataFrame, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(string(data), "#PIPE\n")), "\n")
+		df := NewDataFrame(strings.Split(lines[0], "|"))
+		for _, line := range lines[1:] {
+			cells := strings.Split(line, "|")
+			row := make([]interface{}, len(cells))
+			for j, cell := range cells {
+				row[j] = inferType(cell)
+			}
+			df.AddRow(row)
+		}
+		return df, nil
+	}, func(w io.Writer, df *DataFrame) error {
+		fmt.Fprintln(w, "#PIPE")
+		fmt.Fprintln(w, strings.Join(df.columns, "|"))
+		for _, row := range df.data {
+			cells := make([]string, len(row))
+			for j, val := range row {
+				cells[j] = fmt.Sprint(val)
+			}
+			fmt.Fprintln(w, strings.Join(cells, "|"))
+		}
+		return nil
+	}, []byte("#PIPE"))
+	// registered later with the same magic, so it must never be sniffed
+	RegisterFormat("pipe2", func(io.Reader) (*DataFrame, error) {
+		return nil, fmt.Errorf("wrong format")
+	}, nil, []byte("#PIPE"))
+	defer func() {
+		formatsMu.Lock()
+		delete(formats, "pipe")
+		delete(formats, "pipe2")
+		formatsMu.Unlock()
+	}()
+
+	dir := t.TempDir()
+	df := NewDataFrame([]string{"id", "name"})
+	df.AddRow([]interface{}{1, "ann"})
+	df.AddRow([]interface{}{2, "bob"})
+
+	for _, name := range []string{"out.pipe", "out.tsv", "out.gpd", "out.csv"} {
+		path := filepath.Join(dir, name)
+		if err := df.WriteAuto(path); err != nil {
+			t.Fatalf("WriteAuto(%s) failed: %v", name, err)
+		}
+		back, err := ReadAuto(path)
+		if err != nil {
+			t.Fatalf("ReadAuto(%s) failed: %v", name, err)
+		}
+		if fmt.Sprint(back.columns, back.data) != "[id name] [[1 ann] [2 bob]]" {
+			t.Errorf("%s: unexpected frame %v %v", name, back.columns, back.data)
+		}
+	}
+
+	// no usable extension: recognised from the leading bytes
+	for _, pair := range [][2]string{{"out.pipe", "sniffed.dat"}, {"out.gpd", "binary.dat"}, {"out.tsv", "plain.dat"}} {
+		data, _ := os.ReadFile(filepath.Join(dir, pair[0]))
+		path := filepath.Join(dir, pair[1])
+		os.WriteFile(path, data, 0o644)
+		back, err := ReadAuto(path)
+		if err != nil || len(back.data) != 2 || back.data[1][1] != "bob" {
+			t.Errorf("ReadAuto(%s) = %v, %v", pair[1], back, err)
+		}
+	}
+
+	if err := df.WriteAuto(filepath.Join(dir, "out.unknown")); err == nil {
+		t.Error("Expected an error for a format without a writer")
+	}
+
+	legacy := buildTestCompoundFile(map[string][]byte{"Workbook": {0x09, 0x08, 0x10, 0x00}})
+	encrypted := buildTestCompoundFile(map[string][]byte{"EncryptionInfo": {4, 0}, "EncryptedPackage": {0}})
+	if kind, err := sniffCompoundFormat(legacy); kind != "xls" || err != nil {
+		t.Errorf("Expected a BIFF workbook to be sniffed as xls, got %q %v", kind, err)
+	}
+	if kind, err := sniffCompoundFormat(encrypted); kind != "xlsx" || err != nil {
+		t.Errorf("Expected an encrypted package to be sniffed as xlsx, got %q %v", kind, err)
+	}
+	path := filepath.Join(dir, "locked.dat")
+	os.WriteFile(path, encrypted, 0o644)
+	if _, err := ReadAuto(path); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("Expected the encrypted workbook error, got %v", err)
+	}
+}
+
+func TestRegisterFunc(t *testing.T) {
+	RegisterFunc("mask_email", func(args ...interface{}) (interface{}, error) {
+		email, ok := args[0].(string)
+		if !ok {
+			return nil, nil
+		}
+		user, domain, found := strings.Cut(email, "@")
+		if !found {
+			return nil, fmt.Errorf("not an email: %q", email)
+		}
+		return user[:1] + "***@" + domain, nil
+	})
+	defer func() {
+		exprFuncsMu.Lock()
+		delete(exprFuncs, "mask_email")
+		exprFuncsMu.Unlock()
+	}()
+
+	df := NewDataFrame([]string{"email", "amount"})
+	df.AddRow([]interface{}{"ann@example.com", 150})
+	df.AddRow([]interface{}{"bob@test.org", 40})
+	df.AddRow([]interface{}{nil, 500})
+
+	masked, err := df.Eval("email = MASK_EMAIL(email)")
+	if err != nil {
+		t.Fatalf("Eval failed: %v", err)
+	}
+	if fmt.Sprint(masked.data) != "[[a***@example.com 150] [b***@test.org 40] [<nil> 500]]" {
+		t.Errorf("Unexpected masked rows: %v", masked.data)
+	}
+
+	big, err := df.Query("amount > 100 and mask_email(email) == 'a***@example.com'")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(big.data) != 1 || big.index[0] != 0 {
+		t.Errorf("Unexpected query rows: %v", big.data)
+	}
+	if _, err := df.Query("amount + 1"); err == nil {
+		t.Error("Expected an error for a non-boolean query")
+	}
+
+	bad := NewDataFrame([]string{"email"})
+	bad.AddRow([]interface{}{"nope"})
+	if _, err := bad.Eval("x = mask_email(email)"); err == nil || !strings.Contains(err.Error(), "row 0") {
+		t.Errorf("Expected a row error, got %v", err)
+	}
+}
+
+func TestWithColumnParser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "odd.csv")
+	os.WriteFile(path, []byte("ts,price,meta\n1700000000000,\"1,234원\",\"{\"\"a\"\":1}\"\n1700000001500,500원,{}\n"), 0o644)
+
+	epochMillis := func(s string) (interface{}, error) {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	won := func(s string) (interface{}, error) {
+		return strconv.Atoi(strings.ReplaceAll(strings.TrimSuffix(s, "원"), ",", ""))
+	}
+
+	df, err := ReadCSV(path, WithColumnParser("ts", epochMillis), WithColumnParser("price", won))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if ts, ok := df.data[1][0].(time.Time); !ok || ts.UnixMilli() != 1700000001500 {
+		t.Errorf("Unexpected ts: %v", df.data[1][0])
+	}
+	if df.data[0][1] != 1234 || df.data[1][1] != 500 || df.data[0][2] != `{"a":1}` {
+		t.Errorf("Unexpected rows: %v", df.data)
+	}
+
+	_, err = ReadCSV(path, WithColumnParser("meta", func(s string) (interface{}, error) {
+		var v map[string]int
+		return v, json.Unmarshal([]byte(s), &v)
+	}), WithColumnParser("price", epochMillis))
+	if err == nil || !strings.Contains(err.Error(), "row 0, column 'price'") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+	if _, err := ReadCSV(path, WithColumnParser("missing", won)); err == nil {
+		t.Error("Expected an error for a parser on a missing column")
+	}
+}
+
+func TestDuplicateColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dups.csv")
+	os.WriteFile(path, []byte("id,amount,amount,amount.1,id\n1,10,20,30,x\n"), 0o644)
+
+	kept, err := ReadCSV(path)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	second, _ := kept.ColumnAt(2)
+	if second.name != "amount" || second.data[0] != 20 {
+		t.Errorf("Unexpected positional column: %v %v", second.name, second.data)
+	}
+	if _, err := kept.ColumnAt(5); err == nil {
+		t.Error("Expected an out of range error")
+	}
+
+	mangled, err := ReadCSV(path, WithDuplicateColumns(MangleDuplicates))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if fmt.Sprint(mangled.columns) != "[id amount amount.2 amount.1 id.1]" {
+		t.Errorf("Unexpected mangled columns: %v", mangled.columns)
+	}
+
+	if _, err := ReadCSV(path, WithDuplicateColumns(ErrorOnDuplicates)); err == nil || !strings.Contains(err.Error(), "duplicate column 'amount'") {
+		t.Errorf("Expected a duplicate error, got %v", err)
+	}
+
+	sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>x</t></is></c><c r="B1" t="inlineStr"><is><t>x</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="B2"><v>2</v></c></row>
+</sheetData></worksheet>`
+	xlsx := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheet})
+	defer os.Remove(xlsx)
+
+	df, err := ReadExcel(xlsx, WithExcelDuplicateColumns(MangleDuplicates))
+	if err != nil || fmt.Sprint(df.columns) != "[x x.1]" {
+		t.Errorf("Unexpected Excel columns: %v, %v", df, err)
+	}
+	if _, err := ReadExcel(xlsx, WithExcelDuplicateColumns(ErrorOnDuplicates)); err == nil {
+		t.Error("Expected a duplicate error from ReadExcel")
+	}
+}
+
+func TestCleanColumnNames(t *testing.T) {
+	df := NewDataFrame([]string{"\ufeffCustomer ID", " Total Sales ($) ", "total-sales", "%%", "Région"})
+	df.AddRow([]interface{}{1, 2, 3, 4, 5})
+	df.SetColumnMeta(" Total Sales ($) ", "unit", "USD")
+
+	result := df.CleanColumnNames(CleanNamesOptions{})
+	if fmt.Sprint(result.columns) != "[customer_id total_sales total_sales_1 col_3 région]" {
+		t.Errorf("Unexpected names: %q", result.columns)
+	}
+	if unit, _ := result.GetColumnMeta("total_sales", "unit"); unit != "USD" {
+		t.Errorf("Expected metadata to follow the rename, got %v", unit)
+	}
+	if df.columns[1] != " Total Sales ($) " {
+		t.Error("Expected the source frame to keep its names")
+	}
+
+	kept := df.CleanColumnNames(CleanNamesOptions{KeepCase: true, Separator: "-"})
+	if kept.columns[0] != "Customer-ID" {
+		t.Errorf("Unexpected names: %q", kept.columns)
+	}
+}
+
+func TestWithHeaderRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "two_row.csv")
+	os.WriteFile(path, []byte("region,2023,,2024,\n,Q1,Q2,Q1,Q2\nEU,1,2,3,4\n"), 0o644)
+
+	df, err := ReadCSV(path, WithHeaderRows(2, "_"))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if fmt.Sprint(df.columns) != "[region 2023_Q1 2023_Q2 2024_Q1 2024_Q2]" {
+		t.Errorf("Unexpected columns: %v", df.columns)
+	}
+	if len(df.data) != 1 || df.data[0][4] != 4 {
+		t.Errorf("Unexpected rows: %v", df.data)
+	}
+
+	sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Sales report</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>store</t></is></c><c r="B2" t="inlineStr"><is><t>Revenue</t></is></c></row>
+<row r="3"><c r="B3" t="inlineStr"><is><t>gross</t></is></c><c r="C3" t="inlineStr"><is><t>net</t></is></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>s1</t></is></c><c r="B4"><v>10</v></c><c r="C4"><v>8</v></c></row>
+</sheetData></worksheet>`
+	xlsx := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheet})
+	defer os.Remove(xlsx)
+
+	book, err := ReadExcel(xlsx, WithHeaderRow(1), WithExcelHeaderRows(2, " "))
+	if err != nil {
+		t.Fatalf("ReadExcel failed: %v", err)
+	}
+	if fmt.Sprint(book.columns) != "[store Revenue gross Revenue net]" {
+		t.Errorf("Unexpected Excel columns: %q", book.columns)
+	}
+	if len(book.data) != 1 || book.data[0][2] != 8 {
+		t.Errorf("Unexpected Excel rows: %v", book.data)
+	}
+}
+
+func TestSkipFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	os.WriteFile(path, []byte("store,sales\ns1,10\ns2,20\n,\nTotal,30\nExported 2024-01-01,\n"), 0o644)
+
+	df, err := ReadCSV(path, WithSkipFooter(1), WithAutoFooter())
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(df.data) != 2 {
+		t.Fatalf("Expected 2 rows, got %v", df.data)
+	}
+	sales, _ := df.GetColumn("sales")
+	if sum, _ := sales.Sum(); fmt.Sprint(sum) != "30" {
+		t.Errorf("Footer leaked into sum: %v", sum)
+	}
+
+	counted, _ := ReadCSV(path, WithSkipFooter(3))
+	if len(counted.data) != 2 {
+		t.Errorf("Expected 2 rows, got %v", counted.data)
+	}
+
+	sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>store</t></is></c><c r="B1" t="inlineStr"><is><t>sales</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>s1</t></is></c><c r="B2"><v>10</v></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>Subtotal</t></is></c><c r="B3"><v>10</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>s2</t></is></c><c r="B4"><v>20</v></c></row>
+<row r="5"><c r="A5" t="inlineStr"><is><t>Grand Total</t></is></c><c r="B5"><v>30</v></c></row>
+</sheetData></worksheet>`
+	xlsx := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheet})
+	defer os.Remove(xlsx)
+
+	book, err := ReadExcel(xlsx, WithExcelAutoFooter())
+	if err != nil {
+		t.Fatalf("ReadExcel failed: %v", err)
+	}
+	if len(book.data) != 3 || book.data[2][0] != "s2" {
+		t.Errorf("Unexpected Excel rows: %v", book.data)
+	}
+
+	rows := 0
+	err = ReadExcelChunks(xlsx, 1, func(chunk *DataFrame) error {
+		rows += len(chunk.data)
+		return nil
+	}, WithExcelAutoFooter())
+	if err != nil || rows != 3 {
+		t.Errorf("Streaming kept %d rows: %v", rows, err)
+	}
+}
+
+func reportTemplateParts() map[string]string {
+	return map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/calcChain.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml"/></Types>`,
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Data" sheetId="2" r:id="rId2"/></sheets><calcPr calcId="191029"/></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>` +
+			`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/calcChain" Target="calcChain.xml"/></Relationships>`,
+		"xl/calcChain.xml": `<calcChain><c r="B1" i="1"/></calcChain>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Total</t></is></c>` +
+			`<c r="B1"><f>SUM(Data!C3:C10)</f><v>0</v></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><dimension ref="A1"/><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Report</t></is></c></row>` +
+			`<row r="3" spans="2:3" ht="20"><c r="B3" s="4"><v>1</v></c><c r="C3" s="7"><v>2</v></c></row></sheetData><pageMargins left="0.7"/></worksheet>`,
+	}
+}
+
+func TestToExcelAppend(t *testing.T) {
+	path := writeTestXLSX(t, reportTemplateParts())
+	defer os.Remove(path)
+
+	df := NewDataFrame([]string{"store", "sales"})
+	df.AddRow([]interface{}{"s1", 10})
+	df.AddRow([]interface{}{"s<2>", 2.5})
+	if err := df.ToExcelAppend(path, "data", "B2"); err != nil {
+		t.Fatalf("ToExcelAppend failed: %v", err)
+	}
+
+	got, err := ReadExcel(path, WithSheet("Data"), WithRange("B2:C4"))
+	if err != nil {
+		t.Fatalf("ReadExcel failed: %v", err)
+	}
+	if fmt.Sprint(got.columns) != "[store sales]" || fmt.Sprint(got.data) != "[[s1 10] [s<2> 2.5]]" {
+		t.Errorf("Unexpected region: %v %v", got.columns, got.data)
+	}
+
+	reader, _ := zip.OpenReader(path)
+	parts := make(map[string]string)
+	for _, file := range reader.File {
+		rc, _ := file.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[file.Name] = string(data)
+	}
+	reader.Close()
+
+	sheet := parts["xl/worksheets/sheet2.xml"]
+	for _, want := range []string{`<c r="A1" t="inlineStr"><is><t>Report</t></is></c>`, `<c r="B3" s="4" t="inlineStr">`, `<c r="C3" s="7"><v>10</v></c>`,
+		`<row r="3" ht="20">`, `<dimension ref="A1:C4"/>`, `<pageMargins left="0.7"/>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Sheet is missing %s:\n%s", want, sheet)
+		}
+	}
+	if parts["xl/worksheets/sheet1.xml"] != reportTemplateParts()["xl/worksheets/sheet1.xml"] {
+		t.Errorf("Other sheet changed: %s", parts["xl/worksheets/sheet1.xml"])
+	}
+	if _, ok := parts["xl/calcChain.xml"]; ok || strings.Contains(parts["xl/_rels/workbook.xml.rels"], "calcChain") {
+		t.Error("Expected calcChain to be dropped")
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<calcPr fullCalcOnLoad="1" calcId="191029"/>`) {
+		t.Errorf("Expected full recalculation: %s", parts["xl/workbook.xml"])
+	}
+
+	if err := df.ToExcelAppend(path, "Extra", ""); err != nil {
+		t.Fatalf("ToExcelAppend to a new sheet failed: %v", err)
+	}
+	extra, err := ReadExcel(path, WithSheet("Extra"))
+	if err != nil || len(extra.data) != 2 {
+		t.Errorf("Unexpected new sheet: %v %v", extra, err)
+	}
+
+	fresh := filepath.Join(t.TempDir(), "new.xlsx")
+	if err := df.ToExcelAppend(fresh, "", "A1"); err != nil {
+		t.Fatalf("ToExcelAppend to a new file failed: %v", err)
+	}
+	created, err := ReadExcel(fresh)
+	if err != nil || fmt.Sprint(created.data) != "[[s1 10] [s<2> 2.5]]" {
+		t.Errorf("Unexpected new workbook: %v %v", created, err)
+	}
+
+	parts = reportTemplateParts()
+	parts["xl/worksheets/sheet2.xml"] = `<worksheet><sheetData>` +
+		`<row r="1"><c r="A1"><v>1</v></c><c r="B1"><f t="shared" ref="B1:B3" si="0">A1*2+$A$1&amp;"A1"</f><v>3</v></c></row>` +
+		`<row r="2"><c r="A2"><v>2</v></c><c r="B2"><f t="shared" si="0"/><v>5</v></c></row>` +
+		`<row r="3"><c r="A3"><v>3</v></c><c r="B3"><f t="shared" si="0"/><v>7</v></c></row></sheetData></worksheet>`
+	shared := writeTestXLSX(t, parts)
+	defer os.Remove(shared)
+	if err := NewDataFrame([]string{"label"}).ToExcelAppend(shared, "Data", "B1"); err != nil {
+		t.Fatalf("ToExcelAppend over a shared formula failed: %v", err)
+	}
+	reader, _ = zip.OpenReader(shared)
+	for _, file := range reader.File {
+		if file.Name == "xl/worksheets/sheet2.xml" {
+			rc, _ := file.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			sheet = string(data)
+		}
+	}
+	reader.Close()
+	for _, want := range []string{`<c r="B2"><f>A2*2+$A$1&amp;&#34;A1&#34;</f><v>5</v></c>`, `<c r="B3"><f>A3*2+$A$1&amp;&#34;A1&#34;</f><v>7</v></c>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Expected shared formula to be expanded to %s:\n%s", want, sheet)
+		}
+	}
+	if got := shiftFormula(`SUM(B$2:$C3)+LOG10(A1)+'My Sheet'!D4`, 1, 2); got != `SUM(C$2:$C5)+LOG10(B3)+'My Sheet'!E6` {
+		t.Errorf("Unexpected shifted formula: %s", got)
+	}
+}
+
+func TestWriteToNamedRange(t *testing.T) {
+	parts := reportTemplateParts()
+	parts["xl/workbook.xml"] = strings.Replace(parts["xl/workbook.xml"], "</sheets>",
+		`</sheets><definedNames><definedName name="SalesData" localSheetId="0">Summary!$H$1:$I$1</definedName>`+
+			`<definedName name="SalesData">Data!$B$3:$C$3</definedName></definedNames>`, 1)
+	parts["xl/worksheets/_rels/sheet1.xml.rels"] = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/table" Target="../tables/table1.xml"/></Relationships>`
+	parts["xl/tables/table1.xml"] = `<table xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" id="1" name="Table1" displayName="Targets" ref="D1:F4" totalsRowCount="1">` +
+		`<autoFilter ref="D1:F3"/><tableColumns count="3"><tableColumn id="1" name="Store"/><tableColumn id="2" name="Target"/>` +
+		`<tableColumn id="3" name="Double"><calculatedColumnFormula>Targets[[#This Row],[Target]]*2</calculatedColumnFormula></tableColumn></tableColumns></table>`
+	parts["xl/worksheets/sheet1.xml"] = `<worksheet><sheetData>` +
+		`<row r="1"><c r="D1" t="inlineStr"><is><t>Store</t></is></c><c r="E1" t="inlineStr"><is><t>Target</t></is></c><c r="F1" t="inlineStr"><is><t>Double</t></is></c></row>` +
+		`<row r="2"><c r="D2" s="2" t="inlineStr"><is><t>old</t></is></c><c r="E2" s="3"><v>1</v></c><c r="F2" s="3"><f>Targets[[#This Row],[Target]]*2</f></c></row>` +
+		`<row r="3"><c r="D3" s="2" t="inlineStr"><is><t>old</t></is></c><c r="E3" s="3"><v>2</v></c><c r="F3" s="3"><f>Targets[[#This Row],[Target]]*2</f></c></row>` +
+		`<row r="4"><c r="D4" t="inlineStr"><is><t>Total</t></is></c><c r="E4" s="5"><f>SUBTOTAL(109,[Target])</f></c></row></sheetData></worksheet>`
+	path := writeTestXLSX(t, parts)
+	defer os.Remove(path)
+
+	sales := NewDataFrame([]string{"store", "sales"})
+	sales.AddRow([]interface{}{"s1", 10})
+	sales.AddRow([]interface{}{"s2", 20})
+	sales.AddRow([]interface{}{"s3", 30})
+	if err := WriteToNamedRange(path, "SalesData", sales); err != nil {
+		t.Fatalf("WriteToNamedRange failed: %v", err)
+	}
+	got, err := ReadExcel(path, WithSheet("Data"), WithRange("B3:C5"), WithHeaderRow(-1))
+	if err != nil || fmt.Sprint(got.data) != "[[s1 10] [s2 20] [s3 30]]" {
+		t.Errorf("Unexpected named range: %v %v", got, err)
+	}
+
+	targets := NewDataFrame([]string{"target", "store"})
+	targets.AddRow([]interface{}{100, "s1"})
+	if err := WriteToNamedRange(path, "Targets", targets); err != nil {
+		t.Fatalf("WriteToNamedRange on a table failed: %v", err)
+	}
+
+	reader, _ := zip.OpenReader(path)
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, _ := file.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(data)
+	}
+	reader.Close()
+
+	if !strings.Contains(files["xl/workbook.xml"], `<definedName name="SalesData">Data!$B$3:$C$5</definedName>`) ||
+		!strings.Contains(files["xl/workbook.xml"], `<definedName name="SalesData" localSheetId="0">Summary!$H$1:$I$1</definedName>`) {
+		t.Errorf("Defined name not resized: %s", files["xl/workbook.xml"])
+	}
+	if !strings.Contains(files["xl/worksheets/sheet2.xml"], `<c r="C5" s="7"><v>30</v></c>`) {
+		t.Errorf("Added row did not take the template style: %s", files["xl/worksheets/sheet2.xml"])
+	}
+	table := files["xl/tables/table1.xml"]
+	if !strings.Contains(table, `ref="D1:F3"`) || !strings.Contains(table, `<autoFilter ref="D1:F2"/>`) {
+		t.Errorf("Table not resized: %s", table)
+	}
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{`<c r="D2" s="2" t="inlineStr"><is><t>s1</t></is></c>`, `<c r="E2" s="3"><v>100</v></c>`,
+		`<c r="F2" s="3"><f>Targets[[#This Row],[Target]]*2</f></c>`, `<c r="D3" t="inlineStr"><is><t>Total</t></is></c>`,
+		`<c r="E3" s="5"><f>SUBTOTAL(109,[Target])</f></c>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Sheet is missing %s:\n%s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, `r="D4"`) {
+		t.Errorf("Old totals row was left behind:\n%s", sheet)
+	}
+
+	if err := WriteToNamedRange(path, "Missing", sales); err == nil {
+		t.Error("Expected an error for an unknown name")
+	}
+}
+
+func TestSheetsClient(t *testing.T) {
+	key, err := rsa.GenerateKey(crand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var written map[string]interface{}
+	var cleared string
+	tokens := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			tokens++
+			r.ParseForm()
+			if parts := strings.Split(r.Form.Get("assertion"), "."); len(parts) != 3 {
+				http.Error(w, "bad assertion", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"tok","expires_in":3600}`)
+			return
+		case r.Header.Get("Authorization") != "Bearer tok":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, ":clear"):
+			cleared = r.URL.Path
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodPut:
+			if r.URL.Query().Get("valueInputOption") != "RAW" {
+				http.Error(w, "missing input option", http.StatusBadRequest)
+			}
+			json.NewDecoder(r.Body).Decode(&written)
+			fmt.Fprint(w, `{}`)
+		default:
+			fmt.Fprint(w, `{"values":[["name","score","ok"],["a",1.5,true],["b",2]]}`)
+		}
+	}))
+	defer server.Close()
+
+	account, _ := json.Marshal(map[string]string{
+		"client_email": "reporter@example.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+	client, err := NewSheetsClientFromServiceAccount(account)
+	if err != nil {
+		t.Fatalf("NewSheetsClientFromServiceAccount failed: %v", err)
+	}
+	client.Endpoint = server.URL
+
+	df := NewDataFrame([]string{"name", "score"})
+	df.AddRow([]interface{}{"=cmd", 1.5})
+	df.AddRow([]interface{}{nil, math.NaN()})
+	if err := client.WriteSheet("sheet-id", "Results!A1", df); err != nil {
+		t.Fatalf("WriteSheet failed: %v", err)
+	}
+	if cleared != "" {
+		t.Errorf("Expected no separate clear, got %s", cleared)
+	}
+	if fmt.Sprint(written["values"]) != "[[name score ] [=cmd 1.5 ] [ ]]" {
+		t.Errorf("Unexpected values: %v", written["values"])
+	}
+	if err := client.WriteSheet("sheet-id", "Results", NewDataFrame([]string{"x"})); err != nil {
+		t.Fatalf("WriteSheet failed: %v", err)
+	}
+	if fmt.Sprint(written["values"]) != "[[x  ] [  ] [ ]]" {
+		t.Errorf("Expected leftover cells to be blanked: %v", written["values"])
+	}
+
+	got, err := client.ReadSheet("sheet-id", "Results")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	if fmt.Sprint(got.data) != "[[a 1.5 true] [b 2 <nil>]]" {
+		t.Errorf("Unexpected frame: %v", got.data)
+	}
+	if tokens != 1 {
+		t.Errorf("Expected the token to be reused, fetched %d", tokens)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.tsv")
+	df.ToTSV(path)
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "name\tscore\n") {
+		t.Errorf("Unexpected TSV: %q", data)
+	}
+}
+
+func TestGenerateStruct(t *testing.T) {
+	df := NewDataFrame([]string{"employee_id", "First Name", "salary", "manager", "misc"})
+	df.AddRow([]interface{}{1, "Ann", 10, nil, "x"})
+	df.AddRow([]interface{}{2, "Bo", 2.5, "Ann", 3})
+
+	src, err := GenerateStruct(df, "Employee")
+	if err != nil {
+		t.Fatalf("GenerateStruct failed: %v", err)
+	}
+	for _, want := range []string{
+		"EmployeeID int         `df:\"employee_id\" json:\"employee_id\"`",
+		"FirstName  string      `df:\"First Name\" json:\"First Name\"`",
+		"Salary     float64",
+		"Manager    *string",
+		"Misc       interface{}",
+		"func LoadEmployeeRows(df *gopandas.DataFrame) ([]Employee, error) {",
+		"out[i].Salary = float64(v)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Generated code is missing %q:\n%s", want, src)
+		}
+	}
+
+	if _, err := GenerateStruct(df, "employee"); err == nil {
+		t.Error("Expected an error for an unexported type name")
+	}
+	if goFieldName("2nd place") != "Col2ndPlace" || goFieldName("api_url") != "APIURL" {
+		t.Errorf("Unexpected field names: %s %s", goFieldName("2nd place"), goFieldName("api_url"))
+	}
+}
+
+func TestRowsAndValuesIterators(t *testing.T) {
+	df := NewDataFrame([]string{"name", "amount"})
+	df.AddRow([]interface{}{"a", 1})
+	df.AddRow([]interface{}{"b", 2.5})
+	df.AddRow([]interface{}{"c", 4})
+
+	var names []string
+	total := 0.0
+	for i, row := range df.Rows() {
+		if i == 2 {
+			break
+		}
+		names = append(names, row.Get("name").(string))
+		amount, _ := row.Float("amount")
+		total += amount
+	}
+	if fmt.Sprint(names) != "[a b]" || total != 3.5 {
+		t.Errorf("Unexpected iteration: %v %v", names, total)
+	}
+
+	amounts, _ := df.GetColumn("amount")
+	positions := 0
+	for i, val := range amounts.Values() {
+		if val != amounts.data[i] {
+			t.Errorf("Value %d mismatch: %v", i, val)
+		}
+		positions += i
+	}
+	if positions != 3 {
+		t.Errorf("Unexpected positions sum: %d", positions)
+	}
+}
+
+func TestApplyBatches(t *testing.T) {
+	df := NewDataFrame([]string{"id"})
+	for i := 0; i < 7; i++ {
+		df.AddRow([]interface{}{i})
+	}
+
+	var running, peak atomic.Int32
+	sizes := make([]int, 0)
+	var mu sync.Mutex
+	result, err := df.ApplyBatches(3, func(batch *DataFrame) (*DataFrame, error) {
+		now := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		sizes = append(sizes, len(batch.data))
+		mu.Unlock()
+		ids, _ := batch.GetColumn("id")
+		scores := make([]interface{}, ids.Len())
+		for i, id := range ids.Values() {
+			scores[i] = id.(int) * 10
+		}
+		batch.SetColumn("score", scores)
+		return batch, nil
+	}, WithWorkers(2))
+	if err != nil {
+		t.Fatalf("ApplyBatches failed: %v", err)
+	}
+	if len(result.data) != 7 || fmt.Sprint(result.columns) != "[id score]" || result.data[6][1] != 60 {
+		t.Errorf("Unexpected result: %v %v", result.columns, result.data)
+	}
+	if len(df.columns) != 1 {
+		t.Errorf("Source frame was modified: %v", df.columns)
+	}
+	sort.Ints(sizes)
+	if fmt.Sprint(sizes) != "[1 3 3]" || peak.Load() > 2 {
+		t.Errorf("Unexpected batches %v or concurrency %d", sizes, peak.Load())
+	}
+
+	_, err = df.ApplyBatches(2, func(batch *DataFrame) (*DataFrame, error) {
+		if batch.data[0][0] == 2 {
+			return nil, fmt.Errorf("service unavailable")
+		}
+		return batch, nil
+	})
+	if err == nil || err.Error() != "batch 1: service unavailable" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	df := NewDataFrame([]string{"ip", "bytes"})
+	df.AddRow([]interface{}{"10.0.0.1", 5})
+	df.AddRow([]interface{}{"10.0.0.2", 7})
+	df.AddRow([]interface{}{"10.0.0.1", 9})
+	df.AddRow([]interface{}{nil, 1})
+
+	var calls atomic.Int32
+	lookup := func(key interface{}) (map[string]interface{}, error) {
+		calls.Add(1)
+		if key == "10.0.0.1" {
+			return map[string]interface{}{"country": "KR", "asn": 4766}, nil
+		}
+		return map[string]interface{}{"country": "US"}, nil
+	}
+
+	store := NewMemoryStore()
+	start := time.Now()
+	result, err := df.Enrich("ip", lookup, WithCache(store, time.Minute), WithRateLimit(50), WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("Enrich failed: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected one lookup per distinct key, got %d", calls.Load())
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Rate limit was not applied")
+	}
+	if fmt.Sprint(result.columns) != "[ip bytes asn country]" {
+		t.Errorf("Unexpected columns: %v", result.columns)
+	}
+	if fmt.Sprint(result.data) != "[[10.0.0.1 5 4766 KR] [10.0.0.2 7 <nil> US] [10.0.0.1 9 4766 KR] [<nil> 1 <nil> <nil>]]" {
+		t.Errorf("Unexpected rows: %v", result.data)
+	}
+
+	again, err := df.Enrich("ip", lookup, WithCache(store, time.Minute))
+	if err != nil || calls.Load() != 2 || again.data[0][2] != 4766 {
+		t.Errorf("Expected cached results: %v %v calls=%d", again, err, calls.Load())
+	}
+
+	_, err = df.Enrich("ip", func(interface{}) (map[string]interface{}, error) {
+		return nil, fmt.Errorf("quota exceeded")
+	})
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+}
+
+func TestMapJoin(t *testing.T) {
+	df := NewDataFrame([]string{"dept_id", "name"})
+	df.AddRow([]interface{}{1, "ann"})
+	df.AddRow([]interface{}{2.0, "bo"})
+	df.AddRow([]interface{}{9, "cy"})
+
+	named, err := df.MapJoin("dept_id", map[int]string{1: "Sales", 2: "Ops"}, "dept")
+	if err != nil {
+		t.Fatalf("MapJoin failed: %v", err)
+	}
+	if fmt.Sprint(named.data) != "[[1 ann Sales] [2 bo Ops] [9 cy <nil>]]" {
+		t.Errorf("Unexpected scalar join: %v", named.data)
+	}
+
+	details := map[int]map[string]interface{}{1: {"floor": 3, "head": "kim"}, 2: {"floor": 5}}
+	all, _ := df.MapJoin("dept_id", details)
+	if fmt.Sprint(all.columns) != "[dept_id name floor head]" || fmt.Sprint(all.data[1]) != "[2 bo 5 <nil>]" {
+		t.Errorf("Unexpected field join: %v %v", all.columns, all.data)
+	}
+
+	depts := NewDataFrame([]string{"id", "budget"})
+	depts.AddRow([]interface{}{1, 100})
+	rows := make(map[interface{}]Row)
+	for _, row := range depts.Rows() {
+		rows[row.Get("id")] = row
+	}
+	budget, _ := df.MapJoin("dept_id", rows, "budget")
+	if fmt.Sprint(budget.data[0]) != "[1 ann 100]" || budget.data[1][2] != nil {
+		t.Errorf("Unexpected row join: %v", budget.data)
+	}
+
+	if _, err := df.MapJoin("dept_id", map[int]string{}, "a", "b"); err == nil {
+		t.Error("Expected an error for scalar values with two columns")
+	}
+}
+
+func TestRollup(t *testing.T) {
+	df := NewDataFrame([]string{"region", "country", "sales"})
+	df.AddRow([]interface{}{"EU", "DE", 10})
+	df.AddRow([]interface{}{"US", "US", 5})
+	df.AddRow([]interface{}{"EU", "FR", 7})
+	df.AddRow([]interface{}{"EU", "DE", 3})
+
+	rollup, err := df.Rollup([]string{"region", "country"}, Aggregation{Column: "sales", Func: "sum", As: "total"})
+	if err != nil {
+		t.Fatalf("Rollup failed: %v", err)
+	}
+	expected := "[[EU DE 13 0] [EU FR 7 0] [EU <nil> 20 1] [US US 5 0] [US <nil> 5 1] [<nil> <nil> 25 3]]"
+	if fmt.Sprint(rollup.columns) != "[region country total grouping_id]" || fmt.Sprint(rollup.data) != expected {
+		t.Errorf("Unexpected rollup: %v %v", rollup.columns, rollup.data)
+	}
+
+	cube, err := df.Cube([]string{"region", "country"}, Aggregation{Column: "sales", Func: "count"})
+	if err != nil {
+		t.Fatalf("Cube failed: %v", err)
+	}
+	if len(cube.data) != 9 || fmt.Sprint(cube.data[5]) != "[<nil> DE 2 2]" {
+		t.Errorf("Unexpected cube: %v", cube.data)
+	}
+
+	if _, err := df.GroupingSets([][]string{{"city"}}); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+}
+
+func TestPercentOfTotal(t *testing.T) {
+	df := NewDataFrame([]string{"region", "sales"})
+	df.AddRow([]interface{}{"EU", 30})
+	df.AddRow([]interface{}{"US", 50})
+	df.AddRow([]interface{}{"EU", 10})
+	df.AddRow([]interface{}{"US", nil})
+
+	overall, err := df.PercentOfTotal("sales")
+	if err != nil {
+		t.Fatalf("PercentOfTotal failed: %v", err)
+	}
+	if fmt.Sprint(overall.columns) != "[region sales sales_pct]" || fmt.Sprint(overall.data[0][2]) != "33.33333333333333" {
+		t.Errorf("Unexpected overall percentages: %v %v", overall.columns, overall.data)
+	}
+
+	within, _ := df.PercentOfTotal("sales", "region")
+	if within.data[0][2] != 75.0 || within.data[1][2] != 100.0 || within.data[3][2] != nil {
+		t.Errorf("Unexpected partition percentages: %v", within.data)
+	}
+
+	g, _ := df.GroupByColumns("region")
+	share, err := g.Share("sales")
+	if err != nil {
+		t.Fatalf("Share failed: %v", err)
+	}
+	if share.name != "sales_share" || fmt.Sprint(share.data) != "[0.75 1 0.25 <nil>]" {
+		t.Errorf("Unexpected shares: %s %v", share.name, share.data)
+	}
+
+	if _, err := g.Share("missing"); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+}
+
+func TestSessionize(t *testing.T) {
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	df := NewDataFrame([]string{"user", "ts"})
+	df.AddRow([]interface{}{"a", base})
+	df.AddRow([]interface{}{"b", base.Add(5 * time.Minute)})
+	df.AddRow([]interface{}{"a", base.Add(50 * time.Minute)})
+	df.AddRow([]interface{}{"a", base.Add(10 * time.Minute)})
+	df.AddRow([]interface{}{"b", nil})
+
+	sessions, err := Sessionize(df, "user", "ts", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("Sessionize failed: %v", err)
+	}
+	ids, _ := sessions.GetColumn(SessionColumn)
+	if fmt.Sprint(ids.Data()) != "[0 1 2 0 <nil>]" {
+		t.Errorf("Unexpected session IDs: %v", ids.Data())
+	}
+
+	if _, err := Sessionize(df, "user", "ts", 0); err == nil {
+		t.Error("Expected an error for a zero gap")
+	}
+}
+
+func TestFunnelAndRetention(t *testing.T) {
+	events := NewDataFrame([]string{"user", "event"})
+	for _, e := range [][]interface{}{
+		{"a", "view"}, {"a", "cart"}, {"b", "cart"}, {"b", "view"},
+		{"c", "view"}, {"a", "buy"}, {"c", "cart"},
+	} {
+		events.AddRow(e)
+	}
+	funnel, err := Funnel(events, "user", "event", []string{"view", "cart", "buy"})
+	if err != nil {
+		t.Fatalf("Funnel failed: %v", err)
+	}
+	expected := "[[view 3 1 1] [cart 2 0.6666666666666666 0.6666666666666666] [buy 1 0.3333333333333333 0.5]]"
+	if fmt.Sprint(funnel.data) != expected {
+		t.Errorf("Unexpected funnel: %v", funnel.data)
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	visits := NewDataFrame([]string{"user", "ts"})
+	for _, v := range [][]interface{}{
+		{"a", day(1)}, {"b", day(2)}, {"a", day(9)}, {"c", day(10)}, {"a", day(17)}, {"c", day(17)},
+	} {
+		visits.AddRow(v)
+	}
+	cohorts, err := RetentionCohorts(visits, "user", "ts", "W")
+	if err != nil {
+		t.Fatalf("RetentionCohorts failed: %v", err)
+	}
+	if fmt.Sprint(cohorts.columns) != "[cohort users period_0 period_1 period_2]" {
+		t.Fatalf("Unexpected cohort columns: %v", cohorts.columns)
+	}
+	if fmt.Sprint(cohorts.data[0][1:]) != "[2 1 0.5 0.5]" || fmt.Sprint(cohorts.data[1][1:]) != "[1 1 1 <nil>]" {
+		t.Errorf("Unexpected cohorts: %v", cohorts.data)
+	}
+
+	if _, err := RetentionCohorts(visits, "user", "ts", "fortnight"); err == nil {
+		t.Error("Expected an error for an invalid period")
+	}
+}
+
+func TestHistogramAndFreqTable(t *testing.T) {
+	s := NewSeries("x", []interface{}{1, 2, 2, 3, 4, nil, "n/a", 5})
+	hist, err := s.Histogram(2)
+	if err != nil {
+		t.Fatalf("Histogram failed: %v", err)
+	}
+	if fmt.Sprint(hist.data) != "[[1 3 3] [3 5 3]]" {
+		t.Errorf("Unexpected histogram: %v", hist.data)
+	}
+
+	edges, _ := s.HistogramEdges([]float64{0, 2, 4})
+	if fmt.Sprint(edges.data) != "[[0 2 1] [2 4 4]]" {
+		t.Errorf("Unexpected edge histogram: %v", edges.data)
+	}
+	if _, err := s.HistogramEdges([]float64{2, 1}); err == nil {
+		t.Error("Expected an error for decreasing edges")
+	}
+
+	df := NewDataFrame([]string{"os", "browser"})
+	df.AddRow([]interface{}{"mac", "safari"})
+	df.AddRow([]interface{}{"win", "edge"})
+	df.AddRow([]interface{}{"win", "edge"})
+	df.AddRow([]interface{}{"mac", "chrome"})
+	freq, err := df.FreqTable("os", "browser")
+	if err != nil {
+		t.Fatalf("FreqTable failed: %v", err)
+	}
+	if fmt.Sprint(freq.columns) != "[os browser count percent]" || fmt.Sprint(freq.data) != "[[win edge 2 50] [mac safari 1 25] [mac chrome 1 25]]" {
+		t.Errorf("Unexpected frequency table: %v %v", freq.columns, freq.data)
+	}
+}
+
+func TestSparklineAndHistogramASCII(t *testing.T) {
+	s := NewSeries("latency", []interface{}{1, 8, nil, 4, 8})
+	if line := s.Sparkline(); line != "▁█ ▄█" {
+		t.Errorf("Unexpected sparkline: %q", line)
+	}
+	if line := NewSeries("inf", []interface{}{0, math.Inf(1), 2, math.Inf(-1)}).Sparkline(); line != "▁ █ " {
+		t.Errorf("Expected infinities drawn as spaces, got %q", line)
+	}
+	if line := NewSeries("flat", []interface{}{2, 2}).Sparkline(); line != "▅▅" {
+		t.Errorf("Unexpected flat sparkline: %q", line)
+	}
+
+	text, err := NewSeries("x", []interface{}{0, 1, 1, 9, 10}).HistogramASCII(4)
+	if err != nil {
+		t.Fatalf("HistogramASCII failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) != 10 || lines[0] != "[0, 1)  ██   1" || lines[1] != "[1, 2)  ████ 2" || lines[9] != "[9, 10] ████ 2" {
+		t.Errorf("Unexpected histogram:\n%s", text)
+	}
+}
+
+func TestFloatFormat(t *testing.T) {
+	df := NewDataFrame([]string{"item", "amount"})
+	df.AddRow([]interface{}{"a", 70000.00000000001})
+	df.AddRow([]interface{}{"b", 0.0123456})
+	df.AddRow([]interface{}{"c", 3})
+
+	cases := map[string]struct {
+		format FloatFormat
+		locale string
+	}{
+		"item,amount\na,70000.00\nb,0.01\nc,3\n":               {format: FixedDecimals(2)},
+		"item,amount\na,7.0e+04\nb,1.2e-02\nc,3\n":             {format: Scientific(1)},
+		"item,amount\na,70000\nb,0.0123\nc,3\n":                {format: SignificantDigits(3)},
+		"item;amount\na;70000,0\nb;0,0\nc;3\n":                 {format: FixedDecimals(1), locale: "de-DE"},
+		"item,amount\na,70000.00000000001\nb,0.0123456\nc,3\n": {},
+	}
+	for expected, c := range cases {
+		file := filepath.Join(t.TempDir(), "out.csv")
+		options := []CSVOption{WithFloatFormat(c.format)}
+		if c.locale != "" {
+			options = append(options, WithLocale(c.locale), WithDelimiter(';'))
+		}
+		if err := df.ToCSV(file, options...); err != nil {
+			t.Fatalf("ToCSV failed: %v", err)
+		}
+		data, _ := os.ReadFile(file)
+		if string(data) != expected {
+			t.Errorf("Expected %q, got %q", expected, data)
+		}
+	}
+}
+
+func TestSeriesMath(t *testing.T) {
+	s := NewSeries("x", []interface{}{-2.567, 1250, nil, "n/a", 4.0})
+	cases := []struct {
+		got      *Series
+		expected string
+	}{
+		{s.Round(1), "[-2.6 1250 <nil> <nil> 4]"},
+		{s.Round(-2), "[-0 1300 <nil> <nil> 0]"},
+		{s.Floor(), "[-3 1250 <nil> <nil> 4]"},
+		{s.Ceil(), "[-2 1250 <nil> <nil> 4]"},
+		{s.Abs(), "[2.567 1250 <nil> <nil> 4]"},
+		{s.Sqrt(), "[NaN 35.35533905932738 <nil> <nil> 2]"},
+		{s.Pow(2), "[6.589489000000001 1.5625e+06 <nil> <nil> 16]"},
+		{NewSeries("y", []interface{}{1, 100}).Log10(), "[0 2]"},
+		{NewSeries("y", []interface{}{0, nil}).Exp(), "[1 <nil>]"},
+	}
+	for k, c := range cases {
+		if fmt.Sprint(c.got.Data()) != c.expected {
+			t.Errorf("case %d: expected %s, got %v", k, c.expected, c.got.Data())
+		}
+	}
+	if _, ok := s.Round(-2).Data()[1].(int); !ok {
+		t.Error("Expected Round to keep ints as ints")
+	}
+	if s.Log().Data()[4] != math.Log(4) {
+		t.Errorf("Unexpected log: %v", s.Log().Data())
+	}
+
+	meters := NewSeries("d", []interface{}{1.26}).SetUnit("m")
+	if meters.Round(1).Unit() != "m" || meters.Sqrt().Unit() != "" {
+		t.Error("Expected Round to keep the unit and Sqrt to drop it")
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	flags := NewSeries("late", []interface{}{true, false, nil, true})
+	if !flags.Any() || flags.All() {
+		t.Error("Expected Any true and All false")
+	}
+	if sum, _ := flags.Sum(); sum != 2.0 {
+		t.Errorf("Expected bools to sum as 0/1, got %v", sum)
+	}
+	if mean, _ := flags.Mean(); mean != 2.0/3 {
+		t.Errorf("Expected mean of 2/3, got %v", mean)
+	}
+
+	late := NewDataFrame([]string{"team", "late"})
+	late.AddRow([]interface{}{"a", true})
+	late.AddRow([]interface{}{"a", true})
+	late.AddRow([]interface{}{"a", false})
+	grouped, err := late.GroupByColumns("team")
+	if err != nil {
+		t.Fatalf("GroupByColumns failed: %v", err)
+	}
+	sums, err := grouped.Agg("late", "sum")
+	if err != nil {
+		t.Fatalf("Agg failed: %v", err)
+	}
+	parallel, err := late.GroupAggParallel(2, []string{"team"}, Aggregation{Column: "late", Func: "sum"})
+	if err != nil {
+		t.Fatalf("GroupAggParallel failed: %v", err)
+	}
+	if sums.data[0][1] != 2.0 || parallel.data[0][1] != 2.0 {
+		t.Errorf("Expected grouped sums to count trues like Series.Sum, got %v and %v", sums.data, parallel.data)
+	}
+	if NewSeries("empty", nil).Any() || !NewSeries("empty", nil).All() {
+		t.Error("Expected Any false and All true for an empty series")
+	}
+
+	df := NewDataFrame([]string{"neg", "big"})
+	df.AddRow([]interface{}{false, true})
+	df.AddRow([]interface{}{false, 0})
+	cols, err := df.Any(0)
+	if err != nil {
+		t.Fatalf("Any failed: %v", err)
+	}
+	if fmt.Sprint(cols.Data(), cols.Index()) != "[false true] [neg big]" {
+		t.Errorf("Unexpected column Any: %v %v", cols.Data(), cols.Index())
+	}
+	rows, _ := df.All(1)
+	if fmt.Sprint(rows.Data()) != "[false false]" {
+		t.Errorf("Unexpected row All: %v", rows.Data())
+	}
+	if _, err := df.All(2); err == nil {
+		t.Error("Expected an error for an invalid axis")
+	}
+}
+
+func TestNullSemantics(t *testing.T) {
+	df := NewDataFrame([]string{"a", "b"})
+	df.AddRow([]interface{}{1, nil})
+	df.AddRow([]interface{}{nil, nil})
+	df.AddRow([]interface{}{5, 2})
+
+	cases := []struct {
+		expr      string
+		semantics NullSemantics
+		expected  string
+	}{
+		{"a > 2 and b > 1", NullsUnknown, "[false <nil> true]"},
+		{"a > 2 or b > 1", NullsUnknown, "[<nil> <nil> true]"},
+		{"not (b > 1)", NullsUnknown, "[<nil> <nil> false]"},
+		{"a == b", NullsUnknown, "[<nil> <nil> false]"},
+		{"a == b", NullsFalse, "[false true false]"},
+		{"a != b", NullsFalse, "[true false true]"},
+		{"not (b > 1)", NullsFalse, "[true true false]"},
+		{"a > 2 or b > 1", NullsFalse, "[false false true]"},
+	}
+	for _, c := range cases {
+		got, err := df.EvalSeries(c.expr, WithNullSemantics(c.semantics))
+		if err != nil {
+			t.Fatalf("EvalSeries(%q) failed: %v", c.expr, err)
+		}
+		if fmt.Sprint(got.data) != c.expected {
+			t.Errorf("%q with semantics %d: expected %s, got %v", c.expr, c.semantics, c.expected, got.data)
+		}
+	}
+
+	unknown, _ := df.Query("not (b > 1)")
+	known, _ := df.Query("not (b > 1)", WithNullSemantics(NullsFalse))
+	if len(unknown.data) != 0 || len(known.data) != 2 {
+		t.Errorf("Unexpected query results: %v %v", unknown.data, known.data)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	ledger := NewDataFrame([]string{"id", "amount"})
+	ledger.AddRow([]interface{}{1, 10.0})
+	ledger.AddRow([]interface{}{2, 20.0})
+	ledger.AddRow([]interface{}{2, 20.0})
+	ledger.AddRow([]interface{}{3, 30.0})
+
+	bank := NewDataFrame([]string{"amount", "id"})
+	bank.AddRow([]interface{}{20, 2})
+	bank.AddRow([]interface{}{35, 3})
+	bank.AddRow([]interface{}{40, 4})
+
+	all, err := ledger.UnionAll(bank)
+	if err != nil {
+		t.Fatalf("UnionAll failed: %v", err)
+	}
+	if len(all.data) != 7 || fmt.Sprint(all.data[4]) != "[2 20]" {
+		t.Errorf("Unexpected union all: %v", all.data)
+	}
+
+	union, _ := ledger.Union(bank)
+	if fmt.Sprint(union.data) != "[[1 10] [2 20] [3 30] [3 35] [4 40]]" {
+		t.Errorf("Unexpected union: %v", union.data)
+	}
+	byID, _ := ledger.Union(bank, "id")
+	if len(byID.data) != 4 {
+		t.Errorf("Unexpected keyed union: %v", byID.data)
+	}
+
+	both, _ := ledger.Intersect(bank)
+	if fmt.Sprint(both.data) != "[[2 20]]" {
+		t.Errorf("Unexpected intersect: %v", both.data)
+	}
+	missing, _ := ledger.Except(bank)
+	if fmt.Sprint(missing.data) != "[[1 10] [3 30]]" {
+		t.Errorf("Unexpected except: %v", missing.data)
+	}
+	unmatched, _ := ledger.Except(bank, "id")
+	if fmt.Sprint(unmatched.data) != "[[1 10]]" {
+		t.Errorf("Unexpected keyed except: %v", unmatched.data)
+	}
+
+	if _, err := ledger.Union(NewDataFrame([]string{"id", "total"})); err == nil {
+		t.Error("Expected an error for mismatched columns")
+	}
+}
+
+func TestHash(t *testing.T) {
+	build := func(rows ...[]interface{}) *DataFrame {
+		df := NewDataFrame([]string{"id", "name"})
+		for _, row := range rows {
+			df.AddRow(row)
+		}
+		return df
+	}
+	a := build([]interface{}{1, "ann"}, []interface{}{2, "bo"})
+	b := build([]interface{}{2, "bo"}, []interface{}{1, "ann"})
+
+	if len(a.Hash()) != 64 || a.Hash() != build([]interface{}{1, "ann"}, []interface{}{2, "bo"}).Hash() {
+		t.Errorf("Expected a stable digest, got %s", a.Hash())
+	}
+	if a.Hash() == b.Hash() || a.HashUnordered() != b.HashUnordered() {
+		t.Error("Expected Hash to follow row order and HashUnordered to ignore it")
+	}
+	if a.Hash() == build([]interface{}{1.0, "ann"}, []interface{}{2, "bo"}).Hash() {
+		t.Error("Expected the value type to change the digest")
+	}
+	if build([]interface{}{"ab", ""}).Hash() == build([]interface{}{"a", "b"}).Hash() {
+		t.Error("Expected adjacent values not to run together")
+	}
+	if a.HashUnordered() == build([]interface{}{1, "ann"}, []interface{}{1, "ann"}, []interface{}{2, "bo"}).HashUnordered() {
+		t.Error("Expected repeated rows to change the unordered digest")
+	}
+
+	rows := a.HashRows()
+	if rows.Len() != 2 || rows.Data()[0] != b.HashRows().Data()[1] {
+		t.Errorf("Unexpected row digests: %v", rows.Data())
+	}
+	names, _ := a.GetColumn("name")
+	if hashed := names.HashRows(); hashed.Data()[0] == hashed.Data()[1] || hashed.Name() != "name" {
+		t.Errorf("Unexpected value digests: %v", hashed.Data())
+	}
+}
+
+func TestFrameStore(t *testing.T) {
+	store, err := OpenFrameStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenFrameStore failed: %v", err)
+	}
+
+	v1 := NewDataFrame([]string{"id", "price"})
+	v1.AddRow([]interface{}{1, 9.5})
+	v1.AddRow([]interface{}{2, 4.0})
+	if n, err := store.Commit(v1, "initial prices"); err != nil || n != 1 {
+		t.Fatalf("Commit failed: %d %v", n, err)
+	}
+
+	v2 := NewDataFrame([]string{"id", "price", "currency"})
+	v2.AddRow([]interface{}{1, 9.5, "EUR"})
+	v2.AddRow([]interface{}{3, 7.25, "EUR"})
+	store.Commit(v2, "add currency")
+	store.Commit(v2, "no change")
+
+	log, _ := store.Log()
+	if len(log) != 3 || log[1].Message != "add currency" || log[1].Object != log[2].Object || log[2].Rows != 2 {
+		t.Errorf("Unexpected log: %+v", log)
+	}
+
+	first, err := store.Checkout(1)
+	if err != nil {
+		t.Fatalf("Checkout failed: %v", err)
+	}
+	if fmt.Sprint(first.columns, first.data) != "[id price] [[1 9.5] [2 4]]" {
+		t.Errorf("Unexpected checkout: %v %v", first.columns, first.data)
+	}
+	if latest, _ := store.Checkout(0); len(latest.columns) != 3 {
+		t.Errorf("Expected the latest version, got %v", latest.columns)
+	}
+	if _, err := store.Checkout(9); err == nil {
+		t.Error("Expected an error for a missing version")
+	}
+
+	diff, err := store.Diff(1, 2)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if fmt.Sprint(diff.AddedColumns, diff.RemovedColumns) != "[currency] []" {
+		t.Errorf("Unexpected column changes: %v %v", diff.AddedColumns, diff.RemovedColumns)
+	}
+	if fmt.Sprint(diff.Added.data, diff.Removed.data) != "[[3 7.25]] [[2 4]]" {
+		t.Errorf("Unexpected row changes: %v %v", diff.Added.data, diff.Removed.data)
+	}
+}
+
+func TestReadDelta(t *testing.T) {
+	table := t.TempDir()
+	os.MkdirAll(filepath.Join(table, "_delta_log"), 0o755)
+	os.MkdirAll(filepath.Join(table, "day=2024-01-01"), 0o755)
+	schema := `{"type":"struct","fields":[{"name":"id","type":"long","nullable":true,"metadata":{}},{"name":"name","type":"string","nullable":true,"metadata":{}},{"name":"day","type":"date","nullable":true,"metadata":{}}]}`
+	commits := []string{
+		`{"protocol":{"minReaderVersion":1,"minWriterVersion":2}}
+{"metaData":{"id":"t1","schemaString":` + strconv.Quote(schema) + `,"partitionColumns":["day"],"configuration":{}}}
+{"add":{"path":"day=2024-01-01/part-1.json","partitionValues":{"day":"2024-01-01"},"dataChange":true}}
+{"add":{"path":"day%3D2024-01-01/part-2.json","partitionValues":{"day":"2024-01-01"},"dataChange":true}}`,
+		`{"remove":{"path":"day=2024-01-01/part-1.json","dataChange":true}}
+{"add":{"path":"part-3.json","partitionValues":{"day":null},"dataChange":true}}
+{"add":{"path":"part-4.json","partitionValues":{"day":""},"dataChange":true}}
+{"commitInfo":{"operation":"DELETE"}}`,
+	}
+	for v, commit := range commits {
+		os.WriteFile(filepath.Join(table, "_delta_log", fmt.Sprintf("%020d.json", v)), []byte(commit), 0o644)
+	}
+	files := map[string]string{
+		"day=2024-01-01/part-1.json": `[[1, "ann"]]`,
+		"day=2024-01-01/part-2.json": `[[2, "bo"]]`,
+		"part-3.json":                `[[3, "cy"], [4, "di"]]`,
+		"part-4.json":                `[[5, "ed"]]`,
+	}
+	for name, rows := range files {
+		os.WriteFile(filepath.Join(table, name), []byte(rows), 0o644)
+	}
+
+	// stands in for a Parquet library: data files hold JSON rows of id, name
+	decoder := func(data []byte) (*DataFrame, error) {
+		var rows [][]interface{}
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, err
+		}
+		df := NewDataFrame([]string{"id", "name"})
+		for _, row := range rows {
+			df.AddRow([]interface{}{int(row[0].(float64)), row[1]})
+		}
+		return df, nil
+	}
+
+	latest, err := ReadDelta(table, decoder)
+	if err != nil {
+		t.Fatalf("ReadDelta failed: %v", err)
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if fmt.Sprint(latest.columns) != "[id name day]" || fmt.Sprint(latest.data) != fmt.Sprint([][]interface{}{{2, "bo", day}, {3, "cy", nil}, {4, "di", nil}, {5, "ed", nil}}) {
+		t.Errorf("Unexpected latest table: %v %v", latest.columns, latest.data)
+	}
+
+	first, err := ReadDelta(table, decoder, WithDeltaVersion(0))
+	if err != nil {
+		t.Fatalf("ReadDelta at version 0 failed: %v", err)
+	}
+	if len(first.data) != 2 || first.data[0][1] != "ann" {
+		t.Errorf("Unexpected version 0: %v", first.data)
+	}
+
+	if _, err := ReadDelta(table, decoder, WithDeltaVersion(5)); err == nil {
+		t.Error("Expected an error for a missing version")
+	}
+	if _, err := ReadDelta(t.TempDir(), decoder); err == nil {
+		t.Error("Expected an error for a directory without a Delta log")
+	}
+}
+
+// recordingDriver is a database/sql driver that records statements and
+// answers every query with the same canned result.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{c.d, query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.statements = append(s.d.statements, s.query)
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(0), nil
+}
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &recordingRows{values: [][]driver.Value{{"EU", int64(30)}, {"US", int64(5)}}}, nil
+}
+
+type recordingRows struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *recordingRows) Columns() []string { return []string{"region", "total"} }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if r.next == len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+func TestDuckDB(t *testing.T) {
+	recorder := &recordingDriver{}
+	sql.Register("gopandas-recording", recorder)
+	db, err := sql.Open("gopandas-recording", "")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+	duck := NewDuckDB(db)
+
+	df := NewDataFrame([]string{"region", "amount", "paid", "note \"x\""})
+	df.AddRow([]interface{}{"EU", 10, true, nil})
+	df.AddRow([]interface{}{"EU", 20.5, false, 3})
+	if err := duck.Register("sales", df); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if len(recorder.statements) != 2 {
+		t.Fatalf("Expected create and insert, got %v", recorder.statements)
+	}
+	if recorder.statements[0] != `CREATE OR REPLACE TABLE "sales" ("region" VARCHAR, "amount" DOUBLE, "paid" BOOLEAN, "note ""x""" BIGINT)` {
+		t.Errorf("Unexpected create: %s", recorder.statements[0])
+	}
+	if !strings.HasSuffix(recorder.statements[1], "VALUES (?, ?, ?, ?), (?, ?, ?, ?)") || fmt.Sprint(recorder.args[1]) != "[EU 10 true <nil> EU 20.5 false 3]" {
+		t.Errorf("Unexpected insert: %s %v", recorder.statements[1], recorder.args[1])
+	}
+
+	result, err := duck.Query("SELECT region, sum(amount) AS total FROM sales GROUP BY region")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if fmt.Sprint(result.columns, result.data) != "[region total] [[EU 30] [US 5]]" {
+		t.Errorf("Unexpected result: %v %v", result.columns, result.data)
+	}
+}
+
+func TestToPrometheus(t *testing.T) {
+	df := NewDataFrame([]string{"region", "daily revenue", "healthy", "orders"})
+	df.AddRow([]interface{}{"eu-west", 1250.5, true, 12})
+	df.AddRow([]interface{}{`us "east"`, 980, false, nil})
+
+	config := PrometheusConfig{
+		Namespace:   "sales",
+		Values:      []string{"daily revenue", "healthy", "orders"},
+		Labels:      []string{"region"},
+		ConstLabels: map[string]string{"job": "nightly"},
+		Help:        map[string]string{"daily revenue": "Revenue per region"},
+	}
+	var buf bytes.Buffer
+	if err := df.ToPrometheus(&buf, config); err != nil {
+		t.Fatalf("ToPrometheus failed: %v", err)
+	}
+	expected := `# HELP sales_daily_revenue Revenue per region
+# TYPE sales_daily_revenue gauge
+sales_daily_revenue{region="eu-west",job="nightly"} 1250.5
+sales_daily_revenue{region="us \"east\"",job="nightly"} 980
+# HELP sales_healthy healthy
+# TYPE sales_healthy gauge
+sales_healthy{region="eu-west",job="nightly"} 1
+sales_healthy{region="us \"east\"",job="nightly"} 0
+# HELP sales_orders orders
+# TYPE sales_orders gauge
+sales_orders{region="eu-west",job="nightly"} 12
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected exposition:\n%s", buf.String())
+	}
+
+	var pushed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pushed = r.Method + " " + r.URL.Path + "\n" + string(body)
+	}))
+	defer server.Close()
+	if err := df.PushPrometheus(server.URL, "sales report", config); err != nil {
+		t.Fatalf("PushPrometheus failed: %v", err)
+	}
+	if !strings.HasPrefix(pushed, "PUT /metrics/job/sales report\n# HELP") {
+		t.Errorf("Unexpected push: %q", pushed)
+	}
+
+	duplicate := NewDataFrame([]string{"region", "v"})
+	duplicate.AddRow([]interface{}{"eu", 1})
+	duplicate.AddRow([]interface{}{"eu", 2})
+	if err := duplicate.ToPrometheus(io.Discard, PrometheusConfig{Values: []string{"v"}, Labels: []string{"region"}}); err == nil {
+		t.Error("Expected an error for repeated label sets")
+	}
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &recordingSpan{name: name, attrs: make(map[string]interface{})}
+	span.parent, _ = ctx.Value(spanKey{}).(string)
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanKey{}, name), span
+}
+
+type recordingSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+type recordingMetrics struct {
+	steps []string
+}
+
+func (r *recordingMetrics) RecordStep(ctx context.Context, name string, duration time.Duration, rows int, err error) {
+	r.steps = append(r.steps, fmt.Sprintf("%s %d %v %v", name, rows, err != nil, duration >= 0))
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordingSpan) RecordError(err error)                      { s.err = err }
+func (s *recordingSpan) End()                                       { s.ended = true }
+
+func TestTracing(t *testing.T) {
+	tracer := &recordingTracer{}
+	SetTracerProvider(tracer)
+	defer SetTracerProvider(nil)
+
+	file := filepath.Join(t.TempDir(), "sales.csv")
+	os.WriteFile(file, []byte("region,amount\neu,3\nus,1\neu,2\n"), 0o644)
+	df, err := ReadCSV(file)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	df.Sort("amount", true)
+	df.GroupByColumns("region")
+	df.Sort("missing", true)
+
+	if len(tracer.spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(tracer.spans))
+	}
+	read, sorted, grouped, failed := tracer.spans[0], tracer.spans[1], tracer.spans[2], tracer.spans[3]
+	if read.name != "gopandas.read_csv" || read.attrs["gopandas.rows_out"] != 3 || read.attrs["gopandas.path"] != file || !read.ended {
+		t.Errorf("Unexpected read span: %+v", read)
+	}
+	if sorted.name != "gopandas.sort" || sorted.attrs["gopandas.rows_in"] != 3 || sorted.attrs["gopandas.column"] != "amount" {
+		t.Errorf("Unexpected sort span: %+v", sorted)
+	}
+	if grouped.name != "gopandas.group_by" || grouped.attrs["gopandas.groups"] != 2 {
+		t.Errorf("Unexpected group span: %+v", grouped)
+	}
+	if failed.err == nil || !failed.ended {
+		t.Errorf("Expected the failed sort to record its error: %+v", failed)
+	}
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "request")
+	if _, err := df.SortContext(ctx, "amount", false); err != nil || tracer.spans[4].parent != "request" {
+		t.Errorf("Expected the span to be parented to the caller's: %+v %v", tracer.spans[4], err)
+	}
+
+	recorder := &recordingMetrics{}
+	SetMetricsRecorder(recorder)
+	defer SetMetricsRecorder(nil)
+	SetTracerProvider(nil)
+	df.Sort("amount", true)
+	ReadCSV(file)
+	df.Sort("missing", true)
+	if len(tracer.spans) != 5 {
+		t.Error("Expected no spans after tracing is turned off")
+	}
+	if fmt.Sprint(recorder.steps) != "[gopandas.sort 3 false true gopandas.read_csv 3 false true gopandas.sort 3 true true]" {
+		t.Errorf("Unexpected metrics: %v", recorder.steps)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	defer SetLimits(Limits{})
+	file := t.TempDir() + "/upload.csv"
+	os.WriteFile(file, []byte("id,name\n1,a\n2,b\n3,c\n"), 0o644)
+
+	SetLimits(Limits{MaxRows: 2})
+	if _, err := ReadCSV(file); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected a row limit error, got %v", err)
+	}
+	SetLimits(Limits{MaxMemory: 100})
+	if _, err := ReadCSV(file); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected a memory limit error, got %v", err)
+	}
+	SetLimits(Limits{})
+	df, err := ReadCSV(file)
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if df.MemoryEstimate() <= 0 {
+		t.Error("Expected a positive memory estimate")
+	}
+	SetLimits(Limits{MaxRows: 3})
+	if _, err := ReadCSV(file); err != nil {
+		t.Errorf("Expected the header not to count against MaxRows, got %v", err)
+	}
+
+	sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>id</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c></row>
+<row r="3"><c r="A3"><v>2</v></c></row>
+<row r="4"><c r="A4"><v>3</v></c></row>
+</sheetData></worksheet>`
+	xlsx := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheet})
+	defer os.Remove(xlsx)
+	if _, err := ReadExcel(xlsx); err != nil {
+		t.Errorf("Expected three sheet rows to fit MaxRows, got %v", err)
+	}
+	SetLimits(Limits{MaxRows: 2})
+	if _, err := ReadExcel(xlsx); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected a row limit error from ReadExcel, got %v", err)
+	}
+	SetLimits(Limits{})
+
+	left := NewDataFrame([]string{"k", "x"})
+	right := NewDataFrame([]string{"k", "y"})
+	for i := 0; i < 10; i++ {
+		left.AddRow([]interface{}{1, i})
+		right.AddRow([]interface{}{1, i})
+	}
+	SetLimits(Limits{MaxJoinRows: 50})
+	_, err = left.Join(right, "k", "inner")
+	if !errors.Is(err, ErrLimitExceeded) || !strings.Contains(err.Error(), "100 rows") {
+		t.Errorf("Expected a join limit error naming 100 rows, got %v", err)
+	}
+	SetLimits(Limits{MaxJoinRows: 100})
+	if joined, err := left.Join(right, "k", "inner"); err != nil || len(joined.data) != 100 {
+		t.Errorf("Expected 100 joined rows within the limit, got %v", err)
+	}
+}
+
+func TestHardening(t *testing.T) {
+	sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>x</t></is></c><c r="B1" t="inlineStr"><is><t>y</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="B2"><v>2</v></c></row>
+<row r="3"><c r="A3"><v>3</v></c><c r="B3"><v>4</v></c></row>
+</sheetData></worksheet>`
+	bomb := strings.Repeat("0", 1<<20)
+	xlsx := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheet, "xl/padding.bin": bomb})
+	defer os.Remove(xlsx)
+
+	if _, err := ReadExcel(xlsx, WithExcelHardening(Hardening{MaxUncompressedSize: 1 << 16})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected the archive size to be rejected, got %v", err)
+	}
+	if _, err := ReadExcel(xlsx, WithExcelHardening(Hardening{MaxEntries: 1})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected the entry count to be rejected, got %v", err)
+	}
+	if _, err := ReadExcel(xlsx, WithExcelHardening(Hardening{MaxCells: 5})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected the cell count to be rejected, got %v", err)
+	}
+	if df, err := ReadExcel(xlsx, WithExcelHardening(DefaultHardening())); err != nil || len(df.data) != 2 {
+		t.Errorf("Expected the workbook to pass the default limits, got %v", err)
+	}
+	chunks := func(h Hardening) error {
+		return ReadExcelChunks(xlsx, 1, func(*DataFrame) error { return nil }, WithExcelHardening(h))
+	}
+	if err := chunks(Hardening{MaxRows: 2}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected ReadExcelChunks to apply the row cap, got %v", err)
+	}
+	if err := chunks(Hardening{MaxCells: 5}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected ReadExcelChunks to apply the cell cap, got %v", err)
+	}
+
+	wide := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1"><v>1</v></c><c r="XFD1"><v>2</v></c></row>
+</sheetData></worksheet>`})
+	defer os.Remove(wide)
+	if _, err := ReadExcel(wide, WithExcelHardening(DefaultHardening())); !errors.Is(err, ErrLimitExceeded) || !strings.Contains(err.Error(), "column 16384") {
+		t.Errorf("Expected a far column reference to be rejected, got %v", err)
+	}
+	for _, row := range []string{`<row r="1"><c r="AAAAAAAAAAAAAA1"><v>1</v></c></row>`, `<row r="1"><c r="A1048577"><v>1</v></c></row>`, `<row r="2000000"><c><v>1</v></c></row>`} {
+		beyond := writeTestXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + row + `</sheetData></worksheet>`})
+		defer os.Remove(beyond)
+		if _, err := ReadExcel(beyond); err == nil || !strings.Contains(err.Error(), "beyond") {
+			t.Errorf("Expected a reference past XFD1048576 to be rejected without hardening, got %v", err)
+		}
+	}
+
+	dir := t.TempDir()
+	upload := dir + "/upload.csv"
+	os.WriteFile(upload, []byte("a,b\n1,2\n3,4\n5,6\n"), 0o644)
+	if _, err := ReadCSV(upload, WithHardening(Hardening{MaxRows: 3})); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected the row count to be rejected, got %v", err)
+	}
+	if df, err := ReadCSV(upload, WithHardening(Hardening{MaxRows: 4, MaxCells: 8})); err != nil || len(df.data) != 3 {
+		t.Errorf("Expected the file to fit the limits, got %v", err)
+	}
+
+	df := NewDataFrame([]string{"name", "delta"})
+	df.AddRow([]interface{}{"=HYPERLINK(\"http://x\")", -5})
+	df.AddRow([]interface{}{"@SUM(A1)", 3})
+	df.AddRow([]interface{}{"plain", -1.5})
+	out := dir + "/out.csv"
+	if err := df.ToCSV(out, WithEscapeFormulas()); err != nil {
+		t.Fatalf("ToCSV failed: %v", err)
+	}
+	written, _ := os.ReadFile(out)
+	expected := "name,delta\n\"'=HYPERLINK(\"\"http://x\"\")\",-5\n'@SUM(A1),3\nplain,-1.5\n"
+	if string(written) != expected {
+		t.Errorf("Unexpected escaped CSV:\n%s", written)
+	}
+}
+
+func TestDifferentialPrivacy(t *testing.T) {
+	df := NewDataFrame([]string{"region", "salary"})
+	for i := 0; i < 20000; i++ {
+		region := "north"
+		if i%4 == 0 {
+			region = "south"
+		}
+		df.AddRow([]interface{}{region, 100})
+	}
+	df.AddRow([]interface{}{"south", nil})
+
+	config := &privacyConfig{sensitivity: 10, rng: rand.New(rand.NewSource(1))}
+	noisy, err := df.addLaplaceNoise([]string{"salary"}, 0.5, config)
+	if err != nil {
+		t.Fatalf("addLaplaceNoise failed: %v", err)
+	}
+	var sum, absSum float64
+	for _, row := range noisy.data[:20000] {
+		noise := row[1].(float64) - 100
+		sum += noise
+		absSum += math.Abs(noise)
+	}
+	// Laplace(b) has mean 0 and mean absolute deviation b = 10/0.5
+	if mean := sum / 20000; math.Abs(mean) > 1 {
+		t.Errorf("Expected noise centred on 0, got mean %v", mean)
+	}
+	if mad := absSum / 20000; math.Abs(mad-20) > 1 {
+		t.Errorf("Expected mean absolute noise near 20, got %v", mad)
+	}
+	if noisy.data[20000][1] != nil || df.data[0][1] != 100 {
+		t.Error("Expected nulls kept and the input untouched")
+	}
+
+	g, _ := df.GroupByColumns("region")
+	counts, err := g.privateCount(1, &privacyConfig{sensitivity: 1, rng: rand.New(rand.NewSource(2))})
+	if err != nil {
+		t.Fatalf("privateCount failed: %v", err)
+	}
+	if counts.index[0] != "south" || math.Abs(counts.data[0].(float64)-5001) > 20 || math.Abs(counts.data[1].(float64)-15000) > 20 {
+		t.Errorf("Unexpected private counts: %v %v", counts.index, counts.data)
+	}
+
+	if total, err := df.PrivateCount(1); err != nil || math.Abs(total-20001) > 30 {
+		t.Errorf("Unexpected private count %v: %v", total, err)
+	}
+	if _, err := df.AddLaplaceNoise([]string{"salary"}, 0); err == nil {
+		t.Error("Expected a zero epsilon to be rejected")
+	}
+	if _, err := df.AddLaplaceNoise([]string{"region"}, 1); err == nil {
+		t.Error("Expected text columns to be rejected")
+	}
+	if _, err := df.PrivateCount(1, WithSensitivity(-1)); err == nil {
+		t.Error("Expected a negative sensitivity to be rejected")
+	}
+}
+
+func TestScanPII(t *testing.T) {
+	df := NewDataFrame([]string{"id", "contact", "phone", "card", "ssn", "note"})
+	df.AddRow([]interface{}{1, "minjun@example.com", "010-1234-5678", "4111 1111 1111 1111", "123-45-6789", "call me"})
+	df.AddRow([]interface{}{2, "jordan.lee@example.org", "+1 (555) 010-9999", 5500005555555559, "900101-1234567", "n/a"})
+	df.AddRow([]interface{}{3, "not an email", nil, "4111 1111 1111 1112", "666-12-3456", "12345678"})
+
+	report := ScanPII(df)
+	if cols := report.Columns(); fmt.Sprint(cols) != "[contact phone card ssn]" {
+		t.Fatalf("Unexpected flagged columns: %v", cols)
+	}
+	email := report.Findings[0]
+	if email.Kind != PIIEmail || email.Matches != 2 || email.Checked != 3 || math.Abs(email.Share-2.0/3) > 1e-9 {
+		t.Errorf("Unexpected email finding: %+v", email)
+	}
+	if email.Samples[0] != "**************.com" {
+		t.Errorf("Expected masked samples, got %v", email.Samples)
+	}
+	phone := report.Findings[1]
+	if phone.Kind != PIIPhone || phone.Matches != 2 || phone.Share != 1 {
+		t.Errorf("Unexpected phone finding: %+v", phone)
+	}
+	card := report.Findings[2]
+	if card.Kind != PIICreditCard || card.Matches != 2 {
+		t.Errorf("Expected the Luhn-invalid number to be skipped: %+v", card)
+	}
+	ssn := report.Findings[3]
+	if ssn.Kind != PIINationalID || ssn.Matches != 2 {
+		t.Errorf("Expected the 666 area number to be skipped: %+v", ssn)
+	}
+}
+
+func TestStringValidators(t *testing.T) {
+	emails := NewSeries("contact", []interface{}{"minjun@example.com", "a@b", "x@example.co.kr", nil, "two@@example.com"})
+	if got := emails.Str().IsEmail(); fmt.Sprint(got.data) != "[true false true <nil> false]" || got.name != "contact_is_email" {
+		t.Errorf("Unexpected IsEmail result %s: %v", got.name, got.data)
+	}
+
+	phones := NewSeries("phone", []interface{}{"010-1234-5678", "+82 10 1234 5678", "02-123-4567", "011-12", "+1 212 555 0123"})
+	if got := phones.Str().IsPhone("KR").data; fmt.Sprint(got) != "[true true true false false]" {
+		t.Errorf("Unexpected KR IsPhone result: %v", got)
+	}
+	if got := phones.Str().IsPhone("us").data; fmt.Sprint(got) != "[false false false false true]" {
+		t.Errorf("Unexpected US IsPhone result: %v", got)
+	}
+	if got := phones.Str().IsPhone("").data; fmt.Sprint(got) != "[true true true false true]" {
+		t.Errorf("Unexpected generic IsPhone result: %v", got)
+	}
+
+	cards := NewSeries("card", []interface{}{"4111 1111 1111 1111", 4111111111111112, "79927398713", "abc", nil})
+	if got := cards.Str().IsLuhnValid().data; fmt.Sprint(got) != "[true false true false <nil>]" {
+		t.Errorf("Unexpected IsLuhnValid result: %v", got)
+	}
+}
+
+func TestExpectations(t *testing.T) {
+	df := NewDataFrame([]string{"id", "age", "email"})
+	df.AddRow([]interface{}{1, 34, "a@example.com"})
+	df.AddRow([]interface{}{2, 150, "b@example.com"})
+	df.AddRow([]interface{}{2, 28, nil})
+	df.AddRow([]interface{}{4, nil, "d@example.com"})
+
+	suite := NewExpectationSuite("users").
+		ExpectColumnValuesBetween("age", 0, 120).
+		ExpectUnique("id").
+		ExpectNoNulls("email").
+		ExpectNoNulls("missing").
+		Expect(RowRule("adult has email", func(row map[string]interface{}) bool { return row["email"] != nil })).
+		Expect(ColumnRule("", "unnamed", func(interface{}) bool { return true }))
+
+	report := suite.Run(df)
+	if report.Success {
+		t.Fatal("Expected the suite to fail")
+	}
+	between, unique, nulls, missing := report.Results[0], report.Results[1], report.Results[2], report.Results[3]
+	if between.Success || between.Failed != 1 || between.Samples.index[0] != 1 || between.Checked != 4 {
+		t.Errorf("Unexpected range result: %+v", between)
+	}
+	if unique.Failed != 2 || fmt.Sprint(unique.Samples.index) != "[1 2]" {
+		t.Errorf("Unexpected unique result: %+v", unique)
+	}
+	if nulls.Failed != 1 || nulls.Samples.data[0][0] != 2 {
+		t.Errorf("Unexpected null result: %+v", nulls)
+	}
+	if missing.Error != "column 'missing' not found" || missing.Success {
+		t.Errorf("Expected a missing column to fail the expectation: %+v", missing)
+	}
+	if report.Results[4].Failed != 1 {
+		t.Errorf("Expected the row rule to run: %+v", report.Results[4])
+	}
+	if unnamed := report.Results[5]; unnamed.Error != "column '' not found" {
+		t.Errorf("Expected a column rule without a column to fail like ValidateRows: %+v", unnamed)
+	}
+
+	err := report.Err()
+	if err == nil || !strings.HasPrefix(err.Error(), "suite 'users' failed 6 of 6 expectations") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if text := report.String(); !strings.Contains(text, "FAIL  age between 0 and 120: 1 of 4 rows, e.g. rows [1]") {
+		t.Errorf("Unexpected report:\n%s", text)
+	}
+
+	if err := NewExpectationSuite("users").ExpectUnique("id").ExpectNoNulls("email").Run(df.Head(1)).Err(); err != nil {
+		t.Errorf("Expected a clean frame to pass: %v", err)
+	}
+}
+
+func TestDriftReport(t *testing.T) {
+	baseline := NewDataFrame([]string{"amount", "country", "legacy", "score"})
+	current := NewDataFrame([]string{"amount", "country", "score", "channel"})
+	for i := 0; i < 1000; i++ {
+		country := "KR"
+		if i%4 == 0 {
+			country = "US"
+		}
+		baseline.AddRow([]interface{}{i % 100, country, "x", i % 10})
+		if i%2 == 0 {
+			country = "JP"
+		}
+		var score interface{} = fmt.Sprint(i % 10)
+		current.AddRow([]interface{}{i%100 + 50, country, score, "web"})
+	}
+
+	report := DriftReport(baseline, current)
+	if fmt.Sprint(report.Schema) != "[{legacy removed string } {score type int string} {channel added  string}]" {
+		t.Errorf("Unexpected schema changes: %v", report.Schema)
+	}
+	amount, country, score := report.Columns[0], report.Columns[1], report.Columns[2]
+	if amount.Kind != "numeric" || math.Abs(amount.KS-0.5) > 1e-9 || !amount.Drifted {
+		t.Errorf("Unexpected amount drift: %+v", amount)
+	}
+	if country.Kind != "categorical" || !country.Drifted || country.Shifts[0].Value != "JP" || country.Shifts[0].Current != 0.5 {
+		t.Errorf("Unexpected country drift: %+v", country)
+	}
+	if score.PSI != 0 || score.Drifted {
+		t.Errorf("Expected no distribution statistics across a type change: %+v", score)
+	}
+	if fmt.Sprint(report.Drifted()) != "[amount country]" {
+		t.Errorf("Unexpected drifted columns: %v", report.Drifted())
+	}
+
+	baseline.AddRow([]interface{}{math.NaN(), "KR", "x", 1})
+	current.AddRow([]interface{}{math.NaN(), "KR", "1", "web"})
+	withNaN := DriftReport(baseline, current)
+	if withNaN.Columns[0].Kind != "numeric" || withNaN.Columns[0].BaselineNullRate != 1.0/1001 || math.Abs(withNaN.Columns[0].KS-0.5) > 1e-9 {
+		t.Errorf("Expected NaN to count as null: %+v", withNaN.Columns[0])
+	}
+
+	same := DriftReport(baseline, baseline)
+	if len(same.Schema) != 0 || same.Columns[0].PSI != 0 || same.Columns[0].KS != 0 || len(same.Drifted()) != 0 {
+		t.Errorf("Expected no drift against itself: %+v", same)
+	}
+	if rows, cols := report.ToDataFrame().Shape(); rows != 3 || cols != 7 {
+		t.Errorf("Unexpected report frame shape %dx%d", rows, cols)
+	}
+}
+
+func TestStratifiedSampleAndBalance(t *testing.T) {
+	df := NewDataFrame([]string{"id", "label"})
+	for i := 0; i < 100; i++ {
+		label := "neg"
+		if i%10 == 0 {
+			label = "pos"
+		}
+		df.AddRow([]interface{}{i, label})
+	}
+	labelCounts := func(frame *DataFrame) map[interface{}]int {
+		counts := make(map[interface{}]int)
+		for _, row := range frame.data {
+			counts[row[1]]++
+		}
+		return counts
+	}
+
+	sample, err := df.StratifiedSample("label", 0.2, 7)
+	if err != nil {
+		t.Fatalf("StratifiedSample failed: %v", err)
+	}
+	if counts := labelCounts(sample); counts["neg"] != 18 || counts["pos"] != 2 {
+		t.Errorf("Expected class shares to be kept, got %v", counts)
+	}
+	again, _ := df.StratifiedSample("label", 0.2, 7)
+	if fmt.Sprint(again.index) != fmt.Sprint(sample.index) {
+		t.Error("Expected the same seed to draw the same rows")
+	}
+	perGroup, _ := df.StratifiedSample("label", 15, 1)
+	if counts := labelCounts(perGroup); counts["neg"] != 15 || counts["pos"] != 10 {
+		t.Errorf("Expected 15 rows per group capped at the group size, got %v", counts)
+	}
+	if _, err := df.StratifiedSample("label", 1.5, 1); err == nil {
+		t.Error("Expected a fraction above 1 to be rejected")
+	}
+
+	under, err := df.Balance("label", "undersample", 3)
+	if err != nil {
+		t.Fatalf("Balance failed: %v", err)
+	}
+	if counts := labelCounts(under); counts["neg"] != 10 || counts["pos"] != 10 {
+		t.Errorf("Unexpected undersampled classes: %v", counts)
+	}
+	over, _ := df.Balance("label", "oversample", 3)
+	if counts := labelCounts(over); counts["neg"] != 90 || counts["pos"] != 90 {
+		t.Errorf("Unexpected oversampled classes: %v", counts)
+	}
+	for i := 1; i < len(over.data); i++ {
+		if over.data[i][0].(int) < over.data[i-1][0].(int) {
+			t.Fatal("Expected rows in original order")
+		}
+		if over.data[i][0] == over.data[i-1][0] && &over.data[i][0] == &over.data[i-1][0] {
+			t.Fatal("Expected repeated draws not to share a row")
+		}
+	}
+	if _, err := df.Balance("label", "smote", 3); err == nil {
+		t.Error("Expected an unknown strategy to be rejected")
+	}
+}
@@ -3,34 +3,36 @@ package gopandas
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/binary"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 type ExcelReader struct {
-	zipReader *zip.ReadCloser
+	zipReader *zip.Reader
 	strings   map[int]string
 }
 
-type worksheet struct {
-	SheetData struct {
-		Rows []struct {
-			Cells []struct {
-				Reference string `xml:"r,attr"`
-				Type      string `xml:"t,attr"`
-				Value     string `xml:"v"`
-				InlineStr struct {
-					Text string `xml:"t"`
-				} `xml:"is"`
-			} `xml:"c"`
-		} `xml:"row"`
-	} `xml:"sheetData"`
+type xlsxCell struct {
+	Reference string `xml:"r,attr"`
+	Type      string `xml:"t,attr"`
+	Value     string `xml:"v"`
+	Formula   string `xml:"f"`
+	InlineStr struct {
+		Text string `xml:"t"`
+	} `xml:"is"`
+}
+
+type xlsxRow struct {
+	Index int        `xml:"r,attr"`
+	Cells []xlsxCell `xml:"c"`
 }
 
 type sharedStrings struct {
@@ -39,25 +41,94 @@ type sharedStrings struct {
 	} `xml:"si"`
 }
 
-func ReadExcel(filename string, sheetName ...string) (*DataFrame, error) {
+var errEncryptedWorkbook = fmt.Errorf("failed to open Excel file: workbook is encrypted (use WithPassword)")
+
+func ReadExcel(filename string, options ...ExcelOption) (*DataFrame, error) {
+	return ReadExcelContext(context.Background(), filename, options...)
+}
+
+// ReadExcelContext is ReadExcel with ctx as the parent of its trace span.
+func ReadExcelContext(ctx context.Context, filename string, options ...ExcelOption) (*DataFrame, error) {
+	span := startSpan(ctx, "read_excel")
+	span.set("path", filename)
+	df, err := readExcel(filename, options...)
+	span.end(df, err)
+	return df, err
+}
+
+func readExcel(filename string, options ...ExcelOption) (*DataFrame, error) {
 	ext := strings.ToLower(filepath.Ext(filename))
+	config := newExcelConfig(options)
 
+	var df *DataFrame
+	var err error
 	switch ext {
 	case ".xlsx":
-		return readXLSX(filename, sheetName...)
+		df, err = readXLSX(filename, config)
 	case ".xls":
-		return readXLS(filename, sheetName...)
+		df, err = readXLS(filename, config)
 	default:
 		return nil, fmt.Errorf("unsupported file format: %s (only .xlsx and .xls files are supported)", ext)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	df.record("read_excel", map[string]interface{}{"source": filename, "sheet": config.Sheet})
+	return df, nil
 }
 
-func readXLSX(filename string, sheetName ...string) (*DataFrame, error) {
-	reader, err := zip.OpenReader(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+func openXLSX(filename string, config *ExcelConfig) (*ExcelReader, io.Closer, error) {
+	var reader *zip.Reader
+	var closer io.Closer = io.NopCloser(nil)
+
+	if fs, resolved := resolveFileSystem(filename); fs == (localFileSystem{}) && config.Password == "" {
+		// local workbooks are read through the file so large sheets are not buffered
+		file, err := os.Open(resolved)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		reader, err = zip.NewReader(file, info.Size())
+		if err != nil {
+			header := make([]byte, len(cfbSignature))
+			file.ReadAt(header, 0)
+			file.Close()
+			if isCompoundFile(header) {
+				return nil, nil, errEncryptedWorkbook
+			}
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		closer = file
+	} else {
+		data, err := readAllPath(filename)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		if config.Password != "" && isCompoundFile(data) {
+			if data, err = decryptWorkbook(data, config.Password); err != nil {
+				return nil, nil, fmt.Errorf("failed to decrypt Excel file: %w", err)
+			}
+		}
+		reader, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			if isCompoundFile(data) {
+				return nil, nil, errEncryptedWorkbook
+			}
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+	}
+
+	if config.Hardening != nil {
+		if err := config.Hardening.checkArchive(reader); err != nil {
+			closer.Close()
+			return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
 	}
-	defer reader.Close()
 
 	excelReader := &ExcelReader{
 		zipReader: reader,
@@ -65,30 +136,123 @@ func readXLSX(filename string, sheetName ...string) (*DataFrame, error) {
 	}
 
 	if err := excelReader.loadSharedStrings(); err != nil {
-		return nil, fmt.Errorf("failed to load shared strings: %w", err)
+		closer.Close()
+		return nil, nil, fmt.Errorf("failed to load shared strings: %w", err)
+	}
+
+	return excelReader, closer, nil
+}
+
+func readXLSX(filename string, config *ExcelConfig) (*DataFrame, error) {
+	excelReader, closer, err := openXLSX(filename, config)
+	if err != nil {
+		return nil, err
 	}
+	defer closer.Close()
 
-	sheet := "sheet1.xml"
-	if len(sheetName) > 0 && sheetName[0] != "" {
-		sheet = strings.ToLower(sheetName[0]) + ".xml"
+	sheet, err := excelReader.resolveSheet(config)
+	if err != nil {
+		return nil, err
 	}
 
-	return excelReader.readWorksheet(sheet)
+	return excelReader.readWorksheet(sheet, config)
 }
 
-func readXLS(filename string, sheetName ...string) (*DataFrame, error) {
-	file, err := os.Open(filename)
+func ReadExcelChunks(filename string, chunkSize int, fn func(chunk *DataFrame) error, options ...ExcelOption) error {
+	if chunkSize <= 0 {
+		return fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != ".xlsx" {
+		return fmt.Errorf("unsupported file format for streaming: %s (only .xlsx files are supported)", ext)
+	}
+
+	config := newExcelConfig(options)
+	framer, err := newExcelFramer(config)
+	if err != nil {
+		return err
+	}
+
+	excelReader, closer, err := openXLSX(filename, config)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	sheet, err := excelReader.resolveSheet(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open XLS file: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	data, err := io.ReadAll(file)
+	var columns []string
+	var chunk *DataFrame
+	width := 0
+	rowNumber := 0
+	builder := newRowBuilder(0)
+	footer := &footerBuffer{skip: config.SkipFooter, auto: config.AutoFooter}
+	counter := &sheetCounter{h: config.Hardening}
+
+	err = excelReader.streamRows(sheet, func(dimensionCols int, row xlsxRow) error {
+		if err := counter.push(row); err != nil {
+			return err
+		}
+		if row.Index > 0 {
+			rowNumber = row.Index - 1
+		}
+		values, err := excelReader.rowValues(row)
+		if err != nil {
+			return err
+		}
+		cells, ok := framer.push(rowNumber, values)
+		rowNumber++
+		if !ok {
+			return nil
+		}
+
+		if columns == nil {
+			width = framer.width(len(framer.header))
+			if framer.bounds == nil && dimensionCols > width {
+				width = dimensionCols
+			}
+			if width < len(cells) && framer.bounds == nil && !framer.hasHead {
+				width = len(cells)
+			}
+			resolved, err := resolveDuplicates(framer.columns(width), config.Duplicates)
+			if err != nil {
+				return err
+			}
+			columns = resolved
+			chunk = NewDataFrame(columns)
+		}
+
+		for _, released := range footer.push(cells) {
+			chunk.AddRow(builder.build(released, width))
+
+			if len(chunk.data) >= chunkSize {
+				if err := fn(chunk); err != nil {
+					return err
+				}
+				chunk = NewDataFrame(columns)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if chunk != nil && len(chunk.data) > 0 {
+		return fn(chunk)
+	}
+	return nil
+}
+
+func readXLS(filename string, config *ExcelConfig) (*DataFrame, error) {
+	data, err := readAllPath(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read XLS file: %w", err)
 	}
 
-	return parseXLS(data, sheetName...)
+	return parseXLS(data, config)
 }
 
 func (er *ExcelReader) loadSharedStrings() error {
@@ -120,93 +284,222 @@ func (er *ExcelReader) loadSharedStrings() error {
 	return nil
 }
 
-func (er *ExcelReader) readWorksheet(sheetName string) (*DataFrame, error) {
-	var worksheetFile *zip.File
-
+func (er *ExcelReader) findWorksheet(sheetName string) (*zip.File, error) {
 	for _, file := range er.zipReader.File {
 		if strings.HasSuffix(file.Name, sheetName) || file.Name == "xl/worksheets/"+sheetName {
-			worksheetFile = file
-			break
+			return file, nil
 		}
 	}
+	return nil, fmt.Errorf("worksheet '%s' not found", sheetName)
+}
 
-	if worksheetFile == nil {
-		return nil, fmt.Errorf("worksheet '%s' not found", sheetName)
+func (er *ExcelReader) streamRows(sheetName string, fn func(dimensionCols int, row xlsxRow) error) error {
+	worksheetFile, err := er.findWorksheet(sheetName)
+	if err != nil {
+		return err
 	}
 
 	rc, err := worksheetFile.Open()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rc.Close()
 
-	data, err := io.ReadAll(rc)
-	if err != nil {
-		return nil, err
+	decoder := xml.NewDecoder(rc)
+	dimensionCols := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "dimension":
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "ref" {
+					dimensionCols = rangeWidth(attr.Value)
+				}
+			}
+		case "row":
+			var row xlsxRow
+			if err := decoder.DecodeElement(&row, &start); err != nil {
+				return err
+			}
+			if row.Index > excelMaxRows {
+				return fmt.Errorf("row %d is beyond row %d", row.Index, excelMaxRows)
+			}
+			if err := fn(dimensionCols, row); err != nil {
+				return err
+			}
+		}
 	}
+}
 
-	var ws worksheet
-	if err := xml.Unmarshal(data, &ws); err != nil {
-		return nil, err
+// rowValues lays the cells of row out by column. A reference that is
+// invalid or past column XFD is an error rather than a reason to pad.
+func (er *ExcelReader) rowValues(row xlsxRow) ([]string, error) {
+	values := make([]string, 0, len(row.Cells))
+	next := 0
+	for _, cell := range row.Cells {
+		col, err := cellColumn(cell, next)
+		if err != nil {
+			return nil, err
+		}
+		if col < len(values) {
+			// out-of-order or duplicate references overwrite the earlier value
+			values[col] = er.getCellValue(cell)
+			next = col + 1
+			continue
+		}
+		for len(values) < col {
+			values = append(values, "")
+		}
+		values = append(values, er.getCellValue(cell))
+		next = col + 1
 	}
+	return values, nil
+}
 
-	if len(ws.SheetData.Rows) == 0 {
-		return nil, fmt.Errorf("worksheet is empty")
+// cellColumn is the 0-based column of cell, next being the column after the
+// previous cell for cells without a reference.
+func cellColumn(cell xlsxCell, next int) (int, error) {
+	if cell.Reference == "" {
+		if next >= excelMaxColumns {
+			return 0, fmt.Errorf("cell after column XFD")
+		}
+		return next, nil
 	}
+	col, _, err := parseCellReference(cell.Reference)
+	return col, err
+}
 
-	maxCols := 0
-	for _, row := range ws.SheetData.Rows {
-		if len(row.Cells) > maxCols {
-			maxCols = len(row.Cells)
+func (er *ExcelReader) readWorksheet(sheetName string, config *ExcelConfig) (*DataFrame, error) {
+	var rows [][]string
+	var numbers []int
+	var evaluator *formulaEvaluator
+	if config.EvaluateFormulas {
+		maxCells := 0
+		if config.Hardening != nil {
+			maxCells = config.Hardening.MaxCells
 		}
+		evaluator = newFormulaEvaluator(maxCells)
 	}
 
-	columns := make([]string, maxCols)
-	if len(ws.SheetData.Rows) > 0 {
-		firstRow := ws.SheetData.Rows[0]
-		for i, cell := range firstRow.Cells {
-			if i < maxCols {
-				columns[i] = er.getCellValue(cell)
+	// header and skipped rows are not yet known apart from data, so only
+	// rows past the first possible data row count against the limits
+	headerRows := config.SkipRows
+	if config.HeaderRow >= 0 {
+		headerRows += config.HeaderRow + max(config.HeaderRows, 1)
+	}
+	limit := newLimitTracker("read_excel")
+	counter := &sheetCounter{h: config.Hardening}
+
+	err := er.streamRows(sheetName, func(_ int, row xlsxRow) error {
+		if err := counter.push(row); err != nil {
+			return err
+		}
+		number := len(rows)
+		if row.Index > 0 {
+			number = row.Index - 1
+		}
+		values, err := er.rowValues(row)
+		if err != nil {
+			return err
+		}
+		if evaluator != nil {
+			er.collectFormulaCells(evaluator, number, row)
+		}
+		if len(rows) >= headerRows {
+			if err := limit.addRecord(values); err != nil {
+				return err
 			}
 		}
-		for i := len(firstRow.Cells); i < maxCols; i++ {
-			columns[i] = fmt.Sprintf("col_%d", i)
+		rows = append(rows, values)
+		numbers = append(numbers, number)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if evaluator != nil {
+		positions := make(map[int][]int, len(numbers))
+		for i, n := range numbers {
+			positions[n] = append(positions[n], i)
 		}
-	} else {
-		for i := range columns {
-			columns[i] = fmt.Sprintf("col_%d", i)
+		// evaluating top to bottom keeps chains like A3=A2+1 shallow, as
+		// each cell finds the one above it already computed
+		keys := make([][2]int, 0)
+		for key, cell := range evaluator.cells {
+			if cell.formula != "" && cell.value == "" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i][1] != keys[j][1] {
+				return keys[i][1] < keys[j][1]
+			}
+			return keys[i][0] < keys[j][0]
+		})
+		for _, key := range keys {
+			col, number := key[0], key[1]
+			value := formatFormulaValue(evaluator.cellValue(col, number))
+			if evaluator.limitErr != nil {
+				return nil, evaluator.limitErr
+			}
+			for _, i := range positions[number] {
+				for len(rows[i]) <= col {
+					rows[i] = append(rows[i], "")
+				}
+				rows[i][col] = value
+			}
 		}
 	}
 
-	df := NewDataFrame(columns)
+	if config.FillMerged {
+		meta, err := er.readSheetMetadata(sheetName)
+		if err != nil {
+			return nil, err
+		}
+		meta.fillMerged(rows, numbers)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("worksheet is empty")
+	}
 
-	for i := 1; i < len(ws.SheetData.Rows); i++ {
-		row := make([]interface{}, maxCols)
-		cells := ws.SheetData.Rows[i].Cells
+	return buildExcelFrame(rows, numbers, config)
+}
 
-		for j := 0; j < maxCols; j++ {
-			if j < len(cells) {
-				value := er.getCellValue(cells[j])
-				row[j] = inferType(value)
-			} else {
-				row[j] = nil
+func (er *ExcelReader) collectFormulaCells(evaluator *formulaEvaluator, number int, row xlsxRow) {
+	next := 0
+	for _, cell := range row.Cells {
+		col := next
+		if cell.Reference != "" {
+			if parsed, _, err := parseCellReference(cell.Reference); err == nil {
+				col = parsed
 			}
 		}
+		next = col + 1
 
-		df.AddRow(row)
+		value := cell.Value
+		if cell.Type == "s" || cell.Type == "inlineStr" {
+			value = er.getCellValue(cell)
+		}
+		evaluator.set(col, number, &formulaCell{value: value, kind: cell.Type, formula: cell.Formula})
 	}
-
-	return df, nil
 }
 
-func (er *ExcelReader) getCellValue(cell struct {
-	Reference string `xml:"r,attr"`
-	Type      string `xml:"t,attr"`
-	Value     string `xml:"v"`
-	InlineStr struct {
-		Text string `xml:"t"`
-	} `xml:"is"`
-}) string {
+func (er *ExcelReader) getCellValue(cell xlsxCell) string {
 	if cell.Type == "s" {
 		if idx, err := strconv.Atoi(cell.Value); err == nil {
 			if str, exists := er.strings[idx]; exists {
@@ -220,13 +513,71 @@ func (er *ExcelReader) getCellValue(cell struct {
 	return cell.Value
 }
 
+// The sheet size limits of the xlsx format: column XFD and row 1048576.
+const (
+	excelMaxColumns = 16384
+	excelMaxRows    = 1048576
+)
+
+// parseCellReference returns the 0-based column and row of an A1 reference;
+// the row is -1 for a bare column. References past XFD1048576 are rejected.
+func parseCellReference(ref string) (int, int, error) {
+	ref = strings.ReplaceAll(ref, "$", "")
+	col := 0
+	i := 0
+	for i < len(ref) {
+		c := ref[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		if col > excelMaxColumns {
+			return 0, 0, fmt.Errorf("cell reference '%s' is beyond column XFD", ref)
+		}
+		i++
+	}
+	if col == 0 {
+		return 0, 0, fmt.Errorf("invalid cell reference '%s'", ref)
+	}
+
+	row := 0
+	if i < len(ref) {
+		n, err := strconv.Atoi(ref[i:])
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid cell reference '%s'", ref)
+		}
+		if n > excelMaxRows {
+			return 0, 0, fmt.Errorf("cell reference '%s' is beyond row %d", ref, excelMaxRows)
+		}
+		row = n
+	}
+
+	return col - 1, row - 1, nil
+}
+
+func rangeWidth(ref string) int {
+	start, end, found := strings.Cut(ref, ":")
+	if !found {
+		return 0
+	}
+	startCol, _, err1 := parseCellReference(start)
+	endCol, _, err2 := parseCellReference(end)
+	if err1 != nil || err2 != nil || endCol < startCol {
+		return 0
+	}
+	return endCol + 1
+}
+
 type xlsRecord struct {
 	Type uint16
 	Size uint16
 	Data []byte
 }
 
-func parseXLS(data []byte, sheetName ...string) (*DataFrame, error) {
+func parseXLS(data []byte, config *ExcelConfig) (*DataFrame, error) {
 	if len(data) < 8 {
 		return nil, fmt.Errorf("invalid XLS file: too small")
 	}
@@ -249,11 +600,11 @@ func parseXLS(data []byte, sheetName ...string) (*DataFrame, error) {
 	case 0xD0CF: // OLE compound document (little endian)
 		validSignature = true
 		// For OLE files, we need to find the actual workbook stream
-		return parseOLEXLS(data, sheetName...)
+		return parseOLEXLS(data, config)
 	case 0xCFD0: // OLE compound document (big endian read)
 		validSignature = true
 		// For OLE files, we need to find the actual workbook stream
-		return parseOLEXLS(data, sheetName...)
+		return parseOLEXLS(data, config)
 	}
 
 	if !validSignature {
@@ -300,44 +651,7 @@ func parseXLS(data []byte, sheetName ...string) (*DataFrame, error) {
 		return nil, fmt.Errorf("no data found in XLS file")
 	}
 
-	maxCols := 0
-	for _, row := range rows {
-		if len(row) > maxCols {
-			maxCols = len(row)
-		}
-	}
-
-	columns := make([]string, maxCols)
-	if len(rows) > 0 {
-		for i, cell := range rows[0] {
-			if i < maxCols {
-				columns[i] = cell
-			}
-		}
-		for i := len(rows[0]); i < maxCols; i++ {
-			columns[i] = fmt.Sprintf("col_%d", i)
-		}
-	} else {
-		for i := range columns {
-			columns[i] = fmt.Sprintf("col_%d", i)
-		}
-	}
-
-	df := NewDataFrame(columns)
-
-	for i := 1; i < len(rows); i++ {
-		row := make([]interface{}, maxCols)
-		for j := 0; j < maxCols; j++ {
-			if j < len(rows[i]) {
-				row[j] = inferType(rows[i][j])
-			} else {
-				row[j] = nil
-			}
-		}
-		df.AddRow(row)
-	}
-
-	return df, nil
+	return buildExcelFrame(rows, nil, config)
 }
 
 func parseSST(data []byte) string {
@@ -424,7 +738,7 @@ func parseRow(data []byte, strings []string) []string {
 	return row
 }
 
-func parseOLEXLS(data []byte, sheetName ...string) (*DataFrame, error) {
+func parseOLEXLS(data []byte, config *ExcelConfig) (*DataFrame, error) {
 	if len(data) < 512 {
 		return nil, fmt.Errorf("invalid OLE file: too small")
 	}
@@ -445,7 +759,7 @@ func parseOLEXLS(data []byte, sheetName ...string) (*DataFrame, error) {
 			sig := binary.LittleEndian.Uint16(data[offset:])
 			if sig == 0x0809 || sig == 0x0805 {
 				// Found BIFF data, parse from this offset
-				return parseBIFFData(data[offset:], sheetName...)
+				return parseBIFFData(data[offset:], config)
 			}
 		}
 	}
@@ -455,7 +769,7 @@ func parseOLEXLS(data []byte, sheetName ...string) (*DataFrame, error) {
 		if i+4 < len(data) {
 			sig := binary.LittleEndian.Uint16(data[i:])
 			if sig == 0x0809 || sig == 0x0805 {
-				return parseBIFFData(data[i:], sheetName...)
+				return parseBIFFData(data[i:], config)
 			}
 		}
 	}
@@ -463,7 +777,7 @@ func parseOLEXLS(data []byte, sheetName ...string) (*DataFrame, error) {
 	return nil, fmt.Errorf("no valid Excel data found in OLE file")
 }
 
-func parseBIFFData(data []byte, sheetName ...string) (*DataFrame, error) {
+func parseBIFFData(data []byte, config *ExcelConfig) (*DataFrame, error) {
 	reader := bytes.NewReader(data)
 
 	var records []xlsRecord
@@ -508,45 +822,7 @@ func parseBIFFData(data []byte, sheetName ...string) (*DataFrame, error) {
 		}
 	}
 
-	// Create DataFrame from parsed data
-	maxCols := 0
-	for _, row := range rows {
-		if len(row) > maxCols {
-			maxCols = len(row)
-		}
-	}
-
-	columns := make([]string, maxCols)
-	if len(rows) > 0 {
-		for i, cell := range rows[0] {
-			if i < maxCols {
-				columns[i] = cell
-			}
-		}
-		for i := len(rows[0]); i < maxCols; i++ {
-			columns[i] = fmt.Sprintf("col_%d", i)
-		}
-	} else {
-		for i := range columns {
-			columns[i] = fmt.Sprintf("col_%d", i)
-		}
-	}
-
-	df := NewDataFrame(columns)
-
-	for i := 1; i < len(rows); i++ {
-		row := make([]interface{}, maxCols)
-		for j := 0; j < maxCols; j++ {
-			if j < len(rows[i]) {
-				row[j] = inferType(rows[i][j])
-			} else {
-				row[j] = nil
-			}
-		}
-		df.AddRow(row)
-	}
-
-	return df, nil
+	return buildExcelFrame(rows, nil, config)
 }
 
 func parseNumberRecord(data []byte) []string {
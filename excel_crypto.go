@@ -0,0 +1,259 @@
+package gopandas
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+const (
+	encryptedSegmentSize = 4096
+	// MS-OFFCRYPTO caps the password hashing rounds and the salt length;
+	// larger values only come from files crafted to stall the reader.
+	agileMaxSpinCount = 10_000_000
+	agileMaxSaltSize  = 65536
+)
+
+var (
+	agileVerifierInputBlock = []byte{0xfe, 0xa7, 0xd2, 0x76, 0x3b, 0x4b, 0x9e, 0x79}
+	agileVerifierValueBlock = []byte{0xd7, 0xaa, 0x0f, 0x6d, 0x30, 0x61, 0x34, 0x4e}
+	agileKeyValueBlock      = []byte{0x14, 0x6e, 0x0b, 0xe7, 0xab, 0xac, 0xd0, 0xd6}
+)
+
+// agileBytes is a base64-encoded attribute of the encryption info XML.
+type agileBytes []byte
+
+func (b *agileBytes) UnmarshalXMLAttr(attr xml.Attr) error {
+	decoded, err := base64.StdEncoding.DecodeString(attr.Value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", attr.Name.Local, err)
+	}
+	*b = decoded
+	return nil
+}
+
+type agileKeyData struct {
+	SaltSize        int        `xml:"saltSize,attr"`
+	BlockSize       int        `xml:"blockSize,attr"`
+	KeyBits         int        `xml:"keyBits,attr"`
+	HashSize        int        `xml:"hashSize,attr"`
+	CipherAlgorithm string     `xml:"cipherAlgorithm,attr"`
+	CipherChaining  string     `xml:"cipherChaining,attr"`
+	HashAlgorithm   string     `xml:"hashAlgorithm,attr"`
+	SaltValue       agileBytes `xml:"saltValue,attr"`
+}
+
+type agileEncryptedKey struct {
+	agileKeyData
+	SpinCount                  int        `xml:"spinCount,attr"`
+	EncryptedVerifierHashInput agileBytes `xml:"encryptedVerifierHashInput,attr"`
+	EncryptedVerifierHashValue agileBytes `xml:"encryptedVerifierHashValue,attr"`
+	EncryptedKeyValue          agileBytes `xml:"encryptedKeyValue,attr"`
+}
+
+type agileEncryptionInfo struct {
+	KeyData       agileKeyData        `xml:"keyData"`
+	EncryptedKeys []agileEncryptedKey `xml:"keyEncryptors>keyEncryptor>encryptedKey"`
+}
+
+// decryptWorkbook unwraps an agile-encrypted OOXML package (the compound
+// file Excel writes when a workbook is saved with a password) and returns
+// the plain zip archive.
+func decryptWorkbook(data []byte, password string) ([]byte, error) {
+	cf, err := openCompoundFile(data)
+	if err != nil {
+		return nil, err
+	}
+	infoStream, err := cf.stream("EncryptionInfo")
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := cf.stream("EncryptedPackage")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(infoStream) < 8 {
+		return nil, fmt.Errorf("invalid encryption info")
+	}
+	major := binary.LittleEndian.Uint16(infoStream[0:])
+	minor := binary.LittleEndian.Uint16(infoStream[2:])
+	if major != 4 || minor != 4 {
+		return nil, fmt.Errorf("unsupported encryption version %d.%d (only agile encryption is supported)", major, minor)
+	}
+
+	var info agileEncryptionInfo
+	if err := xml.Unmarshal(infoStream[8:], &info); err != nil {
+		return nil, fmt.Errorf("invalid encryption info: %w", err)
+	}
+	if len(info.EncryptedKeys) == 0 {
+		return nil, fmt.Errorf("workbook has no password key encryptor")
+	}
+
+	secret, err := agileSecretKey(&info.EncryptedKeys[0], password)
+	if err != nil {
+		return nil, err
+	}
+	return decryptPackage(&info.KeyData, secret, pkg)
+}
+
+func agileSecretKey(key *agileEncryptedKey, password string) ([]byte, error) {
+	newHash, err := agileHashFunc(key.HashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAgileCipher(&key.agileKeyData); err != nil {
+		return nil, err
+	}
+	if key.SpinCount < 0 || key.SpinCount > agileMaxSpinCount {
+		return nil, fmt.Errorf("invalid spin count %d (at most %d)", key.SpinCount, agileMaxSpinCount)
+	}
+
+	passwordBytes := make([]byte, 0, len(password)*2)
+	for _, u := range utf16.Encode([]rune(password)) {
+		passwordBytes = binary.LittleEndian.AppendUint16(passwordBytes, u)
+	}
+
+	h := agileDigest(newHash, key.SaltValue, passwordBytes)
+	iterator := make([]byte, 4)
+	for i := 0; i < key.SpinCount; i++ {
+		binary.LittleEndian.PutUint32(iterator, uint32(i))
+		h = agileDigest(newHash, iterator, h)
+	}
+
+	keyBytes := key.KeyBits / 8
+	derive := func(block []byte) []byte {
+		return agileFit(agileDigest(newHash, h, block), keyBytes)
+	}
+	iv := agileFit(key.SaltValue, key.BlockSize)
+
+	verifierInput, err := decryptCBC(derive(agileVerifierInputBlock), iv, key.EncryptedVerifierHashInput)
+	if err != nil {
+		return nil, err
+	}
+	verifierHash, err := decryptCBC(derive(agileVerifierValueBlock), iv, key.EncryptedVerifierHashValue)
+	if err != nil {
+		return nil, err
+	}
+	expected := agileDigest(newHash, agileFit(verifierInput, key.SaltSize))
+	if len(verifierHash) < len(expected) || subtle.ConstantTimeCompare(expected, verifierHash[:len(expected)]) != 1 {
+		return nil, fmt.Errorf("incorrect password")
+	}
+
+	secret, err := decryptCBC(derive(agileKeyValueBlock), iv, key.EncryptedKeyValue)
+	if err != nil {
+		return nil, err
+	}
+	return agileFit(secret, keyBytes), nil
+}
+
+func decryptPackage(keyData *agileKeyData, secret, pkg []byte) ([]byte, error) {
+	newHash, err := agileHashFunc(keyData.HashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAgileCipher(keyData); err != nil {
+		return nil, err
+	}
+	if len(pkg) < 8 {
+		return nil, fmt.Errorf("encrypted package is truncated")
+	}
+
+	size := binary.LittleEndian.Uint64(pkg)
+	payload := pkg[8:]
+	var out bytes.Buffer
+	segment := make([]byte, 4)
+	for i := 0; len(payload) > 0; i++ {
+		n := encryptedSegmentSize
+		if n > len(payload) {
+			n = len(payload)
+		}
+		binary.LittleEndian.PutUint32(segment, uint32(i))
+		iv := agileFit(agileDigest(newHash, keyData.SaltValue, segment), keyData.BlockSize)
+		plain, err := decryptCBC(secret, iv, payload[:n])
+		if err != nil {
+			return nil, err
+		}
+		out.Write(plain)
+		payload = payload[n:]
+	}
+
+	if uint64(out.Len()) < size {
+		return nil, fmt.Errorf("encrypted package is truncated")
+	}
+	return out.Bytes()[:size], nil
+}
+
+func agileHashFunc(name string) (func() hash.Hash, error) {
+	switch name {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA384":
+		return sha512.New384, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported hash algorithm %q", name)
+}
+
+func checkAgileCipher(keyData *agileKeyData) error {
+	if keyData.CipherAlgorithm != "AES" || keyData.CipherChaining != "ChainingModeCBC" {
+		return fmt.Errorf("unsupported cipher %s/%s", keyData.CipherAlgorithm, keyData.CipherChaining)
+	}
+	if keyData.BlockSize != aes.BlockSize {
+		return fmt.Errorf("unsupported AES block size %d", keyData.BlockSize)
+	}
+	if keyData.KeyBits != 128 && keyData.KeyBits != 192 && keyData.KeyBits != 256 {
+		return fmt.Errorf("unsupported AES key size %d bits", keyData.KeyBits)
+	}
+	if keyData.SaltSize < 1 || keyData.SaltSize > agileMaxSaltSize {
+		return fmt.Errorf("invalid salt size %d", keyData.SaltSize)
+	}
+	return nil
+}
+
+func agileDigest(newHash func() hash.Hash, parts ...[]byte) []byte {
+	h := newHash()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+// agileFit truncates b to n bytes, padding with 0x36 when it is too short.
+func agileFit(b []byte, n int) []byte {
+	if len(b) >= n {
+		return b[:n]
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	for i := len(b); i < n; i++ {
+		out[i] = 0x36
+	}
+	return out
+}
+
+func decryptCBC(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%block.BlockSize() != 0 || len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("encrypted data is not aligned to the cipher block size")
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	return out, nil
+}
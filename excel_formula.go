@@ -0,0 +1,851 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type formulaError string
+
+func (e formulaError) Error() string {
+	return string(e)
+}
+
+const (
+	errFormulaValue = formulaError("#VALUE!")
+	errFormulaRef   = formulaError("#REF!")
+	errFormulaDiv0  = formulaError("#DIV/0!")
+	errFormulaName  = formulaError("#NAME?")
+	errFormulaNA    = formulaError("#N/A")
+)
+
+const (
+	// maxFormulaDepth bounds how many formulas one cell may chain through
+	// before evaluation gives up with #REF!, as it does for a cycle.
+	maxFormulaDepth = 1024
+	// maxFormulaRangeCells caps the cells one range reference expands to
+	// when no Hardening.MaxCells applies.
+	maxFormulaRangeCells = 10_000_000
+)
+
+var cellRefPattern = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+
+type formulaCell struct {
+	value   string
+	kind    string
+	formula string
+}
+
+type cellRangeValue struct {
+	rows [][]interface{}
+}
+
+// formulaEvaluator computes cell formulas on demand, memoizing results and
+// errors and detecting circular references. A range larger than maxCells
+// stops evaluation with limitErr, which the reader returns.
+type formulaEvaluator struct {
+	cells     map[[2]int]*formulaCell
+	computed  map[[2]int]interface{}
+	failed    map[[2]int]error
+	computing map[[2]int]bool
+	depth     int
+	maxCells  int
+	limitErr  error
+}
+
+func newFormulaEvaluator(maxCells int) *formulaEvaluator {
+	if maxCells <= 0 {
+		maxCells = maxFormulaRangeCells
+	}
+	return &formulaEvaluator{
+		cells:     make(map[[2]int]*formulaCell),
+		computed:  make(map[[2]int]interface{}),
+		failed:    make(map[[2]int]error),
+		computing: make(map[[2]int]bool),
+		maxCells:  maxCells,
+	}
+}
+
+func (fe *formulaEvaluator) set(col, row int, cell *formulaCell) {
+	fe.cells[[2]int{col, row}] = cell
+}
+
+func (fe *formulaEvaluator) cellValue(col, row int) (interface{}, error) {
+	key := [2]int{col, row}
+	if v, ok := fe.computed[key]; ok {
+		return v, nil
+	}
+	if err, ok := fe.failed[key]; ok {
+		return nil, err
+	}
+
+	cell, ok := fe.cells[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if cell.formula == "" || cell.value != "" {
+		return literalCellValue(cell), nil
+	}
+
+	if fe.computing[key] || fe.depth >= maxFormulaDepth {
+		return nil, errFormulaRef
+	}
+	fe.computing[key] = true
+	fe.depth++
+	defer func() {
+		delete(fe.computing, key)
+		fe.depth--
+	}()
+
+	v, err := fe.evaluate(cell.formula)
+	if err != nil {
+		fe.failed[key] = err
+		return nil, err
+	}
+	fe.computed[key] = v
+	return v, nil
+}
+
+func literalCellValue(cell *formulaCell) interface{} {
+	switch cell.kind {
+	case "s", "inlineStr", "str":
+		return cell.value
+	case "b":
+		return cell.value == "1"
+	case "e":
+		return formulaError(cell.value)
+	}
+	if cell.value == "" {
+		return nil
+	}
+	if f, err := strconv.ParseFloat(cell.value, 64); err == nil {
+		return f
+	}
+	return cell.value
+}
+
+func (fe *formulaEvaluator) evaluate(formula string) (interface{}, error) {
+	tokens, err := tokenizeFormula(strings.TrimPrefix(strings.TrimSpace(formula), "="))
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{tokens: tokens, eval: fe}
+	v, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, errFormulaValue
+	}
+	if _, ok := v.(cellRangeValue); ok {
+		return nil, errFormulaValue
+	}
+	return v, nil
+}
+
+func formatFormulaValue(v interface{}, err error) string {
+	if err != nil {
+		if fe, ok := err.(formulaError); ok {
+			return string(fe)
+		}
+		return string(errFormulaValue)
+	}
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		if val {
+			return "TRUE"
+		}
+		return "FALSE"
+	case formulaError:
+		return string(val)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+type formulaToken struct {
+	kind string
+	text string
+}
+
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	tokens := make([]formulaToken, 0)
+	i := 0
+	for i < len(formula) {
+		c := formula[i]
+		switch {
+		case c == ' ':
+			i++
+		case c == '"':
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(formula) {
+					return nil, errFormulaValue
+				}
+				if formula[i] == '"' {
+					if i+1 < len(formula) && formula[i+1] == '"' {
+						b.WriteByte('"')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(formula[i])
+				i++
+			}
+			tokens = append(tokens, formulaToken{"string", b.String()})
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(formula) && (formula[j] >= '0' && formula[j] <= '9' || formula[j] == '.') {
+				j++
+			}
+			if j < len(formula) && (formula[j] == 'e' || formula[j] == 'E') {
+				j++
+				if j < len(formula) && (formula[j] == '+' || formula[j] == '-') {
+					j++
+				}
+				for j < len(formula) && formula[j] >= '0' && formula[j] <= '9' {
+					j++
+				}
+			}
+			tokens = append(tokens, formulaToken{"number", formula[i:j]})
+			i = j
+		case c == '<' || c == '>':
+			if i+1 < len(formula) && (formula[i+1] == '=' || (c == '<' && formula[i+1] == '>')) {
+				tokens = append(tokens, formulaToken{"op", formula[i : i+2]})
+				i += 2
+			} else {
+				tokens = append(tokens, formulaToken{"op", string(c)})
+				i++
+			}
+		case strings.IndexByte("+-*/^&=%(),:", c) != -1:
+			tokens = append(tokens, formulaToken{"op", string(c)})
+			i++
+		case c == '$' || c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+			j := i
+			for j < len(formula) {
+				d := formula[j]
+				if d == '$' || d == '_' || d == '.' || d >= 'A' && d <= 'Z' || d >= 'a' && d <= 'z' || d >= '0' && d <= '9' {
+					j++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, formulaToken{"ident", formula[i:j]})
+			i = j
+		default:
+			// sheet-qualified references and other syntax are not supported
+			return nil, errFormulaRef
+		}
+	}
+	return tokens, nil
+}
+
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+	eval   *formulaEvaluator
+}
+
+func (p *formulaParser) peek() (formulaToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return formulaToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *formulaParser) acceptOp(ops ...string) (string, bool) {
+	tok, ok := p.peek()
+	if !ok || tok.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expression() (interface{}, error) {
+	left, err := p.concat()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("=", "<>", "<", ">", "<=", ">=")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.concat()
+		if err != nil {
+			return nil, err
+		}
+		left, err = compareFormula(op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *formulaParser) concat() (interface{}, error) {
+	left, err := p.additive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&"); !ok {
+			return left, nil
+		}
+		right, err := p.additive()
+		if err != nil {
+			return nil, err
+		}
+		left = formulaText(left) + formulaText(right)
+	}
+}
+
+func (p *formulaParser) additive() (interface{}, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left, err = arithmetic(op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *formulaParser) term() (interface{}, error) {
+	left, err := p.power()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.power()
+		if err != nil {
+			return nil, err
+		}
+		left, err = arithmetic(op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *formulaParser) power() (interface{}, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("^"); !ok {
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left, err = arithmetic("^", left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *formulaParser) unary() (interface{}, error) {
+	if op, ok := p.acceptOp("-", "+"); ok {
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" {
+			return arithmetic("*", -1.0, v)
+		}
+		return v, nil
+	}
+
+	v, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.acceptOp("%"); ok {
+		return arithmetic("/", v, 100.0)
+	}
+	return v, nil
+}
+
+func (p *formulaParser) primary() (interface{}, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, errFormulaValue
+	}
+	p.pos++
+
+	switch tok.kind {
+	case "number":
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, errFormulaValue
+		}
+		return f, nil
+	case "string":
+		return tok.text, nil
+	case "op":
+		if tok.text == "(" {
+			v, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptOp(")"); !ok {
+				return nil, errFormulaValue
+			}
+			return v, nil
+		}
+		return nil, errFormulaValue
+	}
+
+	upper := strings.ToUpper(tok.text)
+	if next, ok := p.peek(); ok && next.kind == "op" && next.text == "(" {
+		p.pos++
+		return p.call(upper)
+	}
+
+	switch upper {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	}
+
+	if !cellRefPattern.MatchString(tok.text) {
+		return nil, errFormulaName
+	}
+	col, row, err := parseCellReference(tok.text)
+	if err != nil {
+		return nil, errFormulaRef
+	}
+
+	if _, ok := p.acceptOp(":"); ok {
+		end, ok := p.peek()
+		if !ok || end.kind != "ident" || !cellRefPattern.MatchString(end.text) {
+			return nil, errFormulaRef
+		}
+		p.pos++
+		endCol, endRow, err := parseCellReference(end.text)
+		if err != nil {
+			return nil, errFormulaRef
+		}
+		return p.rangeValue(col, row, endCol, endRow)
+	}
+
+	return p.eval.cellValue(col, row)
+}
+
+func (p *formulaParser) rangeValue(col1, row1, col2, row2 int) (interface{}, error) {
+	if col2 < col1 {
+		col1, col2 = col2, col1
+	}
+	if row2 < row1 {
+		row1, row2 = row2, row1
+	}
+	if cells := (col2 - col1 + 1) * (row2 - row1 + 1); cells > p.eval.maxCells {
+		if p.eval.limitErr == nil {
+			p.eval.limitErr = fmt.Errorf("%w: formula range of %d cells, more than %d", ErrLimitExceeded, cells, p.eval.maxCells)
+		}
+		return nil, errFormulaRef
+	}
+
+	rows := make([][]interface{}, 0, row2-row1+1)
+	for r := row1; r <= row2; r++ {
+		values := make([]interface{}, 0, col2-col1+1)
+		for c := col1; c <= col2; c++ {
+			v, err := p.eval.cellValue(c, r)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		rows = append(rows, values)
+	}
+	return cellRangeValue{rows: rows}, nil
+}
+
+func (p *formulaParser) call(name string) (interface{}, error) {
+	if name == "IF" {
+		return p.callIf()
+	}
+	args := make([]interface{}, 0)
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOp(","); ok {
+				continue
+			}
+			if _, ok := p.acceptOp(")"); ok {
+				break
+			}
+			return nil, errFormulaValue
+		}
+	}
+	return callFormulaFunction(name, args)
+}
+
+// callIf evaluates only the branch IF takes and skips the other, so
+// IF(B1=0,0,A1/B1) is 0 rather than the #DIV/0! it guards against.
+func (p *formulaParser) callIf() (interface{}, error) {
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	taken, err := formulaBool(cond)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{} = false
+	for branch := 1; ; branch++ {
+		if _, ok := p.acceptOp(")"); ok {
+			if branch < 2 {
+				return nil, errFormulaValue
+			}
+			return result, nil
+		}
+		if _, ok := p.acceptOp(","); !ok || branch > 2 {
+			return nil, errFormulaValue
+		}
+		if (branch == 1) == taken {
+			if result, err = p.expression(); err != nil {
+				return nil, err
+			}
+		} else if err := p.skipArgument(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipArgument moves past one function argument without evaluating it,
+// stopping before the ',' or ')' that ends it.
+func (p *formulaParser) skipArgument() error {
+	depth := 0
+	for {
+		tok, ok := p.peek()
+		if !ok {
+			return errFormulaValue
+		}
+		if tok.kind == "op" {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				if depth == 0 {
+					return nil
+				}
+				depth--
+			case ",":
+				if depth == 0 {
+					return nil
+				}
+			}
+		}
+		p.pos++
+	}
+}
+
+func flattenFormulaArgs(args []interface{}) []interface{} {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if r, ok := arg.(cellRangeValue); ok {
+			for _, row := range r.rows {
+				values = append(values, row...)
+			}
+			continue
+		}
+		values = append(values, arg)
+	}
+	return values
+}
+
+func callFormulaFunction(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "SUM", "AVERAGE", "MIN", "MAX", "COUNT":
+		numbers := make([]float64, 0)
+		for _, v := range flattenFormulaArgs(args) {
+			if f, ok := v.(float64); ok {
+				numbers = append(numbers, f)
+			}
+		}
+		switch name {
+		case "COUNT":
+			return float64(len(numbers)), nil
+		case "SUM":
+			var sum float64
+			for _, n := range numbers {
+				sum += n
+			}
+			return sum, nil
+		case "AVERAGE":
+			if len(numbers) == 0 {
+				return nil, errFormulaDiv0
+			}
+			var sum float64
+			for _, n := range numbers {
+				sum += n
+			}
+			return sum / float64(len(numbers)), nil
+		}
+		if len(numbers) == 0 {
+			return 0.0, nil
+		}
+		best := numbers[0]
+		for _, n := range numbers[1:] {
+			if (name == "MIN" && n < best) || (name == "MAX" && n > best) {
+				best = n
+			}
+		}
+		return best, nil
+	case "COUNTA":
+		count := 0
+		for _, v := range flattenFormulaArgs(args) {
+			if v != nil && v != "" {
+				count++
+			}
+		}
+		return float64(count), nil
+	case "AND", "OR":
+		values := flattenFormulaArgs(args)
+		if len(values) == 0 {
+			return nil, errFormulaValue
+		}
+		result := name == "AND"
+		for _, v := range values {
+			b, err := formulaBool(v)
+			if err != nil {
+				return nil, err
+			}
+			if name == "AND" {
+				result = result && b
+			} else {
+				result = result || b
+			}
+		}
+		return result, nil
+	case "NOT":
+		if len(args) != 1 {
+			return nil, errFormulaValue
+		}
+		b, err := formulaBool(args[0])
+		return !b, err
+	case "ROUND":
+		if len(args) != 2 {
+			return nil, errFormulaValue
+		}
+		x, err1 := formulaNumber(args[0])
+		digits, err2 := formulaNumber(args[1])
+		if err1 != nil || err2 != nil {
+			return nil, errFormulaValue
+		}
+		scale := math.Pow(10, math.Trunc(digits))
+		return math.Round(x*scale) / scale, nil
+	case "ABS":
+		if len(args) != 1 {
+			return nil, errFormulaValue
+		}
+		x, err := formulaNumber(args[0])
+		return math.Abs(x), err
+	case "CONCATENATE", "CONCAT":
+		var b strings.Builder
+		for _, v := range flattenFormulaArgs(args) {
+			b.WriteString(formulaText(v))
+		}
+		return b.String(), nil
+	case "VLOOKUP":
+		return vlookup(args)
+	}
+	return nil, errFormulaName
+}
+
+func vlookup(args []interface{}) (interface{}, error) {
+	if len(args) < 3 || len(args) > 4 {
+		return nil, errFormulaValue
+	}
+	table, ok := args[1].(cellRangeValue)
+	if !ok {
+		return nil, errFormulaValue
+	}
+	colNum, err := formulaNumber(args[2])
+	if err != nil || colNum < 1 {
+		return nil, errFormulaValue
+	}
+	col := int(colNum) - 1
+
+	approximate := true
+	if len(args) == 4 {
+		approximate, err = formulaBool(args[3])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	match := -1
+	for i, row := range table.rows {
+		if len(row) == 0 {
+			continue
+		}
+		comp := compareFormulaValues(row[0], args[0])
+		if comp == 0 {
+			match = i
+			break
+		}
+		if approximate {
+			if comp > 0 {
+				break
+			}
+			match = i
+		}
+	}
+	if match == -1 {
+		return nil, errFormulaNA
+	}
+	if col >= len(table.rows[match]) {
+		return nil, errFormulaRef
+	}
+	return table.rows[match][col], nil
+}
+
+func formulaNumber(v interface{}) (float64, error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return val, nil
+	case bool:
+		if val {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return 0, errFormulaValue
+		}
+		return f, nil
+	case formulaError:
+		return 0, val
+	}
+	return 0, errFormulaValue
+}
+
+func formulaBool(v interface{}) (bool, error) {
+	switch val := v.(type) {
+	case bool:
+		return val, nil
+	case nil:
+		return false, nil
+	case string:
+		switch strings.ToUpper(val) {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		}
+		return false, errFormulaValue
+	}
+	f, err := formulaNumber(v)
+	return f != 0, err
+}
+
+func formulaText(v interface{}) string {
+	return formatFormulaValue(v, nil)
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	a, err := formulaNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := formulaNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, errFormulaDiv0
+		}
+		return a / b, nil
+	case "^":
+		return math.Pow(a, b), nil
+	}
+	return nil, errFormulaValue
+}
+
+func compareFormulaValues(a, b interface{}) int {
+	fa, aNum := a.(float64)
+	fb, bNum := b.(float64)
+	if aNum && bNum {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	sa := strings.ToLower(formulaText(a))
+	sb := strings.ToLower(formulaText(b))
+	return strings.Compare(sa, sb)
+}
+
+func compareFormula(op string, left, right interface{}) (interface{}, error) {
+	comp := compareFormulaValues(left, right)
+	switch op {
+	case "=":
+		return comp == 0, nil
+	case "<>":
+		return comp != 0, nil
+	case "<":
+		return comp < 0, nil
+	case ">":
+		return comp > 0, nil
+	case "<=":
+		return comp <= 0, nil
+	case ">=":
+		return comp >= 0, nil
+	}
+	return nil, errFormulaValue
+}
@@ -0,0 +1,352 @@
+package gopandas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type xlsxComments struct {
+	Comments []struct {
+		Ref  string `xml:"ref,attr"`
+		Text struct {
+			Inner []byte `xml:",innerxml"`
+		} `xml:"text"`
+	} `xml:"commentList>comment"`
+}
+
+// sheetMetadata holds the cell formatting information stored outside the
+// sheet data: merged ranges, hyperlinks and comments keyed by cell reference.
+type sheetMetadata struct {
+	merges     []string
+	hyperlinks *rangeLookup
+	comments   map[string]string
+}
+
+// rangeLookup maps cell ranges to values without expanding them, since a
+// single A1:XFD1048576 range would otherwise mean billions of entries.
+// Single cells are kept in a map and larger ranges are searched in order.
+type rangeLookup struct {
+	cells  map[[2]int]string
+	ranges []rangeValue
+}
+
+type rangeValue struct {
+	bounds *cellRange
+	value  string
+}
+
+func newRangeLookup() *rangeLookup {
+	return &rangeLookup{cells: make(map[[2]int]string)}
+}
+
+// set stores value for ref, a single cell or a range; unparseable
+// references are ignored.
+func (l *rangeLookup) set(ref, value string) {
+	ref = strings.ReplaceAll(ref, "$", "")
+	bounds, err := parseCellRange(ref)
+	if err != nil {
+		col, row, err := parseCellReference(ref)
+		if err != nil || row < 0 {
+			return
+		}
+		bounds = &cellRange{firstCol: col, firstRow: row, lastCol: col, lastRow: row}
+	}
+	if bounds.firstCol == bounds.lastCol && bounds.firstRow == bounds.lastRow {
+		l.cells[[2]int{bounds.firstRow, bounds.firstCol}] = value
+		return
+	}
+	l.ranges = append(l.ranges, rangeValue{bounds: bounds, value: value})
+}
+
+// get returns the value of the cell at col and row, or "".
+func (l *rangeLookup) get(col, row int) string {
+	if value, ok := l.cells[[2]int{row, col}]; ok {
+		return value
+	}
+	for _, r := range l.ranges {
+		if row >= r.bounds.firstRow && row <= r.bounds.lastRow && col >= r.bounds.firstCol && col <= r.bounds.lastCol {
+			return r.value
+		}
+	}
+	return ""
+}
+
+// anchors returns the row and column of every single cell and of the
+// top-left cell of every range.
+func (l *rangeLookup) anchors() [][2]int {
+	keys := make([][2]int, 0, len(l.cells)+len(l.ranges))
+	for key := range l.cells {
+		keys = append(keys, key)
+	}
+	for _, r := range l.ranges {
+		keys = append(keys, [2]int{r.bounds.firstRow, r.bounds.firstCol})
+	}
+	return keys
+}
+
+func (er *ExcelReader) readSheetMetadata(sheetName string) (*sheetMetadata, error) {
+	worksheetFile, err := er.findWorksheet(sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := worksheetFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	meta := &sheetMetadata{
+		hyperlinks: newRangeLookup(),
+		comments:   make(map[string]string),
+	}
+	links := make(map[string]string)
+	locations := make(map[string]string)
+
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "sheetData":
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+		case "mergeCell":
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "ref" {
+					meta.merges = append(meta.merges, attr.Value)
+				}
+			}
+		case "hyperlink":
+			var ref, id, location string
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "ref":
+					ref = attr.Value
+				case "id":
+					id = attr.Value
+				case "location":
+					location = attr.Value
+				}
+			}
+			if id != "" {
+				links[ref] = id
+			} else if location != "" {
+				locations[ref] = "#" + location
+			}
+		}
+	}
+
+	var rels workbookRels
+	dir, base := path.Split(worksheetFile.Name)
+	if _, err := er.readZipXML(dir+"_rels/"+base+".rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if rel.TargetMode != "External" {
+			target = path.Join(dir, target)
+		}
+		targets[rel.ID] = target
+
+		if strings.HasSuffix(rel.Type, "/comments") {
+			if err := er.readComments(target, meta.comments); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for ref, id := range links {
+		if target, ok := targets[id]; ok {
+			meta.hyperlinks.set(ref, target)
+		}
+	}
+	for ref, location := range locations {
+		meta.hyperlinks.set(ref, location)
+	}
+	return meta, nil
+}
+
+func (er *ExcelReader) readComments(name string, comments map[string]string) error {
+	var doc xlsxComments
+	if _, err := er.readZipXML(name, &doc); err != nil {
+		return fmt.Errorf("failed to read comments: %w", err)
+	}
+
+	for _, comment := range doc.Comments {
+		// comment text is split into rich text runs; keep only the <t> contents
+		var text strings.Builder
+		decoder := xml.NewDecoder(strings.NewReader("<text>" + string(comment.Text.Inner) + "</text>"))
+		inText := false
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				break
+			}
+			switch tok := token.(type) {
+			case xml.StartElement:
+				inText = tok.Name.Local == "t"
+			case xml.EndElement:
+				inText = false
+			case xml.CharData:
+				if inText {
+					text.Write(tok)
+				}
+			}
+		}
+		comments[strings.ToUpper(comment.Ref)] = text.String()
+	}
+	return nil
+}
+
+// fillMerged copies the top-left value of each merged range across the
+// range. numbers holds the sheet row number of each entry in rows. Only
+// rows present in the sheet are filled, and no wider than its widest row,
+// so a huge range costs no more than the data it covers.
+func (meta *sheetMetadata) fillMerged(rows [][]string, numbers []int) {
+	positions := make(map[int]int, len(numbers))
+	width, lastRow := 0, -1
+	for i, n := range numbers {
+		positions[n] = i
+		width = max(width, len(rows[i]))
+		lastRow = max(lastRow, n)
+	}
+
+	for _, ref := range meta.merges {
+		bounds, err := parseCellRange(ref)
+		if err != nil {
+			continue
+		}
+		first, ok := positions[bounds.firstRow]
+		if !ok || bounds.firstCol >= len(rows[first]) {
+			continue
+		}
+		value := rows[first][bounds.firstCol]
+		lastCol := min(bounds.lastCol, width-1)
+
+		for r := bounds.firstRow; r <= min(bounds.lastRow, lastRow); r++ {
+			i, ok := positions[r]
+			if !ok {
+				continue
+			}
+			for c := bounds.firstCol; c <= lastCol; c++ {
+				for len(rows[i]) <= c {
+					rows[i] = append(rows[i], "")
+				}
+				rows[i][c] = value
+			}
+		}
+	}
+}
+
+func cellReference(col, row int) string {
+	name := ""
+	for n := col + 1; n > 0; n = (n - 1) / 26 {
+		name = string(rune('A'+(n-1)%26)) + name
+	}
+	return fmt.Sprintf("%s%d", name, row+1)
+}
+
+// ReadExcelMetadata returns one row per cell that holds a value or a
+// comment or starts a hyperlink or merged range, with columns cell, row,
+// col, value, hyperlink, comment and merged_range. Cells inside a range
+// report its hyperlink and merged range too. Rows and columns are 1-based
+// as in Excel. Only the sheet options apply.
+func ReadExcelMetadata(filename string, options ...ExcelOption) (*DataFrame, error) {
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != ".xlsx" {
+		return nil, fmt.Errorf("unsupported file format for metadata: %s (only .xlsx files are supported)", ext)
+	}
+
+	config := newExcelConfig(options)
+	excelReader, closer, err := openXLSX(filename, config)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	sheet, err := excelReader.resolveSheet(config)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := excelReader.readSheetMetadata(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[[2]int]string)
+	err = excelReader.streamRows(sheet, func(_ int, row xlsxRow) error {
+		for _, cell := range row.Cells {
+			col, r, err := parseCellReference(cell.Reference)
+			if err != nil || r < 0 {
+				continue
+			}
+			values[[2]int{r, col}] = excelReader.getCellValue(cell)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := newRangeLookup()
+	for _, ref := range meta.merges {
+		merged.set(ref, ref)
+	}
+	anchors := append(meta.hyperlinks.anchors(), merged.anchors()...)
+	for ref := range meta.comments {
+		if col, r, err := parseCellReference(ref); err == nil && r >= 0 {
+			anchors = append(anchors, [2]int{r, col})
+		}
+	}
+	for _, key := range anchors {
+		if _, ok := values[key]; !ok {
+			values[key] = ""
+		}
+	}
+
+	keys := make([][2]int, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	result := NewDataFrame([]string{"cell", "row", "col", "value", "hyperlink", "comment", "merged_range"})
+	for _, key := range keys {
+		ref := cellReference(key[1], key[0])
+		result.AddRow([]interface{}{
+			ref, key[0] + 1, key[1] + 1, inferType(values[key]),
+			optionalString(meta.hyperlinks.get(key[1], key[0])), optionalString(meta.comments[ref]), optionalString(merged.get(key[1], key[0])),
+		})
+	}
+	return result, nil
+}
+
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
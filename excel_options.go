@@ -0,0 +1,379 @@
+package gopandas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+type ExcelConfig struct {
+	Sheet            string
+	SheetIndex       int
+	Range            string
+	HeaderRow        int
+	SkipRows         int
+	EvaluateFormulas bool
+	Password         string
+	FillMerged       bool
+	Duplicates       DuplicatePolicy
+	HeaderRows       int
+	HeaderJoiner     string
+	SkipFooter       int
+	AutoFooter       bool
+	Hardening        *Hardening
+}
+
+type ExcelOption func(*ExcelConfig)
+
+func WithSheet(name string) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.Sheet = name
+	}
+}
+
+func WithSheetIndex(index int) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.SheetIndex = index
+	}
+}
+
+func WithRange(cellRange string) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.Range = cellRange
+	}
+}
+
+func WithHeaderRow(row int) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.HeaderRow = row
+	}
+}
+
+func WithSkipRows(n int) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.SkipRows = n
+	}
+}
+
+func WithEvaluateFormulas() ExcelOption {
+	return func(c *ExcelConfig) {
+		c.EvaluateFormulas = true
+	}
+}
+
+func WithPassword(password string) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.Password = password
+	}
+}
+
+// WithFillMerged copies the value of each merged range's top-left cell into
+// every cell the range covers, so grouped labels repeat on each row.
+func WithFillMerged() ExcelOption {
+	return func(c *ExcelConfig) {
+		c.FillMerged = true
+	}
+}
+
+func newExcelConfig(options []ExcelOption) *ExcelConfig {
+	config := &ExcelConfig{
+		SheetIndex: -1,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+type cellRange struct {
+	firstCol int
+	firstRow int
+	lastCol  int
+	lastRow  int
+}
+
+func parseCellRange(ref string) (*cellRange, error) {
+	start, end, found := strings.Cut(strings.TrimSpace(ref), ":")
+	if !found {
+		return nil, fmt.Errorf("invalid range '%s': expected form A1:C10", ref)
+	}
+
+	firstCol, firstRow, err := parseCellReference(start)
+	if err != nil {
+		return nil, err
+	}
+	lastCol, lastRow, err := parseCellReference(end)
+	if err != nil {
+		return nil, err
+	}
+	if firstRow < 0 || lastRow < 0 {
+		// column-only ranges such as B:F cover every row
+		firstRow, lastRow = 0, int(^uint(0)>>1)
+	}
+	if lastCol < firstCol || lastRow < firstRow {
+		return nil, fmt.Errorf("invalid range '%s': end precedes start", ref)
+	}
+
+	return &cellRange{firstCol: firstCol, firstRow: firstRow, lastCol: lastCol, lastRow: lastRow}, nil
+}
+
+// excelFramer turns raw sheet rows into header and data rows, applying the
+// range, skip and header options in a single streaming pass.
+type excelFramer struct {
+	config   *ExcelConfig
+	bounds   *cellRange
+	accepted int
+	header   []string
+	hasHead  bool
+
+	headerParts [][]string
+}
+
+func (f *excelFramer) headerRows() int {
+	if f.config.HeaderRows > 1 {
+		return f.config.HeaderRows
+	}
+	return 1
+}
+
+func newExcelFramer(config *ExcelConfig) (*excelFramer, error) {
+	framer := &excelFramer{config: config}
+	if config.Range != "" {
+		bounds, err := parseCellRange(config.Range)
+		if err != nil {
+			return nil, err
+		}
+		framer.bounds = bounds
+	}
+	if config.HeaderRow < -1 || config.SkipRows < 0 {
+		return nil, fmt.Errorf("header row and skip rows must not be negative")
+	}
+	return framer, nil
+}
+
+func (f *excelFramer) width(fallback int) int {
+	if f.bounds != nil {
+		return f.bounds.lastCol - f.bounds.firstCol + 1
+	}
+	return fallback
+}
+
+// push returns the data cells of a row and whether the row is a data row.
+func (f *excelFramer) push(rowNumber int, cells []string) ([]string, bool) {
+	if f.bounds != nil {
+		if rowNumber < f.bounds.firstRow || rowNumber > f.bounds.lastRow {
+			return nil, false
+		}
+		clipped := make([]string, f.bounds.lastCol-f.bounds.firstCol+1)
+		for i := range clipped {
+			if f.bounds.firstCol+i < len(cells) {
+				clipped[i] = cells[f.bounds.firstCol+i]
+			}
+		}
+		cells = clipped
+	}
+
+	position := f.accepted
+	f.accepted++
+
+	if position < f.config.SkipRows {
+		return nil, false
+	}
+	position -= f.config.SkipRows
+
+	if f.config.HeaderRow >= 0 {
+		if position < f.config.HeaderRow {
+			return nil, false
+		}
+		if position < f.config.HeaderRow+f.headerRows() {
+			f.headerParts = append(f.headerParts, cells)
+			if len(f.headerParts) == f.headerRows() {
+				f.header = combineHeaderRows(f.headerParts, f.config.HeaderJoiner)
+				if len(f.headerParts) == 1 {
+					f.header = cells
+				}
+				f.hasHead = true
+			}
+			return nil, false
+		}
+	}
+
+	return cells, true
+}
+
+func (f *excelFramer) columns(width int) []string {
+	columns := make([]string, width)
+	for i := range columns {
+		if i < len(f.header) {
+			columns[i] = f.header[i]
+		} else {
+			columns[i] = fmt.Sprintf("col_%d", i)
+		}
+	}
+	return columns
+}
+
+func buildExcelFrame(rows [][]string, numbers []int, config *ExcelConfig) (*DataFrame, error) {
+	if config.Hardening != nil {
+		if err := config.Hardening.checkRows(rows); err != nil {
+			return nil, err
+		}
+	}
+	framer, err := newExcelFramer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([][]string, 0, len(rows))
+	for i, row := range rows {
+		number := i
+		if numbers != nil {
+			number = numbers[i]
+		}
+		if cells, ok := framer.push(number, row); ok {
+			data = append(data, cells)
+		}
+	}
+	data = trimFooter(data, config.SkipFooter, config.AutoFooter)
+
+	maxCols := framer.width(len(framer.header))
+	if framer.bounds == nil {
+		for _, row := range data {
+			if len(row) > maxCols {
+				maxCols = len(row)
+			}
+		}
+	}
+
+	columns, err := resolveDuplicates(framer.columns(maxCols), config.Duplicates)
+	if err != nil {
+		return nil, err
+	}
+	limit := newLimitTracker("read_excel")
+	if err := limit.expect(len(data)); err != nil {
+		return nil, err
+	}
+	df := NewDataFrame(columns)
+	builder := newRowBuilder(maxCols)
+	for _, cells := range data {
+		row := builder.build(cells, maxCols)
+		if err := limit.add(row); err != nil {
+			return nil, err
+		}
+		df.AddRow(row)
+	}
+
+	return df, nil
+}
+
+type workbookSheets struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type workbookRels struct {
+	Relationships []struct {
+		ID         string `xml:"Id,attr"`
+		Type       string `xml:"Type,attr"`
+		Target     string `xml:"Target,attr"`
+		TargetMode string `xml:"TargetMode,attr"`
+	} `xml:"Relationship"`
+}
+
+func (er *ExcelReader) readZipXML(name string, v interface{}) (bool, error) {
+	for _, file := range er.zipReader.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return true, err
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return true, err
+		}
+		return true, xml.Unmarshal(data, v)
+	}
+	return false, nil
+}
+
+func (er *ExcelReader) sheetTargets() ([]string, []string, error) {
+	var workbook workbookSheets
+	found, err := er.readZipXML("xl/workbook.xml", &workbook)
+	if err != nil || !found {
+		return nil, nil, err
+	}
+
+	var rels workbookRels
+	if _, err := er.readZipXML("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, nil, err
+	}
+	names, files := workbookTargets(workbook, rels)
+	return names, files, nil
+}
+
+// workbookTargets pairs each sheet name with its worksheet part.
+func workbookTargets(workbook workbookSheets, rels workbookRels) ([]string, []string) {
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	names := make([]string, len(workbook.Sheets))
+	files := make([]string, len(workbook.Sheets))
+	for i, sheet := range workbook.Sheets {
+		names[i] = sheet.Name
+		files[i] = targets[sheet.ID]
+		if files[i] == "" {
+			files[i] = fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		}
+	}
+	return names, files
+}
+
+func (er *ExcelReader) resolveSheet(config *ExcelConfig) (string, error) {
+	names, files, err := er.sheetTargets()
+	if err != nil {
+		return "", fmt.Errorf("failed to read workbook: %w", err)
+	}
+
+	if config.SheetIndex >= 0 {
+		if len(files) == 0 {
+			return fmt.Sprintf("xl/worksheets/sheet%d.xml", config.SheetIndex+1), nil
+		}
+		if config.SheetIndex >= len(files) {
+			return "", fmt.Errorf("sheet index %d out of range (workbook has %d sheets)", config.SheetIndex, len(files))
+		}
+		return files[config.SheetIndex], nil
+	}
+
+	if config.Sheet != "" {
+		for i, name := range names {
+			if name == config.Sheet {
+				return files[i], nil
+			}
+		}
+		for i, name := range names {
+			if strings.EqualFold(name, config.Sheet) {
+				return files[i], nil
+			}
+		}
+		return strings.ToLower(config.Sheet) + ".xml", nil
+	}
+
+	if len(files) > 0 {
+		return files[0], nil
+	}
+	return "sheet1.xml", nil
+}
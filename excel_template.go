@@ -0,0 +1,356 @@
+package gopandas
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	cellRefAttrPattern = regexp.MustCompile(`^(<c\b[^>]*?\sr=")[^"]*"`)
+	tableRefPattern    = regexp.MustCompile(`(<table\b[^>]*?\sref=")[^"]*"`)
+	autoFilterPattern  = regexp.MustCompile(`(<autoFilter\b[^>]*?\sref=")[^"]*"`)
+
+	localSheetIDPattern = regexp.MustCompile(`\slocalSheetId="`)
+)
+
+type xlsxTable struct {
+	Name           string `xml:"name,attr"`
+	DisplayName    string `xml:"displayName,attr"`
+	Ref            string `xml:"ref,attr"`
+	HeaderRowCount *int   `xml:"headerRowCount,attr"`
+	TotalsRowCount int    `xml:"totalsRowCount,attr"`
+	Columns        []struct {
+		Name    string `xml:"name,attr"`
+		Formula string `xml:"calculatedColumnFormula"`
+	} `xml:"tableColumns>tableColumn"`
+}
+
+// templateRegion is the block of a sheet that WriteToNamedRange fills.
+type templateRegion struct {
+	part     string
+	firstCol int
+	firstRow int // first data row
+	width    int
+	rows     int // data rows in the template
+	totals   bool
+	sources  []int    // frame column for each region column, or -1
+	formulas []string // calculated column formulas, by region column
+	resize   func(rows int)
+}
+
+// WriteToNamedRange fills a defined name or an Excel table of a template
+// workbook with the frame, leaving styles, formulas and other sheets as they
+// are. A defined name must cover exactly the data rows, one column per frame
+// column; a table's columns are matched by header name, and its calculated
+// columns are carried into added rows. The name or table is resized to the
+// frame's row count: rows are added below using the styles of the last
+// template row, and leftover template rows are blanked.
+func WriteToNamedRange(filename, name string, df *DataFrame) error {
+	pkg, err := loadXLSXPackage(filename)
+	if err != nil {
+		return err
+	}
+
+	region, err := pkg.tableRegion(name, df)
+	if err == nil && region == nil {
+		region, err = pkg.definedNameRegion(name, df)
+	}
+	if err != nil {
+		return err
+	}
+	if region == nil {
+		return fmt.Errorf("no defined name or table named '%s'", name)
+	}
+
+	ws, err := parseWorksheet(pkg.files[region.part])
+	if err != nil {
+		return fmt.Errorf("failed to read worksheet '%s': %w", region.part, err)
+	}
+	ws.fillRegion(region, df)
+	pkg.set(region.part, ws.bytes())
+
+	rows := len(df.data)
+	if rows == 0 {
+		// a range or table keeps at least one (blank) data row
+		rows = 1
+	}
+	region.resize(rows)
+	pkg.recalculate()
+	return pkg.save(filename)
+}
+
+func (pkg *xlsxPackage) definedNameRegion(name string, df *DataFrame) (*templateRegion, error) {
+	pattern := regexp.MustCompile(`(<definedName\b[^>]*\sname="` + regexp.QuoteMeta(name) + `"[^>]*>)([^<]*)(</definedName>)`)
+	workbook := pkg.files["xl/workbook.xml"]
+	// a name can be defined once per sheet (localSheetId) and once for the
+	// workbook; the workbook-wide one wins, as when Excel resolves it
+	matches := pattern.FindAllSubmatch(workbook, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	match := matches[0]
+	for _, m := range matches {
+		if !localSheetIDPattern.Match(m[1]) {
+			match = m
+			break
+		}
+	}
+	// resize rewrites only this definition, matched by its whole start tag
+	// so another scope's definition of the name is left alone
+	pattern = regexp.MustCompile(`(` + regexp.QuoteMeta(string(match[1])) + `)([^<]*)(</definedName>)`)
+
+	formula := xmlUnescape(string(match[2]))
+	bang := strings.LastIndex(formula, "!")
+	if bang < 0 || strings.ContainsAny(formula, ",()") {
+		return nil, fmt.Errorf("defined name '%s' is not a single cell range: %s", name, formula)
+	}
+	sheet := strings.TrimPrefix(formula[:bang], "=")
+	if strings.HasPrefix(sheet, "'") {
+		sheet = strings.ReplaceAll(strings.Trim(sheet, "'"), "''", "'")
+	}
+	ref := formula[bang+1:]
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	bounds, err := parseCellRange(ref)
+	if err != nil {
+		return nil, fmt.Errorf("defined name '%s': %w", name, err)
+	}
+	if !strings.ContainsAny(ref, "0123456789") {
+		return nil, fmt.Errorf("defined name '%s' covers whole columns: %s", name, formula)
+	}
+
+	part, _, err := pkg.findSheet(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if part == "" || sheet == "" {
+		return nil, fmt.Errorf("defined name '%s' refers to unknown sheet '%s'", name, sheet)
+	}
+
+	width := bounds.lastCol - bounds.firstCol + 1
+	if width != len(df.columns) {
+		return nil, fmt.Errorf("defined name '%s' has %d columns, frame has %d", name, width, len(df.columns))
+	}
+	region := &templateRegion{
+		part:     part,
+		firstCol: bounds.firstCol,
+		firstRow: bounds.firstRow,
+		width:    width,
+		rows:     bounds.lastRow - bounds.firstRow + 1,
+		sources:  make([]int, width),
+		formulas: make([]string, width),
+	}
+	for k := range region.sources {
+		region.sources[k] = k
+	}
+
+	prefix := formula[:bang+1]
+	region.resize = func(rows int) {
+		value := prefix + absoluteReference(region.firstCol, region.firstRow) + ":" +
+			absoluteReference(region.firstCol+width-1, region.firstRow+rows-1)
+		var escaped bytes.Buffer
+		xml.EscapeText(&escaped, []byte(value))
+		updated := pattern.ReplaceAllFunc(pkg.files["xl/workbook.xml"], func(m []byte) []byte {
+			parts := pattern.FindSubmatch(m)
+			return append(append(append([]byte{}, parts[1]...), escaped.Bytes()...), parts[3]...)
+		})
+		pkg.set("xl/workbook.xml", updated)
+	}
+	return region, nil
+}
+
+func (pkg *xlsxPackage) tableRegion(name string, df *DataFrame) (*templateRegion, error) {
+	_, sheets, err := pkg.sheetTargets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range sheets {
+		rels, ok := pkg.files[path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")]
+		if !ok {
+			continue
+		}
+		var sheetRels workbookRels
+		if err := xml.Unmarshal(rels, &sheetRels); err != nil {
+			return nil, fmt.Errorf("failed to read relationships of '%s': %w", part, err)
+		}
+		for _, rel := range sheetRels.Relationships {
+			if !strings.HasSuffix(rel.Type, "/table") {
+				continue
+			}
+			tablePart := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(rel.Target, "/") {
+				tablePart = path.Join(path.Dir(part), rel.Target)
+			}
+			var table xlsxTable
+			if err := xml.Unmarshal(pkg.files[tablePart], &table); err != nil {
+				continue
+			}
+			if strings.EqualFold(table.DisplayName, name) || strings.EqualFold(table.Name, name) {
+				return pkg.newTableRegion(part, tablePart, table, df)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (pkg *xlsxPackage) newTableRegion(part, tablePart string, table xlsxTable, df *DataFrame) (*templateRegion, error) {
+	bounds, err := parseCellRange(table.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("table '%s': %w", table.DisplayName, err)
+	}
+	header := 1
+	if table.HeaderRowCount != nil {
+		header = *table.HeaderRowCount
+	}
+
+	width := bounds.lastCol - bounds.firstCol + 1
+	region := &templateRegion{
+		part:     part,
+		firstCol: bounds.firstCol,
+		firstRow: bounds.firstRow + header,
+		width:    width,
+		rows:     bounds.lastRow - bounds.firstRow + 1 - header - table.TotalsRowCount,
+		totals:   table.TotalsRowCount > 0,
+		sources:  make([]int, width),
+		formulas: make([]string, width),
+	}
+	for k := range region.sources {
+		region.sources[k] = -1
+		if k < len(table.Columns) {
+			region.formulas[k] = table.Columns[k].Formula
+		}
+	}
+	for j, col := range df.columns {
+		found := false
+		for k := 0; k < width && k < len(table.Columns); k++ {
+			if strings.EqualFold(table.Columns[k].Name, col) {
+				region.sources[k] = j
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column '%s' is not in table '%s'", col, table.DisplayName)
+		}
+	}
+
+	region.resize = func(rows int) {
+		lastRow := region.firstRow + rows - 1
+		filterRef := cellReference(region.firstCol, bounds.firstRow) + ":" + cellReference(bounds.lastCol, lastRow)
+		tableRef := cellReference(region.firstCol, bounds.firstRow) + ":" + cellReference(bounds.lastCol, lastRow+table.TotalsRowCount)
+		data := tableRefPattern.ReplaceAll(pkg.files[tablePart], []byte(`${1}`+tableRef+`"`))
+		data = autoFilterPattern.ReplaceAll(data, []byte(`${1}`+filterRef+`"`))
+		pkg.set(tablePart, data)
+	}
+	return region, nil
+}
+
+// fillRegion writes df into region. Cells beyond the template rows take the
+// style of the last template row; template rows past the frame are blanked.
+func (ws *worksheetXML) fillRegion(region *templateRegion, df *DataFrame) {
+	var totals []sheetCell
+	if region.totals {
+		totals = ws.takeCells(region.firstRow+region.rows, region.firstCol, region.width)
+	}
+
+	styles := make([]string, region.width)
+	if region.rows > 0 {
+		last := ws.row(region.firstRow + region.rows - 1)
+		for k := range styles {
+			if cell, ok := last.cell(region.firstCol + k); ok {
+				styles[k] = cell.style
+			}
+		}
+	}
+
+	n := len(df.data)
+	for i := 0; i < n || i < region.rows; i++ {
+		number := region.firstRow + i
+		row := ws.row(number)
+		for k := 0; k < region.width; k++ {
+			col := region.firstCol + k
+			existing, exists := row.cell(col)
+			style := existing.style
+			if i >= region.rows {
+				style = styles[k]
+			}
+
+			var raw []byte
+			switch {
+			case i >= n:
+				raw = renderCell(col, number, style, nil)
+			case region.sources[k] >= 0:
+				raw = renderCell(col, number, style, df.data[i][region.sources[k]])
+			case region.formulas[k] != "":
+				raw = renderFormula(col, number, style, region.formulas[k])
+			case exists && i < region.rows:
+				continue
+			default:
+				raw = renderCell(col, number, style, nil)
+			}
+			row.set(sheetCell{col: col, style: style, raw: raw})
+		}
+	}
+
+	if region.totals {
+		rows := n
+		if rows == 0 {
+			rows = 1
+		}
+		to := ws.row(region.firstRow + rows)
+		for _, cell := range totals {
+			cell.raw = cellRefAttrPattern.ReplaceAll(cell.raw, []byte(`${1}`+cellReference(cell.col, to.number)+`"`))
+			to.set(cell)
+		}
+	}
+}
+
+// takeCells removes and returns the cells of a row within [col, col+width).
+func (ws *worksheetXML) takeCells(number, col, width int) []sheetCell {
+	row := ws.row(number)
+	taken := make([]sheetCell, 0, width)
+	kept := row.cells[:0]
+	for _, cell := range row.cells {
+		if cell.col >= col && cell.col < col+width {
+			taken = append(taken, cell)
+		} else {
+			kept = append(kept, cell)
+		}
+	}
+	row.cells = kept
+	return taken
+}
+
+func renderFormula(col, row int, style, formula string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<c r="%s"`, cellReference(col, row))
+	if style != "" {
+		fmt.Fprintf(&buf, ` s="%s"`, style)
+	}
+	buf.WriteString("><f>")
+	xml.EscapeText(&buf, []byte(formula))
+	buf.WriteString("</f></c>")
+	return buf.Bytes()
+}
+
+func absoluteReference(col, row int) string {
+	ref := cellReference(col, row)
+	i := strings.IndexAny(ref, "0123456789")
+	return "$" + ref[:i] + "$" + ref[i:]
+}
+
+func xmlUnescape(s string) string {
+	var out struct {
+		Text string `xml:",chardata"`
+	}
+	if err := xml.Unmarshal([]byte("<v>"+s+"</v>"), &out); err != nil {
+		return s
+	}
+	return out.Text
+}
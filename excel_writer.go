@@ -0,0 +1,713 @@
+package gopandas
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	worksheetContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
+	worksheetRelType     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
+)
+
+var (
+	sheetIDPattern   = regexp.MustCompile(`sheetId="(\d+)"`)
+	calcPrPattern    = regexp.MustCompile(`<calcPr\b[^>]*?/?>`)
+	dimensionPattern = regexp.MustCompile(`<dimension\b[^>]*?/>`)
+	calcChainPattern = regexp.MustCompile(`<(Override|Relationship)\b[^>]*calcChain[^>]*/>`)
+
+	sharedFormulaPattern = regexp.MustCompile(`<(\w+:)?f\b[^>]*?(/>|>[^<]*</(\w+:)?f>)`)
+	formulaRefPattern    = regexp.MustCompile(`(\$?)([A-Za-z]{1,3})(\$?)([0-9]+)`)
+)
+
+// ToExcelAppend writes the frame, header first, into sheet of an existing
+// workbook with its top-left cell at startCell ("A1" when empty). Other
+// sheets, cells outside the written block and the styles of cells inside it
+// are kept, so report templates can be refreshed in place. An empty sheet
+// means the first one; a missing sheet is added, and a missing file is
+// created as a new workbook. Formulas are recalculated when Excel opens it.
+func (df *DataFrame) ToExcelAppend(filename, sheet, startCell string) error {
+	if startCell == "" {
+		startCell = "A1"
+	}
+	col, row, err := parseCellReference(startCell)
+	if err != nil || row < 0 {
+		return fmt.Errorf("invalid start cell '%s'", startCell)
+	}
+
+	pkg, err := loadXLSXPackage(filename)
+	if err != nil {
+		return err
+	}
+	part, err := pkg.worksheet(sheet)
+	if err != nil {
+		return err
+	}
+	ws, err := parseWorksheet(pkg.files[part])
+	if err != nil {
+		return fmt.Errorf("failed to read worksheet '%s': %w", part, err)
+	}
+
+	header := make([]interface{}, len(df.columns))
+	for j, name := range df.columns {
+		header[j] = name
+	}
+	ws.setRow(row, col, header)
+	for i, values := range df.data {
+		ws.setRow(row+1+i, col, values)
+	}
+
+	pkg.set(part, ws.bytes())
+	pkg.recalculate()
+	return pkg.save(filename)
+}
+
+// xlsxPackage holds every part of a workbook in memory, in archive order,
+// so single parts can be rewritten and the rest copied back untouched.
+type xlsxPackage struct {
+	names []string
+	files map[string][]byte
+}
+
+func newXLSXPackage() *xlsxPackage {
+	pkg := &xlsxPackage{files: make(map[string][]byte)}
+	pkg.set("[Content_Types].xml", []byte(xml.Header+`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`+
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`+
+		`<Default Extension="xml" ContentType="application/xml"/>`+
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/></Types>`))
+	pkg.set("_rels/.rels", []byte(xml.Header+`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`))
+	pkg.set("xl/workbook.xml", []byte(xml.Header+`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets></sheets></workbook>`))
+	pkg.set("xl/_rels/workbook.xml.rels", []byte(xml.Header+`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`))
+	return pkg
+}
+
+func loadXLSXPackage(filename string) (*xlsxPackage, error) {
+	data, err := readAllPath(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newXLSXPackage(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		if isCompoundFile(data) {
+			return nil, fmt.Errorf("failed to open Excel file: encrypted workbooks cannot be written")
+		}
+		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+
+	pkg := &xlsxPackage{files: make(map[string][]byte)}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		pkg.set(file.Name, content)
+	}
+	if _, ok := pkg.files["xl/workbook.xml"]; !ok {
+		return nil, fmt.Errorf("failed to open Excel file: no xl/workbook.xml part")
+	}
+	return pkg, nil
+}
+
+func (pkg *xlsxPackage) set(name string, data []byte) {
+	if _, ok := pkg.files[name]; !ok {
+		pkg.names = append(pkg.names, name)
+	}
+	pkg.files[name] = data
+}
+
+func (pkg *xlsxPackage) remove(name string) {
+	if _, ok := pkg.files[name]; !ok {
+		return
+	}
+	delete(pkg.files, name)
+	for i, n := range pkg.names {
+		if n == name {
+			pkg.names = append(pkg.names[:i], pkg.names[i+1:]...)
+			break
+		}
+	}
+}
+
+func (pkg *xlsxPackage) sheetTargets() ([]string, []string, error) {
+	var workbook workbookSheets
+	if err := xml.Unmarshal(pkg.files["xl/workbook.xml"], &workbook); err != nil {
+		return nil, nil, fmt.Errorf("failed to read workbook: %w", err)
+	}
+	var rels workbookRels
+	if data, ok := pkg.files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := xml.Unmarshal(data, &rels); err != nil {
+			return nil, nil, fmt.Errorf("failed to read workbook: %w", err)
+		}
+	}
+	names, files := workbookTargets(workbook, rels)
+	return names, files, nil
+}
+
+// worksheet returns the part holding sheet, adding the sheet if needed.
+func (pkg *xlsxPackage) worksheet(sheet string) (string, error) {
+	part, count, err := pkg.findSheet(sheet)
+	if err != nil || part != "" {
+		return part, err
+	}
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	return pkg.addSheet(sheet, count)
+}
+
+// findSheet returns the part holding sheet ("" when there is none) and the
+// number of sheets in the workbook. An empty name means the first sheet.
+func (pkg *xlsxPackage) findSheet(sheet string) (string, int, error) {
+	names, files, err := pkg.sheetTargets()
+	if err != nil {
+		return "", 0, err
+	}
+	if sheet == "" && len(files) > 0 {
+		return files[0], len(names), nil
+	}
+	for i, name := range names {
+		if name == sheet {
+			return files[i], len(names), nil
+		}
+	}
+	for i, name := range names {
+		if strings.EqualFold(name, sheet) {
+			return files[i], len(names), nil
+		}
+	}
+	return "", len(names), nil
+}
+
+func (pkg *xlsxPackage) addSheet(name string, count int) (string, error) {
+	if len(name) > 31 || strings.ContainsAny(name, `[]:*?/\`) {
+		return "", fmt.Errorf("invalid sheet name '%s'", name)
+	}
+
+	n := count + 1
+	for pkg.files[fmt.Sprintf("xl/worksheets/sheet%d.xml", n)] != nil {
+		n++
+	}
+	part := fmt.Sprintf("xl/worksheets/sheet%d.xml", n)
+
+	rels := string(pkg.files["xl/_rels/workbook.xml.rels"])
+	id := 1
+	for strings.Contains(rels, fmt.Sprintf(`Id="rId%d"`, id)) {
+		id++
+	}
+	sheetID := 1
+	workbook := string(pkg.files["xl/workbook.xml"])
+	for _, match := range sheetIDPattern.FindAllStringSubmatch(workbook, -1) {
+		if v, _ := strconv.Atoi(match[1]); v >= sheetID {
+			sheetID = v + 1
+		}
+	}
+
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(name))
+	sheetTag := fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escaped.String(), sheetID, id)
+	if workbook, ok := insertBefore(workbook, "</sheets>", sheetTag); ok {
+		pkg.set("xl/workbook.xml", []byte(workbook))
+	} else {
+		return "", fmt.Errorf("failed to add sheet '%s': workbook has no sheet list", name)
+	}
+
+	relTag := fmt.Sprintf(`<Relationship Id="rId%d" Type="%s" Target="worksheets/sheet%d.xml"/>`, id, worksheetRelType, n)
+	if rels == "" {
+		rels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`
+	}
+	rels, _ = insertBefore(rels, "</Relationships>", relTag)
+	pkg.set("xl/_rels/workbook.xml.rels", []byte(rels))
+
+	types := string(pkg.files["[Content_Types].xml"])
+	override := fmt.Sprintf(`<Override PartName="/%s" ContentType="%s"/>`, part, worksheetContentType)
+	types, _ = insertBefore(types, "</Types>", override)
+	pkg.set("[Content_Types].xml", []byte(types))
+
+	pkg.set(part, []byte(xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`))
+	return part, nil
+}
+
+// recalculate drops the cached calculation chain, which may list cells that
+// were overwritten, and asks Excel to recompute every formula on load.
+func (pkg *xlsxPackage) recalculate() {
+	if _, ok := pkg.files["xl/calcChain.xml"]; ok {
+		pkg.remove("xl/calcChain.xml")
+		for _, name := range []string{"[Content_Types].xml", "xl/_rels/workbook.xml.rels"} {
+			pkg.set(name, calcChainPattern.ReplaceAll(pkg.files[name], nil))
+		}
+	}
+
+	workbook := string(pkg.files["xl/workbook.xml"])
+	if tag := calcPrPattern.FindString(workbook); tag != "" {
+		if !strings.Contains(tag, "fullCalcOnLoad") {
+			workbook = strings.Replace(workbook, tag, strings.Replace(tag, "<calcPr", `<calcPr fullCalcOnLoad="1"`, 1), 1)
+		}
+	} else {
+		// calcPr follows these elements in the schema, so place it after
+		// the last one present
+		for _, anchor := range []string{"</definedNames>", "</externalReferences>", "</functionGroups>", "</sheets>"} {
+			if i := strings.LastIndex(workbook, anchor); i >= 0 {
+				i += len(anchor)
+				workbook = workbook[:i] + `<calcPr fullCalcOnLoad="1"/>` + workbook[i:]
+				break
+			}
+		}
+	}
+	pkg.set("xl/workbook.xml", []byte(workbook))
+}
+
+func (pkg *xlsxPackage) save(filename string) error {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, name := range pkg.names {
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return fmt.Errorf("failed to write Excel file: %w", err)
+		}
+		if _, err := w.Write(pkg.files[name]); err != nil {
+			return fmt.Errorf("failed to write Excel file: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write Excel file: %w", err)
+	}
+
+	// a local workbook is replaced in one rename so a failed save never
+	// leaves it half written
+	if fs, resolved := resolveFileSystem(filename); fs == (localFileSystem{}) {
+		if err := writeFileAtomic(resolved, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write Excel file: %w", err)
+		}
+		return nil
+	}
+	file, err := createPath(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write Excel file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
+
+func insertBefore(s, anchor, text string) (string, bool) {
+	i := strings.LastIndex(s, anchor)
+	if i < 0 {
+		return s, false
+	}
+	return s[:i] + text + s[i:], true
+}
+
+// worksheetXML is a worksheet split around its sheetData, with each
+// existing cell kept as raw XML so cells that are not written survive
+// byte for byte.
+type worksheetXML struct {
+	head   []byte
+	tail   []byte
+	rows   []*sheetRow
+	shared map[string]sharedFormula
+}
+
+// sharedFormula is the master of a shared formula group: the cell that
+// holds the formula text the other cells of the group reuse.
+type sharedFormula struct {
+	col  int
+	row  int
+	text string
+}
+
+type sheetRow struct {
+	number int
+	attrs  []xml.Attr
+	cells  []sheetCell
+}
+
+type sheetCell struct {
+	col    int
+	style  string
+	raw    []byte
+	shared string // si of the shared formula the cell belongs to
+	master bool
+}
+
+func parseWorksheet(data []byte) (*worksheetXML, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	ws := &worksheetXML{shared: make(map[string]sharedFormula)}
+	start, end := int64(-1), int64(-1)
+	sheetDepth, depth := 0, 0
+	var row *sheetRow
+	var cell sheetCell
+	var cellStart int64
+	var formula strings.Builder
+	inFormula := false
+	nextRow, nextCol := 0, 0
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case start < 0 && t.Name.Local == "sheetData":
+				start, sheetDepth = offset, depth
+			case end >= 0 || start < 0:
+			case depth == sheetDepth+1 && t.Name.Local == "row":
+				row = &sheetRow{number: nextRow}
+				for _, attr := range t.Attr {
+					switch {
+					case attr.Name.Local == "r" && attr.Name.Space == "":
+						if n, err := strconv.Atoi(attr.Value); err == nil && n > 0 {
+							row.number = n - 1
+						}
+					case attr.Name.Local == "spans" && attr.Name.Space == "":
+						// spans is a hint that goes stale once cells move
+					default:
+						row.attrs = append(row.attrs, attr)
+					}
+				}
+				nextRow, nextCol = row.number+1, 0
+			case depth == sheetDepth+2 && t.Name.Local == "c" && row != nil:
+				cell, cellStart = sheetCell{col: nextCol}, offset
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "r":
+						if col, _, err := parseCellReference(attr.Value); err == nil {
+							cell.col = col
+						}
+					case "s":
+						cell.style = attr.Value
+					}
+				}
+				nextCol = cell.col + 1
+			case depth == sheetDepth+3 && t.Name.Local == "f" && row != nil:
+				shared, ref, si := false, "", ""
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "t":
+						shared = attr.Value == "shared"
+					case "ref":
+						ref = attr.Value
+					case "si":
+						si = attr.Value
+					}
+				}
+				if shared && si != "" {
+					cell.shared, cell.master = si, ref != ""
+					formula.Reset()
+					inFormula = cell.master
+				}
+			}
+		case xml.CharData:
+			if inFormula {
+				formula.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case start < 0 || end >= 0:
+			case depth == sheetDepth:
+				end = decoder.InputOffset()
+			case depth == sheetDepth+1 && row != nil:
+				ws.rows = append(ws.rows, row)
+				row = nil
+			case depth == sheetDepth+3 && t.Name.Local == "f" && inFormula:
+				ws.shared[cell.shared] = sharedFormula{col: cell.col, row: row.number, text: formula.String()}
+				inFormula = false
+			case depth == sheetDepth+2 && t.Name.Local == "c" && row != nil:
+				cell.raw = data[cellStart:decoder.InputOffset()]
+				row.set(cell)
+			}
+			depth--
+		}
+	}
+
+	if start < 0 || end < 0 {
+		return nil, fmt.Errorf("worksheet has no sheetData")
+	}
+	ws.head = data[:start]
+	ws.tail = data[end:]
+	sort.SliceStable(ws.rows, func(i, j int) bool { return ws.rows[i].number < ws.rows[j].number })
+	return ws, nil
+}
+
+func (ws *worksheetXML) row(number int) *sheetRow {
+	i := sort.Search(len(ws.rows), func(i int) bool { return ws.rows[i].number >= number })
+	if i < len(ws.rows) && ws.rows[i].number == number {
+		return ws.rows[i]
+	}
+	row := &sheetRow{number: number}
+	ws.rows = append(ws.rows, nil)
+	copy(ws.rows[i+1:], ws.rows[i:])
+	ws.rows[i] = row
+	return row
+}
+
+// setRow writes values into row from column col on, keeping the style of
+// each cell it replaces.
+func (ws *worksheetXML) setRow(number, col int, values []interface{}) {
+	row := ws.row(number)
+	for j, val := range values {
+		style := ""
+		if existing, ok := row.cell(col + j); ok {
+			style = existing.style
+		}
+		row.set(sheetCell{col: col + j, style: style, raw: renderCell(col+j, number, style, val)})
+	}
+}
+
+func (r *sheetRow) cell(col int) (sheetCell, bool) {
+	i := sort.Search(len(r.cells), func(i int) bool { return r.cells[i].col >= col })
+	if i < len(r.cells) && r.cells[i].col == col {
+		return r.cells[i], true
+	}
+	return sheetCell{}, false
+}
+
+// set stores cell, replacing one in the same column; a cell with no XML
+// removes it.
+func (r *sheetRow) set(cell sheetCell) {
+	i := sort.Search(len(r.cells), func(i int) bool { return r.cells[i].col >= cell.col })
+	exists := i < len(r.cells) && r.cells[i].col == cell.col
+	switch {
+	case cell.raw == nil && exists:
+		r.cells = append(r.cells[:i], r.cells[i+1:]...)
+	case cell.raw == nil:
+	case exists:
+		r.cells[i] = cell
+	default:
+		r.cells = append(r.cells, sheetCell{})
+		copy(r.cells[i+1:], r.cells[i:])
+		r.cells[i] = cell
+	}
+}
+
+// expandShared gives every cell of a shared formula group whose master was
+// overwritten its own copy of the formula, shifted to the cell's position,
+// since the group's other cells carry no formula text of their own.
+func (ws *worksheetXML) expandShared() {
+	masters := make(map[string]bool)
+	for _, row := range ws.rows {
+		for _, cell := range row.cells {
+			if cell.master {
+				masters[cell.shared] = true
+			}
+		}
+	}
+	for _, row := range ws.rows {
+		for k, cell := range row.cells {
+			master, ok := ws.shared[cell.shared]
+			if cell.shared == "" || cell.master || masters[cell.shared] || !ok {
+				continue
+			}
+			var text bytes.Buffer
+			xml.EscapeText(&text, []byte(shiftFormula(master.text, cell.col-master.col, row.number-master.row)))
+			raw := sharedFormulaPattern.ReplaceAllLiteral(cell.raw, []byte("<f>"+text.String()+"</f>"))
+			row.cells[k] = sheetCell{col: cell.col, style: cell.style, raw: raw}
+		}
+	}
+}
+
+// shiftFormula moves the relative cell references of formula by cols and
+// rows, leaving text in quotes and absolute ($) parts alone.
+func shiftFormula(formula string, cols, rows int) string {
+	var out strings.Builder
+	quote := byte(0)
+	last := 0
+	flush := func(end int) {
+		segment := formula[last:end]
+		matches := formulaRefPattern.FindAllStringSubmatchIndex(segment, -1)
+		prev := 0
+		for _, m := range matches {
+			before := byte(0)
+			if m[0] > 0 {
+				before = segment[m[0]-1]
+			}
+			after := byte(0)
+			if m[1] < len(segment) {
+				after = segment[m[1]]
+			}
+			if isNameByte(before) || before == '.' || isNameByte(after) || after == '(' {
+				continue
+			}
+			col, row, err := parseCellReference(segment[m[4]:m[5]] + segment[m[8]:m[9]])
+			if err != nil {
+				continue
+			}
+			if m[3] == m[2] {
+				col += cols
+			}
+			if m[7] == m[6] {
+				row += rows
+			}
+			if col < 0 || row < 0 {
+				continue
+			}
+			ref := cellReference(col, row)
+			i := strings.IndexAny(ref, "0123456789")
+			out.WriteString(segment[prev:m[0]])
+			out.WriteString(segment[m[2]:m[3]] + ref[:i] + segment[m[6]:m[7]] + ref[i:])
+			prev = m[1]
+		}
+		out.WriteString(segment[prev:])
+	}
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		switch {
+		case quote == 0 && (c == '"' || c == '\''):
+			flush(i)
+			quote, last = c, i
+		case quote != 0 && c == quote:
+			out.WriteString(formula[last : i+1])
+			quote, last = 0, i+1
+		}
+	}
+	if quote != 0 {
+		out.WriteString(formula[last:])
+	} else {
+		flush(len(formula))
+	}
+	return out.String()
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+func (ws *worksheetXML) bytes() []byte {
+	ws.expandShared()
+	var buf bytes.Buffer
+	minCol, minRow, maxCol, maxRow := -1, -1, -1, -1
+	for _, row := range ws.rows {
+		if len(row.cells) == 0 {
+			continue
+		}
+		if minRow < 0 {
+			minRow = row.number
+		}
+		maxRow = row.number
+		if first := row.cells[0].col; minCol < 0 || first < minCol {
+			minCol = first
+		}
+		if last := row.cells[len(row.cells)-1].col; last > maxCol {
+			maxCol = last
+		}
+	}
+
+	head := ws.head
+	if minRow >= 0 {
+		ref := cellReference(minCol, minRow)
+		if maxCol != minCol || maxRow != minRow {
+			ref += ":" + cellReference(maxCol, maxRow)
+		}
+		head = dimensionPattern.ReplaceAll(head, []byte(`<dimension ref="`+ref+`"/>`))
+	}
+	buf.Write(head)
+
+	buf.WriteString("<sheetData>")
+	for _, row := range ws.rows {
+		if len(row.cells) == 0 && len(row.attrs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, `<row r="%d"`, row.number+1)
+		for _, attr := range row.attrs {
+			name := attr.Name.Local
+			if attr.Name.Space != "" {
+				name = attr.Name.Space + ":" + name
+			}
+			buf.WriteString(" " + name + `="`)
+			xml.EscapeText(&buf, []byte(attr.Value))
+			buf.WriteString(`"`)
+		}
+		buf.WriteString(">")
+		for _, cell := range row.cells {
+			buf.Write(cell.raw)
+		}
+		buf.WriteString("</row>")
+	}
+	buf.WriteString("</sheetData>")
+
+	buf.Write(ws.tail)
+	return buf.Bytes()
+}
+
+// renderCell returns the XML for one cell, or nil for a null value in an
+// unstyled cell. Numbers and bools keep their type; everything else is
+// written as inline text the way ToCSV formats it.
+func renderCell(col, row int, style string, val interface{}) []byte {
+	attrs := fmt.Sprintf(`r="%s"`, cellReference(col, row))
+	if style != "" {
+		attrs += fmt.Sprintf(` s="%s"`, style)
+	}
+
+	var number string
+	switch v := val.(type) {
+	case nil:
+	case bool:
+		if v {
+			return []byte(`<c ` + attrs + ` t="b"><v>1</v></c>`)
+		}
+		return []byte(`<c ` + attrs + ` t="b"><v>0</v></c>`)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		number = fmt.Sprint(v)
+	default:
+		if f, ok := toFloat64(v); ok {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				val = nil
+				break
+			}
+			number = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+
+	switch {
+	case number != "":
+		return []byte(`<c ` + attrs + `><v>` + number + `</v></c>`)
+	case val == nil && style == "":
+		return nil
+	case val == nil:
+		return []byte(`<c ` + attrs + `/>`)
+	}
+
+	var buf bytes.Buffer
+	text := fmt.Sprintf("%v", val)
+	buf.WriteString(`<c ` + attrs + ` t="inlineStr"><is><t`)
+	if strings.TrimSpace(text) != text {
+		buf.WriteString(` xml:space="preserve"`)
+	}
+	buf.WriteString(">")
+	xml.EscapeText(&buf, []byte(text))
+	buf.WriteString("</t></is></c>")
+	return buf.Bytes()
+}
@@ -0,0 +1,198 @@
+package gopandas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpectationSamples is how many failing rows an ExpectationResult keeps.
+const ExpectationSamples = 5
+
+// ExpectationSuite is a named set of expectations about a dataset, built
+// once and run against each new artifact, for example as a CI step:
+//
+//	suite := NewExpectationSuite("users").
+//		ExpectColumnValuesBetween("age", 0, 120).
+//		ExpectUnique("id").
+//		ExpectNoNulls("email")
+//	if err := suite.Run(df).Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type ExpectationSuite struct {
+	name         string
+	expectations []expectation
+}
+
+// expectation finds the positions of the rows that violate it.
+type expectation struct {
+	name    string
+	column  string
+	failing func(df *DataFrame, j int) []int
+	problem string
+	// needsColumn is set when failing reads column j, which Run looks up.
+	needsColumn bool
+}
+
+// ExpectationResult is the outcome of one expectation. Samples holds up to
+// ExpectationSamples of the failing rows; Error is set instead when the
+// expectation could not be checked, such as for a missing column.
+type ExpectationResult struct {
+	Expectation string
+	Column      string
+	Success     bool
+	Checked     int
+	Failed      int
+	Samples     *DataFrame
+	Error       string
+}
+
+// ExpectationReport is the outcome of running a suite.
+type ExpectationReport struct {
+	Suite   string
+	Success bool
+	Results []ExpectationResult
+}
+
+func NewExpectationSuite(name string) *ExpectationSuite {
+	return &ExpectationSuite{name: name}
+}
+
+// Expect adds a ValidationRule as an expectation that every row passes.
+func (s *ExpectationSuite) Expect(rule ValidationRule) *ExpectationSuite {
+	if rule.Check == nil && rule.RowCheck == nil {
+		s.expectations = append(s.expectations, expectation{name: rule.Name, column: rule.Column, problem: fmt.Sprintf("rule '%s' has no check", rule.Name)})
+		return s
+	}
+	s.expectations = append(s.expectations, expectation{
+		name:        rule.Name,
+		column:      rule.Column,
+		needsColumn: rule.RowCheck == nil,
+		failing: func(df *DataFrame, j int) []int {
+			failed := make([]int, 0)
+			for i, row := range df.data {
+				var ok bool
+				if rule.RowCheck != nil {
+					ok = rule.RowCheck(rowRecord(df.columns, row))
+				} else {
+					ok = rule.Check(row[j])
+				}
+				if !ok {
+					failed = append(failed, i)
+				}
+			}
+			return failed
+		},
+	})
+	return s
+}
+
+// ExpectColumnValuesBetween expects the numbers in column to lie between
+// min and max inclusive. Nulls pass; non-numeric values fail.
+func (s *ExpectationSuite) ExpectColumnValuesBetween(column string, min, max float64) *ExpectationSuite {
+	rule := InRange(column, min, max)
+	rule.Name = fmt.Sprintf("%s between %v and %v", column, min, max)
+	return s.Expect(rule)
+}
+
+// ExpectNoNulls expects column to have no null or empty values.
+func (s *ExpectationSuite) ExpectNoNulls(column string) *ExpectationSuite {
+	rule := NotNull(column)
+	rule.Name = fmt.Sprintf("%s has no nulls", column)
+	return s.Expect(rule)
+}
+
+// ExpectUnique expects the non-null values of column to be distinct. Every
+// row holding a repeated value fails, including its first occurrence.
+func (s *ExpectationSuite) ExpectUnique(column string) *ExpectationSuite {
+	s.expectations = append(s.expectations, expectation{
+		name:        fmt.Sprintf("%s is unique", column),
+		column:      column,
+		needsColumn: true,
+		failing: func(df *DataFrame, j int) []int {
+			counts := make(map[string]int)
+			for _, row := range df.data {
+				if row[j] != nil {
+					counts[indexKey(row[j])]++
+				}
+			}
+			failed := make([]int, 0)
+			for i, row := range df.data {
+				if row[j] != nil && counts[indexKey(row[j])] > 1 {
+					failed = append(failed, i)
+				}
+			}
+			return failed
+		},
+	})
+	return s
+}
+
+// Run checks every expectation against df. It never stops early, so the
+// report covers the whole suite.
+func (s *ExpectationSuite) Run(df *DataFrame) *ExpectationReport {
+	report := &ExpectationReport{Suite: s.name, Success: true, Results: make([]ExpectationResult, 0, len(s.expectations))}
+	for _, e := range s.expectations {
+		result := ExpectationResult{Expectation: e.name, Column: e.column, Checked: len(df.data)}
+		j := -1
+		if e.needsColumn {
+			if j = df.columnIndex(e.column); j == -1 {
+				e.problem = fmt.Sprintf("column '%s' not found", e.column)
+			}
+		}
+		if e.problem != "" {
+			result.Error = e.problem
+			report.Success = false
+			report.Results = append(report.Results, result)
+			continue
+		}
+		failed := e.failing(df, j)
+		result.Failed = len(failed)
+		result.Success = len(failed) == 0
+		if len(failed) > ExpectationSamples {
+			failed = failed[:ExpectationSamples]
+		}
+		result.Samples = df.takeRows(failed, "expectation_samples", map[string]interface{}{"expectation": e.name})
+		report.Success = report.Success && result.Success
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// Err returns an error summarizing the failed expectations, or nil when the
+// suite passed.
+func (r *ExpectationReport) Err() error {
+	if r.Success {
+		return nil
+	}
+	failed := make([]string, 0)
+	for _, result := range r.Results {
+		if !result.Success {
+			failed = append(failed, result.Expectation)
+		}
+	}
+	return fmt.Errorf("suite '%s' failed %d of %d expectations: %s", r.Suite, len(failed), len(r.Results), strings.Join(failed, "; "))
+}
+
+// String renders the report for logs: one PASS or FAIL line per
+// expectation, with the index labels of sample failing rows.
+func (r *ExpectationReport) String() string {
+	var b strings.Builder
+	passed := 0
+	for _, result := range r.Results {
+		if result.Success {
+			passed++
+		}
+	}
+	fmt.Fprintf(&b, "suite %s: %d of %d expectations passed\n", r.Suite, passed, len(r.Results))
+	for _, result := range r.Results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(&b, "  FAIL  %s: %s\n", result.Expectation, result.Error)
+		case result.Success:
+			fmt.Fprintf(&b, "  PASS  %s\n", result.Expectation)
+		default:
+			fmt.Fprintf(&b, "  FAIL  %s: %d of %d rows, e.g. rows %v\n", result.Expectation, result.Failed, result.Checked, result.Samples.index)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,651 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// exprNode evaluates to one value per row of the frame, a column at a time.
+type exprNode interface {
+	eval(ctx *exprContext) ([]interface{}, error)
+}
+
+type exprContext struct {
+	df      *DataFrame
+	columns map[string][]interface{}
+	nulls   NullSemantics
+}
+
+func newExprContext(df *DataFrame, options []ExprOption) *exprContext {
+	ctx := &exprContext{df: df, columns: make(map[string][]interface{})}
+	for _, option := range options {
+		option(ctx)
+	}
+	return ctx
+}
+
+func (ctx *exprContext) rows() int {
+	return len(ctx.df.data)
+}
+
+type literalNode struct{ value interface{} }
+
+type columnNode struct{ name string }
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (n literalNode) eval(ctx *exprContext) ([]interface{}, error) {
+	out := make([]interface{}, ctx.rows())
+	for i := range out {
+		out[i] = n.value
+	}
+	return out, nil
+}
+
+func (n columnNode) eval(ctx *exprContext) ([]interface{}, error) {
+	if values, ok := ctx.columns[n.name]; ok {
+		return values, nil
+	}
+	colIndex := ctx.df.columnIndex(n.name)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", n.name)
+	}
+	values := make([]interface{}, ctx.rows())
+	for i, row := range ctx.df.data {
+		values[i] = row[colIndex]
+	}
+	ctx.columns[n.name] = values
+	return values, nil
+}
+
+func (n unaryNode) eval(ctx *exprContext) ([]interface{}, error) {
+	values, err := n.operand.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]interface{}, len(values))
+	for i, val := range values {
+		switch n.op {
+		case "-":
+			out[i] = arithmeticValue("*", -1, val)
+		case "not":
+			out[i] = ctx.nulls.not(val)
+		}
+	}
+	return out, nil
+}
+
+func (n binaryNode) eval(ctx *exprContext) ([]interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, len(left))
+	switch n.op {
+	case "+", "-", "*", "/":
+		for i := range out {
+			out[i] = arithmeticValue(n.op, left[i], right[i])
+		}
+	case "%", "**":
+		for i := range out {
+			x, ok1 := toFloat64(left[i])
+			y, ok2 := toFloat64(right[i])
+			if !ok1 || !ok2 {
+				continue
+			}
+			if n.op == "**" {
+				out[i] = math.Pow(x, y)
+			} else if y != 0 {
+				out[i] = math.Mod(x, y)
+			}
+		}
+	case "and", "or":
+		for i := range out {
+			if n.op == "and" {
+				out[i] = ctx.nulls.and(left[i], right[i])
+			} else {
+				out[i] = ctx.nulls.or(left[i], right[i])
+			}
+		}
+	default:
+		for i := range out {
+			if left[i] == nil || right[i] == nil {
+				out[i] = ctx.nulls.compareNull(n.op, left[i], right[i])
+				continue
+			}
+			comp := compareExprValues(left[i], right[i])
+			switch n.op {
+			case "==":
+				out[i] = comp == 0
+			case "!=":
+				out[i] = comp != 0
+			case "<":
+				out[i] = comp < 0
+			case "<=":
+				out[i] = comp <= 0
+			case ">":
+				out[i] = comp > 0
+			case ">=":
+				out[i] = comp >= 0
+			}
+		}
+	}
+	return out, nil
+}
+
+// compareExprValues compares ints and floats numerically, since expressions
+// mix literals like 2 with float columns freely.
+func compareExprValues(a, b interface{}) int {
+	x, ok1 := toFloat64(a)
+	y, ok2 := toFloat64(b)
+	if ok1 && ok2 {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return compareValues(a, b)
+}
+
+func (n callNode) eval(ctx *exprContext) ([]interface{}, error) {
+	fn, ok := lookupExprFunc(n.name)
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", n.name)
+	}
+
+	args := make([][]interface{}, len(n.args))
+	for k, arg := range n.args {
+		values, err := arg.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args[k] = values
+	}
+
+	out := make([]interface{}, ctx.rows())
+	row := make([]interface{}, len(args))
+	for i := range out {
+		for k := range args {
+			row[k] = args[k][i]
+		}
+		val, err := fn(row...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: row %d: %w", n.name, i, err)
+		}
+		out[i] = val
+	}
+	return out, nil
+}
+
+// ExprFunc is a function callable by name from Eval and Query expressions.
+// It is called once per row with that row's argument values.
+type ExprFunc func(args ...interface{}) (interface{}, error)
+
+var (
+	exprFuncsMu sync.RWMutex
+	exprFuncs   = map[string]ExprFunc{}
+)
+
+// RegisterFunc makes fn callable as name(...) in Eval and Query strings.
+// Names are case-insensitive, and a registered name takes precedence over a
+// built-in one.
+func RegisterFunc(name string, fn ExprFunc) {
+	exprFuncsMu.Lock()
+	defer exprFuncsMu.Unlock()
+	exprFuncs[strings.ToLower(name)] = fn
+}
+
+var builtinExprFuncs = map[string]ExprFunc{
+	"abs":   mathExprFunc(math.Abs),
+	"sqrt":  mathExprFunc(math.Sqrt),
+	"log":   mathExprFunc(math.Log),
+	"exp":   mathExprFunc(math.Exp),
+	"floor": mathExprFunc(math.Floor),
+	"ceil":  mathExprFunc(math.Ceil),
+	"round": mathExprFunc(math.Round),
+	"lower": stringExprFunc(strings.ToLower),
+	"upper": stringExprFunc(strings.ToUpper),
+}
+
+func lookupExprFunc(name string) (ExprFunc, bool) {
+	name = strings.ToLower(name)
+	exprFuncsMu.RLock()
+	fn, ok := exprFuncs[name]
+	exprFuncsMu.RUnlock()
+	if ok {
+		return fn, true
+	}
+	fn, ok = builtinExprFuncs[name]
+	return fn, ok
+}
+
+func mathExprFunc(fn func(float64) float64) ExprFunc {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		f, ok := toFloat64(args[0])
+		if !ok {
+			return nil, nil
+		}
+		return fn(f), nil
+	}
+}
+
+func stringExprFunc(fn func(string) string) ExprFunc {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		return fn(fmt.Sprintf("%v", args[0])), nil
+	}
+}
+
+type exprToken struct {
+	kind string // "num", "str", "ident", "op"
+	text string
+}
+
+func tokenizeExpr(src string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E' ||
+				(src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+			tokens = append(tokens, exprToken{"num", src[i:j]})
+			i = j
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(src[i+1:], c)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated %c at position %d", c, i)
+			}
+			kind := "str"
+			if c == '`' {
+				kind = "ident"
+			}
+			tokens = append(tokens, exprToken{kind, src[i+1 : i+1+end]})
+			i += end + 2
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+			word := src[i:j]
+			switch strings.ToLower(word) {
+			case "and", "or", "not":
+				tokens = append(tokens, exprToken{"op", strings.ToLower(word)})
+			default:
+				tokens = append(tokens, exprToken{"ident", word})
+			}
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"**", "==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "(", ")", ",", "<", ">", "=", "&", "|", "!"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", c, i)
+			}
+			i += len(op)
+			switch op {
+			case "&", "&&":
+				op = "and"
+			case "|", "||":
+				op = "or"
+			case "!":
+				op = "not"
+			}
+			tokens = append(tokens, exprToken{"op", op})
+		}
+	}
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+// parseExpr parses a column expression such as "revenue - cost > 100".
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	node, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in expression", p.tokens[p.pos].text)
+	}
+	return node, nil
+}
+
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) binary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) or() (exprNode, error) {
+	return p.binary(p.and, "or")
+}
+
+func (p *exprParser) and() (exprNode, error) {
+	return p.binary(p.not, "and")
+}
+
+func (p *exprParser) not() (exprNode, error) {
+	if _, ok := p.accept("not"); ok {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "not", operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *exprParser) comparison() (exprNode, error) {
+	return p.binary(p.additive, "==", "!=", "<=", ">=", "<", ">")
+}
+
+func (p *exprParser) additive() (exprNode, error) {
+	return p.binary(p.term, "+", "-")
+}
+
+func (p *exprParser) term() (exprNode, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+func (p *exprParser) unary() (exprNode, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.power()
+}
+
+func (p *exprParser) power() (exprNode, error) {
+	base, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("**"); ok {
+		exponent, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: "**", left: base, right: exponent}, nil
+	}
+	return base, nil
+}
+
+func (p *exprParser) primary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case "num":
+		if n, err := strconv.Atoi(tok.text); err == nil {
+			return literalNode{n}, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", tok.text)
+		}
+		return literalNode{f}, nil
+	case "str":
+		return literalNode{tok.text}, nil
+	case "ident":
+		if _, ok := p.accept("("); ok {
+			args := make([]exprNode, 0)
+			if _, ok := p.accept(")"); !ok {
+				for {
+					arg, err := p.or()
+					if err != nil {
+						return nil, err
+					}
+					args = append(args, arg)
+					if _, ok := p.accept(","); ok {
+						continue
+					}
+					if _, ok := p.accept(")"); !ok {
+						return nil, fmt.Errorf("expected ')' after arguments to %s", tok.text)
+					}
+					break
+				}
+			}
+			return callNode{name: tok.text, args: args}, nil
+		}
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null", "nil":
+			return literalNode{nil}, nil
+		}
+		return columnNode{name: tok.text}, nil
+	}
+
+	if tok.text == "(" {
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("unexpected '%s' in expression", tok.text)
+}
+
+// Eval creates or replaces columns from expressions over existing columns,
+// such as "profit = revenue - cost". Several assignments can be separated by
+// newlines or semicolons, and later ones may use earlier results. Operators
+// are + - * / % ** (power), comparisons, and/or/not; names with spaces are
+// quoted in backticks. Each operator runs over a whole column at a time.
+// Nulls follow SQL unless WithNullSemantics says otherwise.
+func (df *DataFrame) Eval(expr string, options ...ExprOption) (*DataFrame, error) {
+	statements := splitStatements(expr)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	result := df.derive(NewDataFrame(append([]string{}, df.columns...)), "eval", map[string]interface{}{"expr": expr})
+	for i, row := range df.data {
+		result.data = append(result.data, append([]interface{}{}, row...))
+		result.index = append(result.index, df.index[i])
+	}
+
+	for _, statement := range statements {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		target, source, ok := splitAssignment(statement)
+		if !ok {
+			return nil, fmt.Errorf("expression '%s' must assign to a column (name = expression)", strings.TrimSpace(statement))
+		}
+
+		node, err := parseExpr(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression '%s': %w", strings.TrimSpace(statement), err)
+		}
+		values, err := node.eval(newExprContext(result, options))
+		if err != nil {
+			return nil, err
+		}
+
+		colIndex := result.columnIndex(target)
+		if colIndex == -1 {
+			result.columns = append(result.columns, target)
+			for i := range result.data {
+				result.data[i] = append(result.data[i], values[i])
+			}
+			continue
+		}
+		for i := range result.data {
+			result.data[i][colIndex] = values[i]
+		}
+	}
+
+	return result, nil
+}
+
+// EvalSeries evaluates an expression without assigning it, returning the
+// values as a Series named after the expression.
+func (df *DataFrame) EvalSeries(expr string, options ...ExprOption) (*Series, error) {
+	node, err := parseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expr, err)
+	}
+	values, err := node.eval(newExprContext(df, options))
+	if err != nil {
+		return nil, err
+	}
+	series := NewSeries(strings.TrimSpace(expr), values)
+	series.index = append([]interface{}{}, df.index...)
+	return series, nil
+}
+
+// Query keeps the rows for which a boolean expression holds, such as
+// "amount > 100 and region == 'east'". Rows where it is false or null are
+// dropped; see NullSemantics for when a condition over nulls is null.
+func (df *DataFrame) Query(expr string, options ...ExprOption) (*DataFrame, error) {
+	mask, err := df.EvalSeries(expr, options...)
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]int, 0)
+	for i, val := range mask.data {
+		if keep, ok := val.(bool); ok && keep {
+			positions = append(positions, i)
+		} else if !ok && val != nil {
+			return nil, fmt.Errorf("query '%s' is not a condition: row %d gave %T", expr, i, val)
+		}
+	}
+	return df.takeRows(positions, "query", map[string]interface{}{"expr": expr}), nil
+}
+
+// splitAssignment splits "name = expr" at the first bare '=' (not part of
+// ==, !=, <= or >=).
+// splitStatements splits expr at newlines and semicolons outside quoted
+// strings and backticked names.
+func splitStatements(expr string) []string {
+	statements := make([]string, 0)
+	var quote byte
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '\n' || c == ';':
+			if i > start {
+				statements = append(statements, expr[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(expr) {
+		statements = append(statements, expr[start:])
+	}
+	return statements
+}
+
+func splitAssignment(statement string) (string, string, bool) {
+	for i := 0; i < len(statement); i++ {
+		if statement[i] != '=' {
+			continue
+		}
+		if i+1 < len(statement) && statement[i+1] == '=' {
+			i++
+			continue
+		}
+		if i > 0 && strings.ContainsRune("=!<>", rune(statement[i-1])) {
+			continue
+		}
+		target := strings.Trim(strings.TrimSpace(statement[:i]), "`")
+		if target == "" {
+			return "", "", false
+		}
+		return target, statement[i+1:], true
+	}
+	return "", "", false
+}
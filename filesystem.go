@@ -0,0 +1,107 @@
+package gopandas
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+type FileSystem interface {
+	Open(path string) (io.ReadCloser, error)
+	Create(path string) (io.WriteCloser, error)
+}
+
+type localFileSystem struct{}
+
+func (localFileSystem) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (localFileSystem) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+// envFileSystem builds a cloud file system from the environment the first
+// time it is used, so credentials set after start-up (or never, when no
+// cloud paths are read) are picked up correctly.
+type envFileSystem struct {
+	once sync.Once
+	load func() FileSystem
+	fs   FileSystem
+}
+
+func (e *envFileSystem) get() FileSystem {
+	e.once.Do(func() { e.fs = e.load() })
+	return e.fs
+}
+
+func (e *envFileSystem) Open(path string) (io.ReadCloser, error) {
+	return e.get().Open(path)
+}
+
+func (e *envFileSystem) Create(path string) (io.WriteCloser, error) {
+	return e.get().Create(path)
+}
+
+var (
+	fileSystemsMu sync.RWMutex
+	fileSystems   = map[string]FileSystem{
+		"file": localFileSystem{},
+		"s3":   &envFileSystem{load: func() FileSystem { return NewS3FileSystemFromEnv() }},
+		"gs":   &envFileSystem{load: func() FileSystem { return NewGCSFileSystemFromEnv() }},
+		"az":   &envFileSystem{load: func() FileSystem { return NewAzureBlobFileSystemFromEnv() }},
+	}
+)
+
+func RegisterFileSystem(scheme string, fs FileSystem) {
+	fileSystemsMu.Lock()
+	defer fileSystemsMu.Unlock()
+	fileSystems[scheme] = fs
+}
+
+func resolveFileSystem(path string) (FileSystem, string) {
+	scheme, rest, found := strings.Cut(path, "://")
+	if !found {
+		return localFileSystem{}, path
+	}
+
+	fileSystemsMu.RLock()
+	defer fileSystemsMu.RUnlock()
+	if fs, ok := fileSystems[scheme]; ok {
+		if scheme == "file" {
+			return fs, rest
+		}
+		return fs, path
+	}
+	return localFileSystem{}, path
+}
+
+func openPath(path string) (io.ReadCloser, error) {
+	fs, resolved := resolveFileSystem(path)
+	return fs.Open(resolved)
+}
+
+func createPath(path string) (io.WriteCloser, error) {
+	fs, resolved := resolveFileSystem(path)
+	return fs.Create(resolved)
+}
+
+func splitBucketPath(path string) (string, string, error) {
+	_, rest, _ := strings.Cut(path, "://")
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid object path '%s': expected scheme://bucket/key", path)
+	}
+	return bucket, key, nil
+}
+
+func readAllPath(path string) ([]byte, error) {
+	rc, err := openPath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,85 @@
+package gopandas
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FloatFormat controls how ToCSV and ToTSV write floating-point values.
+// The zero value keeps the default formatting.
+type FloatFormat struct {
+	style  byte // 'f' fixed, 'e' scientific, 's' significant digits
+	digits int
+}
+
+// FixedDecimals writes floats with exactly n digits after the decimal
+// point, as in 70000.00.
+func FixedDecimals(n int) FloatFormat {
+	return FloatFormat{style: 'f', digits: n}
+}
+
+// Scientific writes floats in exponent form with n digits after the
+// decimal point, as in 7.00e+04.
+func Scientific(n int) FloatFormat {
+	return FloatFormat{style: 'e', digits: n}
+}
+
+// SignificantDigits rounds floats to n significant digits and writes them
+// without an exponent or trailing zeros, so 70000.00000000001 becomes 70000
+// and 0.0123456 becomes 0.0123 for n = 3.
+func SignificantDigits(n int) FloatFormat {
+	return FloatFormat{style: 's', digits: n}
+}
+
+// IsZero reports whether f is the default format.
+func (f FloatFormat) IsZero() bool {
+	return f.style == 0
+}
+
+// Format writes v in the format.
+func (f FloatFormat) Format(v float64) string {
+	switch f.style {
+	case 'f', 'e':
+		return strconv.FormatFloat(v, f.style, f.digits, 64)
+	case 's':
+		digits := f.digits
+		if digits < 1 {
+			digits = 1
+		}
+		rounded, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', digits, 64), 64)
+		if err != nil {
+			return strconv.FormatFloat(v, 'g', digits, 64)
+		}
+		return strconv.FormatFloat(rounded, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatFloatValue formats val with f if it is a float, reporting whether
+// it was one. decimalSep, when not empty, replaces the decimal point.
+func (f FloatFormat) formatFloatValue(val interface{}, decimalSep string) (string, bool) {
+	var v float64
+	switch x := val.(type) {
+	case float64:
+		v = x
+	case float32:
+		v = float64(x)
+	default:
+		return "", false
+	}
+	text := f.Format(v)
+	if decimalSep != "" {
+		text = strings.Replace(text, ".", decimalSep, 1)
+	}
+	return text, true
+}
+
+// WithFloatFormat writes float values in ToCSV and ToTSV output with
+// format, such as FixedDecimals(2), instead of Go's default formatting.
+// With WithLocale the locale's decimal separator is kept. Other writers
+// (ToExcelAppend, ToHTML, ToRecords) are not affected.
+func WithFloatFormat(format FloatFormat) CSVOption {
+	return func(c *CSVConfig) {
+		c.FloatFormat = format
+	}
+}
@@ -0,0 +1,90 @@
+package gopandas
+
+import "strings"
+
+// footerLabels start the summary rows spreadsheet exports append.
+var footerLabels = []string{"total", "grand total", "subtotal", "sum", "합계", "총계"}
+
+// WithSkipFooter drops the last n data rows of the file.
+func WithSkipFooter(n int) CSVOption {
+	return func(c *CSVConfig) {
+		c.SkipFooter = n
+	}
+}
+
+// WithAutoFooter drops trailing blank rows and rows labelled "Total",
+// "Subtotal", "Sum" and the like, after any WithSkipFooter rows.
+func WithAutoFooter() CSVOption {
+	return func(c *CSVConfig) {
+		c.AutoFooter = true
+	}
+}
+
+// WithExcelSkipFooter is WithSkipFooter for ReadExcel and ReadExcelChunks.
+func WithExcelSkipFooter(n int) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.SkipFooter = n
+	}
+}
+
+// WithExcelAutoFooter is WithAutoFooter for ReadExcel and ReadExcelChunks.
+func WithExcelAutoFooter() ExcelOption {
+	return func(c *ExcelConfig) {
+		c.AutoFooter = true
+	}
+}
+
+func isFooterRow(cells []string) bool {
+	for _, cell := range cells {
+		cell = strings.ToLower(strings.TrimSpace(cell))
+		if cell == "" {
+			continue
+		}
+		for _, label := range footerLabels {
+			if strings.HasPrefix(cell, label) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// footerBuffer holds back rows that may turn out to be footer rows, so
+// footers can be dropped from a stream without reading it twice.
+type footerBuffer struct {
+	skip    int
+	auto    bool
+	pending [][]string
+}
+
+// push adds a row and returns the rows now known not to be footer.
+func (b *footerBuffer) push(cells []string) [][]string {
+	if b.skip <= 0 && !b.auto {
+		return [][]string{cells}
+	}
+	b.pending = append(b.pending, cells)
+
+	limit := len(b.pending) - b.skip
+	if limit <= 0 {
+		return nil
+	}
+	if b.auto {
+		for limit > 0 && isFooterRow(b.pending[limit-1]) {
+			limit--
+		}
+	}
+	released := b.pending[:limit:limit]
+	b.pending = b.pending[limit:]
+	return released
+}
+
+// trimFooter applies footer options to a complete set of rows.
+func trimFooter(rows [][]string, skip int, auto bool) [][]string {
+	buffer := &footerBuffer{skip: skip, auto: auto}
+	kept := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		kept = append(kept, buffer.push(row)...)
+	}
+	return kept
+}
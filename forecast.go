@@ -0,0 +1,111 @@
+package gopandas
+
+import (
+	"fmt"
+)
+
+func checkSmoothing(name string, value float64) error {
+	if value < 0 || value > 1 {
+		return fmt.Errorf("%s must be between 0 and 1, got %v", name, value)
+	}
+	return nil
+}
+
+func checkHorizon(horizon int) error {
+	if horizon < 0 {
+		return fmt.Errorf("horizon must not be negative, got %d", horizon)
+	}
+	return nil
+}
+
+func (s *Series) ExponentialSmoothing(alpha float64, horizon int) (*Series, *Series, error) {
+	if err := checkSmoothing("alpha", alpha); err != nil {
+		return nil, nil, err
+	}
+	if err := checkHorizon(horizon); err != nil {
+		return nil, nil, err
+	}
+	values, err := s.floatValues()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("series is empty")
+	}
+
+	fitted := make([]interface{}, len(values))
+	level := values[0]
+	for t, v := range values {
+		fitted[t] = level
+		level = alpha*v + (1-alpha)*level
+	}
+
+	forecast := make([]interface{}, horizon)
+	for h := range forecast {
+		forecast[h] = level
+	}
+
+	return s.withData(s.name+"_fitted", fitted), s.forecastSeries(forecast), nil
+}
+
+func (s *Series) HoltWinters(alpha, beta, gamma float64, period, horizon int) (*Series, *Series, error) {
+	if err := checkSmoothing("alpha", alpha); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSmoothing("beta", beta); err != nil {
+		return nil, nil, err
+	}
+	if err := checkSmoothing("gamma", gamma); err != nil {
+		return nil, nil, err
+	}
+	if err := checkHorizon(horizon); err != nil {
+		return nil, nil, err
+	}
+	values, err := s.floatValues()
+	if err != nil {
+		return nil, nil, err
+	}
+	if period < 2 || len(values) < 2*period {
+		return nil, nil, fmt.Errorf("need at least two full periods of %d observations", period)
+	}
+
+	// initial level, trend and seasonal indices from the first two seasons
+	var first, second float64
+	for i := 0; i < period; i++ {
+		first += values[i]
+		second += values[period+i]
+	}
+	level := first / float64(period)
+	trend := (second - first) / float64(period*period)
+
+	seasonal := make([]float64, period)
+	for i := 0; i < period; i++ {
+		seasonal[i] = values[i] - level
+	}
+
+	fitted := make([]interface{}, len(values))
+	for t, v := range values {
+		si := t % period
+		fitted[t] = level + trend + seasonal[si]
+
+		prevLevel := level
+		level = alpha*(v-seasonal[si]) + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+		seasonal[si] = gamma*(v-level) + (1-gamma)*seasonal[si]
+	}
+
+	forecast := make([]interface{}, horizon)
+	for h := range forecast {
+		forecast[h] = level + float64(h+1)*trend + seasonal[(len(values)+h)%period]
+	}
+
+	return s.withData(s.name+"_fitted", fitted), s.forecastSeries(forecast), nil
+}
+
+func (s *Series) forecastSeries(forecast []interface{}) *Series {
+	result := NewSeries(s.name+"_forecast", forecast)
+	for h := range result.index {
+		result.index[h] = len(s.data) + h
+	}
+	return result
+}
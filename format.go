@@ -0,0 +1,258 @@
+package gopandas
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FormatReader decodes a frame from a stream in a registered format.
+type FormatReader func(r io.Reader) (*DataFrame, error)
+
+// FormatWriter encodes a frame to a stream in a registered format.
+type FormatWriter func(w io.Writer, df *DataFrame) error
+
+type format struct {
+	name   string
+	reader FormatReader
+	writer FormatWriter
+	magic  [][]byte
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]*format{}
+	// formatOrder keeps extensions in registration order, so the first
+	// format registered wins when magic prefixes overlap
+	formatOrder []string
+)
+
+// sniffSize is how much of a file ReadAuto looks at to recognise it.
+const sniffSize = 512
+
+// RegisterFormat adds a format under an extension (without the dot) for
+// ReadAuto and WriteAuto. Either function may be nil when the format is
+// read- or write-only. Optional magic prefixes let ReadAuto recognise the
+// format when the extension is missing or unknown. A registered extension
+// takes precedence over a built-in one.
+func RegisterFormat(ext string, reader FormatReader, writer FormatWriter, magic ...[]byte) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	if !containsString(formatOrder, ext) {
+		formatOrder = append(formatOrder, ext)
+	}
+	formats[ext] = &format{name: ext, reader: reader, writer: writer, magic: magic}
+}
+
+func registeredFormat(ext string) (*format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[ext]
+	return f, ok
+}
+
+func sniffRegisteredFormat(header []byte) (*format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	for _, ext := range formatOrder {
+		f, ok := formats[ext]
+		if !ok {
+			continue
+		}
+		for _, magic := range f.magic {
+			if len(magic) > 0 && f.reader != nil && bytes.HasPrefix(header, magic) {
+				return f, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// sniffBuiltinFormat recognises the formats this package reads itself. A
+// compound file is reported as "cfb": telling a legacy .xls workbook from
+// an encrypted .xlsx takes its directory, which sniffCompoundFormat reads.
+func sniffBuiltinFormat(header []byte) string {
+	trimmed := bytes.TrimLeft(header, " \t\r\n")
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return "xlsx"
+	case bytes.HasPrefix(header, cfbSignature):
+		return "cfb"
+	case bytes.HasPrefix(trimmed, []byte(`{"columns"`)):
+		return "gpd"
+	}
+	return "csv"
+}
+
+// sniffCompoundFormat names the workbook format held in a compound file:
+// "xlsx" for an encrypted OOXML package, "xls" for a BIFF workbook.
+func sniffCompoundFormat(data []byte) (string, error) {
+	cf, err := openCompoundFile(data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := cf.stream("EncryptedPackage"); err == nil {
+		return "xlsx", nil
+	}
+	for _, name := range []string{"Workbook", "Book"} {
+		if _, err := cf.stream(name); err == nil {
+			return "xls", nil
+		}
+	}
+	return "", fmt.Errorf("compound file holds no workbook")
+}
+
+func formatExt(path string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ReadAuto reads a file in whichever format its extension names, falling
+// back to its leading bytes when the extension is unknown. Besides
+// registered formats it handles csv, tsv, xlsx, xls and gpd (MarshalBinary)
+// files; anything unrecognised is read as CSV with dialect detection.
+func ReadAuto(path string) (*DataFrame, error) {
+	ext := formatExt(path)
+	if f, ok := registeredFormat(ext); ok && f.reader != nil {
+		return readWithFormat(path, f)
+	}
+
+	switch ext {
+	case "csv":
+		return ReadCSV(path)
+	case "tsv":
+		return ReadCSV(path, WithDelimiter('\t'))
+	case "xlsx", "xls":
+		return ReadExcel(path)
+	case "gpd":
+		return readBinaryPath(path)
+	}
+
+	header, err := readHeader(path)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := sniffRegisteredFormat(header); ok {
+		return readWithFormat(path, f)
+	}
+	switch sniffBuiltinFormat(header) {
+	case "cfb":
+		data, err := readAllPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		kind, err := sniffCompoundFormat(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if kind == "xls" {
+			df, err := parseXLS(data, newExcelConfig(nil))
+			if err != nil {
+				return nil, err
+			}
+			df.record("read_excel", map[string]interface{}{"source": path})
+			return df, nil
+		}
+		fallthrough
+	case "xlsx":
+		df, err := readXLSX(path, newExcelConfig(nil))
+		if err != nil {
+			return nil, err
+		}
+		df.record("read_excel", map[string]interface{}{"source": path})
+		return df, nil
+	case "gpd":
+		return readBinaryPath(path)
+	}
+	return ReadCSV(path, WithAutoDetect())
+}
+
+// WriteAuto writes the frame in the format named by the path's extension:
+// a registered format, csv, tsv or gpd.
+func (df *DataFrame) WriteAuto(path string) error {
+	ext := formatExt(path)
+	if f, ok := registeredFormat(ext); ok && f.writer != nil {
+		file, err := createPath(path)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		if err := f.writer(file, df); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		return file.Close()
+	}
+
+	switch ext {
+	case "csv":
+		return df.ToCSV(path)
+	case "tsv":
+		return df.ToTSV(path)
+	case "gpd":
+		data, err := df.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		file, err := createPath(path)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+	return fmt.Errorf("no writer for format '%s'", ext)
+}
+
+func readHeader(path string) ([]byte, error) {
+	file, err := openPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	header := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return header[:n], nil
+}
+
+func readWithFormat(path string, f *format) (*DataFrame, error) {
+	file, err := openPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	df, err := f.reader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.name, err)
+	}
+	df.record("read_"+f.name, map[string]interface{}{"source": path})
+	return df, nil
+}
+
+func readBinaryPath(path string) (*DataFrame, error) {
+	if fs, resolved := resolveFileSystem(path); fs == (localFileSystem{}) {
+		return ReadBinaryFile(resolved)
+	}
+	file, err := openPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	df := NewDataFrame(nil)
+	if err := df.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return df, nil
+}
@@ -0,0 +1,123 @@
+package gopandas
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ValueGenerator produces the value of one column for row i of a
+// generated frame.
+type ValueGenerator func(rng *rand.Rand, i int) interface{}
+
+// Generator builds frames of random data for tests and benchmarks. Output
+// is reproducible: the same seed, columns and row count give the same frame.
+type Generator struct {
+	names      []string
+	generators []ValueGenerator
+	rows       int
+	seed       int64
+}
+
+// Gen starts a generator with seed 1 and no rows.
+func Gen() *Generator {
+	return &Generator{seed: 1}
+}
+
+func (g *Generator) Col(name string, gen ValueGenerator) *Generator {
+	g.names = append(g.names, name)
+	g.generators = append(g.generators, gen)
+	return g
+}
+
+func (g *Generator) Rows(n int) *Generator {
+	g.rows = n
+	return g
+}
+
+func (g *Generator) Seed(seed int64) *Generator {
+	g.seed = seed
+	return g
+}
+
+func (g *Generator) Build() *DataFrame {
+	rng := rand.New(rand.NewSource(g.seed))
+	df := NewDataFrame(append([]string{}, g.names...))
+	for i := 0; i < g.rows; i++ {
+		row := make([]interface{}, len(g.generators))
+		for j, gen := range g.generators {
+			row[j] = gen(rng, i)
+		}
+		df.AddRow(row)
+	}
+	return df
+}
+
+const randLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// StringRand generates random letter strings of the given length.
+func StringRand(length int) ValueGenerator {
+	return func(rng *rand.Rand, _ int) interface{} {
+		b := make([]byte, length)
+		for i := range b {
+			b[i] = randLetters[rng.Intn(len(randLetters))]
+		}
+		return string(b)
+	}
+}
+
+// IntRange generates ints uniformly between min and max inclusive. It
+// fails when max is below min.
+func IntRange(min, max int) (ValueGenerator, error) {
+	if max < min {
+		return nil, fmt.Errorf("int range max %d is below min %d", max, min)
+	}
+	return func(rng *rand.Rand, _ int) interface{} {
+		return min + rng.Intn(max-min+1)
+	}, nil
+}
+
+// FloatRange generates float64s uniformly in [min, max).
+func FloatRange(min, max float64) ValueGenerator {
+	return func(rng *rand.Rand, _ int) interface{} {
+		return min + rng.Float64()*(max-min)
+	}
+}
+
+// TimeRange generates times uniformly in [start, end), to the second.
+func TimeRange(start, end time.Time) ValueGenerator {
+	return func(rng *rand.Rand, _ int) interface{} {
+		span := end.Sub(start) / time.Second
+		if span <= 0 {
+			return start
+		}
+		return start.Add(time.Duration(rng.Int63n(int64(span))) * time.Second)
+	}
+}
+
+// Choice picks uniformly from values. It fails when there are none.
+func Choice(values ...interface{}) (ValueGenerator, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("choice needs at least one value")
+	}
+	return func(rng *rand.Rand, _ int) interface{} {
+		return values[rng.Intn(len(values))]
+	}, nil
+}
+
+// Sequence generates start, start+1, ... by row number, for IDs.
+func Sequence(start int) ValueGenerator {
+	return func(_ *rand.Rand, i int) interface{} {
+		return start + i
+	}
+}
+
+// WithNulls wraps gen so that roughly rate of the values are nil.
+func WithNulls(gen ValueGenerator, rate float64) ValueGenerator {
+	return func(rng *rand.Rand, i int) interface{} {
+		if rng.Float64() < rate {
+			return nil
+		}
+		return gen(rng, i)
+	}
+}
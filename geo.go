@@ -0,0 +1,77 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+)
+
+const earthRadiusKm = 6371.0088
+
+type BoundingBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return lon >= b.MinLon && lon <= b.MaxLon
+	}
+	// box crosses the antimeridian
+	return lon >= b.MinLon || lon <= b.MaxLon
+}
+
+func Haversine(lat1, lon1, lat2, lon2 *Series) (*Series, error) {
+	n := lat1.Len()
+	if lon1.Len() != n || lat2.Len() != n || lon2.Len() != n {
+		return nil, fmt.Errorf("coordinate series lengths do not match")
+	}
+
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		la1, ok1 := toFloat64(lat1.data[i])
+		lo1, ok2 := toFloat64(lon1.data[i])
+		la2, ok3 := toFloat64(lat2.data[i])
+		lo2, ok4 := toFloat64(lon2.data[i])
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue
+		}
+		result[i] = HaversineKm(la1, lo1, la2, lo2)
+	}
+
+	return lat1.withData("distance_km", result), nil
+}
+
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	// rounding can push a past 1 for antipodal points, where Asin is NaN
+	a = math.Min(math.Max(a, 0), 1)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func (df *DataFrame) FilterBBox(latColumn, lonColumn string, box BoundingBox) (*DataFrame, error) {
+	latIndex := df.columnIndex(latColumn)
+	if latIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", latColumn)
+	}
+	lonIndex := df.columnIndex(lonColumn)
+	if lonIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", lonColumn)
+	}
+
+	return df.Filter(func(row []interface{}) bool {
+		lat, ok1 := toFloat64(row[latIndex])
+		lon, ok2 := toFloat64(row[lonIndex])
+		return ok1 && ok2 && box.Contains(lat, lon)
+	}), nil
+}
@@ -0,0 +1,92 @@
+package gopandas
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SourceFileColumn is the column ReadCSVGlob adds when WithSourceColumn is set.
+const SourceFileColumn = "_source_file"
+
+// WithSourceColumn makes ReadCSVGlob record the file each row came from in a
+// _source_file column.
+func WithSourceColumn() CSVOption {
+	return func(c *CSVConfig) {
+		c.SourceColumn = true
+	}
+}
+
+// ReadCSVGlob reads every local file matching pattern, in lexical order, and
+// concatenates them with Concat.
+func ReadCSVGlob(pattern string, options ...CSVOption) (*DataFrame, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match '%s'", pattern)
+	}
+
+	config := &CSVConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	frames := make([]*DataFrame, 0, len(matches))
+	for _, match := range matches {
+		df, err := ReadCSV(match, options...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", match, err)
+		}
+		if config.SourceColumn {
+			source := make([]interface{}, len(df.data))
+			for i := range source {
+				source[i] = match
+			}
+			if err := df.SetColumn(SourceFileColumn, source); err != nil {
+				return nil, err
+			}
+		}
+		frames = append(frames, df)
+	}
+
+	return Concat(frames...), nil
+}
+
+// Concat stacks frames vertically, aligning columns by name. The result has
+// the union of all columns in first-seen order; cells a frame lacks are nil.
+// Nil frames are skipped.
+func Concat(frames ...*DataFrame) *DataFrame {
+	present := make([]*DataFrame, 0, len(frames))
+	for _, df := range frames {
+		if df != nil {
+			present = append(present, df)
+		}
+	}
+	frames = present
+
+	columns := make([]string, 0)
+	positions := make(map[string]int)
+	for _, df := range frames {
+		for _, col := range df.columns {
+			if _, ok := positions[col]; !ok {
+				positions[col] = len(columns)
+				columns = append(columns, col)
+			}
+		}
+	}
+
+	result := combine(NewDataFrame(columns), "concat", nil, frames...)
+	for _, df := range frames {
+		for _, row := range df.data {
+			newRow := make([]interface{}, len(columns))
+			for j, col := range df.columns {
+				if j < len(row) {
+					newRow[positions[col]] = row[j]
+				}
+			}
+			result.AddRow(newRow)
+		}
+	}
+	return result
+}
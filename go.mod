@@ -1,3 +1,3 @@
 module github.com/donghquinn/gopandas
 
-go 1.21
+go 1.23
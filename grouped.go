@@ -0,0 +1,334 @@
+package gopandas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Grouped is a frame split into groups by one or more key columns. Unlike the
+// map returned by GroupBy it keeps the groups in first-seen order and the key
+// values with their original types.
+type Grouped struct {
+	df     *DataFrame
+	by     []string
+	byCols []int
+	keys   [][]interface{}
+	rows   [][]int
+}
+
+// GroupByColumns groups rows by the values of the given columns.
+func (df *DataFrame) GroupByColumns(columns ...string) (*Grouped, error) {
+	return df.GroupByColumnsContext(context.Background(), columns...)
+}
+
+// GroupByColumnsContext is GroupByColumns with ctx as the parent of its
+// trace span.
+func (df *DataFrame) GroupByColumnsContext(ctx context.Context, columns ...string) (*Grouped, error) {
+	span := startSpan(ctx, "group_by")
+	span.set("column", strings.Join(columns, ","))
+	span.set("rows_in", len(df.data))
+	g, err := df.groupByColumns(columns)
+	if err == nil {
+		span.set("groups", len(g.keys))
+	}
+	span.end(nil, err)
+	return g, err
+}
+
+func (df *DataFrame) groupByColumns(columns []string) (*Grouped, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no group columns given")
+	}
+	g := &Grouped{df: df, by: append([]string{}, columns...), byCols: make([]int, len(columns))}
+	for k, col := range columns {
+		g.byCols[k] = df.columnIndex(col)
+		if g.byCols[k] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+	}
+
+	if len(columns) == 1 && df.sortedBy == columns[0] {
+		g.groupSortedRuns()
+		return g, nil
+	}
+
+	positions := make(map[string]int)
+	for i, row := range df.data {
+		key := make([]interface{}, len(g.byCols))
+		for k, j := range g.byCols {
+			key[k] = row[j]
+		}
+		id := groupKeyString(key)
+		group, ok := positions[id]
+		if !ok {
+			group = len(g.keys)
+			positions[id] = group
+			g.keys = append(g.keys, key)
+			g.rows = append(g.rows, nil)
+		}
+		g.rows[group] = append(g.rows[group], i)
+	}
+	return g, nil
+}
+
+// groupKeyString identifies a key by type and value, so 1 and "1" differ.
+func groupKeyString(key []interface{}) string {
+	var b strings.Builder
+	for _, val := range key {
+		fmt.Fprintf(&b, "%T:%v\x00", val, val)
+	}
+	return b.String()
+}
+
+// Len returns the number of groups.
+func (g *Grouped) Len() int {
+	return len(g.keys)
+}
+
+// keyFrame starts a result frame holding one row per group with the key
+// columns filled in, followed by extra columns left empty.
+func (g *Grouped) keyFrame(op string, details map[string]interface{}, extra ...string) *DataFrame {
+	columns := append(append([]string{}, g.by...), extra...)
+	result := g.df.derive(NewDataFrame(columns), op, details)
+	for group, key := range g.keys {
+		row := make([]interface{}, len(columns))
+		copy(row, key)
+		result.data = append(result.data, row)
+		result.index = append(result.index, group)
+	}
+	return result
+}
+
+// Aggregation describes one output column of ToDataFrame. As defaults to
+// "<Column>_<Func>", and Where, when set, limits the rows aggregated.
+type Aggregation struct {
+	Column string
+	Func   string
+	As     string
+	Where  func(Row) bool
+}
+
+func (a Aggregation) name() string {
+	if a.As != "" {
+		return a.As
+	}
+	return a.Column + "_" + a.Func
+}
+
+// Sorted returns the same groups ordered by key instead of first appearance.
+func (g *Grouped) Sorted() *Grouped {
+	order := make([]int, len(g.keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		for k := range g.by {
+			if comp := compareValues(g.keys[order[a]][k], g.keys[order[b]][k]); comp != 0 {
+				return comp < 0
+			}
+		}
+		return false
+	})
+
+	sorted := &Grouped{df: g.df, by: g.by, byCols: g.byCols}
+	for _, group := range order {
+		sorted.keys = append(sorted.keys, g.keys[group])
+		sorted.rows = append(sorted.rows, g.rows[group])
+	}
+	return sorted
+}
+
+// ToDataFrame returns one row per group: the key columns, with their original
+// values, followed by one column per aggregation.
+func (g *Grouped) ToDataFrame(aggs ...Aggregation) (*DataFrame, error) {
+	names := make([]string, len(aggs))
+	cols := make([]int, len(aggs))
+	for k, agg := range aggs {
+		cols[k] = g.df.columnIndex(agg.Column)
+		if cols[k] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", agg.Column)
+		}
+		names[k] = agg.name()
+	}
+
+	result := g.keyFrame("group_agg", map[string]interface{}{"by": g.by, "columns": names}, names...)
+	positions := g.df.rowPositions()
+	for group, rows := range g.rows {
+		for k, agg := range aggs {
+			values := make([]interface{}, 0, len(rows))
+			for _, i := range rows {
+				row := g.df.data[i]
+				if agg.Where != nil && !agg.Where(Row{values: row, positions: positions}) {
+					continue
+				}
+				values = append(values, row[cols[k]])
+			}
+			value, err := aggregateValues(agg.Func, values)
+			if err != nil {
+				return nil, err
+			}
+			result.data[group][len(g.by)+k] = value
+		}
+	}
+	return result, nil
+}
+
+// Agg applies an aggregation (sum, mean, count, min, max, ...) to column
+// within each group. The result has the key columns followed by a column
+// named "<column>_<agg>".
+func (g *Grouped) Agg(column, agg string) (*DataFrame, error) {
+	return g.ToDataFrame(Aggregation{Column: column, Func: agg})
+}
+
+// AggIf is Agg restricted to the rows where the condition holds, like SQL's
+// FILTER (WHERE ...) clause. Groups with no matching rows are kept, with the
+// aggregation of an empty set (0 for count, nil otherwise).
+func (g *Grouped) AggIf(column, agg string, where func(Row) bool) (*DataFrame, error) {
+	return g.ToDataFrame(Aggregation{Column: column, Func: agg, Where: where})
+}
+
+// groupLabel is the index label for a group: the key value itself for a
+// single key column, or the slice of key values otherwise.
+func (g *Grouped) groupLabel(group int) interface{} {
+	if len(g.by) == 1 {
+		return g.keys[group][0]
+	}
+	return g.keys[group]
+}
+
+// Size returns the number of rows in each group, indexed by group key.
+func (g *Grouped) Size() *Series {
+	sizes := make([]interface{}, len(g.rows))
+	index := make([]interface{}, len(g.rows))
+	for group, rows := range g.rows {
+		sizes[group] = len(rows)
+		index[group] = g.groupLabel(group)
+	}
+	series := NewSeries("size", sizes)
+	series.index = index
+	return series
+}
+
+// Ngroup returns, for every row of the frame, the number of its group
+// (0 to Len()-1 in group order), aligned with the frame's index.
+func (g *Grouped) Ngroup() *Series {
+	ids := make([]interface{}, len(g.df.data))
+	for group, rows := range g.rows {
+		for _, i := range rows {
+			ids[i] = group
+		}
+	}
+	series := NewSeries("ngroup", ids)
+	series.index = append([]interface{}{}, g.df.index...)
+	return series
+}
+
+// First returns the first row of each group, in group order. Whole rows are
+// kept, so nulls are not skipped as the "first" aggregation does.
+func (g *Grouped) First() *DataFrame {
+	return g.Nth(0)
+}
+
+// Last returns the last row of each group, such as the latest record per
+// entity after sorting by timestamp.
+func (g *Grouped) Last() *DataFrame {
+	return g.Nth(-1)
+}
+
+// Nth returns the n-th row of each group, counting from the end when n is
+// negative. Groups with too few rows are left out.
+func (g *Grouped) Nth(n int) *DataFrame {
+	positions := make([]int, 0, len(g.rows))
+	for _, rows := range g.rows {
+		k := n
+		if k < 0 {
+			k += len(rows)
+		}
+		if k >= 0 && k < len(rows) {
+			positions = append(positions, rows[k])
+		}
+	}
+	return g.take(positions, "group_nth", map[string]interface{}{"by": g.by, "n": n})
+}
+
+// Head returns up to n leading rows of each group, in the frame's original
+// row order. A negative n keeps all but the last -n rows of each group.
+func (g *Grouped) Head(n int) *DataFrame {
+	return g.take(g.slice(n, false), "group_head", map[string]interface{}{"by": g.by, "n": n})
+}
+
+// Tail returns up to n trailing rows of each group, in the frame's original
+// row order. A negative n keeps all but the first -n rows of each group.
+func (g *Grouped) Tail(n int) *DataFrame {
+	return g.take(g.slice(n, true), "group_tail", map[string]interface{}{"by": g.by, "n": n})
+}
+
+func (g *Grouped) slice(n int, fromEnd bool) []int {
+	keep := make([]bool, len(g.df.data))
+	for _, rows := range g.rows {
+		count := n
+		if count < 0 {
+			count = max(len(rows)+n, 0)
+		}
+		start, end := 0, len(rows)
+		if count < len(rows) {
+			if fromEnd {
+				start = len(rows) - count
+			} else {
+				end = count
+			}
+		}
+		for _, i := range rows[start:end] {
+			keep[i] = true
+		}
+	}
+
+	positions := make([]int, 0)
+	for i, ok := range keep {
+		if ok {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+func (g *Grouped) take(positions []int, op string, details map[string]interface{}) *DataFrame {
+	result := g.df.derive(NewDataFrame(g.df.columns), op, details)
+	for _, i := range positions {
+		result.data = append(result.data, append([]interface{}{}, g.df.data[i]...))
+		result.index = append(result.index, g.df.index[i])
+	}
+	return result
+}
+
+// SumWhere sums the numeric values at positions where mask is true.
+func (s *Series) SumWhere(mask *Series) (float64, error) {
+	if len(mask.data) != len(s.data) {
+		return 0, fmt.Errorf("mask length %d does not match series length %d", len(mask.data), len(s.data))
+	}
+	var sum float64
+	for i, val := range s.data {
+		if keep, ok := mask.data[i].(bool); ok && keep {
+			if f, ok := toFloat64(val); ok {
+				sum += f
+			}
+		}
+	}
+	return sum, nil
+}
+
+// CountWhere counts the non-null values at positions where mask is true.
+func (s *Series) CountWhere(mask *Series) (int, error) {
+	if len(mask.data) != len(s.data) {
+		return 0, fmt.Errorf("mask length %d does not match series length %d", len(mask.data), len(s.data))
+	}
+	count := 0
+	for i, val := range s.data {
+		if keep, ok := mask.data[i].(bool); ok && keep && val != nil {
+			count++
+		}
+	}
+	return count, nil
+}
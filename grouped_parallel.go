@@ -0,0 +1,193 @@
+package gopandas
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// partialAgg is the mergeable state of an algebraic aggregation over part of
+// a group's rows.
+type partialAgg struct {
+	sum      float64
+	numbers  int
+	count    int
+	min, max interface{}
+}
+
+func (p *partialAgg) add(val interface{}) {
+	if val == nil {
+		return
+	}
+	p.count++
+	if f, ok := summableFloat(val); ok {
+		p.sum += f
+		p.numbers++
+	}
+	if p.min == nil || compareValues(val, p.min) < 0 {
+		p.min = val
+	}
+	if p.max == nil || compareValues(val, p.max) > 0 {
+		p.max = val
+	}
+}
+
+func (p *partialAgg) merge(other *partialAgg) {
+	p.sum += other.sum
+	p.numbers += other.numbers
+	p.count += other.count
+	if other.min != nil && (p.min == nil || compareValues(other.min, p.min) < 0) {
+		p.min = other.min
+	}
+	if other.max != nil && (p.max == nil || compareValues(other.max, p.max) > 0) {
+		p.max = other.max
+	}
+}
+
+func (p *partialAgg) result(name string) interface{} {
+	switch name {
+	case "count":
+		return p.count
+	case "min":
+		return p.min
+	case "max":
+		return p.max
+	}
+	if p.numbers == 0 {
+		return nil
+	}
+	if name == "mean" {
+		return p.sum / float64(p.numbers)
+	}
+	return p.sum
+}
+
+func algebraicAggregation(name string) bool {
+	if _, ok := registeredAggregation(name); ok {
+		return false
+	}
+	switch name {
+	case "sum", "count", "min", "max", "mean":
+		return true
+	}
+	return false
+}
+
+// partialGroups holds one worker's groups in the order it first saw them.
+type partialGroups struct {
+	ids   map[string]int
+	keys  [][]interface{}
+	state [][]partialAgg
+}
+
+// GroupAggParallel groups by the given columns and aggregates in one pass,
+// split across workers (runtime.NumCPU() when workers <= 0). Each worker
+// combines its rows into per-group partial results, which are then merged,
+// so the cost scales with cores rather than with a single grouping pass.
+// Only sum, count, min, max and mean are supported; the result matches
+// GroupByColumns(by...).ToDataFrame(aggs...) up to float rounding. Where
+// predicates must be safe to call from several goroutines.
+func (df *DataFrame) GroupAggParallel(workers int, by []string, aggs ...Aggregation) (*DataFrame, error) {
+	if len(by) == 0 {
+		return nil, fmt.Errorf("no group columns given")
+	}
+	byCols := make([]int, len(by))
+	for k, col := range by {
+		byCols[k] = df.columnIndex(col)
+		if byCols[k] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+	}
+	aggCols := make([]int, len(aggs))
+	names := make([]string, len(aggs))
+	for k, agg := range aggs {
+		if !algebraicAggregation(agg.Func) {
+			return nil, fmt.Errorf("aggregation '%s' cannot be computed in parallel", agg.Func)
+		}
+		aggCols[k] = df.columnIndex(agg.Column)
+		if aggCols[k] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", agg.Column)
+		}
+		names[k] = agg.name()
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(df.data) {
+		workers = len(df.data)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// contiguous chunks merged in order keep groups in first-seen order
+	partials := make([]*partialGroups, workers)
+	chunk := (len(df.data) + workers - 1) / workers
+	positions := df.rowPositions()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, (w+1)*chunk
+		if end > len(df.data) {
+			end = len(df.data)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			part := &partialGroups{ids: make(map[string]int)}
+			for i := start; i < end; i++ {
+				row := df.data[i]
+				key := make([]interface{}, len(byCols))
+				for k, j := range byCols {
+					key[k] = row[j]
+				}
+				id := groupKeyString(key)
+				group, ok := part.ids[id]
+				if !ok {
+					group = len(part.keys)
+					part.ids[id] = group
+					part.keys = append(part.keys, key)
+					part.state = append(part.state, make([]partialAgg, len(aggs)))
+				}
+				for k, agg := range aggs {
+					if agg.Where != nil && !agg.Where(Row{values: row, positions: positions}) {
+						continue
+					}
+					part.state[group][k].add(row[aggCols[k]])
+				}
+			}
+			partials[w] = part
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	merged := &partialGroups{ids: make(map[string]int)}
+	for _, part := range partials {
+		for group, key := range part.keys {
+			id := groupKeyString(key)
+			target, ok := merged.ids[id]
+			if !ok {
+				merged.ids[id] = len(merged.keys)
+				merged.keys = append(merged.keys, key)
+				merged.state = append(merged.state, part.state[group])
+				continue
+			}
+			for k := range aggs {
+				merged.state[target][k].merge(&part.state[group][k])
+			}
+		}
+	}
+
+	columns := append(append([]string{}, by...), names...)
+	result := df.derive(NewDataFrame(columns), "group_agg", map[string]interface{}{"by": by, "columns": names, "workers": workers})
+	for group, key := range merged.keys {
+		row := make([]interface{}, len(columns))
+		copy(row, key)
+		for k, agg := range aggs {
+			row[len(by)+k] = merged.state[group][k].result(agg.Func)
+		}
+		result.data = append(result.data, row)
+		result.index = append(result.index, group)
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package gopandas
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GroupingIDColumn is the indicator column added by GroupingSets, Rollup and
+// Cube. As in SQL's GROUPING_ID, bit n-1-k is set when the k-th of n key
+// columns is rolled up (nil because it was aggregated away), so detail rows
+// are 0 and the grand total has every bit set.
+const GroupingIDColumn = "grouping_id"
+
+// GroupingSets aggregates once per set of key columns, like SQL's GROUPING
+// SETS, and stacks the results. The key columns are every column named in
+// any set, in order of first mention; an empty set yields a grand total.
+// Rows are ordered so each subtotal follows the rows it summarizes, with
+// key values in first-seen order.
+func (df *DataFrame) GroupingSets(sets [][]string, aggs ...Aggregation) (*DataFrame, error) {
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("no grouping sets given")
+	}
+	by := make([]string, 0)
+	for _, set := range sets {
+		for _, col := range set {
+			if !containsString(by, col) {
+				by = append(by, col)
+			}
+		}
+	}
+	for _, col := range by {
+		if df.columnIndex(col) == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+	}
+
+	names := make([]string, len(aggs))
+	for k, agg := range aggs {
+		names[k] = agg.name()
+	}
+	columns := append(append(append([]string{}, by...), names...), GroupingIDColumn)
+
+	type setRow struct {
+		values  []interface{}
+		present []bool
+	}
+	rows := make([]setRow, 0)
+	for _, set := range sets {
+		var g *Grouped
+		if len(set) == 0 {
+			all := make([]int, len(df.data))
+			for i := range all {
+				all[i] = i
+			}
+			g = &Grouped{df: df, keys: [][]interface{}{{}}, rows: [][]int{all}}
+		} else {
+			var err error
+			if g, err = df.GroupByColumns(set...); err != nil {
+				return nil, err
+			}
+		}
+		partial, err := g.ToDataFrame(aggs...)
+		if err != nil {
+			return nil, err
+		}
+
+		grouping := 0
+		present := make([]bool, len(by))
+		positions := make([]int, len(by))
+		for k, col := range by {
+			positions[k] = -1
+			for s, name := range set {
+				if name == col {
+					positions[k] = s
+					present[k] = true
+				}
+			}
+			if !present[k] {
+				grouping |= 1 << (len(by) - 1 - k)
+			}
+		}
+		for _, values := range partial.data {
+			row := make([]interface{}, len(columns))
+			for k, s := range positions {
+				if s >= 0 {
+					row[k] = values[s]
+				}
+			}
+			copy(row[len(by):], values[len(set):])
+			row[len(columns)-1] = grouping
+			rows = append(rows, setRow{values: row, present: present})
+		}
+	}
+
+	// rank each key value by first appearance so ordering follows the data
+	ranks := make([]map[string]int, len(by))
+	for k, col := range by {
+		ranks[k] = make(map[string]int)
+		j := df.columnIndex(col)
+		for _, row := range df.data {
+			key := indexKey(row[j])
+			if _, ok := ranks[k][key]; !ok {
+				ranks[k][key] = len(ranks[k])
+			}
+		}
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		for k := range by {
+			pa, pb := rows[a].present[k], rows[b].present[k]
+			if pa != pb {
+				return pa
+			}
+			if !pa {
+				continue
+			}
+			ra, rb := ranks[k][indexKey(rows[a].values[k])], ranks[k][indexKey(rows[b].values[k])]
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		return false
+	})
+
+	result := df.derive(NewDataFrame(columns), "grouping_sets", map[string]interface{}{"sets": sets, "columns": names})
+	for i, row := range rows {
+		result.data = append(result.data, row.values)
+		result.index = append(result.index, i)
+	}
+	return result, nil
+}
+
+// Rollup aggregates by each prefix of by, from every column down to a grand
+// total: subtotals per region, per region and country, and so on.
+func (df *DataFrame) Rollup(by []string, aggs ...Aggregation) (*DataFrame, error) {
+	sets := make([][]string, 0, len(by)+1)
+	for n := len(by); n >= 0; n-- {
+		sets = append(sets, by[:n])
+	}
+	return df.GroupingSets(sets, aggs...)
+}
+
+// Cube aggregates by every subset of by, including the grand total.
+func (df *DataFrame) Cube(by []string, aggs ...Aggregation) (*DataFrame, error) {
+	if len(by) > 16 {
+		return nil, fmt.Errorf("cube over %d columns is too large (at most 16)", len(by))
+	}
+	sets := make([][]string, 0, 1<<len(by))
+	for mask := 1<<len(by) - 1; mask >= 0; mask-- {
+		set := make([]string, 0, len(by))
+		for k, col := range by {
+			if mask&(1<<(len(by)-1-k)) != 0 {
+				set = append(set, col)
+			}
+		}
+		sets = append(sets, set)
+	}
+	return df.GroupingSets(sets, aggs...)
+}
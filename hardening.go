@@ -0,0 +1,154 @@
+package gopandas
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+)
+
+// Hardening bounds what a reader accepts from an untrusted file, for
+// services ingesting user uploads. Zero fields are unlimited.
+type Hardening struct {
+	// MaxUncompressedSize caps the total uncompressed bytes of an xlsx
+	// archive, so a small zip bomb cannot expand to gigabytes.
+	MaxUncompressedSize int64
+	// MaxEntries caps the number of files in an xlsx archive.
+	MaxEntries int
+	// MaxRows caps the rows read, including header rows.
+	MaxRows int
+	// MaxCells caps the cells read across all rows. Worksheet rows count
+	// up to their last cell, since empty cells before it are filled in.
+	MaxCells int
+	// MaxColumns rejects worksheet cells past this column before the row
+	// is padded out to them, so a stray cell at XFD1 cannot make every row
+	// 16,384 cells wide.
+	MaxColumns int
+}
+
+// DefaultHardening returns limits suited to typical uploads: 100 MiB
+// uncompressed, 1,000 archive entries, Excel's 1,048,576 rows, 4,096
+// columns and 10 million cells.
+func DefaultHardening() Hardening {
+	return Hardening{
+		MaxUncompressedSize: 100 << 20,
+		MaxEntries:          1000,
+		MaxRows:             1 << 20,
+		MaxCells:            10_000_000,
+		MaxColumns:          4096,
+	}
+}
+
+// WithHardening applies h while ReadCSV reads the file. Passed to ToCSV it
+// escapes formulas instead, as WithEscapeFormulas does.
+func WithHardening(h Hardening) CSVOption {
+	return func(c *CSVConfig) {
+		c.Hardening = &h
+		c.EscapeFormulas = true
+	}
+}
+
+// WithExcelHardening is WithHardening for ReadExcel and ReadExcelChunks.
+func WithExcelHardening(h Hardening) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.Hardening = &h
+	}
+}
+
+// WithEscapeFormulas makes ToCSV prefix text cells starting with =, +, -,
+// @, tab or carriage return with a single quote, so a spreadsheet opening
+// the file shows them as text rather than running them as formulas.
+func WithEscapeFormulas() CSVOption {
+	return func(c *CSVConfig) {
+		c.EscapeFormulas = true
+	}
+}
+
+// escapeFormula neutralizes text a spreadsheet would treat as a formula.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// checkArchive rejects archives whose entry count or declared uncompressed
+// size exceed the limits. archive/zip fails reads that run past an entry's
+// declared size, so the declared sizes bound what is decompressed.
+func (h *Hardening) checkArchive(reader *zip.Reader) error {
+	if h.MaxEntries > 0 && len(reader.File) > h.MaxEntries {
+		return fmt.Errorf("%w: archive has %d entries, more than %d", ErrLimitExceeded, len(reader.File), h.MaxEntries)
+	}
+	if h.MaxUncompressedSize > 0 {
+		var total uint64
+		for _, file := range reader.File {
+			total += file.UncompressedSize64
+			if total > uint64(h.MaxUncompressedSize) {
+				return fmt.Errorf("%w: archive expands to more than %d bytes", ErrLimitExceeded, h.MaxUncompressedSize)
+			}
+		}
+	}
+	return nil
+}
+
+// sheetCounter applies the row, column and cell caps to worksheet rows as
+// they are streamed, before rowValues pads them.
+type sheetCounter struct {
+	h     *Hardening
+	rows  int
+	cells int
+}
+
+func (c *sheetCounter) push(row xlsxRow) error {
+	if c.h == nil {
+		return nil
+	}
+	c.rows++
+	if c.h.MaxRows > 0 && c.rows > c.h.MaxRows {
+		return fmt.Errorf("%w: more than %d rows", ErrLimitExceeded, c.h.MaxRows)
+	}
+	width, err := rowWidth(row)
+	if err != nil {
+		return err
+	}
+	if c.h.MaxColumns > 0 && width > c.h.MaxColumns {
+		return fmt.Errorf("%w: row %d has a cell in column %d, beyond %d columns", ErrLimitExceeded, c.rows, width, c.h.MaxColumns)
+	}
+	c.cells += width
+	if c.h.MaxCells > 0 && c.cells > c.h.MaxCells {
+		return fmt.Errorf("%w: more than %d cells", ErrLimitExceeded, c.h.MaxCells)
+	}
+	return nil
+}
+
+// rowWidth is the length rowValues will give row: one past its last cell.
+func rowWidth(row xlsxRow) (int, error) {
+	width, next := 0, 0
+	for _, cell := range row.Cells {
+		col, err := cellColumn(cell, next)
+		if err != nil {
+			return 0, err
+		}
+		width = max(width, col+1)
+		next = col + 1
+	}
+	return width, nil
+}
+
+// checkRows fails once rows holds more rows or cells than allowed. Worksheet
+// rows are already checked as they stream; this covers .xls sheets, which
+// are parsed whole.
+func (h *Hardening) checkRows(rows [][]string) error {
+	if h.MaxRows > 0 && len(rows) > h.MaxRows {
+		return fmt.Errorf("%w: more than %d rows", ErrLimitExceeded, h.MaxRows)
+	}
+	if h.MaxCells > 0 {
+		cells := 0
+		for _, row := range rows {
+			cells += len(row)
+			if cells > h.MaxCells {
+				return fmt.Errorf("%w: more than %d cells", ErrLimitExceeded, h.MaxCells)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package gopandas
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+// Hash returns a hex SHA-256 digest of the column names and every cell, in
+// order, that stays the same across runs and processes for the same data.
+// Values are hashed with their type, so 3 and 3.0 differ; the index,
+// attributes and lineage are not included. Use it to tell whether a
+// dataset changed or as a cache key.
+func (df *DataFrame) Hash() string {
+	h := df.headerHash("ordered")
+	for _, row := range df.data {
+		for _, val := range row {
+			writeHashValue(h, val)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashUnordered is Hash ignoring row order: frames with the same rows, each
+// repeated the same number of times, give the same digest in any order.
+// Column order still counts.
+func (df *DataFrame) HashUnordered() string {
+	digests := make([]string, len(df.data))
+	for i, row := range df.data {
+		digests[i] = rowDigest(row)
+	}
+	sort.Strings(digests)
+
+	h := df.headerHash("unordered")
+	for _, digest := range digests {
+		h.Write([]byte(digest))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashRows returns a hex SHA-256 digest of each row's cells, aligned with
+// the index, for spotting which rows changed between two versions.
+func (df *DataFrame) HashRows() *Series {
+	digests := make([]interface{}, len(df.data))
+	for i, row := range df.data {
+		digests[i] = hex.EncodeToString([]byte(rowDigest(row)))
+	}
+	series := NewSeries("hash", digests)
+	series.index = append([]interface{}{}, df.index...)
+	return series
+}
+
+// HashRows returns a hex SHA-256 digest of each value, hashed as Hash
+// does, for example to pseudonymize keys or bucket rows consistently.
+func (s *Series) HashRows() *Series {
+	digests := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		digests[i] = hex.EncodeToString([]byte(rowDigest([]interface{}{val})))
+	}
+	return s.withData(s.name, digests)
+}
+
+func (df *DataFrame) headerHash(variant string) hash.Hash {
+	h := sha256.New()
+	hashBytes(h, []byte("gopandas/"+variant+"/v1"))
+	binary.Write(h, binary.BigEndian, uint64(len(df.columns)))
+	for _, col := range df.columns {
+		hashBytes(h, []byte(col))
+	}
+	return h
+}
+
+// rowDigest is the raw SHA-256 of a row's cells.
+func rowDigest(row []interface{}) string {
+	h := sha256.New()
+	for _, val := range row {
+		writeHashValue(h, val)
+	}
+	return string(h.Sum(nil))
+}
+
+// writeHashValue writes val with its type, length-prefixed so that adjacent
+// values cannot run together.
+func writeHashValue(h hash.Hash, val interface{}) {
+	encoded, err := encodeValue(val)
+	if err != nil {
+		hashBytes(h, []byte(fmt.Sprintf("%T", val)))
+		hashBytes(h, []byte(fmt.Sprintf("%v", val)))
+		return
+	}
+	hashBytes(h, []byte(encoded.Type))
+	hashBytes(h, encoded.Value)
+}
+
+func hashBytes(h hash.Hash, data []byte) {
+	binary.Write(h, binary.BigEndian, uint64(len(data)))
+	h.Write(data)
+}
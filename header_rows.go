@@ -0,0 +1,57 @@
+package gopandas
+
+import "strings"
+
+// WithHeaderRows reads the first n rows as the header, joining each
+// column's parts with joiner, so a group row over a field row gives names
+// like "2024_Q1". Blank cells in the upper rows take the value to their
+// left, as merged group labels leave them empty.
+func WithHeaderRows(n int, joiner string) CSVOption {
+	return func(c *CSVConfig) {
+		c.HeaderRows = n
+		c.HeaderJoiner = joiner
+	}
+}
+
+// WithExcelHeaderRows is WithHeaderRows for ReadExcel; the header starts at
+// the row chosen by WithHeaderRow.
+func WithExcelHeaderRows(n int, joiner string) ExcelOption {
+	return func(c *ExcelConfig) {
+		c.HeaderRows = n
+		c.HeaderJoiner = joiner
+	}
+}
+
+// combineHeaderRows merges stacked header rows into one name per column.
+func combineHeaderRows(rows [][]string, joiner string) []string {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	names := make([]string, width)
+	for j := range names {
+		parts := make([]string, 0, len(rows))
+		for r, row := range rows {
+			cell := ""
+			if j < len(row) {
+				cell = strings.TrimSpace(row[j])
+			}
+			if cell == "" && r < len(rows)-1 {
+				// group labels span the blank cells after them
+				for k := j - 1; k >= 0 && cell == ""; k-- {
+					if k < len(row) {
+						cell = strings.TrimSpace(row[k])
+					}
+				}
+			}
+			if cell != "" {
+				parts = append(parts, cell)
+			}
+		}
+		names[j] = strings.Join(parts, joiner)
+	}
+	return names
+}
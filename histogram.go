@@ -0,0 +1,170 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Histogram counts the numeric values of the series in bins equal-width
+// bins spanning its minimum to maximum. See HistogramEdges for the result.
+func (s *Series) Histogram(bins int) (*DataFrame, error) {
+	if bins <= 0 {
+		return nil, fmt.Errorf("bin count must be positive, got %d", bins)
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, val := range s.data {
+		if f, ok := toFloat64(val); ok && !math.IsNaN(f) {
+			low, high = math.Min(low, f), math.Max(high, f)
+		}
+	}
+	switch {
+	case low > high:
+		low, high = 0, 1
+	case low == high:
+		low, high = low-0.5, high+0.5
+	}
+
+	edges := make([]float64, bins+1)
+	for k := range edges {
+		edges[k] = low + (high-low)*float64(k)/float64(bins)
+	}
+	edges[bins] = high
+	return s.HistogramEdges(edges)
+}
+
+// HistogramEdges counts the numeric values of the series between
+// consecutive edges, which must increase. Bins include their left edge and
+// the last bin also its right edge; values outside the edges, nulls and
+// non-numeric values are not counted. The result has one row per bin with
+// columns left, right and count.
+func (s *Series) HistogramEdges(edges []float64) (*DataFrame, error) {
+	if len(edges) < 2 {
+		return nil, fmt.Errorf("need at least two bin edges, got %d", len(edges))
+	}
+	for k := 1; k < len(edges); k++ {
+		if !(edges[k] > edges[k-1]) {
+			return nil, fmt.Errorf("bin edges must increase: %v then %v", edges[k-1], edges[k])
+		}
+	}
+
+	counts := make([]int, len(edges)-1)
+	last := len(edges) - 1
+	for _, val := range s.data {
+		f, ok := toFloat64(val)
+		if !ok || math.IsNaN(f) || f < edges[0] || f > edges[last] {
+			continue
+		}
+		k := sort.SearchFloat64s(edges, f)
+		if k == len(edges) || edges[k] != f {
+			k--
+		}
+		if k == last {
+			k--
+		}
+		counts[k]++
+	}
+
+	result := NewDataFrame([]string{"left", "right", "count"})
+	for k, count := range counts {
+		result.data = append(result.data, []interface{}{edges[k], edges[k+1], count})
+		result.index = append(result.index, k)
+	}
+	return result, nil
+}
+
+// FreqTable counts the rows for each combination of values in columns. The
+// result has the columns followed by count and percent (of all rows),
+// most frequent first, ties in order of first appearance.
+func (df *DataFrame) FreqTable(columns ...string) (*DataFrame, error) {
+	g, err := df.GroupByColumns(columns...)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(g.keys))
+	for group := range order {
+		order[group] = group
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(g.rows[order[a]]) > len(g.rows[order[b]])
+	})
+
+	result := df.derive(NewDataFrame(append(append([]string{}, columns...), "count", "percent")), "freq_table", map[string]interface{}{"by": columns})
+	for i, group := range order {
+		row := append(append([]interface{}{}, g.keys[group]...), len(g.rows[group]), 100*float64(len(g.rows[group]))/float64(len(df.data)))
+		result.data = append(result.data, row)
+		result.index = append(result.index, i)
+	}
+	return result, nil
+}
+
+// sparkBlocks are the bar characters of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the series as one line of block characters scaled from
+// its minimum to maximum, one per value. Nulls, non-numeric values, NaN and
+// infinities are spaces.
+func (s *Series) Sparkline() string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, val := range s.data {
+		if f, ok := toFloat64(val); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			low, high = math.Min(low, f), math.Max(high, f)
+		}
+	}
+
+	line := make([]rune, len(s.data))
+	for i, val := range s.data {
+		f, ok := toFloat64(val)
+		switch {
+		case !ok || math.IsNaN(f) || math.IsInf(f, 0):
+			line[i] = ' '
+		case high == low:
+			line[i] = sparkBlocks[len(sparkBlocks)/2]
+		default:
+			line[i] = sparkBlocks[int((f-low)/(high-low)*float64(len(sparkBlocks)-1)+0.5)]
+		}
+	}
+	return string(line)
+}
+
+// HistogramASCII draws a histogram of the series (ten bins, as Histogram)
+// with one line per bin: its range, a bar at most width characters long
+// scaled to the fullest bin, and its count.
+func (s *Series) HistogramASCII(width int) (string, error) {
+	if width <= 0 {
+		return "", fmt.Errorf("width must be positive, got %d", width)
+	}
+	hist, err := s.Histogram(10)
+	if err != nil {
+		return "", err
+	}
+
+	labels := make([]string, len(hist.data))
+	labelWidth, most := 0, 0
+	for k, row := range hist.data {
+		closer := ")"
+		if k == len(hist.data)-1 {
+			closer = "]"
+		}
+		labels[k] = fmt.Sprintf("[%.4g, %.4g%s", row[0], row[1], closer)
+		if len(labels[k]) > labelWidth {
+			labelWidth = len(labels[k])
+		}
+		if count := row[2].(int); count > most {
+			most = count
+		}
+	}
+
+	var b strings.Builder
+	for k, row := range hist.data {
+		count := row[2].(int)
+		bar := 0
+		if most > 0 {
+			bar = int(math.Round(float64(count) / float64(most) * float64(width)))
+		}
+		fmt.Fprintf(&b, "%-*s %-*s %d\n", labelWidth, labels[k], width, strings.Repeat("█", bar), count)
+	}
+	return b.String(), nil
+}
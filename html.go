@@ -0,0 +1,60 @@
+package gopandas
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+func (df *DataFrame) ToHTML() string {
+	var b strings.Builder
+
+	b.WriteString("<table class=\"dataframe\">\n<thead>\n<tr>")
+	for _, col := range df.columns {
+		if summary := df.metaSummary(col); summary != "" {
+			b.WriteString("<th title=\"" + html.EscapeString(summary) + "\">" + html.EscapeString(col) + "</th>")
+			continue
+		}
+		b.WriteString("<th>" + html.EscapeString(col) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, row := range df.data {
+		b.WriteString("<tr>")
+		for _, val := range row {
+			if val == nil {
+				b.WriteString("<td></td>")
+				continue
+			}
+			b.WriteString("<td>" + html.EscapeString(fmt.Sprintf("%v", val)) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+const displayMaxRows = 60
+
+func (df *DataFrame) MIMEBundle() map[string]interface{} {
+	shown := df
+	note := ""
+	if len(df.data) > displayMaxRows {
+		shown = df.Head(displayMaxRows)
+		note = fmt.Sprintf("... %d more rows\n", len(df.data)-displayMaxRows)
+	}
+
+	rows, cols := df.Shape()
+	shape := fmt.Sprintf("%d rows × %d columns", rows, cols)
+
+	return map[string]interface{}{
+		"text/plain": shown.String() + note + shape + "\n",
+		"text/html":  shown.ToHTML() + "<p>" + html.EscapeString(strings.TrimSpace(note+" "+shape)) + "</p>\n",
+	}
+}
+
+// SimpleRender lets gophernotes display frames as rich tables.
+func (df *DataFrame) SimpleRender() map[string]interface{} {
+	return df.MIMEBundle()
+}
@@ -0,0 +1,156 @@
+package gopandas
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultPageSize = 100
+
+func (df *DataFrame) Slice(start, end int) *DataFrame {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(df.data) {
+		end = len(df.data)
+	}
+	if end < 0 {
+		end = 0
+	}
+	if start > end {
+		start = end
+	}
+
+	result := NewDataFrame(df.columns)
+	result.data = df.data[start:end:end]
+	result.index = df.index[start:end:end]
+	return df.derive(result, "slice", map[string]interface{}{"start": start, "end": end})
+}
+
+func (df *DataFrame) ToRecords() []map[string]interface{} {
+	records := make([]map[string]interface{}, len(df.data))
+	for i, row := range df.data {
+		record := make(map[string]interface{}, len(df.columns))
+		for j, col := range df.columns {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+	return records
+}
+
+func Handler(df *DataFrame) http.Handler {
+	return HandlerFunc(func(*http.Request) (*DataFrame, error) {
+		return df, nil
+	})
+}
+
+func HandlerFunc(provider func(r *http.Request) (*DataFrame, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		df, err := provider(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		if columns := query.Get("columns"); columns != "" {
+			df, err = df.Select(strings.Split(columns, ",")...)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		offset, err := queryInt(query.Get("offset"), 0)
+		if err != nil {
+			http.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		limit, err := queryInt(query.Get("limit"), defaultPageSize)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+
+		total := len(df.data)
+		// offset+limit can overflow for huge query values, so stop at total
+		end := total
+		if limit < total-offset {
+			end = offset + limit
+		}
+		page := df.Slice(offset, end)
+
+		// the body is built before anything is written, so an encoding
+		// error (such as NaN in JSON) can still become a 500
+		var body bytes.Buffer
+		contentType := "application/json"
+		switch negotiateFormat(r) {
+		case "csv":
+			contentType = "text/csv; charset=utf-8"
+			err = page.writeCSV(&body, &CSVConfig{HasHeader: true, Delimiter: ','}, nil)
+		case "html":
+			contentType = "text/html; charset=utf-8"
+			body.WriteString(page.ToHTML())
+		default:
+			err = json.NewEncoder(&body).Encode(map[string]interface{}{
+				"columns": page.columns,
+				"data":    page.ToRecords(),
+				"offset":  offset,
+				"limit":   limit,
+				"total":   total,
+			})
+		}
+		if err != nil {
+			http.Error(w, "failed to encode frame: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		w.Write(body.Bytes())
+	})
+}
+
+func negotiateFormat(r *http.Request) string {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "csv":
+		return "csv"
+	case "html":
+		return "html"
+	case "json":
+		return "json"
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch mediaType {
+		case "text/csv":
+			return "csv"
+		case "text/html":
+			return "html"
+		case "application/json":
+			return "json"
+		}
+	}
+	return "json"
+}
+
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return n, nil
+}
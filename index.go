@@ -0,0 +1,245 @@
+package gopandas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// hashIndex maps the normalized value of a column to the rows holding it.
+type hashIndex map[string][]int
+
+// indexKey normalizes a value for hashing; numbers compare by value, so
+// 3 and 3.0 land in the same bucket.
+func indexKey(val interface{}) string {
+	if f, ok := toFloat64(val); ok {
+		return "n:" + strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return groupKeyString([]interface{}{val})
+}
+
+func buildHashIndex(df *DataFrame, colIndex int) hashIndex {
+	index := make(hashIndex)
+	for i, row := range df.data {
+		if row[colIndex] == nil {
+			continue
+		}
+		key := indexKey(row[colIndex])
+		index[key] = append(index[key], i)
+	}
+	return index
+}
+
+// CreateIndex builds a hash index on column, used by Loc, IsIn and Join to
+// look up rows without scanning. AddRow keeps the index up to date, and
+// SetColumn on the column drops it. SetColumn on a derived frame writes to
+// copied rows, so it never leaves this frame's index stale.
+func (df *DataFrame) CreateIndex(column string) error {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return fmt.Errorf("column '%s' not found", column)
+	}
+	if df.indexes == nil {
+		df.indexes = make(map[string]hashIndex)
+	}
+	df.indexes[column] = buildHashIndex(df, colIndex)
+	return nil
+}
+
+// DropIndex removes the index on column, if any.
+func (df *DataFrame) DropIndex(column string) {
+	delete(df.indexes, column)
+}
+
+// HasIndex reports whether column has a hash index.
+func (df *DataFrame) HasIndex(column string) bool {
+	_, ok := df.indexes[column]
+	return ok
+}
+
+// indexAppended adds the last row to every index.
+func (df *DataFrame) indexAppended() {
+	i := len(df.data) - 1
+	for column, index := range df.indexes {
+		val := df.data[i][df.columnIndex(column)]
+		if val == nil {
+			continue
+		}
+		key := indexKey(val)
+		index[key] = append(index[key], i)
+	}
+}
+
+// lookupIndex returns the hash index on column, building a temporary one
+// when the frame has none.
+func (df *DataFrame) lookupIndex(column string) (hashIndex, error) {
+	if index, ok := df.indexes[column]; ok {
+		return index, nil
+	}
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	return buildHashIndex(df, colIndex), nil
+}
+
+func (df *DataFrame) takeRows(positions []int, op string, details map[string]interface{}) *DataFrame {
+	result := NewDataFrame(df.columns)
+	for _, i := range positions {
+		result.data = append(result.data, df.data[i])
+		result.index = append(result.index, df.index[i])
+	}
+	return df.derive(result, op, details)
+}
+
+// Loc returns the rows whose value in column equals value.
+func (df *DataFrame) Loc(column string, value interface{}) (*DataFrame, error) {
+	if df.columnIndex(column) == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	var positions []int
+	if index, ok := df.indexes[column]; ok {
+		positions = index[indexKey(value)]
+	} else {
+		colIndex := df.columnIndex(column)
+		key := indexKey(value)
+		for i, row := range df.data {
+			if row[colIndex] != nil && indexKey(row[colIndex]) == key {
+				positions = append(positions, i)
+			}
+		}
+	}
+	return df.takeRows(positions, "loc", map[string]interface{}{"column": column, "value": value}), nil
+}
+
+// IsIn keeps the rows whose value in column is one of values, in their
+// original order.
+func (df *DataFrame) IsIn(column string, values []interface{}) (*DataFrame, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+
+	var positions []int
+	if index, ok := df.indexes[column]; ok {
+		seen := make(map[string]bool, len(values))
+		for _, val := range values {
+			key := indexKey(val)
+			if !seen[key] {
+				seen[key] = true
+				positions = append(positions, index[key]...)
+			}
+		}
+		sort.Ints(positions)
+	} else {
+		keys := make(map[string]bool, len(values))
+		for _, val := range values {
+			keys[indexKey(val)] = true
+		}
+		for i, row := range df.data {
+			if row[colIndex] != nil && keys[indexKey(row[colIndex])] {
+				positions = append(positions, i)
+			}
+		}
+	}
+	return df.takeRows(positions, "is_in", map[string]interface{}{"column": column, "values": len(values)}), nil
+}
+
+// Join matches rows of df and other on equal values of column on. how is
+// "inner", "left", "semi" (left rows with a match) or "anti" (left rows
+// without one). Inner and left joins append other's columns, suffixing
+// clashing names with "_right". Other's index on the column is used when
+// present.
+func (df *DataFrame) Join(other *DataFrame, on, how string) (*DataFrame, error) {
+	leftCol := df.columnIndex(on)
+	if leftCol == -1 {
+		return nil, fmt.Errorf("column '%s' not found", on)
+	}
+	rightCol := other.columnIndex(on)
+	if rightCol == -1 {
+		return nil, fmt.Errorf("column '%s' not found in right frame", on)
+	}
+	switch how {
+	case "inner", "left", "semi", "anti":
+	default:
+		return nil, fmt.Errorf("unknown join type '%s'", how)
+	}
+	index, err := other.lookupIndex(on)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{"on": on, "how": how}
+	if how == "semi" || how == "anti" {
+		result := NewDataFrame(df.columns)
+		for i, row := range df.data {
+			matched := row[leftCol] != nil && len(index[indexKey(row[leftCol])]) > 0
+			if matched == (how == "semi") {
+				result.data = append(result.data, row)
+				result.index = append(result.index, df.index[i])
+			}
+		}
+		return combine(result, "join", details, df, other), nil
+	}
+
+	columns := append([]string{}, df.columns...)
+	rightCols := make([]int, 0, len(other.columns)-1)
+	for j, col := range other.columns {
+		if j == rightCol {
+			continue
+		}
+		if df.columnIndex(col) != -1 {
+			col += "_right"
+		}
+		columns = append(columns, col)
+		rightCols = append(rightCols, j)
+	}
+
+	// count the output first so an exploding join fails before allocating
+	limit := newLimitTracker("join")
+	total := 0
+	for _, row := range df.data {
+		matches := 0
+		if row[leftCol] != nil {
+			matches = len(index[indexKey(row[leftCol])])
+		}
+		if matches == 0 && how == "left" {
+			matches = 1
+		}
+		total += matches
+	}
+	if limit.limits.MaxJoinRows > 0 && total > limit.limits.MaxJoinRows {
+		return nil, fmt.Errorf("%w: join on '%s' would produce %d rows, more than MaxJoinRows (%d)", ErrLimitExceeded, on, total, limit.limits.MaxJoinRows)
+	}
+	if err := limit.expect(total); err != nil {
+		return nil, err
+	}
+
+	result := NewDataFrame(columns)
+	for i, row := range df.data {
+		var matches []int
+		if row[leftCol] != nil {
+			matches = index[indexKey(row[leftCol])]
+		}
+		if len(matches) == 0 && how == "left" {
+			joined := append(append([]interface{}{}, row...), make([]interface{}, len(rightCols))...)
+			if err := limit.add(joined); err != nil {
+				return nil, err
+			}
+			result.data = append(result.data, joined)
+			result.index = append(result.index, df.index[i])
+		}
+		for _, m := range matches {
+			joined := append([]interface{}{}, row...)
+			for _, j := range rightCols {
+				joined = append(joined, other.data[m][j])
+			}
+			if err := limit.add(joined); err != nil {
+				return nil, err
+			}
+			result.data = append(result.data, joined)
+			result.index = append(result.index, df.index[i])
+		}
+	}
+	return combine(result, "join", details, df, other), nil
+}
@@ -0,0 +1,96 @@
+package gopandas
+
+import "strings"
+
+const (
+	arenaSlabSize      = 64 * 1024
+	columnCacheEntries = 4096
+)
+
+// rowArena hands out row slices carved from large slabs, so ingesting a
+// file costs one allocation per slab instead of one per row.
+type rowArena struct {
+	slab []interface{}
+}
+
+func (a *rowArena) alloc(width int) []interface{} {
+	if width > len(a.slab) {
+		size := arenaSlabSize
+		if width > size {
+			size = width
+		}
+		a.slab = make([]interface{}, size)
+	}
+	row := a.slab[:width:width]
+	a.slab = a.slab[width:]
+	return row
+}
+
+// columnConverter turns the cells of one column into values. Converted
+// values are cached by text, so a column with few distinct values (codes,
+// categories, flags) shares one boxed value per distinct cell.
+type columnConverter struct {
+	cache map[string]interface{}
+}
+
+func (c *columnConverter) convert(cell string) interface{} {
+	if val, ok := c.cache[cell]; ok {
+		return val
+	}
+	val := inferType(cell)
+	if c.cache == nil {
+		c.cache = make(map[string]interface{})
+	}
+	if len(c.cache) < columnCacheEntries {
+		c.cache[strings.Clone(cell)] = val
+	}
+	return val
+}
+
+// rowBuilder converts raw text rows into frame rows using an arena for the
+// row slices and one converter per column.
+type rowBuilder struct {
+	arena      rowArena
+	converters []columnConverter
+}
+
+func newRowBuilder(width int) *rowBuilder {
+	return &rowBuilder{converters: make([]columnConverter, width)}
+}
+
+// build converts cells into a row of the given width; missing cells are nil
+// and extra cells get converters of their own.
+func (b *rowBuilder) build(cells []string, width int) []interface{} {
+	row := b.arena.alloc(width)
+	for len(b.converters) < width {
+		b.converters = append(b.converters, columnConverter{})
+	}
+	for j := 0; j < width && j < len(cells); j++ {
+		row[j] = b.converters[j].convert(cells[j])
+	}
+	return row
+}
+
+// mayBeNumber reports whether strconv could parse value as a number, letting
+// inferType skip parse attempts (and their error allocations) for text.
+func mayBeNumber(value string) bool {
+	switch c := value[0]; {
+	case c >= '0' && c <= '9', c == '+', c == '-', c == '.':
+		return true
+	case c == 'i', c == 'I', c == 'n', c == 'N':
+		// inf, infinity and nan
+		return len(value) == 3 || len(value) == 8
+	}
+	return false
+}
+
+// parseBool mirrors strconv.ParseBool without allocating an error.
+func parseBool(value string) (bool, bool) {
+	switch value {
+	case "1", "t", "T", "true", "TRUE", "True":
+		return true, true
+	case "0", "f", "F", "false", "FALSE", "False":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,93 @@
+package gopandas
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+type IPAccessor struct {
+	series *Series
+}
+
+func (s *Series) AsIP() (*Series, error) {
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+		addr, err := toIPAddr(val)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		result[i] = addr
+	}
+	return s.withData(s.name, result), nil
+}
+
+func (s *Series) IP() *IPAccessor {
+	return &IPAccessor{series: s}
+}
+
+func (ia *IPAccessor) IsPrivate() *Series {
+	return ia.test("is_private", func(addr netip.Addr) bool {
+		return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast()
+	})
+}
+
+func (ia *IPAccessor) IsIPv4() *Series {
+	return ia.test("is_ipv4", func(addr netip.Addr) bool {
+		return addr.Is4()
+	})
+}
+
+func (ia *IPAccessor) InCIDR(cidr string) (*Series, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR '%s': %w", cidr, err)
+	}
+	return ia.test("in_cidr", func(addr netip.Addr) bool {
+		return prefix.Contains(addr)
+	}), nil
+}
+
+func (ia *IPAccessor) test(suffix string, fn func(netip.Addr) bool) *Series {
+	result := make([]interface{}, len(ia.series.data))
+	for i, val := range ia.series.data {
+		if val == nil {
+			continue
+		}
+		addr, err := toIPAddr(val)
+		if err != nil {
+			continue
+		}
+		result[i] = fn(addr)
+	}
+	return ia.series.withData(ia.series.name+"_"+suffix, result)
+}
+
+func (df *DataFrame) FilterCIDR(column, cidr string) (*DataFrame, error) {
+	col, err := df.GetColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := col.IP().InCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return df.FilterMask(mask)
+}
+
+func toIPAddr(val interface{}) (netip.Addr, error) {
+	switch v := val.(type) {
+	case netip.Addr:
+		return v, nil
+	case string:
+		addr, err := netip.ParseAddr(strings.TrimSpace(v))
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("invalid IP address '%s'", v)
+		}
+		return addr.Unmap(), nil
+	}
+	return netip.Addr{}, fmt.Errorf("invalid IP address '%v'", val)
+}
@@ -0,0 +1,81 @@
+package gopandas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (df *DataFrame) ParseJSONColumn(column string, fields ...string) (*DataFrame, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given to extract")
+	}
+	for _, field := range fields {
+		if df.columnIndex(field) != -1 {
+			return nil, fmt.Errorf("column '%s' already exists", field)
+		}
+	}
+
+	result := NewDataFrame(append(append([]string{}, df.columns...), fields...))
+
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(row), len(row)+len(fields))
+		copy(newRow, row)
+
+		var payload interface{}
+		if row[colIndex] != nil {
+			raw := fmt.Sprintf("%v", row[colIndex])
+			if strings.TrimSpace(raw) != "" {
+				decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+				decoder.UseNumber()
+				if err := decoder.Decode(&payload); err != nil {
+					return nil, fmt.Errorf("failed to parse JSON in row %d: %w", i, err)
+				}
+			}
+		}
+
+		for _, field := range fields {
+			newRow = append(newRow, lookupJSONField(payload, field))
+		}
+
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+
+	return df.derive(result, "parse_json_column", map[string]interface{}{"column": column, "fields": fields}), nil
+}
+
+func lookupJSONField(payload interface{}, field string) interface{} {
+	current := payload
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+
+	switch v := current.(type) {
+	case json.Number:
+		if intVal, err := v.Int64(); err == nil {
+			return int(intVal)
+		}
+		if floatVal, err := v.Float64(); err == nil {
+			return floatVal
+		}
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return string(encoded)
+	}
+
+	return current
+}
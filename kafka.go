@@ -0,0 +1,140 @@
+package gopandas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+}
+
+// KafkaConsumer and KafkaProducer are implemented by thin adapters around
+// the Kafka client of your choice, keeping this package dependency free.
+type KafkaConsumer interface {
+	Fetch(ctx context.Context) (KafkaMessage, error)
+}
+
+type KafkaProducer interface {
+	Produce(ctx context.Context, messages []KafkaMessage) error
+}
+
+type MessageDecoder func(msg KafkaMessage) (map[string]interface{}, error)
+
+type MessageEncoder func(columns []string, row []interface{}) (key, value []byte, err error)
+
+type KafkaReadOptions struct {
+	MaxRecords int
+	Window     time.Duration
+}
+
+func JSONMessageDecoder(msg KafkaMessage) (map[string]interface{}, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(msg.Value, &record); err != nil {
+		return nil, err
+	}
+	for key, val := range record {
+		if f, ok := val.(float64); ok && f == float64(int(f)) {
+			record[key] = int(f)
+		}
+	}
+	return record, nil
+}
+
+func JSONMessageEncoder(keyColumn string) MessageEncoder {
+	return func(columns []string, row []interface{}) ([]byte, []byte, error) {
+		record := make(map[string]interface{}, len(columns))
+		var key []byte
+		for i, col := range columns {
+			record[col] = row[i]
+			if col == keyColumn && row[i] != nil {
+				key = []byte(fmt.Sprintf("%v", row[i]))
+			}
+		}
+		value, err := json.Marshal(record)
+		return key, value, err
+	}
+}
+
+func ReadKafka(ctx context.Context, consumer KafkaConsumer, decoder MessageDecoder, options KafkaReadOptions) (*DataFrame, error) {
+	if options.MaxRecords <= 0 && options.Window <= 0 {
+		return nil, fmt.Errorf("either MaxRecords or Window must be set to bound the read")
+	}
+	if options.Window > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Window)
+		defer cancel()
+	}
+
+	records := make([]map[string]interface{}, 0)
+	for options.MaxRecords <= 0 || len(records) < options.MaxRecords {
+		msg, err := consumer.Fetch(ctx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && options.Window > 0 {
+				break
+			}
+			return nil, fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		record, err := decoder(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode message at offset %d: %w", msg.Offset, err)
+		}
+		records = append(records, record)
+	}
+
+	return fromRecords(records), nil
+}
+
+func fromRecords(records []map[string]interface{}) *DataFrame {
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, record := range records {
+		keys := make([]string, 0, len(record))
+		for key := range record {
+			if !seen[key] {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			seen[key] = true
+			columns = append(columns, key)
+		}
+	}
+
+	df := NewDataFrame(columns)
+	for _, record := range records {
+		row := make([]interface{}, len(columns))
+		for i, col := range columns {
+			row[i] = record[col]
+		}
+		df.AddRow(row)
+	}
+	return df
+}
+
+func (df *DataFrame) ToKafka(ctx context.Context, producer KafkaProducer, topic string, encoder MessageEncoder) error {
+	messages := make([]KafkaMessage, 0, len(df.data))
+	for i, row := range df.data {
+		key, value, err := encoder(df.columns, row)
+		if err != nil {
+			return fmt.Errorf("failed to encode row %d: %w", i, err)
+		}
+		messages = append(messages, KafkaMessage{Topic: topic, Key: key, Value: value})
+	}
+
+	if err := producer.Produce(ctx, messages); err != nil {
+		return fmt.Errorf("failed to produce messages: %w", err)
+	}
+	return nil
+}
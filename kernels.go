@@ -0,0 +1,97 @@
+package gopandas
+
+import "fmt"
+
+// Kernels over typed slices. The loops are unrolled by four with separate
+// accumulators so the compiler can keep them in registers and the CPU can
+// overlap the adds; the benchmarks in example_test.go compare them with the
+// plain loops.
+
+const kernelChunk = 4096
+
+func sumFloat64(xs []float64) float64 {
+	var s0, s1, s2, s3 float64
+	i := 0
+	for ; i+4 <= len(xs); i += 4 {
+		s0 += xs[i]
+		s1 += xs[i+1]
+		s2 += xs[i+2]
+		s3 += xs[i+3]
+	}
+	for ; i < len(xs); i++ {
+		s0 += xs[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// minMaxFloat64 returns the smallest and largest values; xs must not be empty.
+func minMaxFloat64(xs []float64) (float64, float64) {
+	lo0, hi0 := xs[0], xs[0]
+	lo1, hi1 := xs[0], xs[0]
+	i := 0
+	for ; i+2 <= len(xs); i += 2 {
+		a, b := xs[i], xs[i+1]
+		if a < lo0 {
+			lo0 = a
+		}
+		if a > hi0 {
+			hi0 = a
+		}
+		if b < lo1 {
+			lo1 = b
+		}
+		if b > hi1 {
+			hi1 = b
+		}
+	}
+	if i < len(xs) {
+		if xs[i] < lo0 {
+			lo0 = xs[i]
+		}
+		if xs[i] > hi0 {
+			hi0 = xs[i]
+		}
+	}
+	if lo1 < lo0 {
+		lo0 = lo1
+	}
+	if hi1 > hi0 {
+		hi0 = hi1
+	}
+	return lo0, hi0
+}
+
+// maskFloat64 sets out[i] to xs[i] <op> v. The operator is resolved once so
+// each loop body is a single comparison.
+func maskFloat64(xs []float64, op string, v float64, out []bool) error {
+	out = out[:len(xs)]
+	switch op {
+	case ">":
+		for i, x := range xs {
+			out[i] = x > v
+		}
+	case ">=":
+		for i, x := range xs {
+			out[i] = x >= v
+		}
+	case "<":
+		for i, x := range xs {
+			out[i] = x < v
+		}
+	case "<=":
+		for i, x := range xs {
+			out[i] = x <= v
+		}
+	case "==":
+		for i, x := range xs {
+			out[i] = x == v
+		}
+	case "!=":
+		for i, x := range xs {
+			out[i] = x != v
+		}
+	default:
+		return fmt.Errorf("unknown comparison '%s'", op)
+	}
+	return nil
+}
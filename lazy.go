@@ -0,0 +1,449 @@
+package gopandas
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// explainSampleBytes is how much of a CSV file Explain reads to estimate
+// its row count from the average row length.
+const explainSampleBytes = 64 << 10
+
+// LazyFrame records operations on a frame or a CSV file without running
+// them. Collect rewrites the plan before running it: filters move ahead of
+// sorts, selects and unrelated Evals (predicate pushdown), and a CSV scan
+// only builds the columns later steps use (projection pushdown). Explain
+// shows the rewritten plan without running it.
+type LazyFrame struct {
+	source lazySource
+	steps  []lazyStep
+	err    error
+}
+
+type lazySource struct {
+	df      *DataFrame
+	path    string
+	options []CSVOption
+}
+
+type lazyStep struct {
+	op        string
+	expr      string
+	columns   []string
+	ascending bool
+	n         int
+	// uses are the columns the step reads and defines the ones Eval
+	// assigns; select keeps its columns in columns
+	uses    []string
+	defines []string
+}
+
+// Lazy starts a lazy plan over df.
+func (df *DataFrame) Lazy() *LazyFrame {
+	return &LazyFrame{source: lazySource{df: df}}
+}
+
+// ScanCSV starts a lazy plan over a CSV file, read with options when the
+// plan is collected.
+func ScanCSV(path string, options ...CSVOption) *LazyFrame {
+	return &LazyFrame{source: lazySource{path: path, options: options}}
+}
+
+func (lf *LazyFrame) then(step lazyStep, err error) *LazyFrame {
+	next := &LazyFrame{source: lf.source, steps: append(append([]lazyStep{}, lf.steps...), step), err: lf.err}
+	if next.err == nil && err != nil {
+		next.err = fmt.Errorf("lazy step %d (%s): %w", len(next.steps), step.op, err)
+	}
+	return next
+}
+
+// Select keeps the given columns, like DataFrame.Select.
+func (lf *LazyFrame) Select(columns ...string) *LazyFrame {
+	return lf.then(lazyStep{op: "select", columns: append([]string{}, columns...), uses: columns}, nil)
+}
+
+// Query keeps the rows where expr holds, like DataFrame.Query.
+func (lf *LazyFrame) Query(expr string) *LazyFrame {
+	node, err := parseExpr(expr)
+	if err != nil {
+		return lf.then(lazyStep{op: "query", expr: expr}, fmt.Errorf("invalid expression '%s': %w", expr, err))
+	}
+	return lf.then(lazyStep{op: "query", expr: expr, uses: exprColumns(node, nil)}, nil)
+}
+
+// Eval creates or replaces columns, like DataFrame.Eval.
+func (lf *LazyFrame) Eval(expr string) *LazyFrame {
+	step := lazyStep{op: "eval", expr: expr}
+	for _, statement := range splitStatements(expr) {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		target, source, ok := splitAssignment(statement)
+		if !ok {
+			return lf.then(step, fmt.Errorf("expression '%s' must assign to a column (name = expression)", strings.TrimSpace(statement)))
+		}
+		node, err := parseExpr(source)
+		if err != nil {
+			return lf.then(step, fmt.Errorf("invalid expression '%s': %w", strings.TrimSpace(statement), err))
+		}
+		// a later statement reading an earlier target reads the new column
+		for _, name := range exprColumns(node, nil) {
+			if !containsString(step.defines, name) && !containsString(step.uses, name) {
+				step.uses = append(step.uses, name)
+			}
+		}
+		if !containsString(step.defines, target) {
+			step.defines = append(step.defines, target)
+		}
+	}
+	return lf.then(step, nil)
+}
+
+// Sort orders the rows by column, like DataFrame.Sort.
+func (lf *LazyFrame) Sort(column string, ascending bool) *LazyFrame {
+	return lf.then(lazyStep{op: "sort", columns: []string{column}, ascending: ascending, uses: []string{column}}, nil)
+}
+
+// Head keeps the first n rows.
+func (lf *LazyFrame) Head(n int) *LazyFrame {
+	var err error
+	if n < 0 {
+		err = fmt.Errorf("row count must not be negative, got %d", n)
+	}
+	return lf.then(lazyStep{op: "head", n: n}, err)
+}
+
+// exprColumns appends the columns node reads to names.
+func exprColumns(node exprNode, names []string) []string {
+	switch n := node.(type) {
+	case columnNode:
+		if !containsString(names, n.name) {
+			names = append(names, n.name)
+		}
+	case unaryNode:
+		names = exprColumns(n.operand, names)
+	case binaryNode:
+		names = exprColumns(n.right, exprColumns(n.left, names))
+	case callNode:
+		for _, arg := range n.args {
+			names = exprColumns(arg, names)
+		}
+	}
+	return names
+}
+
+// lazyPlan is a LazyFrame after optimization: the steps in the order they
+// run, the columns the scan must produce (nil for all) and a note per
+// rewrite.
+type lazyPlan struct {
+	steps   []lazyStep
+	project []string
+	notes   []string
+}
+
+func (lf *LazyFrame) optimize() *lazyPlan {
+	plan := &lazyPlan{steps: append([]lazyStep{}, lf.steps...)}
+
+	// predicate pushdown: a filter commutes with sorts and selects, and with
+	// Evals that do not define a column it reads; it never crosses a head
+	for i := range plan.steps {
+		if plan.steps[i].op != "query" {
+			continue
+		}
+		j := i
+		for j > 0 && commutesWithQuery(plan.steps[j-1], plan.steps[j]) {
+			plan.steps[j-1], plan.steps[j] = plan.steps[j], plan.steps[j-1]
+			j--
+		}
+		if j < i {
+			plan.notes = append(plan.notes, fmt.Sprintf("predicate pushdown: query %s moved before %s", plan.steps[j].expr, describeStep(plan.steps[j+1])))
+		}
+	}
+
+	// projection pushdown: walk back from the end collecting the columns
+	// each step needs from the one before; nil means every column
+	var needed []string
+	for i := len(plan.steps) - 1; i >= 0; i-- {
+		step := plan.steps[i]
+		switch step.op {
+		case "select":
+			keep := make([]string, 0, len(step.columns))
+			for _, col := range step.columns {
+				if needed == nil || containsString(needed, col) {
+					keep = append(keep, col)
+				}
+			}
+			needed = keep
+		case "eval":
+			if needed != nil {
+				kept := make([]string, 0, len(needed))
+				for _, col := range needed {
+					if !containsString(step.defines, col) {
+						kept = append(kept, col)
+					}
+				}
+				needed = kept
+			}
+			needed = addColumns(needed, step.uses)
+		case "query", "sort":
+			needed = addColumns(needed, step.uses)
+		}
+	}
+	plan.project = needed
+	return plan
+}
+
+func commutesWithQuery(prev, query lazyStep) bool {
+	switch prev.op {
+	case "sort":
+		return true
+	case "select":
+		// a query reading a column the select drops must still fail
+		for _, col := range query.uses {
+			if !containsString(prev.columns, col) {
+				return false
+			}
+		}
+		return true
+	case "eval":
+		for _, col := range query.uses {
+			if containsString(prev.defines, col) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// addColumns adds cols to needed unless needed already means every column.
+func addColumns(needed, cols []string) []string {
+	if needed == nil {
+		return nil
+	}
+	for _, col := range cols {
+		if !containsString(needed, col) {
+			needed = append(needed, col)
+		}
+	}
+	return needed
+}
+
+// Collect optimizes the plan and runs it.
+func (lf *LazyFrame) Collect() (*DataFrame, error) {
+	if lf.err != nil {
+		return nil, lf.err
+	}
+	plan := lf.optimize()
+
+	var df *DataFrame
+	var err error
+	if lf.source.df != nil {
+		df = lf.source.df
+		if plan.project != nil {
+			df, err = df.Select(projectColumns(df.columns, plan.project)...)
+		}
+	} else {
+		options := lf.source.options
+		if plan.project != nil {
+			options = append(append([]CSVOption{}, options...), func(c *CSVConfig) { c.useColumns = plan.project })
+		}
+		df, err = ReadCSV(lf.source.path, options...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, step := range plan.steps {
+		switch step.op {
+		case "select":
+			df, err = df.Select(step.columns...)
+		case "query":
+			df, err = df.Query(step.expr)
+		case "eval":
+			df, err = df.Eval(step.expr)
+		case "sort":
+			df, err = df.Sort(step.columns[0], step.ascending)
+		case "head":
+			df = df.Head(step.n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lazy step %d (%s): %w", i+1, describeStep(step), err)
+		}
+	}
+	return df, nil
+}
+
+// projectColumns keeps the columns of all that are in want, in the order of
+// all, so the scan's output looks like the source with columns removed.
+func projectColumns(all, want []string) []string {
+	kept := make([]string, 0, len(want))
+	for _, col := range all {
+		if containsString(want, col) {
+			kept = append(kept, col)
+		}
+	}
+	return kept
+}
+
+// Explain describes the optimized plan without running it: one line per
+// step with an estimated row count, then the rewrites that were applied. A
+// CSV source is sampled to estimate its rows; a filter's count is an upper
+// bound.
+func (lf *LazyFrame) Explain() (string, error) {
+	if lf.err != nil {
+		return "", lf.err
+	}
+	plan := lf.optimize()
+
+	var columns []string
+	rows, exact := -1, false
+	var b strings.Builder
+	if lf.source.df != nil {
+		columns = lf.source.df.columns
+		rows, exact = len(lf.source.df.data), true
+		b.WriteString("scan frame")
+	} else {
+		var err error
+		columns, rows, exact, err = sampleCSV(lf.source.path, lf.source.options)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "scan csv %q", lf.source.path)
+	}
+	if plan.project != nil {
+		kept := projectColumns(columns, plan.project)
+		fmt.Fprintf(&b, " columns=[%s]", strings.Join(kept, ", "))
+		plan.notes = append(plan.notes, fmt.Sprintf("projection pushdown: scan reads %d of %d columns", len(kept), len(columns)))
+	}
+	bound := ""
+	if !exact {
+		bound = "~"
+	}
+	b.WriteString(formatRows(rows, bound) + "\n")
+
+	for _, step := range plan.steps {
+		switch step.op {
+		case "query":
+			bound = "<="
+		case "head":
+			if rows < 0 || step.n < rows {
+				rows = step.n
+				if bound == "~" {
+					bound = "<="
+				}
+			}
+		}
+		b.WriteString(describeStep(step) + formatRows(rows, bound) + "\n")
+	}
+
+	if len(plan.notes) > 0 {
+		b.WriteString("optimizations:\n")
+		for _, note := range plan.notes {
+			b.WriteString("  " + note + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+func describeStep(step lazyStep) string {
+	switch step.op {
+	case "select":
+		return "select " + strings.Join(step.columns, ", ")
+	case "query", "eval":
+		return step.op + " " + step.expr
+	case "sort":
+		if step.ascending {
+			return "sort " + step.columns[0] + " asc"
+		}
+		return "sort " + step.columns[0] + " desc"
+	case "head":
+		return fmt.Sprintf("head %d", step.n)
+	}
+	return step.op
+}
+
+func formatRows(rows int, bound string) string {
+	if rows < 0 {
+		return " (rows unknown)"
+	}
+	return fmt.Sprintf(" (rows %s%d)", bound, rows)
+}
+
+// sampleCSV reads the header and up to explainSampleBytes of rows. The row
+// count is exact when the sample reaches the end of the file, and otherwise
+// extrapolated from the file size, which is only known for local files.
+func sampleCSV(path string, options []CSVOption) ([]string, int, bool, error) {
+	config := &CSVConfig{HasHeader: true, Delimiter: ','}
+	for _, option := range options {
+		option(config)
+	}
+
+	file, err := openPath(path)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	input, err := config.detectDialect(file)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	reader := newDialectReader(input, config.Delimiter, config.quote())
+	reader.FieldsPerRecord = -1
+
+	headerRows := 1
+	if config.HeaderRows > 1 {
+		headerRows = config.HeaderRows
+	}
+	header := make([][]string, 0, headerRows)
+	for len(header) < headerRows {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		header = append(header, record)
+	}
+
+	rows := 0
+	var columns []string
+	switch {
+	case config.HeaderRows > 1:
+		columns = combineHeaderRows(header, config.HeaderJoiner)
+	case config.HasHeader:
+		columns = header[0]
+	default:
+		columns = make([]string, len(header[0]))
+		for i := range columns {
+			columns[i] = fmt.Sprintf("col_%d", i)
+		}
+		rows = 1
+	}
+	columns, err = resolveDuplicates(columns, config.Duplicates)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	start := reader.InputOffset()
+
+	for reader.InputOffset()-start < explainSampleBytes {
+		if _, err := reader.Read(); err == io.EOF {
+			return columns, rows, true, nil
+		} else if err != nil {
+			return nil, 0, false, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		rows++
+	}
+
+	fs, resolved := resolveFileSystem(path)
+	if _, ok := fs.(localFileSystem); !ok {
+		return columns, -1, false, nil
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return columns, -1, false, nil
+	}
+	sampled := reader.InputOffset() - start
+	return columns, int(float64(info.Size()-start) / float64(sampled) * float64(rows)), false, nil
+}
@@ -0,0 +1,166 @@
+package gopandas
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limits bound how much data readers and joins may produce, so services
+// processing user-uploaded files fail fast with an error instead of being
+// killed for running out of memory. Zero fields are unlimited.
+type Limits struct {
+	// MaxRows caps the rows ReadCSV and ReadExcel load and a join produces.
+	MaxRows int
+	// MaxMemory caps the estimated size in bytes (see MemoryEstimate) of
+	// a frame being read or joined.
+	MaxMemory int64
+	// MaxJoinRows caps the rows a single Join produces, which can be far
+	// more than either input when keys repeat.
+	MaxJoinRows int
+}
+
+// ErrLimitExceeded is wrapped by the errors of operations stopped by Limits.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+var (
+	limitsMu sync.RWMutex
+	limits   Limits
+)
+
+// SetLimits replaces the limits applied to all frames; the default has
+// none.
+func SetLimits(l Limits) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	limits = l
+}
+
+// GetLimits returns the limits in effect.
+func GetLimits() Limits {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return limits
+}
+
+// MemoryEstimate approximates the bytes the frame's cells take in memory,
+// counting interface and slice headers and string contents. It is the
+// measure MaxMemory is checked against.
+func (df *DataFrame) MemoryEstimate() int64 {
+	var total int64
+	for _, row := range df.data {
+		total += rowMemory(row)
+	}
+	return total
+}
+
+func rowMemory(row []interface{}) int64 {
+	size := int64(24 + 16*len(row))
+	for _, val := range row {
+		switch v := val.(type) {
+		case nil, bool:
+		case string:
+			size += int64(16 + len(v))
+		case time.Time:
+			size += 24
+		case Decimal:
+			size += 64
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// limitTracker checks rows against the limits as an operation adds them.
+type limitTracker struct {
+	op     string
+	limits Limits
+	rows   int
+	bytes  int64
+}
+
+func newLimitTracker(op string) *limitTracker {
+	return &limitTracker{op: op, limits: GetLimits()}
+}
+
+// expect fails early when an operation already knows it will produce rows
+// rows.
+func (t *limitTracker) expect(rows int) error {
+	if t.limits.MaxRows > 0 && rows > t.limits.MaxRows {
+		return fmt.Errorf("%w: %s would produce %d rows, more than MaxRows (%d)", ErrLimitExceeded, t.op, rows, t.limits.MaxRows)
+	}
+	return nil
+}
+
+// add counts one more row.
+func (t *limitTracker) add(row []interface{}) error {
+	return t.count(func() int64 { return rowMemory(row) })
+}
+
+// addRecord counts one more row still held as raw text.
+func (t *limitTracker) addRecord(record []string) error {
+	return t.count(func() int64 { return recordMemory(record) })
+}
+
+func (t *limitTracker) count(size func() int64) error {
+	t.rows++
+	if t.limits.MaxRows > 0 && t.rows > t.limits.MaxRows {
+		return fmt.Errorf("%w: %s produced more than MaxRows (%d) rows", ErrLimitExceeded, t.op, t.limits.MaxRows)
+	}
+	if t.limits.MaxMemory > 0 {
+		t.bytes += size()
+		if t.bytes > t.limits.MaxMemory {
+			return fmt.Errorf("%w: %s needs more than MaxMemory (%d bytes) after %d rows", ErrLimitExceeded, t.op, t.limits.MaxMemory, t.rows)
+		}
+	}
+	return nil
+}
+
+func recordMemory(record []string) int64 {
+	size := int64(24 + 16*len(record))
+	for _, field := range record {
+		size += int64(16 + len(field))
+	}
+	return size
+}
+
+// readCheckedRecords is readDialectRecords reading one record at a time, so
+// a file over the limits or the hardening caps (h may be nil) fails at the
+// record that crosses them rather than after it is all in memory. The first
+// skip records are headers and do not count against limit.
+func readCheckedRecords(reader *csv.Reader, quote rune, h *Hardening, limit *limitTracker, skip int) ([][]string, error) {
+	records := make([][]string, 0)
+	cells := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h != nil {
+			if h.MaxRows > 0 && len(records) >= h.MaxRows {
+				return nil, fmt.Errorf("%w: more than %d rows", ErrLimitExceeded, h.MaxRows)
+			}
+			cells += len(record)
+			if h.MaxCells > 0 && cells > h.MaxCells {
+				return nil, fmt.Errorf("%w: more than %d cells", ErrLimitExceeded, h.MaxCells)
+			}
+		}
+		if len(records) >= skip {
+			if err := limit.addRecord(record); err != nil {
+				return nil, err
+			}
+		}
+		if quote != '"' {
+			unquoteFields(record, quote)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
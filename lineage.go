@@ -0,0 +1,74 @@
+package gopandas
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+var lineageEnabled atomic.Bool
+
+// SetLineageTracking turns operation recording on or off for frames created
+// or derived from then on. It is off by default.
+func SetLineageTracking(enabled bool) {
+	lineageEnabled.Store(enabled)
+}
+
+// LineageEntry records one operation in a frame's history. Operations that
+// combine frames keep each input's history in Inputs.
+type LineageEntry struct {
+	Operation string                 `json:"operation"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Time      time.Time              `json:"time"`
+	Inputs    [][]LineageEntry       `json:"inputs,omitempty"`
+}
+
+// History returns the operations that produced the frame, oldest first.
+func (df *DataFrame) History() []LineageEntry {
+	return append([]LineageEntry(nil), df.lineage...)
+}
+
+func (df *DataFrame) HistoryJSON() ([]byte, error) {
+	return json.MarshalIndent(df.History(), "", "  ")
+}
+
+// record appends an operation to the frame's own history.
+func (df *DataFrame) record(op string, details map[string]interface{}) {
+	if !lineageEnabled.Load() {
+		return
+	}
+	df.lineage = append(df.lineage, LineageEntry{Operation: op, Details: details, Time: time.Now()})
+}
+
+// derive gives result the name, attributes, column metadata and history of
+// df, followed by op, and returns result.
+func (df *DataFrame) derive(result *DataFrame, op string, details map[string]interface{}) *DataFrame {
+	result.inheritMeta(df)
+	result.inheritAttrs(df)
+	if !lineageEnabled.Load() {
+		return result
+	}
+	result.lineage = append(append([]LineageEntry(nil), df.lineage...), LineageEntry{
+		Operation: op,
+		Details:   details,
+		Time:      time.Now(),
+	})
+	return result
+}
+
+// combine starts the history of result with op over several input frames.
+func combine(result *DataFrame, op string, details map[string]interface{}, inputs ...*DataFrame) *DataFrame {
+	for _, input := range inputs {
+		result.inheritMeta(input)
+	}
+	result.inheritCommonAttrs(inputs)
+	if !lineageEnabled.Load() {
+		return result
+	}
+	histories := make([][]LineageEntry, len(inputs))
+	for i, input := range inputs {
+		histories[i] = input.History()
+	}
+	result.lineage = []LineageEntry{{Operation: op, Details: details, Time: time.Now(), Inputs: histories}}
+	return result
+}
@@ -0,0 +1,131 @@
+package gopandas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Locale struct {
+	Name         string
+	DecimalSep   string
+	ThousandsSep string
+	DateLayouts  []string
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]*Locale{
+		"en-US": {Name: "en-US", DecimalSep: ".", ThousandsSep: ",", DateLayouts: []string{"01/02/2006", "1/2/2006", "2006-01-02"}},
+		"en-GB": {Name: "en-GB", DecimalSep: ".", ThousandsSep: ",", DateLayouts: []string{"02/01/2006", "2/1/2006", "2006-01-02"}},
+		"de-DE": {Name: "de-DE", DecimalSep: ",", ThousandsSep: ".", DateLayouts: []string{"02.01.2006", "2.1.2006", "2006-01-02"}},
+		"fr-FR": {Name: "fr-FR", DecimalSep: ",", ThousandsSep: " ", DateLayouts: []string{"02/01/2006", "2/1/2006", "2006-01-02"}},
+		"es-ES": {Name: "es-ES", DecimalSep: ",", ThousandsSep: ".", DateLayouts: []string{"02/01/2006", "2/1/2006", "2006-01-02"}},
+		"it-IT": {Name: "it-IT", DecimalSep: ",", ThousandsSep: ".", DateLayouts: []string{"02/01/2006", "2/1/2006", "2006-01-02"}},
+		"pt-BR": {Name: "pt-BR", DecimalSep: ",", ThousandsSep: ".", DateLayouts: []string{"02/01/2006", "2/1/2006", "2006-01-02"}},
+		"ru-RU": {Name: "ru-RU", DecimalSep: ",", ThousandsSep: " ", DateLayouts: []string{"02.01.2006", "2.1.2006", "2006-01-02"}},
+		"ko-KR": {Name: "ko-KR", DecimalSep: ".", ThousandsSep: ",", DateLayouts: []string{"2006-01-02", "2006.01.02", "2006. 1. 2."}},
+		"ja-JP": {Name: "ja-JP", DecimalSep: ".", ThousandsSep: ",", DateLayouts: []string{"2006/01/02", "2006-01-02"}},
+	}
+)
+
+func LookupLocale(name string) (*Locale, error) {
+	localesMu.RLock()
+	locale, ok := locales[name]
+	localesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported locale '%s'", name)
+	}
+	return locale, nil
+}
+
+func RegisterLocale(locale *Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[locale.Name] = locale
+}
+
+func WithLocale(name string) CSVOption {
+	return func(c *CSVConfig) {
+		c.Locale = name
+	}
+}
+
+func (l *Locale) ParseValue(value string) interface{} {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range l.DateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	if number, ok := l.parseNumber(value); ok {
+		return number
+	}
+	// a number the locale rejects, like "1.50" in de-DE, stays text rather
+	// than being read with a C-locale decimal point
+	if mayBeNumber(value) {
+		return value
+	}
+	if boolVal, ok := parseBool(value); ok {
+		return boolVal
+	}
+	return value
+}
+
+func (l *Locale) parseNumber(value string) (interface{}, bool) {
+	normalized := value
+	if l.ThousandsSep == " " {
+		normalized = strings.NewReplacer("\u00a0", " ", "\u202f", " ").Replace(normalized)
+	}
+	if l.ThousandsSep != "" && strings.Contains(normalized, l.ThousandsSep) {
+		integerPart := normalized
+		if dec := strings.Index(normalized, l.DecimalSep); dec != -1 {
+			integerPart = normalized[:dec]
+		}
+		groups := strings.Split(integerPart, l.ThousandsSep)
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return nil, false
+			}
+		}
+		normalized = strings.ReplaceAll(normalized, l.ThousandsSep, "")
+	}
+	if l.DecimalSep != "." {
+		if strings.Contains(normalized, ".") {
+			return nil, false
+		}
+		normalized = strings.ReplaceAll(normalized, l.DecimalSep, ".")
+	}
+
+	if intVal, err := strconv.Atoi(normalized); err == nil {
+		return intVal, true
+	}
+	if floatVal, err := strconv.ParseFloat(normalized, 64); err == nil {
+		return floatVal, true
+	}
+	return nil, false
+}
+
+func (l *Locale) FormatValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", l.DecimalSep, 1)
+	case float32:
+		return strings.Replace(strconv.FormatFloat(float64(v), 'f', -1, 32), ".", l.DecimalSep, 1)
+	case time.Time:
+		if len(l.DateLayouts) > 0 && v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(l.DateLayouts[0])
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", val)
+}
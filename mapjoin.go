@@ -0,0 +1,108 @@
+package gopandas
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MapJoin decorates rows from an in-memory dictionary keyed by the values in
+// column, without building a frame for the right-hand side. lookup is any
+// map whose values are either:
+//
+//   - scalars: newCols names the single column they go in;
+//   - map[string]V or Row: newCols picks the fields to copy, all fields in
+//     name order by default.
+//
+// Keys match like index keys, so an int key finds 3.0. Rows without a match
+// get nil. Existing columns named in newCols are replaced.
+func (df *DataFrame) MapJoin(column string, lookup interface{}, newCols ...string) (*DataFrame, error) {
+	j := df.columnIndex(column)
+	if j == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	m := reflect.ValueOf(lookup)
+	if m.Kind() != reflect.Map {
+		return nil, fmt.Errorf("lookup must be a map, got %T", lookup)
+	}
+
+	entries := make(map[string]reflect.Value, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		entries[indexKey(iter.Key().Interface())] = iter.Value()
+	}
+
+	elem := m.Type().Elem()
+	rowType := reflect.TypeOf(Row{})
+	fields := elem == rowType || elem.Kind() == reflect.Map && elem.Key().Kind() == reflect.String
+	if !fields && len(newCols) != 1 {
+		return nil, fmt.Errorf("lookup values are scalars: name exactly one new column, got %d", len(newCols))
+	}
+	if fields && len(newCols) == 0 {
+		newCols = mapJoinFields(entries, elem == rowType)
+	}
+
+	field := func(val reflect.Value, name string) interface{} {
+		switch {
+		case !fields:
+			return val.Interface()
+		case elem == rowType:
+			return val.Interface().(Row).Get(name)
+		}
+		if v := val.MapIndex(reflect.ValueOf(name).Convert(elem.Key())); v.IsValid() {
+			return v.Interface()
+		}
+		return nil
+	}
+
+	resultCols := append([]string{}, df.columns...)
+	targets := make([]int, len(newCols))
+	for k, name := range newCols {
+		targets[k] = df.columnIndex(name)
+		if targets[k] == -1 {
+			targets[k] = len(resultCols)
+			resultCols = append(resultCols, name)
+		}
+	}
+
+	result := df.derive(NewDataFrame(resultCols), "map_join", map[string]interface{}{"column": column, "columns": newCols})
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(resultCols))
+		copy(newRow, row)
+		val, ok := entries[indexKey(row[j])]
+		for k, name := range newCols {
+			newRow[targets[k]] = nil
+			if ok && row[j] != nil && !(val.Kind() == reflect.Map && val.IsNil()) {
+				newRow[targets[k]] = field(val, name)
+			}
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+// mapJoinFields lists every field name found in the lookup's values.
+func mapJoinFields(entries map[string]reflect.Value, rows bool) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, val := range entries {
+		if rows {
+			for name := range val.Interface().(Row).positions {
+				add(name)
+			}
+			continue
+		}
+		for _, key := range val.MapKeys() {
+			add(key.String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
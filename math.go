@@ -0,0 +1,94 @@
+package gopandas
+
+import "math"
+
+// mapNumbers applies fn to every numeric value, leaving nil for nulls and
+// non-numeric values. Ints are passed to keepInt when it is set, so they
+// can stay ints.
+func (s *Series) mapNumbers(fn func(float64) float64, keepInt func(int) int) *Series {
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if n, ok := val.(int); ok && keepInt != nil {
+			result[i] = keepInt(n)
+			continue
+		}
+		if f, ok := toFloat64(val); ok {
+			result[i] = fn(f)
+		}
+	}
+	return s.withData(s.name, result)
+}
+
+// withUnit carries the series' unit over to result, for operations that
+// keep values in the same unit.
+func (s *Series) withUnit(result *Series) *Series {
+	result.unit = s.unit
+	return result
+}
+
+// Round rounds each value to digits decimal places (to tens, hundreds, ...
+// when digits is negative), halves away from zero. Ints stay ints.
+func (s *Series) Round(digits int) *Series {
+	scale := math.Pow(10, float64(digits))
+	round := func(f float64) float64 {
+		if digits < 0 {
+			return math.Round(f*scale) / scale
+		}
+		if scaled := f * scale; !math.IsInf(scaled, 0) {
+			return math.Round(scaled) / scale
+		}
+		return f
+	}
+	return s.withUnit(s.mapNumbers(round, func(n int) int {
+		if digits >= 0 {
+			return n
+		}
+		return int(round(float64(n)))
+	}))
+}
+
+// Floor rounds each value down. Ints stay ints.
+func (s *Series) Floor() *Series {
+	return s.withUnit(s.mapNumbers(math.Floor, func(n int) int { return n }))
+}
+
+// Ceil rounds each value up. Ints stay ints.
+func (s *Series) Ceil() *Series {
+	return s.withUnit(s.mapNumbers(math.Ceil, func(n int) int { return n }))
+}
+
+// Abs returns the absolute value of each value. Ints stay ints.
+func (s *Series) Abs() *Series {
+	return s.withUnit(s.mapNumbers(math.Abs, func(n int) int {
+		if n < 0 {
+			return -n
+		}
+		return n
+	}))
+}
+
+// Log returns the natural logarithm of each value; values below zero give
+// NaN and zero gives -Inf.
+func (s *Series) Log() *Series {
+	return s.mapNumbers(math.Log, nil)
+}
+
+// Log10 returns the base-10 logarithm of each value.
+func (s *Series) Log10() *Series {
+	return s.mapNumbers(math.Log10, nil)
+}
+
+// Exp returns e raised to each value.
+func (s *Series) Exp() *Series {
+	return s.mapNumbers(math.Exp, nil)
+}
+
+// Sqrt returns the square root of each value; negative values give NaN.
+func (s *Series) Sqrt() *Series {
+	return s.mapNumbers(math.Sqrt, nil)
+}
+
+// Pow raises each value to the power p.
+func (s *Series) Pow(p float64) *Series {
+	return s.mapNumbers(func(f float64) float64 { return math.Pow(f, p) }, nil)
+}
@@ -0,0 +1,183 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// SetColumnMeta attaches a metadata entry, such as a unit, description or
+// provenance, to a column. Metadata follows the column into derived frames.
+func (df *DataFrame) SetColumnMeta(column, key string, value interface{}) error {
+	if df.columnIndex(column) == -1 {
+		return fmt.Errorf("column '%s' not found", column)
+	}
+	if df.columnMeta == nil {
+		df.columnMeta = make(map[string]map[string]interface{})
+	}
+	if df.columnMeta[column] == nil {
+		df.columnMeta[column] = make(map[string]interface{})
+	}
+	df.columnMeta[column][key] = value
+	return nil
+}
+
+func (df *DataFrame) GetColumnMeta(column, key string) (interface{}, bool) {
+	value, ok := df.columnMeta[column][key]
+	return value, ok
+}
+
+// ColumnMeta returns a copy of every metadata entry on a column.
+func (df *DataFrame) ColumnMeta(column string) map[string]interface{} {
+	meta := make(map[string]interface{}, len(df.columnMeta[column]))
+	for key, value := range df.columnMeta[column] {
+		meta[key] = value
+	}
+	return meta
+}
+
+// inheritMeta copies metadata from src for the columns df still has.
+func (df *DataFrame) inheritMeta(src *DataFrame) {
+	for _, col := range df.columns {
+		for key, value := range src.columnMeta[col] {
+			if _, ok := df.GetColumnMeta(col, key); !ok {
+				df.SetColumnMeta(col, key, value)
+			}
+		}
+	}
+}
+
+func (df *DataFrame) metaKeys() []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, col := range df.columns {
+		for key := range df.columnMeta[col] {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (df *DataFrame) metaSummary(column string) string {
+	keys := make([]string, 0, len(df.columnMeta[column]))
+	for key := range df.columnMeta[column] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", key, df.columnMeta[column][key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Describe summarizes the numeric columns with count, mean, std, min,
+// quartiles and max, followed by one row per column metadata key.
+func (df *DataFrame) Describe() *DataFrame {
+	numeric := make([]int, 0)
+	for j := range df.columns {
+		values := make([]interface{}, len(df.data))
+		nonNil := 0
+		for i, row := range df.data {
+			values[i] = row[j]
+			if row[j] != nil {
+				nonNil++
+			}
+		}
+		if nonNil > 0 && len(numericValues(values)) == nonNil {
+			numeric = append(numeric, j)
+		}
+	}
+
+	columns := []string{"statistic"}
+	for _, j := range numeric {
+		columns = append(columns, df.columns[j])
+	}
+	result := NewDataFrame(columns)
+
+	stats := []string{"count", "mean", "std", "min", "25%", "50%", "75%", "max"}
+	summaries := make([][]interface{}, len(numeric))
+	for k, j := range numeric {
+		values := make([]interface{}, len(df.data))
+		for i, row := range df.data {
+			values[i] = row[j]
+		}
+		numbers := numericValues(values)
+		sort.Float64s(numbers)
+
+		mean, _ := aggregateValues("mean", values)
+		std, _ := aggregateValues("std", values)
+		summaries[k] = []interface{}{
+			len(numbers), mean, std, numbers[0],
+			quantileSorted(numbers, 0.25), quantileSorted(numbers, 0.5), quantileSorted(numbers, 0.75),
+			numbers[len(numbers)-1],
+		}
+	}
+
+	for s, stat := range stats {
+		row := []interface{}{stat}
+		for k := range numeric {
+			row = append(row, summaries[k][s])
+		}
+		result.AddRow(row)
+	}
+
+	for _, key := range df.metaKeys() {
+		row := []interface{}{key}
+		for _, j := range numeric {
+			value, _ := df.GetColumnMeta(df.columns[j], key)
+			row = append(row, value)
+		}
+		result.AddRow(row)
+	}
+
+	return result
+}
+
+// quantileSorted interpolates linearly between the closest ranks, as pandas does.
+func quantileSorted(numbers []float64, q float64) float64 {
+	pos := q * float64(len(numbers)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return numbers[lower] + (numbers[upper]-numbers[lower])*(pos-float64(lower))
+}
+
+// Info describes the frame's shape and, per column, the non-null count,
+// value type and metadata.
+func (df *DataFrame) Info() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<gopandas.DataFrame>\n%d entries\nData columns (total %d columns):\n", len(df.data), len(df.columns))
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, " #\tColumn\tNon-Null Count\tDtype\tMeta")
+	for j, col := range df.columns {
+		nonNil := 0
+		dtype := ""
+		for _, row := range df.data {
+			if row[j] == nil {
+				continue
+			}
+			nonNil++
+			t := fmt.Sprintf("%T", row[j])
+			if dtype == "" {
+				dtype = t
+			} else if dtype != t {
+				dtype = "mixed"
+			}
+		}
+		if dtype == "" {
+			dtype = "<nil>"
+		}
+		fmt.Fprintf(w, " %d\t%s\t%d non-null\t%s\t%s\n", j, col, nonNil, dtype, df.metaSummary(col))
+	}
+	w.Flush()
+
+	return b.String()
+}
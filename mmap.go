@@ -0,0 +1,467 @@
+package gopandas
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const (
+	mmapMagic        = "GPDCOL01"
+	mmapHeaderSize   = 24
+	mmapManifestName = "manifest.json"
+)
+
+// Column storage kinds in the mmap layout. Values that are not ints, floats
+// or strings are kept in the binary encoding used by MarshalBinary.
+const (
+	mmapInt byte = iota + 1
+	mmapFloat
+	mmapString
+	mmapEncoded
+)
+
+type mmapManifest struct {
+	Rows    int                  `json:"rows"`
+	Columns []mmapManifestColumn `json:"columns"`
+}
+
+type mmapManifestColumn struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+// WriteMmap stores the frame in dir as one file per column, laid out so
+// OpenMmap can map them and read values in place instead of loading the
+// frame onto the heap. The row index is not kept.
+func (df *DataFrame) WriteMmap(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	manifest := mmapManifest{Rows: len(df.data)}
+	for j, col := range df.columns {
+		values := make([]interface{}, len(df.data))
+		for i, row := range df.data {
+			values[i] = row[j]
+		}
+		data, err := encodeMmapColumn(values)
+		if err != nil {
+			return fmt.Errorf("column '%s': %w", col, err)
+		}
+		file := fmt.Sprintf("col%d.bin", j)
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+			return err
+		}
+		manifest.Columns = append(manifest.Columns, mmapManifestColumn{Name: col, File: file})
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, mmapManifestName), data, 0o644)
+}
+
+func mmapKind(values []interface{}) byte {
+	kind := byte(0)
+	for _, val := range values {
+		var k byte
+		switch val.(type) {
+		case nil:
+			continue
+		case int, int8, int16, int32, int64:
+			k = mmapInt
+		case float32, float64:
+			k = mmapFloat
+		case string:
+			k = mmapString
+		default:
+			return mmapEncoded
+		}
+		switch {
+		case kind == 0 || kind == k:
+			kind = k
+		case kind == mmapInt && k == mmapFloat || kind == mmapFloat && k == mmapInt:
+			kind = mmapFloat
+		default:
+			return mmapEncoded
+		}
+	}
+	if kind == 0 {
+		return mmapString
+	}
+	return kind
+}
+
+// encodeMmapColumn lays out a column as: magic, kind, row count, a null
+// bitmap, then 8-byte values or string offsets followed by the bytes.
+func encodeMmapColumn(values []interface{}) ([]byte, error) {
+	kind := mmapKind(values)
+	words := (len(values) + 63) / 64
+
+	buf := make([]byte, mmapHeaderSize+words*8, mmapHeaderSize+words*8+len(values)*8)
+	copy(buf, mmapMagic)
+	buf[8] = kind
+	binary.LittleEndian.PutUint64(buf[16:], uint64(len(values)))
+	for i, val := range values {
+		if val == nil {
+			off := mmapHeaderSize + i/64*8
+			binary.LittleEndian.PutUint64(buf[off:], binary.LittleEndian.Uint64(buf[off:])|1<<(i%64))
+		}
+	}
+
+	if kind == mmapInt || kind == mmapFloat {
+		for _, val := range values {
+			var bits uint64
+			switch v := val.(type) {
+			case int:
+				bits = uint64(v)
+			case int8:
+				bits = uint64(v)
+			case int16:
+				bits = uint64(v)
+			case int32:
+				bits = uint64(v)
+			case int64:
+				bits = uint64(v)
+			}
+			if f, ok := toFloat64(val); ok && kind == mmapFloat {
+				bits = math.Float64bits(f)
+			}
+			buf = binary.LittleEndian.AppendUint64(buf, bits)
+		}
+		return buf, nil
+	}
+
+	payload := make([]byte, 0)
+	offsets := make([]uint64, 0, len(values)+1)
+	offsets = append(offsets, 0)
+	for _, val := range values {
+		if val != nil {
+			if kind == mmapString {
+				payload = append(payload, val.(string)...)
+			} else {
+				encoded, err := encodeValue(val)
+				if err != nil {
+					return nil, err
+				}
+				data, err := json.Marshal(encoded)
+				if err != nil {
+					return nil, err
+				}
+				payload = append(payload, data...)
+			}
+		}
+		offsets = append(offsets, uint64(len(payload)))
+	}
+	for _, off := range offsets {
+		buf = binary.LittleEndian.AppendUint64(buf, off)
+	}
+	return append(buf, payload...), nil
+}
+
+// MappedColumn reads values of one column straight from a mapped file.
+type MappedColumn struct {
+	name   string
+	kind   byte
+	rows   int
+	nulls  []byte
+	body   []byte
+	closed bool
+}
+
+func newMappedColumn(name string, data []byte) (*MappedColumn, error) {
+	if len(data) < mmapHeaderSize || string(data[:8]) != mmapMagic {
+		return nil, fmt.Errorf("column '%s': not a mapped column file", name)
+	}
+	// Every row takes at least 8 bytes after the header, which bounds the
+	// row count before it is used in any size arithmetic.
+	rows64 := binary.LittleEndian.Uint64(data[16:])
+	if rows64 > uint64(len(data)-mmapHeaderSize)/8 {
+		return nil, fmt.Errorf("column '%s': file is truncated", name)
+	}
+	rows := int(rows64)
+	words := (rows + 63) / 64
+	col := &MappedColumn{name: name, kind: data[8], rows: rows}
+	if len(data) < mmapHeaderSize+words*8 {
+		return nil, fmt.Errorf("column '%s': file is truncated", name)
+	}
+	col.nulls = data[mmapHeaderSize : mmapHeaderSize+words*8]
+	col.body = data[mmapHeaderSize+words*8:]
+
+	need := rows * 8
+	if col.kind == mmapString || col.kind == mmapEncoded {
+		need = (rows + 1) * 8
+		if len(col.body) < need {
+			return nil, fmt.Errorf("column '%s': file is truncated", name)
+		}
+		if binary.LittleEndian.Uint64(col.body[rows*8:]) > uint64(len(col.body)-need) {
+			return nil, fmt.Errorf("column '%s': file is truncated", name)
+		}
+	}
+	if len(col.body) < need {
+		return nil, fmt.Errorf("column '%s': file is truncated", name)
+	}
+	return col, nil
+}
+
+func (c *MappedColumn) Name() string {
+	return c.name
+}
+
+func (c *MappedColumn) Len() int {
+	return c.rows
+}
+
+// IsNull reports whether row i is null. It is false for rows out of range
+// and once the frame is closed.
+func (c *MappedColumn) IsNull(i int) bool {
+	if c.closed || i < 0 || i >= c.rows {
+		return false
+	}
+	return binary.LittleEndian.Uint64(c.nulls[i/64*8:])&(1<<(i%64)) != 0
+}
+
+// Float returns row i as a float64 for int and float columns.
+func (c *MappedColumn) Float(i int) (float64, bool) {
+	if c.closed || i < 0 || i >= c.rows || c.IsNull(i) {
+		return 0, false
+	}
+	bits := binary.LittleEndian.Uint64(c.body[i*8:])
+	switch c.kind {
+	case mmapInt:
+		return float64(int64(bits)), true
+	case mmapFloat:
+		return math.Float64frombits(bits), true
+	}
+	return 0, false
+}
+
+// Value returns row i with the type it was written with (ints as int).
+func (c *MappedColumn) Value(i int) (interface{}, error) {
+	if c.closed {
+		return nil, fmt.Errorf("column '%s' is closed", c.name)
+	}
+	if i < 0 || i >= c.rows {
+		return nil, fmt.Errorf("row %d out of range", i)
+	}
+	if c.IsNull(i) {
+		return nil, nil
+	}
+	switch c.kind {
+	case mmapInt:
+		return int(int64(binary.LittleEndian.Uint64(c.body[i*8:]))), nil
+	case mmapFloat:
+		return math.Float64frombits(binary.LittleEndian.Uint64(c.body[i*8:])), nil
+	}
+
+	start := binary.LittleEndian.Uint64(c.body[i*8:])
+	end := binary.LittleEndian.Uint64(c.body[(i+1)*8:])
+	strings := c.body[(c.rows+1)*8:]
+	if start > end || end > uint64(len(strings)) {
+		return nil, fmt.Errorf("row %d has invalid offsets", i)
+	}
+	raw := strings[start:end]
+	if c.kind == mmapString {
+		return string(raw), nil
+	}
+	var encoded encodedValue
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, err
+	}
+	return decodeValue(encoded)
+}
+
+// chunks decodes a numeric column into float64 chunks, calling fn with the
+// values and the rows they came from. Nulls are dropped from each chunk.
+func (c *MappedColumn) chunks(fn func(values []float64, rows []int)) error {
+	if c.closed {
+		return fmt.Errorf("column '%s' is closed", c.name)
+	}
+	if c.kind != mmapInt && c.kind != mmapFloat {
+		return fmt.Errorf("column '%s' is not numeric", c.name)
+	}
+	values := make([]float64, 0, kernelChunk)
+	rows := make([]int, 0, kernelChunk)
+	for start := 0; start < c.rows; start += kernelChunk {
+		values, rows = values[:0], rows[:0]
+		for i := start; i < start+kernelChunk && i < c.rows; i++ {
+			if f, ok := c.Float(i); ok {
+				values = append(values, f)
+				rows = append(rows, i)
+			}
+		}
+		fn(values, rows)
+	}
+	return nil
+}
+
+// Sum adds the non-null values of a numeric column without boxing them.
+func (c *MappedColumn) Sum() (float64, error) {
+	var sum float64
+	err := c.chunks(func(values []float64, _ []int) {
+		sum += sumFloat64(values)
+	})
+	return sum, err
+}
+
+// MinMax returns the smallest and largest non-null values of a numeric
+// column; ok is false when there are none.
+func (c *MappedColumn) MinMax() (lo, hi float64, ok bool, err error) {
+	err = c.chunks(func(values []float64, _ []int) {
+		if len(values) == 0 {
+			return
+		}
+		l, h := minMaxFloat64(values)
+		if !ok || l < lo {
+			lo = l
+		}
+		if !ok || h > hi {
+			hi = h
+		}
+		ok = true
+	})
+	return lo, hi, ok, err
+}
+
+// Mask compares every row of a numeric column with value using op (>, >=,
+// <, <=, ==, !=). Null rows are false.
+func (c *MappedColumn) Mask(op string, value float64) ([]bool, error) {
+	if c.closed {
+		return nil, fmt.Errorf("column '%s' is closed", c.name)
+	}
+	mask := make([]bool, c.rows)
+	scratch := make([]bool, kernelChunk)
+	var kernelErr error
+	err := c.chunks(func(values []float64, rows []int) {
+		if kernelErr != nil {
+			return
+		}
+		if kernelErr = maskFloat64(values, op, value, scratch); kernelErr != nil {
+			return
+		}
+		for k, i := range rows {
+			mask[i] = scratch[k]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mask, kernelErr
+}
+
+// MmapFrame is a frame written by WriteMmap whose columns stay in mapped
+// files. Close releases the mappings; values already read stay valid, while
+// its columns return an error from then on.
+type MmapFrame struct {
+	rows     int
+	columns  []*MappedColumn
+	releases []func() error
+}
+
+// OpenMmap maps a frame written by WriteMmap. Where mmap is unavailable the
+// column files are read into memory instead.
+func OpenMmap(dir string) (*MmapFrame, error) {
+	data, err := os.ReadFile(filepath.Join(dir, mmapManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest mmapManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	frame := &MmapFrame{rows: manifest.Rows}
+	for _, entry := range manifest.Columns {
+		mapped, release, err := mapFile(filepath.Join(dir, filepath.Base(entry.File)))
+		if err != nil {
+			frame.Close()
+			return nil, err
+		}
+		frame.releases = append(frame.releases, release)
+		col, err := newMappedColumn(entry.Name, mapped)
+		if err != nil {
+			frame.Close()
+			return nil, err
+		}
+		if col.rows != manifest.Rows {
+			frame.Close()
+			return nil, fmt.Errorf("column '%s' has %d rows, expected %d", entry.Name, col.rows, manifest.Rows)
+		}
+		frame.columns = append(frame.columns, col)
+	}
+	return frame, nil
+}
+
+func (m *MmapFrame) Len() int {
+	return m.rows
+}
+
+func (m *MmapFrame) Columns() []string {
+	names := make([]string, len(m.columns))
+	for j, col := range m.columns {
+		names[j] = col.name
+	}
+	return names
+}
+
+func (m *MmapFrame) Column(name string) (*MappedColumn, error) {
+	for _, col := range m.columns {
+		if col.name == name {
+			return col, nil
+		}
+	}
+	return nil, fmt.Errorf("column '%s' not found", name)
+}
+
+// ToDataFrame loads every column onto the heap.
+func (m *MmapFrame) ToDataFrame() (*DataFrame, error) {
+	df := NewDataFrame(m.Columns())
+	for i := 0; i < m.rows; i++ {
+		row := make([]interface{}, len(m.columns))
+		for j, col := range m.columns {
+			val, err := col.Value(i)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column '%s': %w", i, col.name, err)
+			}
+			row[j] = val
+		}
+		df.AddRow(row)
+	}
+	return df, nil
+}
+
+func (m *MmapFrame) Close() error {
+	for _, col := range m.columns {
+		col.closed = true
+		col.nulls, col.body = nil, nil
+	}
+	var first error
+	for _, release := range m.releases {
+		if err := release(); err != nil && first == nil {
+			first = err
+		}
+	}
+	m.releases = nil
+	return first
+}
+
+// ReadBinaryFile loads a frame saved with MarshalBinary, decoding straight
+// from a mapping of the file rather than a heap copy of it.
+func ReadBinaryFile(path string) (*DataFrame, error) {
+	data, release, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	df := NewDataFrame(nil)
+	if err := df.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return df, nil
+}
@@ -0,0 +1,75 @@
+package gopandas
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CleanNamesOptions adjusts CleanColumnNames. The zero value lowercases and
+// joins words with underscores.
+type CleanNamesOptions struct {
+	KeepCase  bool
+	Separator string
+}
+
+// CleanColumnNames returns a copy of the frame with tidy column names: byte
+// order marks and surrounding whitespace removed, lowercased, runs of
+// spaces and punctuation replaced by the separator, and repeats suffixed
+// with _1, _2, ... so " Total Sales ($) " becomes "total_sales". Names that
+// clean to nothing become col_N.
+func (df *DataFrame) CleanColumnNames(opts CleanNamesOptions) *DataFrame {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "_"
+	}
+
+	cleaned := make([]string, len(df.columns))
+	for j, col := range df.columns {
+		cleaned[j] = cleanColumnName(col, sep, opts.KeepCase)
+		if cleaned[j] == "" {
+			cleaned[j] = fmt.Sprintf("col_%d", j)
+		}
+	}
+	cleaned = mangleDuplicates(cleaned, sep)
+
+	renames := make(map[string]string, len(df.columns))
+	for j, col := range df.columns {
+		if col != cleaned[j] {
+			renames[col] = cleaned[j]
+		}
+	}
+
+	result := NewDataFrame(cleaned)
+	result.data = df.data
+	result.index = df.index
+	result = df.derive(result, "clean_column_names", map[string]interface{}{"renamed": renames})
+	for j, col := range df.columns {
+		for key, value := range df.columnMeta[col] {
+			result.SetColumnMeta(cleaned[j], key, value)
+		}
+	}
+	return result
+}
+
+func cleanColumnName(name, sep string, keepCase bool) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "\ufeff", ""))
+	if !keepCase {
+		name = strings.ToLower(name)
+	}
+
+	var b strings.Builder
+	pending := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pending && b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package gopandas
+
+// NullSemantics chooses how comparisons and and/or/not in expressions treat
+// null values.
+type NullSemantics int
+
+const (
+	// NullsUnknown follows SQL: a comparison with a null is unknown (nil),
+	// and and/or/not use three-valued logic, so false and unknown is false,
+	// true or unknown is true, and anything else involving unknown stays
+	// unknown. Query drops rows whose condition is unknown.
+	NullsUnknown NullSemantics = iota
+	// NullsFalse follows Go: null equals only null, ordering comparisons
+	// with a null are false, and a null operand of and/or/not is false, so
+	// every condition is true or false.
+	NullsFalse
+)
+
+// ExprOption configures Eval, EvalSeries and Query.
+type ExprOption func(*exprContext)
+
+// WithNullSemantics selects how expressions treat nulls; the default is
+// NullsUnknown.
+func WithNullSemantics(semantics NullSemantics) ExprOption {
+	return func(ctx *exprContext) {
+		ctx.nulls = semantics
+	}
+}
+
+// compareNull gives the result of comparison op when a or b is null.
+func (s NullSemantics) compareNull(op string, a, b interface{}) interface{} {
+	if s == NullsUnknown {
+		return nil
+	}
+	switch op {
+	case "==":
+		return a == nil && b == nil
+	case "!=":
+		return a != nil || b != nil
+	}
+	return false
+}
+
+// truth reads an operand of and/or/not: known reports whether it is a
+// boolean, after treating null as false under NullsFalse.
+func (s NullSemantics) truth(val interface{}) (value, known bool) {
+	if val == nil && s == NullsFalse {
+		return false, true
+	}
+	b, ok := val.(bool)
+	return b, ok
+}
+
+// and combines two conditions, false winning over unknown.
+func (s NullSemantics) and(a, b interface{}) interface{} {
+	x, okX := s.truth(a)
+	y, okY := s.truth(b)
+	switch {
+	case okX && !x, okY && !y:
+		return false
+	case okX && okY:
+		return true
+	}
+	return nil
+}
+
+// or combines two conditions, true winning over unknown.
+func (s NullSemantics) or(a, b interface{}) interface{} {
+	x, okX := s.truth(a)
+	y, okY := s.truth(b)
+	switch {
+	case okX && x, okY && y:
+		return true
+	case okX && okY:
+		return false
+	}
+	return nil
+}
+
+// not negates a condition; unknown stays unknown.
+func (s NullSemantics) not(a interface{}) interface{} {
+	if x, ok := s.truth(a); ok {
+		return !x
+	}
+	return nil
+}
@@ -1,26 +1,46 @@
 package gopandas
 
 import (
+	"context"
 	"fmt"
+	"net/netip"
 	"sort"
+	"time"
 )
 
 func (df *DataFrame) Filter(predicate func(row []interface{}) bool) *DataFrame {
 	result := NewDataFrame(df.columns)
-	
+
 	for i, row := range df.data {
 		if predicate(row) {
 			result.data = append(result.data, row)
 			result.index = append(result.index, df.index[i])
 		}
 	}
-	
-	return result
+
+	return df.derive(result, "filter", map[string]interface{}{"rows_in": len(df.data), "rows_out": len(result.data)})
+}
+
+func (df *DataFrame) FilterMask(mask *Series) (*DataFrame, error) {
+	if len(mask.data) != len(df.data) {
+		return nil, fmt.Errorf("mask length %d does not match rows length %d", len(mask.data), len(df.data))
+	}
+
+	result := NewDataFrame(df.columns)
+
+	for i, row := range df.data {
+		if keep, ok := mask.data[i].(bool); ok && keep {
+			result.data = append(result.data, row)
+			result.index = append(result.index, df.index[i])
+		}
+	}
+
+	return df.derive(result, "filter", map[string]interface{}{"mask": mask.name, "rows_in": len(df.data), "rows_out": len(result.data)}), nil
 }
 
 func (df *DataFrame) Select(columns ...string) (*DataFrame, error) {
 	colIndices := make([]int, len(columns))
-	
+
 	for i, col := range columns {
 		found := false
 		for j, dfCol := range df.columns {
@@ -34,9 +54,9 @@ func (df *DataFrame) Select(columns ...string) (*DataFrame, error) {
 			return nil, fmt.Errorf("column '%s' not found", col)
 		}
 	}
-	
+
 	result := NewDataFrame(columns)
-	
+
 	for i, row := range df.data {
 		newRow := make([]interface{}, len(columns))
 		for j, colIdx := range colIndices {
@@ -45,11 +65,25 @@ func (df *DataFrame) Select(columns ...string) (*DataFrame, error) {
 		result.data = append(result.data, newRow)
 		result.index = append(result.index, df.index[i])
 	}
-	
-	return result, nil
+
+	return df.derive(result, "select", map[string]interface{}{"columns": columns}), nil
 }
 
 func (df *DataFrame) Sort(column string, ascending bool) (*DataFrame, error) {
+	return df.SortContext(context.Background(), column, ascending)
+}
+
+// SortContext is Sort with ctx as the parent of its trace span.
+func (df *DataFrame) SortContext(ctx context.Context, column string, ascending bool) (*DataFrame, error) {
+	span := startSpan(ctx, "sort")
+	span.set("column", column)
+	span.set("rows_in", len(df.data))
+	result, err := df.sortBy(column, ascending)
+	span.end(result, err)
+	return result, err
+}
+
+func (df *DataFrame) sortBy(column string, ascending bool) (*DataFrame, error) {
 	colIndex := -1
 	for i, col := range df.columns {
 		if col == column {
@@ -57,33 +91,52 @@ func (df *DataFrame) Sort(column string, ascending bool) (*DataFrame, error) {
 			break
 		}
 	}
-	
+
 	if colIndex == -1 {
 		return nil, fmt.Errorf("column '%s' not found", column)
 	}
-	
+
 	result := NewDataFrame(df.columns)
 	result.data = make([][]interface{}, len(df.data))
 	result.index = make([]interface{}, len(df.index))
-	
+
 	copy(result.data, df.data)
 	copy(result.index, df.index)
-	
+
 	sort.Slice(result.data, func(i, j int) bool {
 		valI := result.data[i][colIndex]
 		valJ := result.data[j][colIndex]
-		
+
 		comp := compareValues(valI, valJ)
 		if ascending {
 			return comp < 0
 		}
 		return comp > 0
 	})
-	
-	return result, nil
+
+	if ascending {
+		result.detectSorted(column)
+	}
+
+	return df.derive(result, "sort", map[string]interface{}{"column": column, "ascending": ascending}), nil
 }
 
 func (df *DataFrame) GroupBy(column string) (map[interface{}]*DataFrame, error) {
+	return df.GroupByContext(context.Background(), column)
+}
+
+// GroupByContext is GroupBy with ctx as the parent of its trace span.
+func (df *DataFrame) GroupByContext(ctx context.Context, column string) (map[interface{}]*DataFrame, error) {
+	span := startSpan(ctx, "group_by")
+	span.set("column", column)
+	span.set("rows_in", len(df.data))
+	groups, err := df.groupBy(column)
+	span.set("groups", len(groups))
+	span.end(nil, err)
+	return groups, err
+}
+
+func (df *DataFrame) groupBy(column string) (map[interface{}]*DataFrame, error) {
 	colIndex := -1
 	for i, col := range df.columns {
 		if col == column {
@@ -91,24 +144,24 @@ func (df *DataFrame) GroupBy(column string) (map[interface{}]*DataFrame, error)
 			break
 		}
 	}
-	
+
 	if colIndex == -1 {
 		return nil, fmt.Errorf("column '%s' not found", column)
 	}
-	
+
 	groups := make(map[interface{}]*DataFrame)
-	
+
 	for i, row := range df.data {
 		key := row[colIndex]
-		
+
 		if groups[key] == nil {
-			groups[key] = NewDataFrame(df.columns)
+			groups[key] = df.derive(NewDataFrame(df.columns), "group_by", map[string]interface{}{"column": column, "key": key})
 		}
-		
+
 		groups[key].data = append(groups[key].data, row)
 		groups[key].index = append(groups[key].index, df.index[i])
 	}
-	
+
 	return groups, nil
 }
 
@@ -116,10 +169,10 @@ func (s *Series) Sum() (interface{}, error) {
 	if len(s.data) == 0 {
 		return nil, fmt.Errorf("series is empty")
 	}
-	
+
 	var sum float64
 	count := 0
-	
+
 	for _, val := range s.data {
 		if val != nil {
 			switch v := val.(type) {
@@ -132,14 +185,19 @@ func (s *Series) Sum() (interface{}, error) {
 			case float32:
 				sum += float64(v)
 				count++
+			case bool:
+				if v {
+					sum++
+				}
+				count++
 			}
 		}
 	}
-	
+
 	if count == 0 {
 		return nil, fmt.Errorf("no numeric values found")
 	}
-	
+
 	return sum, nil
 }
 
@@ -148,21 +206,21 @@ func (s *Series) Mean() (float64, error) {
 	if err != nil {
 		return 0, err
 	}
-	
+
 	count := 0
 	for _, val := range s.data {
 		if val != nil {
 			switch val.(type) {
-			case int, float64, float32:
+			case int, float64, float32, bool:
 				count++
 			}
 		}
 	}
-	
+
 	if count == 0 {
 		return 0, fmt.Errorf("no numeric values found")
 	}
-	
+
 	return sum.(float64) / float64(count), nil
 }
 
@@ -186,7 +244,7 @@ func compareValues(a, b interface{}) int {
 	if b == nil {
 		return 1
 	}
-	
+
 	switch va := a.(type) {
 	case int:
 		if vb, ok := b.(int); ok {
@@ -215,7 +273,32 @@ func compareValues(a, b interface{}) int {
 			}
 			return 0
 		}
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			return va.Compare(vb)
+		}
+	case time.Duration:
+		if vb, ok := b.(time.Duration); ok {
+			if va < vb {
+				return -1
+			} else if va > vb {
+				return 1
+			}
+			return 0
+		}
+	case Period:
+		if vb, ok := b.(Period); ok {
+			return va.Compare(vb)
+		}
+	case Decimal:
+		if vb, ok := b.(Decimal); ok {
+			return va.Cmp(vb)
+		}
+	case netip.Addr:
+		if vb, ok := b.(netip.Addr); ok {
+			return va.Compare(vb)
+		}
 	}
-	
+
 	return 0
-}
\ No newline at end of file
+}
@@ -0,0 +1,116 @@
+package gopandas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// hiveDefaultPartition names the directory for nil partition values, as Hive does.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// ToPartitioned writes the frame as a hive-style dataset under dir, one
+// directory level per partition column (dept=Sales/date=2024-01-01/part-0.csv).
+// Partition columns are encoded in the path and dropped from the files.
+// Supported formats are "csv" and "json" (an array of records).
+func (df *DataFrame) ToPartitioned(dir, format string, partitionCols ...string) error {
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unsupported partition format '%s' (use csv or json)", format)
+	}
+
+	partIdx := make([]int, len(partitionCols))
+	isPart := make(map[int]bool)
+	for i, col := range partitionCols {
+		partIdx[i] = df.columnIndex(col)
+		if partIdx[i] == -1 {
+			return fmt.Errorf("column '%s' not found", col)
+		}
+		isPart[partIdx[i]] = true
+	}
+
+	dataCols := make([]string, 0, len(df.columns))
+	for j, col := range df.columns {
+		if !isPart[j] {
+			dataCols = append(dataCols, col)
+		}
+	}
+
+	partitions := make(map[string]*DataFrame)
+	order := make([]string, 0)
+	for _, row := range df.data {
+		segments := make([]string, len(partitionCols))
+		for i, col := range partitionCols {
+			segments[i] = url.PathEscape(col) + "=" + partitionValue(row[partIdx[i]])
+		}
+		key := strings.Join(segments, "/")
+
+		part, ok := partitions[key]
+		if !ok {
+			part = NewDataFrame(dataCols)
+			partitions[key] = part
+			order = append(order, key)
+		}
+
+		values := make([]interface{}, 0, len(dataCols))
+		for j, val := range row {
+			if !isPart[j] {
+				values = append(values, val)
+			}
+		}
+		part.AddRow(values)
+	}
+
+	base := strings.TrimRight(dir, "/")
+	for _, key := range order {
+		partDir := base
+		if key != "" {
+			partDir += "/" + key
+		}
+		if fs, resolved := resolveFileSystem(partDir); fs == (localFileSystem{}) {
+			if err := os.MkdirAll(resolved, 0o755); err != nil {
+				return fmt.Errorf("failed to create partition directory: %w", err)
+			}
+		}
+
+		filename := partDir + "/part-0." + format
+		if err := partitions[key].writePartition(filename, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (df *DataFrame) writePartition(filename, format string) error {
+	if format == "csv" {
+		return df.ToCSV(filename)
+	}
+
+	file, err := createPath(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := json.NewEncoder(file).Encode(df.ToRecords()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
+
+func partitionValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return hiveDefaultPartition
+	case time.Time:
+		if v.Equal(truncateDay(v)) {
+			return v.Format("2006-01-02")
+		}
+		return url.PathEscape(v.Format(time.RFC3339))
+	}
+	return url.PathEscape(fmt.Sprintf("%v", val))
+}
@@ -0,0 +1,189 @@
+package gopandas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Period struct {
+	Year        int
+	Freq        string
+	Num         int
+	FiscalStart time.Month
+}
+
+func NewPeriod(t time.Time, freq string, fiscalStart time.Month) (Period, error) {
+	if fiscalStart == 0 {
+		fiscalStart = time.January
+	}
+
+	// months are counted from the fiscal year start; fiscal years are named
+	// after the calendar year in which they end
+	offset := (int(t.Month()) - int(fiscalStart) + 12) % 12
+	year := t.Year()
+	if fiscalStart != time.January && t.Month() >= fiscalStart {
+		year++
+	}
+
+	switch freq {
+	case "Y":
+		return Period{Year: year, Freq: "Y", Num: 1, FiscalStart: fiscalStart}, nil
+	case "Q":
+		return Period{Year: year, Freq: "Q", Num: offset/3 + 1, FiscalStart: fiscalStart}, nil
+	case "M":
+		return Period{Year: t.Year(), Freq: "M", Num: int(t.Month()), FiscalStart: time.January}, nil
+	}
+	return Period{}, fmt.Errorf("unsupported period frequency '%s'", freq)
+}
+
+func ParsePeriod(value string) (Period, error) {
+	value = strings.TrimSpace(value)
+
+	if i := strings.IndexAny(value, "Qq"); i == 4 {
+		year, err1 := strconv.Atoi(value[:4])
+		quarter, err2 := strconv.Atoi(value[5:])
+		if err1 == nil && err2 == nil && quarter >= 1 && quarter <= 4 {
+			return Period{Year: year, Freq: "Q", Num: quarter, FiscalStart: time.January}, nil
+		}
+	}
+	if t, err := time.Parse("2006-01", value); err == nil {
+		return NewPeriod(t, "M", time.January)
+	}
+	if len(value) == 4 {
+		if year, err := strconv.Atoi(value); err == nil {
+			return Period{Year: year, Freq: "Y", Num: 1, FiscalStart: time.January}, nil
+		}
+	}
+
+	return Period{}, fmt.Errorf("invalid period '%s'", value)
+}
+
+func (p Period) Start() time.Time {
+	fiscalStart := p.FiscalStart
+	if fiscalStart == 0 {
+		fiscalStart = time.January
+	}
+
+	switch p.Freq {
+	case "M":
+		return time.Date(p.Year, time.Month(p.Num), 1, 0, 0, 0, 0, time.UTC)
+	case "Q":
+		start := time.Date(p.Year, fiscalStart, 1, 0, 0, 0, 0, time.UTC)
+		if fiscalStart != time.January {
+			start = start.AddDate(-1, 0, 0)
+		}
+		return start.AddDate(0, 3*(p.Num-1), 0)
+	}
+
+	start := time.Date(p.Year, fiscalStart, 1, 0, 0, 0, 0, time.UTC)
+	if fiscalStart != time.January {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return start
+}
+
+func (p Period) End() time.Time {
+	return p.Next().Start().Add(-time.Nanosecond)
+}
+
+func (p Period) Next() Period {
+	next := p
+	switch p.Freq {
+	case "M":
+		next.Num++
+		if next.Num > 12 {
+			next.Num = 1
+			next.Year++
+		}
+	case "Q":
+		next.Num++
+		if next.Num > 4 {
+			next.Num = 1
+			next.Year++
+		}
+	default:
+		next.Year++
+	}
+	return next
+}
+
+func (p Period) Compare(other Period) int {
+	return p.Start().Compare(other.Start())
+}
+
+func (p Period) String() string {
+	prefix := ""
+	if p.FiscalStart != 0 && p.FiscalStart != time.January {
+		prefix = "FY"
+	}
+
+	switch p.Freq {
+	case "M":
+		return fmt.Sprintf("%04d-%02d", p.Year, p.Num)
+	case "Q":
+		return fmt.Sprintf("%s%04dQ%d", prefix, p.Year, p.Num)
+	}
+	return fmt.Sprintf("%s%04d", prefix, p.Year)
+}
+
+func (s *Series) ToPeriod(freq string) (*Series, error) {
+	return s.ToFiscalPeriod(freq, time.January)
+}
+
+func (s *Series) ToFiscalPeriod(freq string, fiscalStart time.Month) (*Series, error) {
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+
+		var t time.Time
+		switch v := val.(type) {
+		case time.Time:
+			t = v
+		case string:
+			parsed, err := time.Parse("2006-01-02", strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: cannot convert '%s' to period", i, v)
+			}
+			t = parsed
+		default:
+			return nil, fmt.Errorf("row %d: cannot convert %T to period", i, val)
+		}
+
+		p, err := NewPeriod(t, freq, fiscalStart)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = p
+	}
+	return s.withData(s.name, result), nil
+}
+
+func (df *DataFrame) GroupByPeriod(column, freq string, fiscalStart time.Month) (map[interface{}]*DataFrame, error) {
+	col, err := df.GetColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := col.ToFiscalPeriod(freq, fiscalStart)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[interface{}]*DataFrame)
+	for i, row := range df.data {
+		key := periods.data[i]
+		if key == nil {
+			continue
+		}
+		if groups[key] == nil {
+			groups[key] = df.derive(NewDataFrame(df.columns), "group_by_period", map[string]interface{}{"column": column, "period": fmt.Sprint(key)})
+		}
+		groups[key].data = append(groups[key].data, row)
+		groups[key].index = append(groups[key].index, df.index[i])
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,222 @@
+package gopandas
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PII kinds reported by ScanPII.
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIINationalID = "national_id"
+	PIICreditCard = "credit_card"
+)
+
+// PIIFinding reports values of one kind found in a column. Share is
+// Matches over the non-null values checked, and Samples holds up to three
+// matches masked with AnonymizeMask(4) so the report itself is safe to
+// share.
+type PIIFinding struct {
+	Column  string
+	Kind    string
+	Matches int
+	Checked int
+	Share   float64
+	Samples []string
+}
+
+// PIIReport lists what ScanPII found, in column order.
+type PIIReport struct {
+	Findings []PIIFinding
+}
+
+// Columns returns the names of the columns with any finding.
+func (r *PIIReport) Columns() []string {
+	columns := make([]string, 0)
+	for _, finding := range r.Findings {
+		if !containsString(columns, finding.Column) {
+			columns = append(columns, finding.Column)
+		}
+	}
+	return columns
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9](?:[A-Za-z0-9\-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9\-]*[A-Za-z0-9])?)*\.[A-Za-z]{2,}$`)
+	phonePattern = regexp.MustCompile(`^\+?[0-9 ().\-]+$`)
+	ssnPattern   = regexp.MustCompile(`^(\d{3})-(\d{2})-(\d{4})$`)
+	rrnPattern   = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})-?([1-8])\d{6}$`)
+	cardPattern  = regexp.MustCompile(`^\d[\d -]{11,22}\d$`)
+)
+
+// ScanPII flags columns that look like they hold personal data: email
+// addresses, phone numbers, national IDs (US social security and Korean
+// resident registration numbers) and payment card numbers. Each value is
+// matched against a pattern and then validated, with the Luhn checksum for
+// cards and date and range rules for IDs, to keep false positives down.
+// It is a heuristic to aid review, not a guarantee that unflagged columns
+// are clean.
+func ScanPII(df *DataFrame) *PIIReport {
+	kinds := []string{PIIEmail, PIICreditCard, PIINationalID, PIIPhone}
+	report := &PIIReport{Findings: make([]PIIFinding, 0)}
+	for j, col := range df.columns {
+		checked := 0
+		findings := make(map[string]*PIIFinding)
+		for _, row := range df.data {
+			if row[j] == nil {
+				continue
+			}
+			checked++
+			kind := classifyPII(row[j])
+			if kind == "" {
+				continue
+			}
+			finding, ok := findings[kind]
+			if !ok {
+				finding = &PIIFinding{Column: col, Kind: kind, Samples: make([]string, 0)}
+				findings[kind] = finding
+			}
+			finding.Matches++
+			if len(finding.Samples) < 3 {
+				finding.Samples = append(finding.Samples, AnonymizeMask(4)(row[j]).(string))
+			}
+		}
+		for _, kind := range kinds {
+			if finding, ok := findings[kind]; ok {
+				finding.Checked = checked
+				finding.Share = float64(finding.Matches) / float64(checked)
+				report.Findings = append(report.Findings, *finding)
+			}
+		}
+	}
+	return report
+}
+
+// classifyPII returns the kind of personal data val looks like, or "".
+func classifyPII(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		switch {
+		case isEmail(s):
+			return PIIEmail
+		case isCardNumber(s):
+			return PIICreditCard
+		case isNationalID(s):
+			return PIINationalID
+		case isPhone(s):
+			return PIIPhone
+		}
+	case int, int64:
+		if isCardNumber(fmt.Sprint(v)) {
+			return PIICreditCard
+		}
+	}
+	return ""
+}
+
+func isEmail(s string) bool {
+	return len(s) <= 254 && emailPattern.MatchString(s)
+}
+
+// isPhone accepts 7 to 15 digits, the E.164 maximum, written with a
+// leading + or 0 or with separators, so plain numeric IDs do not match.
+func isPhone(s string) bool {
+	if !phonePattern.MatchString(s) {
+		return false
+	}
+	digits := onlyDigits(s)
+	if len(digits) < 7 || len(digits) > 15 {
+		return false
+	}
+	return s[0] == '+' || s[0] == '0' || s[0] == '(' || strings.ContainsAny(s, " -.")
+}
+
+// phoneRegion validates national numbers of one region. Numbers written
+// with the country code are converted to national form first.
+type phoneRegion struct {
+	code     string
+	trunk    string
+	national *regexp.Regexp
+}
+
+var phoneRegions = map[string]phoneRegion{
+	"US": {code: "1", national: regexp.MustCompile(`^1?[2-9]\d{2}[2-9]\d{6}$`)},
+	"CA": {code: "1", national: regexp.MustCompile(`^1?[2-9]\d{2}[2-9]\d{6}$`)},
+	"KR": {code: "82", trunk: "0", national: regexp.MustCompile(`^0(?:1[016789]\d{7,8}|2\d{7,8}|[3-6][1-5]\d{7,8}|70\d{8})$`)},
+	"JP": {code: "81", trunk: "0", national: regexp.MustCompile(`^0[1-9]\d{8,9}$`)},
+	"GB": {code: "44", trunk: "0", national: regexp.MustCompile(`^0[1-9]\d{8,9}$`)},
+}
+
+func (r phoneRegion) valid(s string) bool {
+	if !phonePattern.MatchString(s) {
+		return false
+	}
+	digits := onlyDigits(s)
+	if strings.HasPrefix(s, "+") {
+		if !strings.HasPrefix(digits, r.code) {
+			return false
+		}
+		digits = r.trunk + digits[len(r.code):]
+	}
+	return r.national.MatchString(digits)
+}
+
+// isNationalID matches US social security numbers outside the never-issued
+// ranges and Korean resident registration numbers with a valid birth date.
+func isNationalID(s string) bool {
+	if m := ssnPattern.FindStringSubmatch(s); m != nil {
+		return m[1] != "000" && m[1] != "666" && m[1][0] != '9' && m[2] != "00" && m[3] != "0000"
+	}
+	if m := rrnPattern.FindStringSubmatch(s); m != nil {
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return month >= 1 && month <= 12 && day >= 1 && day <= 31
+	}
+	return false
+}
+
+// isCardNumber accepts 13 to 19 digits, optionally grouped by spaces or
+// dashes, that pass the Luhn checksum.
+func isCardNumber(s string) bool {
+	if !cardPattern.MatchString(s) {
+		return false
+	}
+	digits := onlyDigits(s)
+	return len(digits) >= 13 && len(digits) <= 19 && luhnValid(digits)
+}
+
+// luhnValid checks the Luhn checksum of a string of digits.
+func luhnValid(digits string) bool {
+	if digits == "" {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package gopandas
+
+import "fmt"
+
+// Transform is a reusable frame-to-frame step for Pipe.
+type Transform func(*DataFrame) (*DataFrame, error)
+
+// Pipe runs the transforms in order, feeding each one the previous result,
+// and stops at the first error.
+func (df *DataFrame) Pipe(transforms ...Transform) (*DataFrame, error) {
+	current := df
+	for i, transform := range transforms {
+		next, err := transform(current)
+		if err != nil {
+			return nil, fmt.Errorf("pipe step %d: %w", i+1, err)
+		}
+		if next == nil {
+			return nil, fmt.Errorf("pipe step %d returned no frame", i+1)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// WithArgs binds extra arguments to a transformation function, like the
+// positional arguments of pandas' DataFrame.pipe.
+func WithArgs(fn func(df *DataFrame, args ...interface{}) (*DataFrame, error), args ...interface{}) Transform {
+	return func(df *DataFrame) (*DataFrame, error) {
+		return fn(df, args...)
+	}
+}
@@ -0,0 +1,159 @@
+package gopandas
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// PrivacyOption configures the differential privacy helpers.
+type PrivacyOption func(*privacyConfig)
+
+type privacyConfig struct {
+	sensitivity float64
+	rng         *rand.Rand
+}
+
+// WithSensitivity sets how much one person's data can change each value,
+// which with epsilon sets the noise scale (sensitivity/epsilon). It
+// defaults to 1, right for counts; for sums and values, clip the data to
+// known bounds first and pass the bound width.
+func WithSensitivity(sensitivity float64) PrivacyOption {
+	return func(c *privacyConfig) {
+		c.sensitivity = sensitivity
+	}
+}
+
+func newPrivacyConfig(options []PrivacyOption) (*privacyConfig, error) {
+	config := &privacyConfig{sensitivity: 1, rng: rand.New(cryptoSource{})}
+	for _, option := range options {
+		option(config)
+	}
+	if config.sensitivity <= 0 || math.IsInf(config.sensitivity, 0) || math.IsNaN(config.sensitivity) {
+		return nil, fmt.Errorf("sensitivity must be positive, got %v", config.sensitivity)
+	}
+	return config, nil
+}
+
+// cryptoSource draws from crypto/rand, since noise an attacker can predict
+// protects nothing.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:]) >> 1)
+}
+
+func (cryptoSource) Seed(int64) {}
+
+// laplace draws from a Laplace distribution centred on 0.
+func (c *privacyConfig) laplace(scale float64) float64 {
+	u := c.rng.Float64() - 0.5
+	for u == -0.5 {
+		u = c.rng.Float64() - 0.5
+	}
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+func checkEpsilon(epsilon float64) error {
+	if !(epsilon > 0) || math.IsInf(epsilon, 0) {
+		return fmt.Errorf("epsilon must be positive, got %v", epsilon)
+	}
+	return nil
+}
+
+// AddLaplaceNoise returns a copy with Laplace noise of scale
+// sensitivity/epsilon added to every value of cols, making each column
+// epsilon-differentially private; noising k columns spends k*epsilon in
+// total. Noised values are floats and nulls stay null. Publishing several
+// releases of the same data spends the budget again each time.
+func (df *DataFrame) AddLaplaceNoise(cols []string, epsilon float64, options ...PrivacyOption) (*DataFrame, error) {
+	config, err := newPrivacyConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	return df.addLaplaceNoise(cols, epsilon, config)
+}
+
+func (df *DataFrame) addLaplaceNoise(cols []string, epsilon float64, config *privacyConfig) (*DataFrame, error) {
+	if err := checkEpsilon(epsilon); err != nil {
+		return nil, err
+	}
+	positions := make([]int, len(cols))
+	for k, col := range cols {
+		positions[k] = df.columnIndex(col)
+		if positions[k] == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+	}
+
+	scale := config.sensitivity / epsilon
+	result := df.derive(NewDataFrame(df.columns), "laplace_noise", map[string]interface{}{"columns": cols, "epsilon": epsilon, "sensitivity": config.sensitivity})
+	for i, row := range df.data {
+		newRow := append([]interface{}{}, row...)
+		for k, j := range positions {
+			if row[j] == nil {
+				continue
+			}
+			val, ok := toFloat64(row[j])
+			if !ok {
+				return nil, fmt.Errorf("column '%s' has non-numeric value %v", cols[k], row[j])
+			}
+			newRow[j] = val + config.laplace(scale)
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+// PrivateCount returns the row count with Laplace noise, an
+// epsilon-differentially private answer to "how many rows". The result may
+// be fractional or negative; rounding and clamping it afterwards keeps the
+// guarantee.
+func (df *DataFrame) PrivateCount(epsilon float64, options ...PrivacyOption) (float64, error) {
+	config, err := newPrivacyConfig(options)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkEpsilon(epsilon); err != nil {
+		return 0, err
+	}
+	return float64(len(df.data)) + config.laplace(config.sensitivity/epsilon), nil
+}
+
+// PrivateCount returns each group's size with Laplace noise, indexed like
+// Size. Groups are disjoint, so the whole series costs epsilon once. The
+// set of group keys is not noised: only group by columns whose values are
+// public, such as a fixed list of regions.
+func (g *Grouped) PrivateCount(epsilon float64, options ...PrivacyOption) (*Series, error) {
+	config, err := newPrivacyConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	return g.privateCount(epsilon, config)
+}
+
+func (g *Grouped) privateCount(epsilon float64, config *privacyConfig) (*Series, error) {
+	if err := checkEpsilon(epsilon); err != nil {
+		return nil, err
+	}
+	scale := config.sensitivity / epsilon
+	counts := make([]interface{}, len(g.rows))
+	index := make([]interface{}, len(g.rows))
+	for group, rows := range g.rows {
+		counts[group] = float64(len(rows)) + config.laplace(scale)
+		index[group] = g.groupLabel(group)
+	}
+	series := NewSeries("count", counts)
+	series.index = index
+	return series, nil
+}
@@ -0,0 +1,195 @@
+package gopandas
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusConfig selects what ToPrometheus exposes: one gauge per
+// column in Values, with one sample per row labeled by the Labels columns.
+type PrometheusConfig struct {
+	// Namespace prefixes every metric name, as in "jobs_revenue".
+	Namespace string
+	// Values are the numeric columns to expose; bools are 1 and 0, and
+	// null cells are skipped.
+	Values []string
+	// Labels are the key columns whose values label each sample. Together
+	// they must identify a row.
+	Labels []string
+	// ConstLabels are added to every sample, such as {"job": "daily"}.
+	ConstLabels map[string]string
+	// Help gives HELP text by column; it defaults to the column name.
+	Help map[string]string
+}
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ToPrometheus writes the configured columns as gauges in the Prometheus
+// text exposition format, ready to serve on /metrics or push to a
+// Pushgateway. Metric and label names are sanitized to Prometheus' rules.
+func (df *DataFrame) ToPrometheus(w io.Writer, config PrometheusConfig) error {
+	if len(config.Values) == 0 {
+		return fmt.Errorf("no value columns given")
+	}
+	labelCols := make([]int, len(config.Labels))
+	for k, col := range config.Labels {
+		if labelCols[k] = df.columnIndex(col); labelCols[k] == -1 {
+			return fmt.Errorf("column '%s' not found", col)
+		}
+	}
+	constNames := make([]string, 0, len(config.ConstLabels))
+	for name := range config.ConstLabels {
+		constNames = append(constNames, name)
+	}
+	sort.Strings(constNames)
+
+	// label sets are rendered once, since they repeat for every metric
+	labelSets := make([]string, len(df.data))
+	seen := make(map[string]bool, len(df.data))
+	for i, row := range df.data {
+		pairs := make([]string, 0, len(labelCols)+len(constNames))
+		for k, j := range labelCols {
+			value := ""
+			if row[j] != nil {
+				value = fmt.Sprint(row[j])
+			}
+			pairs = append(pairs, prometheusName(config.Labels[k], false)+`="`+escapePrometheusLabel(value)+`"`)
+		}
+		for _, name := range constNames {
+			pairs = append(pairs, prometheusName(name, false)+`="`+escapePrometheusLabel(config.ConstLabels[name])+`"`)
+		}
+		if len(pairs) > 0 {
+			labelSets[i] = "{" + strings.Join(pairs, ",") + "}"
+		}
+		if seen[labelSets[i]] {
+			return fmt.Errorf("row %d repeats the labels %s: add label columns that identify each row", i, labelSets[i])
+		}
+		seen[labelSets[i]] = true
+	}
+
+	out := bufio.NewWriter(w)
+	for _, col := range config.Values {
+		j := df.columnIndex(col)
+		if j == -1 {
+			return fmt.Errorf("column '%s' not found", col)
+		}
+		name := prometheusName(col, true)
+		if config.Namespace != "" {
+			name = prometheusName(config.Namespace, true) + "_" + name
+		}
+		help := config.Help[col]
+		if help == "" {
+			help = col
+		}
+		help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+
+		for i, row := range df.data {
+			if row[j] == nil {
+				continue
+			}
+			value, ok := toFloat64(row[j])
+			if b, isBool := row[j].(bool); isBool && b {
+				value, ok = 1, true
+			} else if isBool {
+				value, ok = 0, true
+			}
+			if !ok {
+				return fmt.Errorf("column '%s' row %d: %T is not numeric", col, i, row[j])
+			}
+			fmt.Fprintf(out, "%s%s %s\n", name, labelSets[i], formatPrometheusValue(value))
+		}
+	}
+	return out.Flush()
+}
+
+// PushPrometheus replaces the metrics of job on a Prometheus Pushgateway
+// (such as http://pushgateway:9091) with the frame's, for batch jobs that
+// are not around to be scraped.
+func (df *DataFrame) PushPrometheus(gatewayURL, job string, config PrometheusConfig) error {
+	var body bytes.Buffer
+	if err := df.ToPrometheus(&body, config); err != nil {
+		return err
+	}
+	target := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	req, err := http.NewRequest(http.MethodPut, target, &body)
+	if err != nil {
+		return fmt.Errorf("invalid Pushgateway URL: %w", err)
+	}
+	req.Header.Set("Content-Type", prometheusContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("push to %s failed: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push to %s failed with status %d: %s", target, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// PrometheusHandler serves the frame returned by provider, fetched on every
+// scrape, as gauges.
+func PrometheusHandler(provider func() (*DataFrame, error), config PrometheusConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		df, err := provider()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var body bytes.Buffer
+		if err := df.ToPrometheus(&body, config); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", prometheusContentType)
+		w.Write(body.Bytes())
+	})
+}
+
+// prometheusName replaces characters not allowed in metric names (colons
+// included for labels) with underscores and avoids a leading digit.
+func prometheusName(name string, metric bool) string {
+	var b strings.Builder
+	for i, r := range name {
+		valid := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || (metric && r == ':') || (i > 0 && r >= '0' && r <= '9')
+		if !valid && i == 0 && r >= '0' && r <= '9' {
+			b.WriteByte('_')
+			b.WriteRune(r)
+			continue
+		}
+		if !valid {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func escapePrometheusLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatPrometheusValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
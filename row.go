@@ -0,0 +1,85 @@
+package gopandas
+
+import (
+	"iter"
+	"sort"
+)
+
+// Row is a read-only view of one row, passed to callbacks such as Assign.
+type Row struct {
+	values    []interface{}
+	positions map[string]int
+}
+
+func (df *DataFrame) rowPositions() map[string]int {
+	positions := make(map[string]int, len(df.columns))
+	for j, col := range df.columns {
+		positions[col] = j
+	}
+	return positions
+}
+
+// Get returns the value in column, or nil if the frame has no such column.
+func (r Row) Get(column string) interface{} {
+	if j, ok := r.positions[column]; ok {
+		return r.values[j]
+	}
+	return nil
+}
+
+// Float returns the value in column as a float64 when it is numeric.
+func (r Row) Float(column string) (float64, bool) {
+	return toFloat64(r.Get(column))
+}
+
+// Rows iterates over the rows with their positions:
+//
+//	for i, row := range df.Rows() {
+//		fmt.Println(i, row.Get("name"))
+//	}
+//
+// A Row shares storage with the frame, so it reflects later changes to it.
+func (df *DataFrame) Rows() iter.Seq2[int, Row] {
+	return func(yield func(int, Row) bool) {
+		positions := df.rowPositions()
+		for i, values := range df.data {
+			if !yield(i, Row{values: values, positions: positions}) {
+				return
+			}
+		}
+	}
+}
+
+// Assign computes several derived columns in a single pass over the rows.
+// Each function sees the original row, so derived columns cannot refer to
+// each other. Existing columns are replaced in place; new columns are
+// appended in name order.
+func (df *DataFrame) Assign(columns map[string]func(Row) interface{}) *DataFrame {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resultCols := append([]string{}, df.columns...)
+	targets := make([]int, len(names))
+	for k, name := range names {
+		targets[k] = df.columnIndex(name)
+		if targets[k] == -1 {
+			targets[k] = len(resultCols)
+			resultCols = append(resultCols, name)
+		}
+	}
+
+	result := df.derive(NewDataFrame(resultCols), "assign", map[string]interface{}{"columns": names})
+	for i, view := range df.Rows() {
+		newRow := make([]interface{}, len(resultCols))
+		copy(newRow, view.values)
+		for k, name := range names {
+			newRow[targets[k]] = columns[name](view)
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result
+}
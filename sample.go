@@ -0,0 +1,97 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// StratifiedSample samples every group of byCol separately, so rare
+// classes keep their share of the result. size is either a fraction of
+// each group (a float64 in (0, 1]) or a number of rows per group (an int,
+// capped at the group's size). Rows are drawn without replacement with a
+// generator seeded by seed and keep their original order.
+func (df *DataFrame) StratifiedSample(byCol string, size interface{}, seed int64) (*DataFrame, error) {
+	var take func(n int) int
+	switch v := size.(type) {
+	case int:
+		if v < 0 {
+			return nil, fmt.Errorf("rows per group must not be negative, got %d", v)
+		}
+		take = func(n int) int { return min(v, n) }
+	case float64:
+		if !(v > 0 && v <= 1) {
+			return nil, fmt.Errorf("fraction must be in (0, 1], got %v", v)
+		}
+		take = func(n int) int { return int(math.Round(v * float64(n))) }
+	default:
+		return nil, fmt.Errorf("size must be an int count or a float64 fraction, got %T", size)
+	}
+
+	g, err := df.GroupByColumns(byCol)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	positions := make([]int, 0)
+	for _, rows := range g.rows {
+		shuffled := append([]int{}, rows...)
+		rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		positions = append(positions, shuffled[:take(len(rows))]...)
+	}
+	sort.Ints(positions)
+	return df.takeRows(positions, "stratified_sample", map[string]interface{}{"by": byCol, "size": size, "seed": seed}), nil
+}
+
+// Balance evens out the classes in byCol for training: "undersample" draws
+// every class down to the size of the smallest without replacement, and
+// "oversample" tops every class up to the size of the largest by repeating
+// random rows of it. Repeated rows keep their index labels, and rows stay
+// in their original order.
+func (df *DataFrame) Balance(byCol, strategy string, seed int64) (*DataFrame, error) {
+	if strategy != "undersample" && strategy != "oversample" {
+		return nil, fmt.Errorf("unknown balance strategy '%s': use undersample or oversample", strategy)
+	}
+	g, err := df.GroupByColumns(byCol)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.rows) == 0 {
+		return df.takeRows(nil, "balance", map[string]interface{}{"by": byCol, "strategy": strategy}), nil
+	}
+
+	target := len(g.rows[0])
+	for _, rows := range g.rows {
+		if strategy == "undersample" {
+			target = min(target, len(rows))
+		} else {
+			target = max(target, len(rows))
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	positions := make([]int, 0, target*len(g.rows))
+	for _, rows := range g.rows {
+		if strategy == "undersample" {
+			shuffled := append([]int{}, rows...)
+			rng.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+			positions = append(positions, shuffled[:target]...)
+			continue
+		}
+		positions = append(positions, rows...)
+		for n := len(rows); n < target; n++ {
+			positions = append(positions, rows[rng.Intn(len(rows))])
+		}
+	}
+	sort.Ints(positions)
+	result := df.takeRows(positions, "balance", map[string]interface{}{"by": byCol, "strategy": strategy, "seed": seed})
+	// a row drawn more than once gets its own copy each extra time, so
+	// writing to one draw does not change the others
+	for k := 1; k < len(positions); k++ {
+		if positions[k] == positions[k-1] {
+			result.data[k] = append([]interface{}{}, result.data[k]...)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,264 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnSchema describes one target column for Conform. Type is one of
+// "int", "float", "string", "bool", "time", "duration", "decimal", "ip" or
+// empty to keep values as they are.
+type ColumnSchema struct {
+	Name    string
+	Type    string
+	Default interface{}
+}
+
+// ConformPolicy controls how Conform treats columns outside the schema and
+// values that cannot be cast.
+type ConformPolicy struct {
+	Rename    map[string]string
+	DropExtra bool
+	Strict    bool
+}
+
+// ConformReport lists the changes Conform made. Failed counts the values per
+// column that could not be cast and were set to nil.
+type ConformReport struct {
+	Added   []string
+	Dropped []string
+	Renamed map[string]string
+	Cast    []string
+	Failed  map[string]int
+}
+
+var schemaTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Conform reshapes df to a target schema: columns are renamed per the
+// policy, missing columns are added with their defaults, values are cast to
+// the schema types and, with DropExtra, other columns are removed. Schema
+// columns come first in schema order, followed by any kept extras.
+func Conform(df *DataFrame, target []ColumnSchema, policy ConformPolicy) (*DataFrame, *ConformReport, error) {
+	report := &ConformReport{
+		Added:   make([]string, 0),
+		Dropped: make([]string, 0),
+		Renamed: make(map[string]string),
+		Cast:    make([]string, 0),
+		Failed:  make(map[string]int),
+	}
+
+	names := make([]string, len(df.columns))
+	sourceIdx := make(map[string]int)
+	for j, col := range df.columns {
+		names[j] = col
+		if to, ok := policy.Rename[col]; ok && to != col {
+			names[j] = to
+			report.Renamed[col] = to
+		}
+		if _, dup := sourceIdx[names[j]]; dup {
+			return nil, nil, fmt.Errorf("duplicate column '%s' after renaming", names[j])
+		}
+		sourceIdx[names[j]] = j
+	}
+
+	inSchema := make(map[string]bool)
+	columns := make([]string, 0, len(target))
+	for _, col := range target {
+		if inSchema[col.Name] {
+			return nil, nil, fmt.Errorf("duplicate column '%s' in schema", col.Name)
+		}
+		if !validSchemaType(col.Type) {
+			return nil, nil, fmt.Errorf("unsupported type '%s' for column '%s'", col.Type, col.Name)
+		}
+		inSchema[col.Name] = true
+		columns = append(columns, col.Name)
+		if _, ok := sourceIdx[col.Name]; !ok {
+			report.Added = append(report.Added, col.Name)
+		}
+	}
+
+	extras := make([]int, 0)
+	for j, name := range names {
+		if inSchema[name] {
+			continue
+		}
+		if policy.DropExtra {
+			report.Dropped = append(report.Dropped, name)
+			continue
+		}
+		extras = append(extras, j)
+		columns = append(columns, name)
+	}
+
+	result := df.derive(NewDataFrame(columns), "conform", map[string]interface{}{"columns": len(columns)})
+	for i, row := range df.data {
+		newRow := make([]interface{}, 0, len(columns))
+		for _, col := range target {
+			j, ok := sourceIdx[col.Name]
+			if !ok {
+				newRow = append(newRow, col.Default)
+				continue
+			}
+
+			val, ok := castValue(row[j], col.Type)
+			if !ok {
+				if policy.Strict {
+					return nil, nil, fmt.Errorf("row %d: cannot cast '%v' in column '%s' to %s", i, row[j], col.Name, col.Type)
+				}
+				report.Failed[col.Name]++
+				val = nil
+			}
+			if val == nil {
+				val = col.Default
+			}
+			newRow = append(newRow, val)
+		}
+		for _, j := range extras {
+			newRow = append(newRow, row[j])
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+
+	for _, col := range target {
+		j, ok := sourceIdx[col.Name]
+		if !ok || col.Type == "" {
+			continue
+		}
+		for _, row := range df.data {
+			if row[j] != nil && valueType(row[j]) != col.Type {
+				report.Cast = append(report.Cast, col.Name)
+				break
+			}
+		}
+	}
+
+	return result, report, nil
+}
+
+func validSchemaType(t string) bool {
+	switch t {
+	case "", "int", "float", "string", "bool", "time", "duration", "decimal", "ip":
+		return true
+	}
+	return false
+}
+
+func valueType(val interface{}) string {
+	switch val.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case time.Time:
+		return "time"
+	case time.Duration:
+		return "duration"
+	case Decimal:
+		return "decimal"
+	case netip.Addr:
+		return "ip"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// castValue converts val to a schema type, reporting false when it cannot.
+func castValue(val interface{}, typ string) (interface{}, bool) {
+	if val == nil || typ == "" {
+		return val, true
+	}
+
+	switch typ {
+	case "int":
+		switch v := val.(type) {
+		case bool:
+			if v {
+				return 1, true
+			}
+			return 0, true
+		case string:
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n, true
+			}
+		}
+		// NaN fails the Trunc check; infinities and floats beyond the int
+		// range fail the bounds check
+		f, ok := castFloat(val)
+		if !ok || f != math.Trunc(f) || f < float64(math.MinInt) || f >= -float64(math.MinInt) {
+			return nil, false
+		}
+		return int(f), true
+	case "float":
+		return castFloatValue(val)
+	case "string":
+		if t, ok := val.(time.Time); ok {
+			return t.Format(time.RFC3339), true
+		}
+		return fmt.Sprintf("%v", val), true
+	case "bool":
+		switch v := val.(type) {
+		case bool:
+			return v, true
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			return b, err == nil
+		}
+		f, ok := toFloat64(val)
+		return f != 0, ok
+	case "time":
+		switch v := val.(type) {
+		case time.Time:
+			return v, true
+		case string:
+			for _, layout := range schemaTimeLayouts {
+				if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+					return t, true
+				}
+			}
+		}
+		return nil, false
+	case "duration":
+		switch v := val.(type) {
+		case time.Duration:
+			return v, true
+		case string:
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			return d, err == nil
+		}
+		return nil, false
+	case "decimal":
+		d, err := toDecimal(val)
+		return d, err == nil
+	case "ip":
+		addr, err := toIPAddr(val)
+		return addr, err == nil
+	}
+	return nil, false
+}
+
+func castFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	case Decimal:
+		return v.Float64(), true
+	}
+	return toFloat64(val)
+}
+
+func castFloatValue(val interface{}) (interface{}, bool) {
+	f, ok := castFloat(val)
+	if !ok {
+		return nil, false
+	}
+	return f, true
+}
@@ -0,0 +1,155 @@
+package gopandas
+
+import (
+	"fmt"
+)
+
+type Decomposition struct {
+	Observed *Series
+	Trend    *Series
+	Seasonal *Series
+	Residual *Series
+}
+
+func (s *Series) floatValues() ([]float64, error) {
+	values := make([]float64, len(s.data))
+	for i, val := range s.data {
+		f, ok := toFloat64(val)
+		if !ok {
+			return nil, fmt.Errorf("row %d: non-numeric or missing value '%v'", i, val)
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+func (s *Series) ACF(lags int) ([]float64, error) {
+	values, err := s.floatValues()
+	if err != nil {
+		return nil, err
+	}
+	if lags < 0 || lags >= len(values) {
+		return nil, fmt.Errorf("lags must be between 0 and %d", len(values)-1)
+	}
+
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	if variance == 0 {
+		return nil, fmt.Errorf("series has zero variance")
+	}
+
+	acf := make([]float64, lags+1)
+	for k := 0; k <= lags; k++ {
+		var cov float64
+		for t := k; t < len(values); t++ {
+			cov += (values[t] - mean) * (values[t-k] - mean)
+		}
+		acf[k] = cov / variance
+	}
+	return acf, nil
+}
+
+func (s *Series) PACF(lags int) ([]float64, error) {
+	acf, err := s.ACF(lags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Durbin-Levinson recursion
+	pacf := make([]float64, lags+1)
+	pacf[0] = 1
+	phi := make([]float64, lags+1)
+	prev := make([]float64, lags+1)
+
+	for k := 1; k <= lags; k++ {
+		num := acf[k]
+		den := 1.0
+		for j := 1; j < k; j++ {
+			num -= prev[j] * acf[k-j]
+			den -= prev[j] * acf[j]
+		}
+		if den == 0 {
+			return nil, fmt.Errorf("PACF is undefined at lag %d", k)
+		}
+		phi[k] = num / den
+		for j := 1; j < k; j++ {
+			phi[j] = prev[j] - phi[k]*prev[k-j]
+		}
+		pacf[k] = phi[k]
+		copy(prev, phi)
+	}
+	return pacf, nil
+}
+
+func (s *Series) Decompose(period int) (*Decomposition, error) {
+	values, err := s.floatValues()
+	if err != nil {
+		return nil, err
+	}
+	if period < 2 || len(values) < 2*period {
+		return nil, fmt.Errorf("need at least two full periods of %d observations", period)
+	}
+
+	n := len(values)
+	trend := make([]interface{}, n)
+	half := period / 2
+	for t := half; t < n-half; t++ {
+		var sum float64
+		if period%2 == 0 {
+			// centred 2xm moving average for even periods
+			sum = values[t-half]/2 + values[t+half]/2
+			for j := t - half + 1; j < t+half; j++ {
+				sum += values[j]
+			}
+		} else {
+			for j := t - half; j <= t+half; j++ {
+				sum += values[j]
+			}
+		}
+		trend[t] = sum / float64(period)
+	}
+
+	seasonalSums := make([]float64, period)
+	seasonalCounts := make([]int, period)
+	for t, tr := range trend {
+		if tr == nil {
+			continue
+		}
+		seasonalSums[t%period] += values[t] - tr.(float64)
+		seasonalCounts[t%period]++
+	}
+
+	seasonalMeans := make([]float64, period)
+	var total float64
+	for i := range seasonalMeans {
+		if seasonalCounts[i] > 0 {
+			seasonalMeans[i] = seasonalSums[i] / float64(seasonalCounts[i])
+		}
+		total += seasonalMeans[i]
+	}
+	adjust := total / float64(period)
+
+	seasonal := make([]interface{}, n)
+	residual := make([]interface{}, n)
+	for t := range values {
+		seasonal[t] = seasonalMeans[t%period] - adjust
+		if trend[t] != nil {
+			residual[t] = values[t] - trend[t].(float64) - seasonal[t].(float64)
+		}
+	}
+
+	return &Decomposition{
+		Observed: s,
+		Trend:    s.withData(s.name+"_trend", trend),
+		Seasonal: s.withData(s.name+"_seasonal", seasonal),
+		Residual: s.withData(s.name+"_residual", residual),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package gopandas
+
+import "iter"
+
+func (s *Series) Name() string {
+	return s.name
+}
+
+func (s *Series) Len() int {
+	return len(s.data)
+}
+
+func (s *Series) Data() []interface{} {
+	return s.data
+}
+
+// Values iterates over the series with each value's position.
+func (s *Series) Values() iter.Seq2[int, interface{}] {
+	return func(yield func(int, interface{}) bool) {
+		for i, val := range s.data {
+			if !yield(i, val) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Series) Index() []interface{} {
+	return s.index
+}
+
+func (s *Series) withData(name string, data []interface{}) *Series {
+	result := NewSeries(name, data)
+	if len(s.index) == len(data) {
+		result.index = append([]interface{}{}, s.index...)
+	}
+	return result
+}
+
+func toFloat64(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
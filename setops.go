@@ -0,0 +1,149 @@
+package gopandas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnionAll stacks the rows of other under the frame's, keeping duplicates
+// like SQL's UNION ALL. Both frames must have the same columns, in any
+// order; other's are lined up by name.
+func (df *DataFrame) UnionAll(other *DataFrame) (*DataFrame, error) {
+	aligned, err := df.alignRows(other)
+	if err != nil {
+		return nil, err
+	}
+	result := combine(NewDataFrame(append([]string{}, df.columns...)), "union_all", nil, df, other)
+	for i, row := range df.data {
+		result.data = append(result.data, append([]interface{}{}, row...))
+		result.index = append(result.index, df.index[i])
+	}
+	for i, row := range aligned {
+		result.data = append(result.data, row)
+		result.index = append(result.index, other.index[i])
+	}
+	return result, nil
+}
+
+// Union returns the distinct rows of both frames, like SQL's UNION: the
+// frame's rows first, then other's, keeping the first of each duplicate.
+// Rows are equal when all their cells are, or only the cells of keys when
+// given; numbers compare by value, so 3 and 3.0 match.
+func (df *DataFrame) Union(other *DataFrame, keys ...string) (*DataFrame, error) {
+	aligned, err := df.alignRows(other)
+	if err != nil {
+		return nil, err
+	}
+	rowKey, err := df.rowKeyFunc(keys)
+	if err != nil {
+		return nil, err
+	}
+	result := NewDataFrame(append([]string{}, df.columns...))
+	seen := make(map[string]bool)
+	add := func(row []interface{}, label interface{}) {
+		if key := rowKey(row); !seen[key] {
+			seen[key] = true
+			result.data = append(result.data, row)
+			result.index = append(result.index, label)
+		}
+	}
+	for i, row := range df.data {
+		add(append([]interface{}{}, row...), df.index[i])
+	}
+	for i, row := range aligned {
+		add(row, other.index[i])
+	}
+	return combine(result, "union", map[string]interface{}{"keys": keys}, df, other), nil
+}
+
+// Intersect returns the distinct rows of the frame that also appear in
+// other, like SQL's INTERSECT, comparing rows as Union does.
+func (df *DataFrame) Intersect(other *DataFrame, keys ...string) (*DataFrame, error) {
+	return df.filterByOther(other, keys, true, "intersect")
+}
+
+// Except returns the distinct rows of the frame that do not appear in
+// other, like SQL's EXCEPT, comparing rows as Union does.
+func (df *DataFrame) Except(other *DataFrame, keys ...string) (*DataFrame, error) {
+	return df.filterByOther(other, keys, false, "except")
+}
+
+func (df *DataFrame) filterByOther(other *DataFrame, keys []string, keep bool, op string) (*DataFrame, error) {
+	aligned, err := df.alignRows(other)
+	if err != nil {
+		return nil, err
+	}
+	rowKey, err := df.rowKeyFunc(keys)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(aligned))
+	for _, row := range aligned {
+		present[rowKey(row)] = true
+	}
+
+	seen := make(map[string]bool)
+	positions := make([]int, 0)
+	for i, row := range df.data {
+		key := rowKey(row)
+		if present[key] == keep && !seen[key] {
+			seen[key] = true
+			positions = append(positions, i)
+		}
+	}
+	result := NewDataFrame(append([]string{}, df.columns...))
+	for _, i := range positions {
+		result.data = append(result.data, append([]interface{}{}, df.data[i]...))
+		result.index = append(result.index, df.index[i])
+	}
+	return combine(result, op, map[string]interface{}{"keys": keys}, df, other), nil
+}
+
+// alignRows copies other's rows with their cells in the frame's column
+// order, failing unless both frames have the same columns.
+func (df *DataFrame) alignRows(other *DataFrame) ([][]interface{}, error) {
+	if len(other.columns) != len(df.columns) {
+		return nil, fmt.Errorf("column mismatch: %v vs %v", df.columns, other.columns)
+	}
+	positions := make([]int, len(df.columns))
+	for j, col := range df.columns {
+		positions[j] = other.columnIndex(col)
+		if positions[j] == -1 {
+			return nil, fmt.Errorf("column '%s' not found in other frame", col)
+		}
+	}
+	rows := make([][]interface{}, len(other.data))
+	for i, row := range other.data {
+		rows[i] = make([]interface{}, len(positions))
+		for j, k := range positions {
+			rows[i][j] = row[k]
+		}
+	}
+	return rows, nil
+}
+
+// rowKeyFunc returns a function hashing a row (in the frame's column order)
+// by the given key columns, or by every column when none are given.
+func (df *DataFrame) rowKeyFunc(keys []string) (func([]interface{}) string, error) {
+	cols := make([]int, 0, len(keys))
+	for _, key := range keys {
+		j := df.columnIndex(key)
+		if j == -1 {
+			return nil, fmt.Errorf("column '%s' not found", key)
+		}
+		cols = append(cols, j)
+	}
+	if len(keys) == 0 {
+		for j := range df.columns {
+			cols = append(cols, j)
+		}
+	}
+	return func(row []interface{}) string {
+		var b strings.Builder
+		for _, j := range cols {
+			b.WriteString(indexKey(row[j]))
+			b.WriteByte(0)
+		}
+		return b.String()
+	}, nil
+}
@@ -0,0 +1,83 @@
+package gopandas
+
+import "fmt"
+
+// Share returns, for every row of the frame, its value in column divided by
+// the total of column within the row's group, aligned with the frame's
+// index. Rows with a null or non-numeric value, or in a group that totals
+// zero, get nil.
+func (g *Grouped) Share(column string) (*Series, error) {
+	j := g.df.columnIndex(column)
+	if j == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	series := NewSeries(column+"_share", shareValues(g.df, j, g.rows))
+	series.index = append([]interface{}{}, g.df.index...)
+	return series, nil
+}
+
+// PercentOfTotal adds a "<valueCol>_pct" column holding each row's value as
+// a percentage (0-100) of the total of valueCol within its partition, or of
+// the whole column when no partition columns are given. Rows that Share
+// would give nil stay nil.
+func (df *DataFrame) PercentOfTotal(valueCol string, partitionCols ...string) (*DataFrame, error) {
+	j := df.columnIndex(valueCol)
+	if j == -1 {
+		return nil, fmt.Errorf("column '%s' not found", valueCol)
+	}
+	groups := [][]int{make([]int, len(df.data))}
+	for i := range groups[0] {
+		groups[0][i] = i
+	}
+	if len(partitionCols) > 0 {
+		g, err := df.GroupByColumns(partitionCols...)
+		if err != nil {
+			return nil, err
+		}
+		groups = g.rows
+	}
+
+	name := valueCol + "_pct"
+	resultCols := append([]string{}, df.columns...)
+	target := df.columnIndex(name)
+	if target == -1 {
+		target = len(resultCols)
+		resultCols = append(resultCols, name)
+	}
+
+	shares := shareValues(df, j, groups)
+	result := df.derive(NewDataFrame(resultCols), "percent_of_total", map[string]interface{}{"column": valueCol, "by": partitionCols})
+	for i, row := range df.data {
+		newRow := make([]interface{}, len(resultCols))
+		copy(newRow, row)
+		newRow[target] = nil
+		if f, ok := shares[i].(float64); ok {
+			newRow[target] = f * 100
+		}
+		result.data = append(result.data, newRow)
+		result.index = append(result.index, df.index[i])
+	}
+	return result, nil
+}
+
+// shareValues divides each row's value in column j by its group's total.
+func shareValues(df *DataFrame, j int, groups [][]int) []interface{} {
+	shares := make([]interface{}, len(df.data))
+	for _, rows := range groups {
+		var total float64
+		for _, i := range rows {
+			if f, ok := toFloat64(df.data[i][j]); ok {
+				total += f
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		for _, i := range rows {
+			if f, ok := toFloat64(df.data[i][j]); ok {
+				shares[i] = f / total
+			}
+		}
+	}
+	return shares
+}
@@ -0,0 +1,326 @@
+package gopandas
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sheetsScope    = "https://www.googleapis.com/auth/spreadsheets"
+	googleTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// SheetsClient reads and writes frames through the Google Sheets API. It
+// authenticates with a fixed OAuth access token or a service account key.
+type SheetsClient struct {
+	Token    string
+	Endpoint string
+	Client   *http.Client
+
+	account *serviceAccountKey
+	mu      sync.Mutex
+	expiry  time.Time
+}
+
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+	key         *rsa.PrivateKey
+}
+
+func NewSheetsClient(token string) *SheetsClient {
+	return &SheetsClient{Token: token}
+}
+
+// NewSheetsClientFromEnv uses GOOGLE_OAUTH_ACCESS_TOKEN, or the service
+// account key file named by GOOGLE_APPLICATION_CREDENTIALS.
+func NewSheetsClientFromEnv() (*SheetsClient, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return NewSheetsClient(token), nil
+	}
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		return nil, fmt.Errorf("no Google credentials: set GOOGLE_OAUTH_ACCESS_TOKEN or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	return NewSheetsClientFromServiceAccount(data)
+}
+
+// NewSheetsClientFromServiceAccount authenticates with a service account
+// JSON key; the spreadsheet must be shared with the account's email.
+func NewSheetsClientFromServiceAccount(keyJSON []byte) (*SheetsClient, error) {
+	var account serviceAccountKey
+	if err := json.Unmarshal(keyJSON, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("invalid service account key: missing client_email or private_key")
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid service account key: private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("invalid service account key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid service account key: private_key is not RSA")
+	}
+	account.key = key
+	if account.TokenURI == "" {
+		account.TokenURI = googleTokenURL
+	}
+	return &SheetsClient{account: &account}, nil
+}
+
+func (c *SheetsClient) endpoint() string {
+	if c.Endpoint != "" {
+		return strings.TrimRight(c.Endpoint, "/")
+	}
+	return "https://sheets.googleapis.com"
+}
+
+func (c *SheetsClient) httpClient() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// ReadSheet reads an A1 range such as "Sheet1" or "Data!A1:D100"; the first
+// row is the header.
+func (c *SheetsClient) ReadSheet(spreadsheetID, readRange string) (*DataFrame, error) {
+	var payload struct {
+		Values [][]interface{} `json:"values"`
+	}
+	query := url.Values{"valueRenderOption": {"UNFORMATTED_VALUE"}}
+	if err := c.call(http.MethodGet, c.valuesURL(spreadsheetID, readRange, "", query), nil, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload.Values) == 0 {
+		return NewDataFrame([]string{}), nil
+	}
+
+	columns := make([]string, len(payload.Values[0]))
+	for j, val := range payload.Values[0] {
+		columns[j] = fmt.Sprint(val)
+	}
+	df := NewDataFrame(columns)
+	for _, values := range payload.Values[1:] {
+		row := make([]interface{}, len(columns))
+		for j := 0; j < len(row) && j < len(values); j++ {
+			row[j] = sheetsValue(values[j])
+		}
+		df.AddRow(row)
+	}
+	df.record("read_sheets", map[string]interface{}{"spreadsheet": spreadsheetID, "range": readRange})
+	return df, nil
+}
+
+// WriteSheet writes the frame, header first, from the top-left cell of
+// writeRange, blanking whatever the range held beyond the frame in the
+// same request, so the old data stays in place if the write fails. Values
+// are stored as entered, so text is never parsed as a formula.
+func (c *SheetsClient) WriteSheet(spreadsheetID, writeRange string, df *DataFrame) error {
+	var current struct {
+		Values [][]interface{} `json:"values"`
+	}
+	if err := c.call(http.MethodGet, c.valuesURL(spreadsheetID, writeRange, "", nil), nil, &current); err != nil {
+		return err
+	}
+
+	values := make([][]interface{}, 0, len(df.data)+1)
+	header := make([]interface{}, len(df.columns))
+	for j, col := range df.columns {
+		header[j] = col
+	}
+	values = append(values, header)
+	for _, row := range df.data {
+		cells := make([]interface{}, len(row))
+		for j, val := range row {
+			cells[j] = sheetsCell(val)
+		}
+		values = append(values, cells)
+	}
+	// the API skips nulls, so cells the frame no longer covers are written
+	// as empty strings
+	for i, old := range current.Values {
+		if i == len(values) {
+			values = append(values, nil)
+		}
+		for len(values[i]) < len(old) {
+			values[i] = append(values[i], "")
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"range": writeRange, "majorDimension": "ROWS", "values": values})
+	if err != nil {
+		return err
+	}
+	query := url.Values{"valueInputOption": {"RAW"}}
+	return c.call(http.MethodPut, c.valuesURL(spreadsheetID, writeRange, "", query), body, nil)
+}
+
+func (c *SheetsClient) valuesURL(spreadsheetID, a1Range, action string, query url.Values) string {
+	u := fmt.Sprintf("%s/v4/spreadsheets/%s/values/%s%s", c.endpoint(), url.PathEscape(spreadsheetID), url.PathEscape(a1Range), action)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c *SheetsClient) call(method, u string, body []byte, out interface{}) error {
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("sheets request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sheets request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode sheets response: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns the fixed token, or exchanges a signed JWT for one
+// when the client was built from a service account.
+func (c *SheetsClient) accessToken() (string, error) {
+	if c.account == nil {
+		return c.Token, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Token != "" && time.Now().Before(c.expiry) {
+		return c.Token, nil
+	}
+
+	now := time.Now()
+	assertion, err := signJWT(c.account.key, map[string]interface{}{
+		"iss":   c.account.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   c.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	resp, err := c.httpClient().PostForm(c.account.TokenURI, form)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	c.Token = payload.AccessToken
+	// refresh a minute early so a token never expires mid-request
+	c.expiry = now.Add(time.Duration(payload.ExpiresIn)*time.Second - time.Minute)
+	return c.Token, nil
+}
+
+func signJWT(key *rsa.PrivateKey, claims map[string]interface{}) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token request: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// sheetsCell converts a value for the API: numbers and bools stay typed,
+// nulls and non-finite numbers become empty cells, the rest is text.
+func sheetsCell(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case bool, string:
+		return v
+	}
+	if f, ok := toFloat64(val); ok {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return ""
+		}
+		return val
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+func sheetsValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int(v)
+		}
+	}
+	return val
+}
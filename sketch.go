@@ -0,0 +1,256 @@
+package gopandas
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	defaultHLLError        = 0.01
+	defaultTDigestCompress = 100
+)
+
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < 4 || precision > 18 {
+		return nil, fmt.Errorf("precision must be between 4 and 18, got %d", precision)
+	}
+	return &HyperLogLog{precision: precision, registers: make([]uint8, 1<<precision)}, nil
+}
+
+func NewHyperLogLogWithError(relativeError float64) (*HyperLogLog, error) {
+	if relativeError <= 0 || relativeError >= 1 {
+		return nil, fmt.Errorf("relative error must be between 0 and 1, got %v", relativeError)
+	}
+	m := math.Pow(1.04/relativeError, 2)
+	precision := uint8(math.Ceil(math.Log2(m)))
+	if precision < 4 {
+		precision = 4
+	}
+	if precision > 18 {
+		precision = 18
+	}
+	return NewHyperLogLog(precision)
+}
+
+func (h *HyperLogLog) Add(val interface{}) {
+	hash := hashValue(val)
+	idx := hash >> (64 - h.precision)
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("cannot merge sketches with precision %d and %d", h.precision, other.precision)
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+func (h *HyperLogLog) Count() int {
+	m := float64(len(h.registers))
+	var sum float64
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Pow(2, -float64(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	}
+
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(estimate + 0.5)
+}
+
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.registers)))
+}
+
+func hashValue(val interface{}) uint64 {
+	hasher := fnv.New64a()
+	fmt.Fprintf(hasher, "%T:%v", val, val)
+	x := hasher.Sum64()
+
+	// splitmix64 finalizer spreads FNV output across all bits
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []float64
+	total       float64
+	min         float64
+	max         float64
+}
+
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = defaultTDigestCompress
+	}
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (td *TDigest) Add(x float64) {
+	td.buffer = append(td.buffer, x)
+	td.total++
+	if x < td.min {
+		td.min = x
+	}
+	if x > td.max {
+		td.max = x
+	}
+	if len(td.buffer) >= int(td.compression)*5 {
+		td.compress()
+	}
+}
+
+func (td *TDigest) Merge(other *TDigest) {
+	other.compress()
+	td.centroids = append(td.centroids, other.centroids...)
+	td.total += other.total
+	td.min = math.Min(td.min, other.min)
+	td.max = math.Max(td.max, other.max)
+	td.compress()
+}
+
+func (td *TDigest) Count() int {
+	return int(td.total)
+}
+
+func (td *TDigest) compress() {
+	if len(td.buffer) == 0 && len(td.centroids) <= 1 {
+		return
+	}
+
+	all := td.centroids
+	for _, x := range td.buffer {
+		all = append(all, centroid{mean: x, weight: 1})
+	}
+	td.buffer = td.buffer[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	scale := func(q float64) float64 {
+		return td.compression / (2 * math.Pi) * math.Asin(2*q-1)
+	}
+
+	merged := make([]centroid, 0, len(all))
+	current := all[0]
+	cumulative := 0.0
+	kLeft := scale(0)
+	for _, c := range all[1:] {
+		q := (cumulative + current.weight + c.weight) / td.total
+		if scale(q)-kLeft <= 1 {
+			current.mean += (c.mean - current.mean) * c.weight / (current.weight + c.weight)
+			current.weight += c.weight
+			continue
+		}
+		cumulative += current.weight
+		kLeft = scale(cumulative / td.total)
+		merged = append(merged, current)
+		current = c
+	}
+	td.centroids = append(merged, current)
+}
+
+func (td *TDigest) Quantile(q float64) (float64, error) {
+	if q < 0 || q > 1 {
+		return 0, fmt.Errorf("quantile %v out of range [0, 1]", q)
+	}
+	td.compress()
+	if len(td.centroids) == 0 {
+		return 0, fmt.Errorf("digest is empty")
+	}
+	if len(td.centroids) == 1 || q == 0 {
+		if q == 0 {
+			return td.min, nil
+		}
+		return td.centroids[0].mean, nil
+	}
+	if q == 1 {
+		return td.max, nil
+	}
+
+	target := q * td.total
+	cumulative := 0.0
+	for i, c := range td.centroids {
+		center := cumulative + c.weight/2
+		if target < center {
+			if i == 0 {
+				return td.min + (c.mean-td.min)*target/center, nil
+			}
+			prev := td.centroids[i-1]
+			prevCenter := cumulative - prev.weight/2
+			return prev.mean + (c.mean-prev.mean)*(target-prevCenter)/(center-prevCenter), nil
+		}
+		cumulative += c.weight
+	}
+
+	last := td.centroids[len(td.centroids)-1]
+	lastCenter := td.total - last.weight/2
+	return last.mean + (td.max-last.mean)*(target-lastCenter)/(td.total-lastCenter), nil
+}
+
+func (s *Series) ApproxNUnique(relativeError float64) (int, error) {
+	if relativeError == 0 {
+		relativeError = defaultHLLError
+	}
+	hll, err := NewHyperLogLogWithError(relativeError)
+	if err != nil {
+		return 0, err
+	}
+	for _, val := range s.data {
+		if val != nil {
+			hll.Add(val)
+		}
+	}
+	return hll.Count(), nil
+}
+
+func (s *Series) ApproxQuantile(q, compression float64) (float64, error) {
+	td := NewTDigest(compression)
+	for _, val := range s.data {
+		if f, ok := toFloat64(val); ok {
+			td.Add(f)
+		}
+	}
+	return td.Quantile(q)
+}
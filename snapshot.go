@@ -0,0 +1,136 @@
+package gopandas
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TestingT is the part of *testing.T that SnapshotTest uses.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// UpdateSnapshotsEnv names the environment variable that, when set to 1,
+// makes SnapshotTest write golden files, new or changed, instead of
+// comparing.
+const UpdateSnapshotsEnv = "GOPANDAS_UPDATE_SNAPSHOTS"
+
+const maxSnapshotDiffs = 20
+
+// snapshotAbsTolerance is the difference always allowed between numbers,
+// so values that should be zero can come out as rounding noise.
+const snapshotAbsTolerance = 1e-12
+
+// SnapshotTest compares df with a golden CSV file, allowing a relative
+// difference of 1e-9 between numbers. A missing golden file fails the test
+// unless UpdateSnapshotsEnv is set.
+func SnapshotTest(t TestingT, df *DataFrame, golden string) {
+	t.Helper()
+	SnapshotTestWithTolerance(t, df, golden, 1e-9)
+}
+
+func SnapshotTestWithTolerance(t TestingT, df *DataFrame, golden string, tolerance float64) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := df.writeCSV(&buf, &CSVConfig{HasHeader: true, Delimiter: ','}, nil); err != nil {
+		t.Errorf("snapshot %s: %v", golden, err)
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if os.Getenv(UpdateSnapshotsEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Errorf("snapshot %s: %v", golden, err)
+			return
+		}
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Errorf("snapshot %s: %v", golden, err)
+			return
+		}
+		t.Logf("snapshot %s written", golden)
+		return
+	}
+	if os.IsNotExist(err) {
+		t.Errorf("snapshot %s does not exist (set %s=1 to create it)", golden, UpdateSnapshotsEnv)
+		return
+	}
+	if err != nil {
+		t.Errorf("snapshot %s: %v", golden, err)
+		return
+	}
+
+	want, err := csv.NewReader(bytes.NewReader(expected)).ReadAll()
+	if err != nil {
+		t.Errorf("snapshot %s: invalid golden file: %v", golden, err)
+		return
+	}
+	got, _ := csv.NewReader(&buf).ReadAll()
+
+	if diffs := diffRecords(want, got, tolerance); len(diffs) > 0 {
+		t.Errorf("snapshot %s does not match (set %s=1 to update):\n%s", golden, UpdateSnapshotsEnv, strings.Join(diffs, "\n"))
+	}
+}
+
+// diffRecords lists the differences between two CSV tables, the first row
+// being the header.
+func diffRecords(want, got [][]string, tolerance float64) []string {
+	diffs := make([]string, 0)
+	if len(want) == 0 || len(got) == 0 {
+		if len(want) != len(got) {
+			diffs = append(diffs, fmt.Sprintf("  expected %d lines, got %d", len(want), len(got)))
+		}
+		return diffs
+	}
+
+	header := want[0]
+	if strings.Join(want[0], ",") != strings.Join(got[0], ",") {
+		diffs = append(diffs, fmt.Sprintf("  columns: expected %v, got %v", want[0], got[0]))
+		return diffs
+	}
+	if len(want) != len(got) {
+		diffs = append(diffs, fmt.Sprintf("  rows: expected %d, got %d", len(want)-1, len(got)-1))
+	}
+
+	for i := 1; i < len(want) && i < len(got); i++ {
+		for j := range header {
+			var w, g string
+			if j < len(want[i]) {
+				w = want[i][j]
+			}
+			if j < len(got[i]) {
+				g = got[i][j]
+			}
+			if cellsMatch(w, g, tolerance) {
+				continue
+			}
+			if len(diffs) == maxSnapshotDiffs {
+				diffs = append(diffs, "  ...")
+				return diffs
+			}
+			diffs = append(diffs, fmt.Sprintf("  row %d, column '%s': expected %q, got %q", i-1, header[j], w, g))
+		}
+	}
+	return diffs
+}
+
+func cellsMatch(want, got string, tolerance float64) bool {
+	if want == got {
+		return true
+	}
+	w, err1 := strconv.ParseFloat(want, 64)
+	g, err2 := strconv.ParseFloat(got, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	scale := math.Max(math.Abs(w), math.Abs(g))
+	return math.Abs(w-g) <= math.Max(tolerance*scale, snapshotAbsTolerance)
+}
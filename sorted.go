@@ -0,0 +1,120 @@
+package gopandas
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SetSorted records that the frame is sorted ascending by column, so
+// FilterRange can binary search and GroupByColumns can group runs instead of
+// hashing every key. The order is checked once here; mutations that could
+// break it (AddRow out of order, SetColumn on the column) clear the flag.
+func (df *DataFrame) SetSorted(column string) error {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return fmt.Errorf("column '%s' not found", column)
+	}
+	for i := 1; i < len(df.data); i++ {
+		if compareExprValues(df.data[i-1][colIndex], df.data[i][colIndex]) > 0 {
+			return fmt.Errorf("column '%s' is not sorted at row %d", column, i)
+		}
+	}
+	df.sortedBy = column
+	return nil
+}
+
+// SortedBy returns the column the frame is known to be sorted by, or "".
+func (df *DataFrame) SortedBy() string {
+	return df.sortedBy
+}
+
+// detectSorted sets the sorted flag when column turns out to be ascending,
+// as after an ascending Sort.
+func (df *DataFrame) detectSorted(column string) {
+	if df.SetSorted(column) != nil {
+		df.sortedBy = ""
+	}
+}
+
+// keepSorted is called before a row is appended and clears the sorted flag
+// unless the row keeps the order.
+func (df *DataFrame) keepSorted(row []interface{}) {
+	if df.sortedBy == "" || len(df.data) == 0 {
+		return
+	}
+	colIndex := df.columnIndex(df.sortedBy)
+	if colIndex == -1 || compareExprValues(df.data[len(df.data)-1][colIndex], row[colIndex]) > 0 {
+		df.sortedBy = ""
+	}
+}
+
+// FilterRange keeps the rows whose value in column lies between lo and hi,
+// inclusive. A nil bound is open, and nulls are never kept. On a frame marked
+// sorted by column the bounds are found by binary search.
+func (df *DataFrame) FilterRange(column string, lo, hi interface{}) (*DataFrame, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+
+	result := NewDataFrame(df.columns)
+	if df.sortedBy == column {
+		// nulls sort first, so the first non-null row starts the range
+		start := sort.Search(len(df.data), func(i int) bool {
+			val := df.data[i][colIndex]
+			return val != nil && (lo == nil || compareExprValues(val, lo) >= 0)
+		})
+		end := len(df.data)
+		if hi != nil {
+			end = sort.Search(len(df.data), func(i int) bool {
+				val := df.data[i][colIndex]
+				return val != nil && compareExprValues(val, hi) > 0
+			})
+		}
+		if start < end {
+			result.data = df.data[start:end:end]
+			result.index = df.index[start:end:end]
+		}
+		result.sortedBy = column
+	} else {
+		for i, row := range df.data {
+			val := row[colIndex]
+			if val == nil || lo != nil && compareExprValues(val, lo) < 0 || hi != nil && compareExprValues(val, hi) > 0 {
+				continue
+			}
+			result.data = append(result.data, row)
+			result.index = append(result.index, df.index[i])
+		}
+	}
+
+	return df.derive(result, "filter_range", map[string]interface{}{"column": column, "lo": lo, "hi": hi, "rows_in": len(df.data), "rows_out": len(result.data)}), nil
+}
+
+// groupSortedRuns fills g from consecutive runs of equal keys, which on a
+// sorted column are exactly the groups.
+func (g *Grouped) groupSortedRuns() {
+	colIndex := g.byCols[0]
+	for i, row := range g.df.data {
+		last := len(g.keys) - 1
+		if last >= 0 && sameValue(g.keys[last][0], row[colIndex]) {
+			g.rows[last] = append(g.rows[last], i)
+			continue
+		}
+		g.keys = append(g.keys, []interface{}{row[colIndex]})
+		g.rows = append(g.rows, []int{i})
+	}
+}
+
+func sameValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if reflect.TypeOf(a).Comparable() {
+		return a == b
+	}
+	return groupKeyString([]interface{}{a}) == groupKeyString([]interface{}{b})
+}
@@ -0,0 +1,158 @@
+package gopandas
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowSource adapts warehouse client iterators (BigQuery, Snowflake, ...)
+// that are not exposed through database/sql. Next returns io.EOF when done.
+type RowSource interface {
+	Columns() []string
+	Next() ([]interface{}, error)
+}
+
+var sqlTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func FromSQLRows(rows *sql.Rows) (*DataFrame, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column types: %w", err)
+	}
+
+	columns := make([]string, len(columnTypes))
+	typeNames := make([]string, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = ct.Name()
+		typeNames[i] = strings.ToUpper(ct.DatabaseTypeName())
+	}
+
+	df := NewDataFrame(columns)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make([]interface{}, len(columns))
+		for i, val := range values {
+			converted, err := convertSQLValue(val, typeNames[i])
+			if err != nil {
+				return nil, fmt.Errorf("column '%s': %w", columns[i], err)
+			}
+			row[i] = converted
+		}
+		df.AddRow(row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	df.record("read_sql", nil)
+	return df, nil
+}
+
+func FromRowSource(source RowSource) (*DataFrame, error) {
+	df := NewDataFrame(source.Columns())
+	for {
+		values, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return df, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+
+		row := make([]interface{}, len(values))
+		for i, val := range values {
+			converted, err := convertSQLValue(val, "")
+			if err != nil {
+				return nil, err
+			}
+			row[i] = converted
+		}
+		if err := df.AddRow(row); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func convertSQLValue(val interface{}, typeName string) (interface{}, error) {
+	if b, ok := val.([]byte); ok {
+		val = string(b)
+	}
+
+	switch v := val.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return int(v), nil
+	case int32:
+		return int(v), nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return convertSQLString(v, typeName)
+	}
+	return val, nil
+}
+
+// sqlIntegerTypes are the integer type names drivers report, so INTERVAL
+// and POINT, which merely contain "INT", are left alone.
+var sqlIntegerTypes = map[string]bool{
+	"INT": true, "INTEGER": true, "BIGINT": true, "SMALLINT": true, "TINYINT": true, "MEDIUMINT": true,
+	"INT2": true, "INT4": true, "INT8": true, "SERIAL": true, "BIGSERIAL": true, "SMALLSERIAL": true,
+}
+
+func isSQLIntegerType(typeName string) bool {
+	return sqlIntegerTypes[strings.TrimPrefix(typeName, "UNSIGNED ")]
+}
+
+func convertSQLString(value, typeName string) (interface{}, error) {
+	switch {
+	case strings.Contains(typeName, "NUMERIC"), strings.Contains(typeName, "DECIMAL"), typeName == "NUMBER", typeName == "MONEY":
+		return ParseDecimal(value)
+	case isSQLIntegerType(typeName):
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer '%s'", value)
+		}
+		return n, nil
+	case strings.Contains(typeName, "FLOAT"), strings.Contains(typeName, "DOUBLE"), typeName == "REAL":
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float '%s'", value)
+		}
+		return f, nil
+	case strings.Contains(typeName, "BOOL"):
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean '%s'", value)
+		}
+		return b, nil
+	case strings.Contains(typeName, "TIMESTAMP"), strings.Contains(typeName, "DATE"), strings.Contains(typeName, "TIME"):
+		for _, layout := range sqlTimeLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid timestamp '%s'", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,223 @@
+package gopandas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FrameStore keeps numbered versions of a dataset in a directory, so it can
+// be committed next to the code that produces it. Each version is stored
+// in the native binary format (MarshalBinary) under its content hash, so
+// committing an unchanged frame takes no extra space, and log.json lists
+// the versions.
+type FrameStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// FrameVersion describes one commit in a FrameStore.
+type FrameVersion struct {
+	Version int       `json:"version"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	Object  string    `json:"object"`
+	Rows    int       `json:"rows"`
+	Columns []string  `json:"columns"`
+}
+
+// FrameDiff is the difference between two versions. Rows are compared on
+// the columns both versions share, as multisets: a row repeated twice in
+// the old version and once in the new one is listed once in Removed.
+type FrameDiff struct {
+	AddedColumns   []string
+	RemovedColumns []string
+	Added          *DataFrame
+	Removed        *DataFrame
+}
+
+// OpenFrameStore opens the store in dir, creating the directory if needed.
+func OpenFrameStore(dir string) (*FrameStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "objects"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &FrameStore{dir: dir}, nil
+}
+
+// Commit stores df as a new version with message and returns its number,
+// starting at 1.
+func (s *FrameStore) Commit(df *DataFrame, message string) (int, error) {
+	data, err := df.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode frame: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	object := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.objectPath(object)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if err := writeFileAtomic(path, data); err != nil {
+			return 0, fmt.Errorf("failed to write frame: %w", err)
+		}
+	}
+
+	versions, err := s.readLog()
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := df.Shape()
+	version := FrameVersion{
+		Version: len(versions) + 1,
+		Message: message,
+		Time:    time.Now().UTC(),
+		Object:  object,
+		Rows:    rows,
+		Columns: append([]string{}, df.columns...),
+	}
+	logData, err := json.MarshalIndent(append(versions, version), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, "log.json"), logData); err != nil {
+		return 0, fmt.Errorf("failed to write store log: %w", err)
+	}
+	return version.Version, nil
+}
+
+// Log lists the versions, oldest first.
+func (s *FrameStore) Log() ([]FrameVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLog()
+}
+
+// Checkout loads a version; 0 or a negative number counts back from the
+// latest, so Checkout(0) is the latest version.
+func (s *FrameStore) Checkout(version int) (*DataFrame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, err := s.readLog()
+	if err != nil {
+		return nil, err
+	}
+	if version <= 0 {
+		version += len(versions)
+	}
+	if version < 1 || version > len(versions) {
+		return nil, fmt.Errorf("version %d not found (store has %d)", version, len(versions))
+	}
+
+	data, err := os.ReadFile(s.objectPath(versions[version-1].Object))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version %d: %w", version, err)
+	}
+	df := &DataFrame{}
+	if err := df.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("failed to decode version %d: %w", version, err)
+	}
+	df.record("checkout", map[string]interface{}{"store": s.dir, "version": version})
+	return df, nil
+}
+
+// Diff compares version v1 with version v2.
+func (s *FrameStore) Diff(v1, v2 int) (*FrameDiff, error) {
+	before, err := s.Checkout(v1)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.Checkout(v2)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &FrameDiff{AddedColumns: make([]string, 0), RemovedColumns: make([]string, 0)}
+	common := make([]string, 0)
+	for _, col := range after.columns {
+		if before.columnIndex(col) == -1 {
+			diff.AddedColumns = append(diff.AddedColumns, col)
+		} else {
+			common = append(common, col)
+		}
+	}
+	for _, col := range before.columns {
+		if after.columnIndex(col) == -1 {
+			diff.RemovedColumns = append(diff.RemovedColumns, col)
+		}
+	}
+
+	before, _ = before.Select(common...)
+	after, _ = after.Select(common...)
+	diff.Added = rowsNotIn(after, before)
+	diff.Removed = rowsNotIn(before, after)
+	return diff, nil
+}
+
+// rowsNotIn returns the rows of df left over after matching each row of
+// other against one equal row of df.
+func rowsNotIn(df, other *DataFrame) *DataFrame {
+	counts := make(map[string]int, len(other.data))
+	for _, row := range other.data {
+		counts[rowDigest(row)]++
+	}
+	positions := make([]int, 0)
+	for i, row := range df.data {
+		digest := rowDigest(row)
+		if counts[digest] > 0 {
+			counts[digest]--
+			continue
+		}
+		positions = append(positions, i)
+	}
+	return df.takeRows(positions, "diff", nil)
+}
+
+func (s *FrameStore) objectPath(object string) string {
+	return filepath.Join(s.dir, "objects", object+".gpd")
+}
+
+func (s *FrameStore) readLog() ([]FrameVersion, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "log.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []FrameVersion{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store log: %w", err)
+	}
+	var versions []FrameVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse store log: %w", err)
+	}
+	return versions, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so a crash never leaves a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
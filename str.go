@@ -0,0 +1,214 @@
+package gopandas
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type StringAccessor struct {
+	series *Series
+}
+
+type URLComponents struct {
+	Scheme   *Series
+	Host     *Series
+	Port     *Series
+	Path     *Series
+	Query    *Series
+	Fragment *Series
+}
+
+type UserAgentComponents struct {
+	Browser        *Series
+	BrowserVersion *Series
+	OS             *Series
+	Device         *Series
+}
+
+func (s *Series) Str() *StringAccessor {
+	return &StringAccessor{series: s}
+}
+
+func (sa *StringAccessor) apply(suffix string, fn func(string) interface{}) *Series {
+	result := make([]interface{}, len(sa.series.data))
+	for i, val := range sa.series.data {
+		if val == nil {
+			continue
+		}
+		result[i] = fn(fmt.Sprintf("%v", val))
+	}
+
+	name := sa.series.name
+	if suffix != "" {
+		name += "_" + suffix
+	}
+	return sa.series.withData(name, result)
+}
+
+func (sa *StringAccessor) ParseURL() *URLComponents {
+	parsed := make([]*url.URL, len(sa.series.data))
+	for i, val := range sa.series.data {
+		if val == nil {
+			continue
+		}
+		if u, err := url.Parse(strings.TrimSpace(fmt.Sprintf("%v", val))); err == nil {
+			parsed[i] = u
+		}
+	}
+
+	component := func(suffix string, fn func(*url.URL) string) *Series {
+		result := make([]interface{}, len(parsed))
+		for i, u := range parsed {
+			if u == nil {
+				continue
+			}
+			if v := fn(u); v != "" {
+				result[i] = v
+			}
+		}
+		return sa.series.withData(sa.series.name+"_"+suffix, result)
+	}
+
+	return &URLComponents{
+		Scheme:   component("scheme", func(u *url.URL) string { return u.Scheme }),
+		Host:     component("host", func(u *url.URL) string { return u.Hostname() }),
+		Port:     component("port", func(u *url.URL) string { return u.Port() }),
+		Path:     component("path", func(u *url.URL) string { return u.Path }),
+		Query:    component("query", func(u *url.URL) string { return u.RawQuery }),
+		Fragment: component("fragment", func(u *url.URL) string { return u.Fragment }),
+	}
+}
+
+func (sa *StringAccessor) QueryParam(key string) *Series {
+	return sa.apply(key, func(val string) interface{} {
+		u, err := url.Parse(strings.TrimSpace(val))
+		if err != nil {
+			return nil
+		}
+		values := u.Query()
+		if !values.Has(key) {
+			return nil
+		}
+		return values.Get(key)
+	})
+}
+
+var (
+	browserPatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+		{"Opera", regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+		{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+		{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+		{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+		{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+		{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+		{"curl", regexp.MustCompile(`curl/([\d.]+)`)},
+	}
+
+	osPatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Windows", regexp.MustCompile(`Windows`)},
+		{"iOS", regexp.MustCompile(`iPhone|iPad|iPod`)},
+		{"macOS", regexp.MustCompile(`Mac OS X|Macintosh`)},
+		{"Android", regexp.MustCompile(`Android`)},
+		{"ChromeOS", regexp.MustCompile(`CrOS`)},
+		{"Linux", regexp.MustCompile(`Linux`)},
+	}
+
+	botPattern    = regexp.MustCompile(`(?i)bot|crawler|spider|slurp`)
+	tabletPattern = regexp.MustCompile(`iPad|Tablet`)
+	mobilePattern = regexp.MustCompile(`Mobi|iPhone|iPod|Android`)
+)
+
+func (sa *StringAccessor) ParseUserAgent() *UserAgentComponents {
+	parse := func(suffix string, fn func(string) string) *Series {
+		return sa.apply(suffix, func(ua string) interface{} {
+			if v := fn(ua); v != "" {
+				return v
+			}
+			return nil
+		})
+	}
+
+	return &UserAgentComponents{
+		Browser: parse("browser", func(ua string) string {
+			name, _ := parseBrowser(ua)
+			return name
+		}),
+		BrowserVersion: parse("browser_version", func(ua string) string {
+			_, version := parseBrowser(ua)
+			return version
+		}),
+		OS: parse("os", func(ua string) string {
+			for _, p := range osPatterns {
+				if p.pattern.MatchString(ua) {
+					return p.name
+				}
+			}
+			return ""
+		}),
+		Device: parse("device", func(ua string) string {
+			switch {
+			case botPattern.MatchString(ua):
+				return "bot"
+			case tabletPattern.MatchString(ua):
+				return "tablet"
+			case mobilePattern.MatchString(ua):
+				return "mobile"
+			case strings.TrimSpace(ua) == "":
+				return ""
+			}
+			return "desktop"
+		}),
+	}
+}
+
+func parseBrowser(ua string) (string, string) {
+	for _, p := range browserPatterns {
+		if m := p.pattern.FindStringSubmatch(ua); m != nil {
+			return p.name, m[1]
+		}
+	}
+	return "", ""
+}
+
+// IsEmail reports whether each value is a syntactically valid email
+// address. Null values stay null.
+func (sa *StringAccessor) IsEmail() *Series {
+	return sa.apply("is_email", func(val string) interface{} {
+		return isEmail(strings.TrimSpace(val))
+	})
+}
+
+// IsPhone reports whether each value is a valid phone number for region:
+// "US", "CA", "KR", "JP" or "GB", written nationally or with the country
+// code. Other regions, including "", accept any 7 to 15 digit number in
+// E.164 form with optional separators.
+func (sa *StringAccessor) IsPhone(region string) *Series {
+	rule, ok := phoneRegions[strings.ToUpper(region)]
+	return sa.apply("is_phone", func(val string) interface{} {
+		val = strings.TrimSpace(val)
+		if !ok {
+			digits := onlyDigits(val)
+			return phonePattern.MatchString(val) && len(digits) >= 7 && len(digits) <= 15
+		}
+		return rule.valid(val)
+	})
+}
+
+// IsLuhnValid reports whether each value is a number, optionally grouped
+// by spaces or dashes, that passes the Luhn checksum used by payment cards
+// and many ID numbers.
+func (sa *StringAccessor) IsLuhnValid() *Series {
+	return sa.apply("is_luhn_valid", func(val string) interface{} {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(val))
+		return len(digits) >= 2 && onlyDigits(digits) == digits && luhnValid(digits)
+	})
+}
@@ -0,0 +1,291 @@
+package gopandas
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+type StreamAgg struct {
+	Column   string
+	Func     string
+	Quantile float64
+}
+
+type StreamingFrame struct {
+	mu        sync.Mutex
+	columns   []string
+	groupBy   []int
+	aggs      []StreamAgg
+	aggCols   []int
+	groups    map[string]*streamGroup
+	order     []string
+	rowsTotal int
+}
+
+type streamGroup struct {
+	keys   []interface{}
+	states []*streamState
+}
+
+type streamState struct {
+	count    int
+	numeric  int
+	sum      float64
+	min      interface{}
+	max      interface{}
+	digest   *TDigest
+	distinct *HyperLogLog
+}
+
+func NewStreamingFrame(columns []string, groupBy []string, aggs ...StreamAgg) (*StreamingFrame, error) {
+	sf := &StreamingFrame{
+		columns: columns,
+		aggs:    aggs,
+		groups:  make(map[string]*streamGroup),
+	}
+	lookup := NewDataFrame(columns)
+
+	for _, col := range groupBy {
+		idx := lookup.columnIndex(col)
+		if idx == -1 {
+			return nil, fmt.Errorf("column '%s' not found", col)
+		}
+		sf.groupBy = append(sf.groupBy, idx)
+	}
+
+	for _, agg := range aggs {
+		idx := lookup.columnIndex(agg.Column)
+		if idx == -1 {
+			return nil, fmt.Errorf("column '%s' not found", agg.Column)
+		}
+		switch agg.Func {
+		case "count", "sum", "mean", "min", "max", "distinct":
+		case "quantile":
+			if agg.Quantile < 0 || agg.Quantile > 1 {
+				return nil, fmt.Errorf("quantile %v out of range [0, 1]", agg.Quantile)
+			}
+		default:
+			return nil, fmt.Errorf("unknown streaming aggregation '%s'", agg.Func)
+		}
+		sf.aggCols = append(sf.aggCols, idx)
+	}
+
+	return sf, nil
+}
+
+func (sf *StreamingFrame) Add(row []interface{}) error {
+	if len(row) != len(sf.columns) {
+		return fmt.Errorf("row length %d does not match columns length %d", len(row), len(sf.columns))
+	}
+
+	keys := make([]interface{}, len(sf.groupBy))
+	keyParts := make([]string, len(sf.groupBy))
+	for i, idx := range sf.groupBy {
+		keys[i] = row[idx]
+		keyParts[i] = fmt.Sprintf("%T:%v", row[idx], row[idx])
+	}
+	groupKey := strings.Join(keyParts, "\x00")
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	group, ok := sf.groups[groupKey]
+	if !ok {
+		group = &streamGroup{keys: keys, states: make([]*streamState, len(sf.aggs))}
+		for i, agg := range sf.aggs {
+			group.states[i] = newStreamState(agg)
+		}
+		sf.groups[groupKey] = group
+		sf.order = append(sf.order, groupKey)
+	}
+
+	for i, agg := range sf.aggs {
+		group.states[i].update(agg, row[sf.aggCols[i]])
+	}
+	sf.rowsTotal++
+
+	return nil
+}
+
+func newStreamState(agg StreamAgg) *streamState {
+	state := &streamState{}
+	switch agg.Func {
+	case "distinct":
+		state.distinct, _ = NewHyperLogLogWithError(defaultHLLError)
+	case "quantile":
+		state.digest = NewTDigest(defaultTDigestCompress)
+	}
+	return state
+}
+
+func (state *streamState) update(agg StreamAgg, val interface{}) {
+	if val == nil {
+		return
+	}
+	state.count++
+
+	switch agg.Func {
+	case "min":
+		if state.min == nil || compareValues(val, state.min) < 0 {
+			state.min = val
+		}
+	case "max":
+		if state.max == nil || compareValues(val, state.max) > 0 {
+			state.max = val
+		}
+	case "distinct":
+		state.distinct.Add(val)
+	case "sum", "mean":
+		if f, ok := summableFloat(val); ok {
+			state.sum += f
+			state.numeric++
+		}
+	case "quantile":
+		if f, ok := toFloat64(val); ok {
+			state.digest.Add(f)
+		}
+	}
+}
+
+func (sf *StreamingFrame) Consume(ctx context.Context, rows <-chan []interface{}) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case row, ok := <-rows:
+			if !ok {
+				return nil
+			}
+			if err := sf.Add(row); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (sf *StreamingFrame) ConsumeCSV(r io.Reader, options ...CSVOption) error {
+	config := &CSVConfig{
+		HasHeader: true,
+		Delimiter: ',',
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	input, err := config.detectDialect(r)
+	if err != nil {
+		return err
+	}
+	reader := newDialectReader(input, config.Delimiter, config.quote())
+
+	columns := sf.columns
+	if config.HasHeader {
+		header, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read header: %w", err)
+		}
+		columns = header
+	}
+	parsers, err := config.columnParsers(columns)
+	if err != nil {
+		return err
+	}
+
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if config.quote() != '"' {
+			unquoteFields(record, config.quote())
+		}
+
+		row := make([]interface{}, len(record))
+		for i, val := range record {
+			row[i] = inferType(val)
+		}
+		if err := applyParsers(row, record, parsers, columns, line); err != nil {
+			return err
+		}
+		line++
+		if err := sf.Add(row); err != nil {
+			return err
+		}
+	}
+}
+
+func (sf *StreamingFrame) Rows() int {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.rowsTotal
+}
+
+func (sf *StreamingFrame) Snapshot() *DataFrame {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	columns := make([]string, 0, len(sf.groupBy)+len(sf.aggs))
+	for _, idx := range sf.groupBy {
+		columns = append(columns, sf.columns[idx])
+	}
+	for _, agg := range sf.aggs {
+		if agg.Func == "quantile" {
+			columns = append(columns, fmt.Sprintf("%s_p%g", agg.Column, agg.Quantile*100))
+		} else {
+			columns = append(columns, agg.Column+"_"+agg.Func)
+		}
+	}
+
+	result := NewDataFrame(columns)
+	for _, key := range sf.order {
+		group := sf.groups[key]
+		row := make([]interface{}, 0, len(columns))
+		row = append(row, group.keys...)
+		for i, agg := range sf.aggs {
+			row = append(row, group.states[i].value(agg))
+		}
+		result.AddRow(row)
+	}
+
+	return result
+}
+
+func (state *streamState) value(agg StreamAgg) interface{} {
+	switch agg.Func {
+	case "count":
+		return state.count
+	case "sum":
+		if state.numeric == 0 {
+			return nil
+		}
+		return state.sum
+	case "mean":
+		if state.numeric == 0 {
+			return nil
+		}
+		return state.sum / float64(state.numeric)
+	case "min":
+		return state.min
+	case "max":
+		return state.max
+	case "distinct":
+		return state.distinct.Count()
+	case "quantile":
+		q, err := state.digest.Quantile(agg.Quantile)
+		if err != nil {
+			return nil
+		}
+		return q
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package gopandas
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// synthColumn models one column's marginal distribution. Numeric and time
+// columns use their sorted values as an empirical quantile function;
+// other columns use category frequencies.
+type synthColumn struct {
+	kind       string // "int", "float", "time", "category" or "token"
+	sorted     []float64
+	categories []interface{}
+	cumulative []float64
+	nullRate   float64
+}
+
+// Synthesize generates n synthetic rows that follow each column's
+// distribution and the rank correlations between columns (a Gaussian
+// copula). Numeric values are interpolated between observed quantiles, and
+// high-cardinality text columns such as names or IDs are replaced with
+// generated tokens so identifiers do not leak.
+func Synthesize(df *DataFrame, n int) (*DataFrame, error) {
+	return synthesize(df, n, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func synthesize(df *DataFrame, n int, rng *rand.Rand) (*DataFrame, error) {
+	if len(df.data) == 0 {
+		return nil, fmt.Errorf("cannot synthesize from an empty frame")
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("row count must not be negative, got %d", n)
+	}
+
+	models := make([]*synthColumn, len(df.columns))
+	scores := make([][]float64, len(df.columns))
+	for j := range df.columns {
+		values := make([]interface{}, len(df.data))
+		for i, row := range df.data {
+			values[i] = row[j]
+		}
+		models[j], scores[j] = fitSynthColumn(values)
+	}
+
+	chol := cholesky(correlationMatrix(scores))
+	result := df.derive(NewDataFrame(df.columns), "synthesize", map[string]interface{}{"rows": n})
+
+	z := make([]float64, len(df.columns))
+	for i := 0; i < n; i++ {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+
+		row := make([]interface{}, len(df.columns))
+		for j, model := range models {
+			var correlated float64
+			for k := 0; k <= j; k++ {
+				correlated += chol[j][k] * z[k]
+			}
+			if rng.Float64() < model.nullRate {
+				continue
+			}
+			row[j] = model.sample(normalCDF(correlated), i)
+		}
+		result.data = append(result.data, row)
+		result.index = append(result.index, i)
+	}
+
+	return result, nil
+}
+
+// fitSynthColumn builds the column model and each row's normal score, the
+// input to the copula correlation. Null rows score 0.
+func fitSynthColumn(values []interface{}) (*synthColumn, []float64) {
+	model := &synthColumn{}
+	present := make([]int, 0, len(values))
+	numbers := make([]float64, len(values))
+	allInt, allNumeric, allTime := true, true, true
+	for i, val := range values {
+		if val == nil {
+			continue
+		}
+		present = append(present, i)
+		if t, ok := val.(time.Time); ok {
+			numbers[i] = float64(t.UnixNano())
+			allInt, allNumeric = false, false
+			continue
+		}
+		allTime = false
+		switch val.(type) {
+		case string, bool:
+			allInt, allNumeric = false, false
+			continue
+		case float32, float64:
+			allInt = false
+		}
+		f, ok := toFloat64(val)
+		if !ok {
+			allInt, allNumeric = false, false
+			continue
+		}
+		numbers[i] = f
+	}
+
+	switch {
+	case allInt:
+		model.kind = "int"
+	case allNumeric:
+		model.kind = "float"
+	case allTime:
+		model.kind = "time"
+	default:
+		model.kind = "category"
+	}
+
+	scores := make([]float64, len(values))
+	model.nullRate = 1 - float64(len(present))/float64(len(values))
+	if len(present) == 0 {
+		model.kind = "category"
+		return model, scores
+	}
+
+	if model.kind != "category" {
+		order := append([]int{}, present...)
+		sort.SliceStable(order, func(a, b int) bool { return numbers[order[a]] < numbers[order[b]] })
+		for rank, i := range order {
+			model.sorted = append(model.sorted, numbers[i])
+			scores[i] = normalQuantile((float64(rank) + 0.5) / float64(len(order)))
+		}
+		return model, scores
+	}
+
+	counts := make(map[interface{}]int)
+	for _, i := range present {
+		if _, seen := counts[values[i]]; !seen {
+			model.categories = append(model.categories, values[i])
+		}
+		counts[values[i]]++
+	}
+	if len(model.categories) > 20 && float64(len(model.categories)) > 0.5*float64(len(present)) {
+		// mostly unique text is treated as an identifier and never copied
+		model.kind = "token"
+		return model, scores
+	}
+
+	sort.SliceStable(model.categories, func(a, b int) bool {
+		return compareValues(model.categories[a], model.categories[b]) < 0
+	})
+	mids := make(map[interface{}]float64)
+	var total float64
+	for _, category := range model.categories {
+		share := float64(counts[category]) / float64(len(present))
+		mids[category] = total + share/2
+		total += share
+		model.cumulative = append(model.cumulative, total)
+	}
+	for _, i := range present {
+		scores[i] = normalQuantile(mids[values[i]])
+	}
+	return model, scores
+}
+
+func (model *synthColumn) sample(u float64, row int) interface{} {
+	switch model.kind {
+	case "token":
+		return fmt.Sprintf("synthetic_%d", row+1)
+	case "category":
+		if len(model.categories) == 0 {
+			return nil
+		}
+		k := sort.SearchFloat64s(model.cumulative, u)
+		if k >= len(model.categories) {
+			k = len(model.categories) - 1
+		}
+		return model.categories[k]
+	}
+
+	pos := u * float64(len(model.sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	value := model.sorted[lower] + (model.sorted[upper]-model.sorted[lower])*(pos-float64(lower))
+
+	switch model.kind {
+	case "int":
+		return int(math.Round(value))
+	case "time":
+		return time.Unix(0, int64(value)).UTC()
+	}
+	return value
+}
+
+func correlationMatrix(scores [][]float64) [][]float64 {
+	k := len(scores)
+	corr := make([][]float64, k)
+	for a := range corr {
+		corr[a] = make([]float64, k)
+		for b := range corr[a] {
+			if a == b {
+				corr[a][b] = 1
+				continue
+			}
+			var sab, saa, sbb float64
+			for i := range scores[a] {
+				sab += scores[a][i] * scores[b][i]
+				saa += scores[a][i] * scores[a][i]
+				sbb += scores[b][i] * scores[b][i]
+			}
+			if saa > 0 && sbb > 0 {
+				corr[a][b] = sab / math.Sqrt(saa*sbb)
+			}
+		}
+	}
+	return corr
+}
+
+// cholesky factors a correlation matrix, shrinking it towards the identity
+// until it is positive definite.
+func cholesky(corr [][]float64) [][]float64 {
+	k := len(corr)
+	for shrink := 0.0; shrink <= 1; shrink += 0.1 {
+		l := make([][]float64, k)
+		ok := true
+		for i := 0; i < k && ok; i++ {
+			l[i] = make([]float64, k)
+			for j := 0; j <= i; j++ {
+				target := corr[i][j] * (1 - shrink)
+				if i == j {
+					target = 1
+				}
+				sum := target
+				for m := 0; m < j; m++ {
+					sum -= l[i][m] * l[j][m]
+				}
+				if i == j {
+					if sum <= 1e-12 {
+						ok = false
+						break
+					}
+					l[i][i] = math.Sqrt(sum)
+				} else {
+					l[i][j] = sum / l[j][j]
+				}
+			}
+		}
+		if ok {
+			return l
+		}
+	}
+
+	identity := make([][]float64, k)
+	for i := range identity {
+		identity[i] = make([]float64, k)
+		identity[i][i] = 1
+	}
+	return identity
+}
+
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
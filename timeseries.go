@@ -0,0 +1,133 @@
+package gopandas
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type FillMethod string
+
+const (
+	FillForward  FillMethod = "ffill"
+	FillBackward FillMethod = "bfill"
+)
+
+func (df *DataFrame) sortedTimes(column string) (int, []time.Time, map[time.Time]int, error) {
+	colIndex := df.columnIndex(column)
+	if colIndex == -1 {
+		return -1, nil, nil, fmt.Errorf("column '%s' not found", column)
+	}
+
+	rowsByTime := make(map[time.Time]int)
+	times := make([]time.Time, 0, len(df.data))
+	for i, row := range df.data {
+		t, ok := row[colIndex].(time.Time)
+		if !ok {
+			continue
+		}
+		if _, seen := rowsByTime[t]; !seen {
+			rowsByTime[t] = i
+			times = append(times, t)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	return colIndex, times, rowsByTime, nil
+}
+
+func (df *DataFrame) AsFreq(column, freq string, fill interface{}) (*DataFrame, error) {
+	f, err := ParseFrequency(freq)
+	if err != nil {
+		return nil, err
+	}
+	colIndex, times, rowsByTime, err := df.sortedTimes(column)
+	if err != nil {
+		return nil, err
+	}
+
+	result := df.derive(NewDataFrame(df.columns), "as_freq", map[string]interface{}{"column": column, "freq": freq})
+	if len(times) == 0 {
+		return result, nil
+	}
+
+	missing := make([]bool, 0)
+	for t := f.Floor(times[0]); !t.After(times[len(times)-1]); t = f.Next(t) {
+		var row []interface{}
+		if i, ok := rowsByTime[t]; ok {
+			row = append([]interface{}{}, df.data[i]...)
+			missing = append(missing, false)
+		} else {
+			row = make([]interface{}, len(df.columns))
+			row[colIndex] = t
+			missing = append(missing, true)
+		}
+		result.data = append(result.data, row)
+		result.index = append(result.index, t)
+	}
+
+	switch fill {
+	case nil:
+	case FillForward:
+		for i := 1; i < len(result.data); i++ {
+			if missing[i] {
+				fillRow(result.data[i], result.data[i-1], colIndex)
+			}
+		}
+	case FillBackward:
+		for i := len(result.data) - 2; i >= 0; i-- {
+			if missing[i] {
+				fillRow(result.data[i], result.data[i+1], colIndex)
+			}
+		}
+	default:
+		for i, row := range result.data {
+			if missing[i] {
+				for j := range row {
+					if j != colIndex {
+						row[j] = fill
+					}
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func fillRow(row, source []interface{}, skip int) {
+	for j := range row {
+		if j != skip {
+			row[j] = source[j]
+		}
+	}
+}
+
+func (df *DataFrame) ReportGaps(column, freq string) (*DataFrame, error) {
+	f, err := ParseFrequency(freq)
+	if err != nil {
+		return nil, err
+	}
+	_, times, _, err := df.sortedTimes(column)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewDataFrame([]string{"gap_start", "gap_end", "missing"})
+	for i := 1; i < len(times); i++ {
+		expected := f.Next(times[i-1])
+		if !expected.Before(times[i]) {
+			continue
+		}
+
+		missing := 0
+		last := expected
+		for t := expected; t.Before(times[i]); t = f.Next(t) {
+			missing++
+			last = t
+		}
+		result.AddRow([]interface{}{expected, last, missing})
+	}
+
+	return result, nil
+}
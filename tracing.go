@@ -0,0 +1,125 @@
+package gopandas
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TracerProvider starts the spans gopandas reports around its heavier
+// steps (reading and writing files, sorting, grouping). It mirrors the
+// OpenTelemetry API closely enough that an adapter is a few lines, which
+// keeps this package dependency free:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, gopandas.Span) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+// where otelSpan forwards SetAttribute, RecordError and End. The context is
+// the one passed to the Context variants (ReadCSVContext, SortContext and
+// so on), so spans are parented to the caller's span; the plain methods use
+// context.Background.
+type TracerProvider interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is one traced step. Attributes are ints and strings, such as
+// gopandas.rows_in and gopandas.rows_out.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// MetricsRecorder receives one measurement per traced step, for feeding a
+// duration histogram and a row counter. rows is the number of rows the step
+// took in, or produced when it takes no frame; err is the step's error.
+type MetricsRecorder interface {
+	RecordStep(ctx context.Context, name string, duration time.Duration, rows int, err error)
+}
+
+var (
+	tracerMu sync.RWMutex
+	tracer   TracerProvider
+	metrics  MetricsRecorder
+)
+
+// SetTracerProvider turns on tracing with provider; nil turns it off, which
+// is the default.
+func SetTracerProvider(provider TracerProvider) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+	tracer = provider
+}
+
+// SetMetricsRecorder turns on step metrics with recorder; nil turns them
+// off, which is the default.
+func SetMetricsRecorder(recorder MetricsRecorder) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+	metrics = recorder
+}
+
+// traceSpan is a span that may be absent (nil), so call sites need no
+// checks. It also times the step for the metrics recorder.
+type traceSpan struct {
+	ctx      context.Context
+	name     string
+	span     Span
+	recorder MetricsRecorder
+	start    time.Time
+	rows     int
+}
+
+// startSpan starts a span named gopandas.<op> when tracing or metrics are
+// on.
+func startSpan(ctx context.Context, op string) *traceSpan {
+	tracerMu.RLock()
+	provider, recorder := tracer, metrics
+	tracerMu.RUnlock()
+	if provider == nil && recorder == nil {
+		return nil
+	}
+	s := &traceSpan{ctx: ctx, name: "gopandas." + op, recorder: recorder, start: time.Now(), rows: -1}
+	if provider != nil {
+		s.ctx, s.span = provider.StartSpan(ctx, s.name)
+	}
+	return s
+}
+
+func (s *traceSpan) set(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	if n, ok := value.(int); ok && key == "rows_in" {
+		s.rows = n
+	}
+	if s.span != nil {
+		s.span.SetAttribute("gopandas."+key, value)
+	}
+}
+
+// end records the output row count or the error and ends the span.
+func (s *traceSpan) end(result *DataFrame, err error) {
+	if s == nil {
+		return
+	}
+	if result != nil && s.rows < 0 {
+		s.rows = len(result.data)
+	}
+	if s.span != nil {
+		if err != nil {
+			s.span.RecordError(err)
+		} else if result != nil {
+			s.set("rows_out", len(result.data))
+			s.set("columns", len(result.columns))
+		}
+		s.span.End()
+	}
+	if s.recorder != nil {
+		s.recorder.RecordStep(s.ctx, s.name, time.Since(s.start), s.rows, err)
+	}
+}
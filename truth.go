@@ -0,0 +1,82 @@
+package gopandas
+
+import "fmt"
+
+// truthy reports whether a non-null value counts as true: true itself, a
+// non-zero number, a non-empty string, or any other value.
+func truthy(val interface{}) bool {
+	switch v := val.(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if f, ok := toFloat64(val); ok {
+		return f != 0
+	}
+	return true
+}
+
+// Any reports whether any non-null value is true (see All for what counts
+// as true). An empty or all-null series gives false.
+func (s *Series) Any() bool {
+	for _, val := range s.data {
+		if val != nil && truthy(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether every non-null value is true: true, a non-zero
+// number or a non-empty string. Nulls are skipped, so an empty or all-null
+// series gives true.
+func (s *Series) All() bool {
+	for _, val := range s.data {
+		if val != nil && !truthy(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Any reports, per column (axis 0, indexed by column name) or per row
+// (axis 1, aligned with the index), whether any non-null cell is true, as
+// Series.Any does. After a comparison it answers "does any row violate X".
+func (df *DataFrame) Any(axis int) (*Series, error) {
+	return df.reduceTruth("any", axis, (*Series).Any)
+}
+
+// All reports, per column (axis 0) or per row (axis 1), whether every
+// non-null cell is true, as Series.All does.
+func (df *DataFrame) All(axis int) (*Series, error) {
+	return df.reduceTruth("all", axis, (*Series).All)
+}
+
+func (df *DataFrame) reduceTruth(name string, axis int, reduce func(*Series) bool) (*Series, error) {
+	switch axis {
+	case 0:
+		data := make([]interface{}, len(df.columns))
+		index := make([]interface{}, len(df.columns))
+		for j, col := range df.columns {
+			values := make([]interface{}, len(df.data))
+			for i, row := range df.data {
+				values[i] = row[j]
+			}
+			data[j] = reduce(NewSeries(col, values))
+			index[j] = col
+		}
+		series := NewSeries(name, data)
+		series.index = index
+		return series, nil
+	case 1:
+		data := make([]interface{}, len(df.data))
+		for i, row := range df.data {
+			data[i] = reduce(NewSeries(name, row))
+		}
+		series := NewSeries(name, data)
+		series.index = append([]interface{}{}, df.index...)
+		return series, nil
+	}
+	return nil, fmt.Errorf("invalid axis %d: use 0 or 1", axis)
+}
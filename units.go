@@ -0,0 +1,155 @@
+package gopandas
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type unitDef struct {
+	dimension string
+	factor    float64
+}
+
+var (
+	unitsMu   sync.RWMutex
+	unitTable = map[string]unitDef{
+		"ns":  {"time", 1e-9},
+		"us":  {"time", 1e-6},
+		"ms":  {"time", 1e-3},
+		"s":   {"time", 1},
+		"min": {"time", 60},
+		"h":   {"time", 3600},
+		"d":   {"time", 86400},
+
+		"mg": {"mass", 1e-6},
+		"g":  {"mass", 1e-3},
+		"kg": {"mass", 1},
+		"t":  {"mass", 1000},
+		"oz": {"mass", 0.028349523125},
+		"lb": {"mass", 0.45359237},
+
+		"mm": {"length", 1e-3},
+		"cm": {"length", 1e-2},
+		"m":  {"length", 1},
+		"km": {"length", 1000},
+		"in": {"length", 0.0254},
+		"ft": {"length", 0.3048},
+		"mi": {"length", 1609.344},
+
+		"B":  {"data", 1},
+		"KB": {"data", 1e3},
+		"MB": {"data", 1e6},
+		"GB": {"data", 1e9},
+		"TB": {"data", 1e12},
+	}
+)
+
+func RegisterUnit(unit, dimension string, factor float64) {
+	unitsMu.Lock()
+	defer unitsMu.Unlock()
+	unitTable[unit] = unitDef{dimension: dimension, factor: factor}
+}
+
+func (s *Series) Unit() string {
+	return s.unit
+}
+
+func (s *Series) SetUnit(unit string) *Series {
+	s.unit = strings.TrimSpace(unit)
+	return s
+}
+
+func (s *Series) ConvertUnit(target string) (*Series, error) {
+	factor, err := unitFactor(s.unit, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(s.data))
+	for i, val := range s.data {
+		if val == nil {
+			continue
+		}
+		f, ok := toFloat64(val)
+		if !ok {
+			return nil, fmt.Errorf("row %d: cannot convert non-numeric value '%v'", i, val)
+		}
+		result[i] = f * factor
+	}
+
+	converted := s.withData(s.name, result)
+	converted.unit = target
+	return converted, nil
+}
+
+func (s *Series) Add(other *Series) (*Series, error) {
+	return s.combineWithUnits(other, func(a, b float64) float64 { return a + b })
+}
+
+func (s *Series) Sub(other *Series) (*Series, error) {
+	for _, val := range s.data {
+		if _, ok := val.(time.Time); ok {
+			return s.subTimes(other)
+		}
+		if val != nil {
+			break
+		}
+	}
+	return s.combineWithUnits(other, func(a, b float64) float64 { return a - b })
+}
+
+func (s *Series) combineWithUnits(other *Series, op func(a, b float64) float64) (*Series, error) {
+	if len(s.data) != len(other.data) {
+		return nil, fmt.Errorf("series length %d does not match length %d", len(s.data), len(other.data))
+	}
+
+	factor := 1.0
+	if s.unit != other.unit {
+		if s.unit == "" || other.unit == "" {
+			return nil, fmt.Errorf("cannot combine series with unit '%s' and series with unit '%s'", s.unit, other.unit)
+		}
+		f, err := unitFactor(other.unit, s.unit)
+		if err != nil {
+			return nil, err
+		}
+		factor = f
+	}
+
+	result := make([]interface{}, len(s.data))
+	for i := range s.data {
+		a, ok1 := toFloat64(s.data[i])
+		b, ok2 := toFloat64(other.data[i])
+		if !ok1 || !ok2 {
+			continue
+		}
+		result[i] = op(a, b*factor)
+	}
+
+	combined := s.withData(s.name, result)
+	combined.unit = s.unit
+	return combined, nil
+}
+
+func unitFactor(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	unitsMu.RLock()
+	fromDef, fromOK := unitTable[from]
+	toDef, toOK := unitTable[to]
+	unitsMu.RUnlock()
+	if !fromOK {
+		return 0, fmt.Errorf("unknown unit '%s'", from)
+	}
+	if !toOK {
+		return 0, fmt.Errorf("unknown unit '%s'", to)
+	}
+	if fromDef.dimension != toDef.dimension {
+		return 0, fmt.Errorf("incompatible units '%s' (%s) and '%s' (%s)", from, fromDef.dimension, to, toDef.dimension)
+	}
+
+	return fromDef.factor / toDef.factor, nil
+}
@@ -0,0 +1,134 @@
+package gopandas
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RejectReasonColumn is added to the rejected frame returned by ValidateRows.
+const RejectReasonColumn = "_reject_reason"
+
+// ValidationRule is one row-level check. Column rules set Column and Check;
+// rules spanning several columns set RowCheck instead.
+type ValidationRule struct {
+	Name     string
+	Column   string
+	Check    func(value interface{}) bool
+	RowCheck func(row map[string]interface{}) bool
+}
+
+func ColumnRule(column, name string, check func(value interface{}) bool) ValidationRule {
+	return ValidationRule{Name: name, Column: column, Check: check}
+}
+
+func RowRule(name string, check func(row map[string]interface{}) bool) ValidationRule {
+	return ValidationRule{Name: name, RowCheck: check}
+}
+
+func NotNull(column string) ValidationRule {
+	return ColumnRule(column, fmt.Sprintf("%s is null", column), func(value interface{}) bool {
+		return value != nil && value != ""
+	})
+}
+
+// InRange accepts numbers between min and max inclusive. Nulls pass; combine
+// with NotNull to require a value.
+func InRange(column string, min, max float64) ValidationRule {
+	return ColumnRule(column, fmt.Sprintf("%s not in [%v, %v]", column, min, max), func(value interface{}) bool {
+		if value == nil {
+			return true
+		}
+		f, ok := toFloat64(value)
+		return ok && f >= min && f <= max
+	})
+}
+
+func OneOf(column string, allowed ...interface{}) ValidationRule {
+	return ColumnRule(column, fmt.Sprintf("%s not one of %v", column, allowed), func(value interface{}) bool {
+		if value == nil {
+			return true
+		}
+		for _, a := range allowed {
+			if compareValues(value, a) == 0 {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func MatchesPattern(column string, pattern *regexp.Regexp) ValidationRule {
+	return ColumnRule(column, fmt.Sprintf("%s does not match %s", column, pattern), func(value interface{}) bool {
+		if value == nil {
+			return true
+		}
+		return pattern.MatchString(fmt.Sprintf("%v", value))
+	})
+}
+
+// ValidateRows splits the frame into rows passing every rule and rejected
+// rows, so bad records can be quarantined instead of failing the load. The
+// rejected frame has an extra _reject_reason column listing failed rules.
+func (df *DataFrame) ValidateRows(rules ...ValidationRule) (*DataFrame, *DataFrame, error) {
+	colIndices := make([]int, len(rules))
+	needsRecord := false
+	for i, rule := range rules {
+		colIndices[i] = -1
+		if rule.RowCheck != nil {
+			needsRecord = true
+			continue
+		}
+		if rule.Check == nil {
+			return nil, nil, fmt.Errorf("rule '%s' has no check", rule.Name)
+		}
+		colIndices[i] = df.columnIndex(rule.Column)
+		if colIndices[i] == -1 {
+			return nil, nil, fmt.Errorf("column '%s' not found", rule.Column)
+		}
+	}
+
+	valid := df.derive(NewDataFrame(df.columns), "validate_rows", map[string]interface{}{"rules": len(rules), "part": "valid"})
+	rejectedColumns := append(append([]string{}, df.columns...), RejectReasonColumn)
+	rejected := df.derive(NewDataFrame(rejectedColumns), "validate_rows", map[string]interface{}{"rules": len(rules), "part": "rejected"})
+
+	for i, row := range df.data {
+		var record map[string]interface{}
+		if needsRecord {
+			record = rowRecord(df.columns, row)
+		}
+
+		reasons := make([]string, 0)
+		for r, rule := range rules {
+			var ok bool
+			if rule.RowCheck != nil {
+				ok = rule.RowCheck(record)
+			} else {
+				ok = rule.Check(row[colIndices[r]])
+			}
+			if !ok {
+				reasons = append(reasons, rule.Name)
+			}
+		}
+
+		if len(reasons) == 0 {
+			valid.data = append(valid.data, row)
+			valid.index = append(valid.index, df.index[i])
+			continue
+		}
+		newRow := append(append([]interface{}{}, row...), strings.Join(reasons, "; "))
+		rejected.data = append(rejected.data, newRow)
+		rejected.index = append(rejected.index, df.index[i])
+	}
+
+	return valid, rejected, nil
+}
+
+// rowRecord maps column names to a row's values for RowCheck rules.
+func rowRecord(columns []string, row []interface{}) map[string]interface{} {
+	record := make(map[string]interface{}, len(columns))
+	for j, col := range columns {
+		record[col] = row[j]
+	}
+	return record
+}